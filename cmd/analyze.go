@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/history"
+)
+
+// commitHashRegex matches full or abbreviated commit hashes
+var commitHashRegex = regexp.MustCompile(`^[0-9a-fA-F]{4,40}$`)
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+
+	// Share the feedback flags with the moai command
+	analyzeCmd.Flags().BoolVarP(&useAI, "ai", "a", false, "Use AI to generate feedback")
+	analyzeCmd.Flags().StringVarP(&personalityFlag, "personality", "p", "", "Personality to use for feedback, or \"random\" (default: from config)")
+	analyzeCmd.Flags().BoolVarP(&debugMode, "debug", "D", false, "Enable debug mode to show detailed API information")
+}
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze <commit>",
+	Short: "Get feedback on a specific commit",
+	Long: `Load a single commit's message and diff and run Moai feedback on it.
+
+Unlike 'noidea moai', which looks at your most recent commit, this lets you
+review any commit in the repository's history.
+
+Examples:
+  noidea analyze 1a2b3c4d        # Feedback on a specific commit
+  noidea analyze HEAD~3 --ai     # AI feedback on an older commit`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Load configuration
+		cfg := config.LoadConfig()
+
+		// Resolve and load the commit, including its diff for analysis
+		commit, err := history.GetCommitByHash(args[0], true)
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		shortHash := commit.Hash
+		if len(shortHash) > 8 {
+			shortHash = shortHash[:8]
+		}
+		fmt.Printf("%s %s by %s on %s\n",
+			color.CyanString("Analyzing commit"),
+			color.YellowString(shortHash),
+			commit.Author,
+			commit.Timestamp.Format("2006-01-02 15:04"))
+
+		showMoaiFeedback(cfg, commit.Message, commit.Hash, commit.DiffSummary)
+	},
+}
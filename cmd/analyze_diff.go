@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/git"
+)
+
+var (
+	analyzeDiffStagedFlag bool
+	analyzeDiffFileFlag   string
+	analyzeDiffJSONFlag   bool
+)
+
+func init() {
+	rootCmd.AddCommand(analyzeDiffCmd)
+
+	analyzeDiffCmd.Flags().BoolVar(&analyzeDiffStagedFlag, "staged", false, "Analyze the staged changes (the default)")
+	analyzeDiffCmd.Flags().StringVarP(&analyzeDiffFileFlag, "file", "F", "", "Analyze the diff in this file (\"-\" for stdin)")
+	analyzeDiffCmd.Flags().BoolVar(&analyzeDiffJSONFlag, "json", false, "Print the analysis as JSON")
+}
+
+var analyzeDiffCmd = &cobra.Command{
+	Use:   "analyze-diff",
+	Short: "Show how noidea reads a diff, without calling a model",
+	Long: `Run the diff analysis behind commit suggestions and print it: the files by
+category and operation, and the functions, imports, structs, interfaces and
+constants that were added or removed. No AI provider is called.
+
+Use it to prepare for a code review, or to see why a suggestion turned out
+the way it did. The staged changes are analyzed, using the diff options from
+the config, unless --file names a diff to read instead. Besides git diffs,
+--file accepts git format-patch output and plain unified diffs (diff -u).`,
+	Example: `  noidea analyze-diff                   # Analyze the staged changes
+  git diff main | noidea analyze-diff -F -
+  noidea analyze-diff --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if analyzeDiffStagedFlag && analyzeDiffFileFlag != "" {
+			fmt.Println(color.RedString("❌ Error:"), "Use only one of --staged and --file")
+			os.Exit(1)
+		}
+
+		diff, err := readAnalyzeDiff(analyzeDiffFileFlag)
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+		if strings.TrimSpace(diff) == "" {
+			fmt.Println(color.YellowString("No changes to analyze."))
+			if analyzeDiffFileFlag == "" {
+				fmt.Println("Stage your changes with 'git add', or pass a diff with --file.")
+			}
+			return
+		}
+
+		analysis := feedback.AnalyzeDiff(diff)
+		if analyzeDiffJSONFlag {
+			data, err := json.MarshalIndent(analysis, "", "  ")
+			if err != nil {
+				fmt.Println(color.RedString("❌ Error:"), err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		printDiffAnalysis(analysis)
+	},
+}
+
+// readAnalyzeDiff returns the diff in file, or the staged diff when file is empty
+func readAnalyzeDiff(file string) (string, error) {
+	if file == "" {
+		return getStagedDiff(git.DiffOptionsFromConfig(config.LoadConfig()))
+	}
+	return readDiffFile(file)
+}
+
+// printDiffAnalysis prints an analysis section by section, skipping empty ones
+func printDiffAnalysis(analysis feedback.DiffAnalysis) {
+	files := analysis.Files
+
+	fmt.Println(color.CyanString("🔍 Diff analysis"))
+	fmt.Println(color.HiBlackString(divider))
+	fmt.Printf("%d files changed (%d added, %d modified, %d deleted), %s %s\n",
+		len(files.Changed), len(files.Added), len(files.Modified), len(files.Deleted),
+		color.GreenString("+%d", files.Additions), color.RedString("-%d", files.Deletions))
+
+	printAnalysisSection("Files by category", func() {
+		for _, category := range feedback.FileCategories {
+			if paths := files.Categories[category]; len(paths) > 0 {
+				fmt.Printf("  %s: %s\n", feedback.CategoryLabel(category), strings.Join(paths, ", "))
+			}
+		}
+	}, len(files.Categories) > 0)
+
+	printAnalysisSection("File operations", func() {
+		for _, path := range files.Added {
+			fmt.Printf("  %s %s\n", color.GreenString("A"), path)
+		}
+		for _, path := range files.Modified {
+			fmt.Printf("  %s %s\n", color.YellowString("M"), path)
+		}
+		for _, path := range files.Deleted {
+			fmt.Printf("  %s %s\n", color.RedString("D"), path)
+		}
+	}, len(files.Changed) > 0)
+
+	printAnalysisSection("Binary files (not counted in line changes)", func() {
+		for _, path := range files.Binary {
+			fmt.Printf("  %s\n", path)
+		}
+	}, len(files.Binary) > 0)
+
+	printAnalysisSection("Functions", func() {
+		for _, name := range sortedKeys(analysis.Functions) {
+			if analysis.Functions[name] == "+" {
+				fmt.Printf("  %s %s\n", color.GreenString("+"), name)
+			} else {
+				fmt.Printf("  %s %s\n", color.RedString("-"), name)
+			}
+		}
+	}, len(analysis.Functions) > 0)
+
+	printAnalysisSection("Imports", func() {
+		for _, imp := range analysis.Imports {
+			fmt.Printf("  %s\n", imp)
+		}
+	}, len(analysis.Imports) > 0)
+
+	printAnalysisSection("Structs", func() {
+		printMembers(analysis.Structs)
+	}, len(analysis.Structs) > 0)
+
+	printAnalysisSection("Interfaces", func() {
+		printMembers(analysis.Interfaces)
+	}, len(analysis.Interfaces) > 0)
+
+	printAnalysisSection("Constants", func() {
+		for _, name := range sortedKeys(analysis.Constants) {
+			fmt.Printf("  %s = %s\n", name, analysis.Constants[name])
+		}
+	}, len(analysis.Constants) > 0)
+
+	printAnalysisSection("Variables", func() {
+		for _, name := range sortedKeys(analysis.Variables) {
+			fmt.Printf("  %s = %s\n", name, analysis.Variables[name])
+		}
+	}, len(analysis.Variables) > 0)
+}
+
+// printAnalysisSection prints a titled section when it has content
+func printAnalysisSection(title string, body func(), show bool) {
+	if !show {
+		return
+	}
+	fmt.Println()
+	fmt.Println(color.New(color.Bold).Sprint(title))
+	body()
+}
+
+// printMembers prints types and their changed fields or methods
+func printMembers(types map[string][]string) {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if members := types[name]; len(members) > 0 {
+			fmt.Printf("  %s: %s\n", name, strings.Join(members, ", "))
+		} else {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+}
+
+// sortedKeys returns the keys of a string map in order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
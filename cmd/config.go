@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -14,11 +14,13 @@ import (
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
 	"github.com/AccursedGalaxy/noidea/internal/secure"
+	"github.com/AccursedGalaxy/noidea/internal/ui"
 )
 
 var (
 	// Config operation flags
 	showConfig   bool
+	revealConfig bool
 	initConfig   bool
 	validateFlag bool
 
@@ -31,15 +33,18 @@ func init() {
 
 	// Add flags
 	configCmd.Flags().BoolVarP(&showConfig, "show", "s", false, "Show current configuration")
+	configCmd.Flags().BoolVar(&revealConfig, "reveal", false, "With --show, print the API key unmasked (asks for confirmation)")
 	configCmd.Flags().BoolVarP(&initConfig, "init", "i", false, "Initialize a new config file")
 	configCmd.Flags().BoolVarP(&validateFlag, "validate", "v", false, "Validate the current configuration")
-	configCmd.Flags().StringVarP(&configPath, "path", "p", "", "Path to config file (default: ~/.noidea/config.toml)")
+	configCmd.Flags().StringVarP(&configPath, "path", "p", "", "Path to config file (default: ~/.noidea/config.json, or config.toml if that is the only one)")
 
 	// Add key management commands
 	configCmd.AddCommand(configAPIKeyCmd)
 	configCmd.AddCommand(configAPIKeyRemoveCmd)
 	configCmd.AddCommand(configAPIKeyStatusCmd)
 	configCmd.AddCommand(configAPIKeyCleanEnvCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	configCmd.AddCommand(configSourcesCmd)
 
 	// Add flags to API key commands
 	configAPIKeyCmd.Flags().Bool("skip-validation", false, "Skip API key validation")
@@ -52,20 +57,15 @@ var configCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		// Determine config path if not specified
 		if configPath == "" {
-			home, err := os.UserHomeDir()
-			if err == nil {
-				configPath = filepath.Join(home, ".noidea", "config.toml")
-			} else {
-				configPath = ".noidea.toml"
-			}
+			configPath = config.ConfigFilePath()
 		}
 
 		// Load configuration
 		cfg := config.LoadConfig()
 
 		// Show configuration
-		if showConfig {
-			printConfig(cfg)
+		if showConfig || revealConfig {
+			printConfig(cfg, revealConfig && confirmReveal())
 			return
 		}
 
@@ -94,8 +94,25 @@ var configCmd = &cobra.Command{
 	},
 }
 
-// printConfig displays the current configuration
-func printConfig(cfg config.Config) {
+// confirmReveal asks before printing secrets in plain text
+func confirmReveal() bool {
+	if ui.AssumeYes {
+		return true
+	}
+
+	fmt.Print("This will print your API key in plain text. Continue? [y/N]: ")
+	var confirm string
+	fmt.Scanln(&confirm)
+
+	if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
+		fmt.Println("Showing the masked key instead.")
+		return false
+	}
+	return true
+}
+
+// printConfig displays the current configuration, masking the API key unless reveal is set
+func printConfig(cfg config.Config, reveal bool) {
 	fmt.Println(color.CyanString("🧠 noidea configuration:"))
 
 	fmt.Println(color.CyanString("\n[LLM]"))
@@ -103,22 +120,42 @@ func printConfig(cfg config.Config) {
 	fmt.Printf("Provider: %s\n", cfg.LLM.Provider)
 
 	// Don't show the full API key for security
-	apiKey := cfg.LLM.APIKey
-	if apiKey != "" {
-		if len(apiKey) > 8 {
-			apiKey = apiKey[:4] + "..." + apiKey[len(apiKey)-4:]
-		} else {
-			apiKey = "***"
-		}
+	apiKey := secure.Mask(cfg.LLM.APIKey)
+	if reveal {
+		apiKey = cfg.LLM.APIKey
 	}
 
 	fmt.Printf("API Key: %s\n", apiKey)
-	fmt.Printf("Model: %s\n", cfg.LLM.Model)
+	model := cfg.LLM.Model
+	if model == "" {
+		model = "(provider default)"
+	}
+	fmt.Printf("Model: %s\n", model)
 	fmt.Printf("Temperature: %.1f\n", cfg.LLM.Temperature)
 
 	fmt.Println(color.CyanString("\n[Moai]"))
 	fmt.Printf("Use Lint: %v\n", cfg.Moai.UseLint)
 	fmt.Printf("Faces Mode: %s\n", cfg.Moai.FacesMode)
+
+	if cfg.Policy != nil {
+		fmt.Println(color.CyanString("\n[Repository Policy]"))
+		fmt.Printf("File: %s\n", cfg.Policy.Path)
+		if cfg.Policy.Provider != "" {
+			fmt.Printf("Pinned Provider: %s\n", cfg.Policy.Provider)
+		}
+		if cfg.Policy.Model != "" {
+			fmt.Printf("Pinned Model: %s\n", cfg.Policy.Model)
+		}
+		if len(cfg.Policy.AllowedProviders) > 0 {
+			fmt.Printf("Allowed Providers: %s\n", strings.Join(cfg.Policy.AllowedProviders, ", "))
+		}
+		if len(cfg.Policy.AllowedModels) > 0 {
+			fmt.Printf("Allowed Models: %s\n", strings.Join(cfg.Policy.AllowedModels, ", "))
+		}
+		if len(cfg.Policy.AllowedPersonalities) > 0 {
+			fmt.Printf("Allowed Personalities: %s\n", strings.Join(cfg.Policy.AllowedPersonalities, ", "))
+		}
+	}
 }
 
 // createConfigInteractive creates a new config file with user input
@@ -142,8 +179,9 @@ func createConfigInteractive(path string) {
 		fmt.Print("Provider (xai, openai, deepseek): ")
 		response, _ = reader.ReadString('\n')
 		provider := strings.TrimSpace(response)
-		if provider != "" {
+		if provider != "" && provider != cfg.LLM.Provider {
 			cfg.LLM.Provider = provider
+			cfg.LLM.Model = config.ProviderModel(cfg, provider)
 		}
 
 		// API Key
@@ -396,10 +434,16 @@ var configAPIKeyCmd = &cobra.Command{
 			fmt.Scanln(&enableLLM)
 
 			if strings.ToLower(enableLLM) == "y" || strings.ToLower(enableLLM) == "yes" {
-				cfg.LLM.Enabled = true
-				cfg.LLM.Provider = provider
+				// Save the user's own settings, not the repository policy
+				userCfg := config.LoadUserConfig()
+				userCfg.LLM.Enabled = true
+				if provider != userCfg.LLM.Provider {
+					// Don't keep the old provider's model
+					userCfg.LLM.Provider = provider
+					userCfg.LLM.Model = config.ProviderModel(userCfg, provider)
+				}
 
-				if err := config.SaveConfig(cfg); err != nil {
+				if err := config.SaveConfig(userCfg); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: Failed to update config: %v\n", err)
 				} else {
 					fmt.Println("LLM features enabled successfully.")
@@ -438,12 +482,99 @@ var configAPIKeyRemoveCmd = &cobra.Command{
 	},
 }
 
+// configSchemaCmd prints a JSON Schema for the config file
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema for the config file",
+	Long: `Print a JSON Schema describing every config setting, its type, allowed
+values and default. Point your editor at it for autocompletion and validation:
+
+  noidea config schema > ~/.noidea/config.schema.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := json.MarshalIndent(config.Schema(), "", "  ")
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	},
+}
+
+// configSourcesCmd shows where each effective setting comes from
+var configSourcesCmd = &cobra.Command{
+	Use:   "sources [setting...]",
+	Short: "Show which config layer set each setting",
+	Long: `Print every setting with its effective value and the layer it came from:
+
+  default   noidea's built-in default
+  shared    the organization's config from NOIDEA_CONFIG_URL
+  file      ~/.noidea/config.json or config.toml
+  project   the nearest .noidea.json or .noidea.toml from the current directory up
+  keyring   the API key from secure storage
+  env       a NOIDEA_* or provider API key environment variable
+  repo      the repository's ` + config.PolicyFile + ` policy
+
+Later layers win. Pass setting names or prefixes such as "llm" or
+"llm.provider" to show only those.`,
+	Example: `  noidea config sources
+  noidea config sources llm.provider llm.model`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, settings := config.LoadConfigSources()
+
+		sourceColors := map[string]func(string, ...interface{}) string{
+			config.SourceDefault: color.HiBlackString,
+			config.SourceShared:  color.BlueString,
+			config.SourceFile:    color.CyanString,
+			config.SourceProject: color.HiCyanString,
+			config.SourceKeyring: color.GreenString,
+			config.SourceEnv:     color.YellowString,
+			config.SourceRepo:    color.MagentaString,
+		}
+
+		shown := 0
+		for _, setting := range settings {
+			if !matchesSetting(setting.Path, args) {
+				continue
+			}
+			value := setting.Value
+			if value == "" {
+				value = color.HiBlackString("(empty)")
+			}
+			fmt.Printf("%-28s %-8s %s\n", setting.Path, sourceColors[setting.Source]("%s", setting.Source), value)
+			shown++
+		}
+
+		if shown == 0 {
+			fmt.Println(color.RedString("❌ Error:"), "No settings match", strings.Join(args, ", "))
+			os.Exit(1)
+		}
+	},
+}
+
+// matchesSetting reports whether a setting path equals or is below one of
+// the requested names; no names match everything
+func matchesSetting(path string, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if path == name || strings.HasPrefix(path, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
 // configAPIKeyStatusCmd shows the status of secure storage
 var configAPIKeyStatusCmd = &cobra.Command{
 	Use:   "apikey-status",
 	Short: "Check API key storage status",
 	Long:  `Check the status of secure API key storage on your system.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		// Load config first so the configured keychain or collection is checked
+		cfg := config.LoadConfig()
+
 		// Get secure storage status
 		status := secure.GetSecureStorageStatus()
 
@@ -463,6 +594,9 @@ var configAPIKeyStatusCmd = &cobra.Command{
 		// Check fallback status
 		fmt.Printf("Fallback storage: %s\n", status["fallback"])
 
+		// Show where the key lives so it can be found in the native tools
+		fmt.Printf("Keyring item: %s\n", secure.KeyringItemLocation(cfg.LLM.Provider))
+
 		// Check if API key is set in environment
 		envApiKey := ""
 		envSource := ""
@@ -482,7 +616,6 @@ var configAPIKeyStatusCmd = &cobra.Command{
 		}
 
 		// Check if API key is in secure storage
-		cfg := config.LoadConfig()
 		secureApiKey, secureErr := secure.GetAPIKey(cfg.LLM.Provider)
 
 		// Show information about both keys
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/ui"
+)
+
+func init() {
+	configCmd.AddCommand(configEditCmd)
+}
+
+// configEditCmd opens the config file in the user's editor and only saves it when it is valid
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the config file in your editor",
+	Long: `Open ~/.noidea/config.json (or config.toml) in your editor, the same one git
+uses: GIT_EDITOR, core.editor, VISUAL, then EDITOR.
+
+The file is checked when the editor closes. A file that does not parse or
+that introduces new validation issues is not written back; you can reopen
+the editor to fix it or discard the changes. When no config file exists yet,
+the editor starts from the defaults.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !ui.IsInteractive() {
+			fmt.Println(color.RedString("❌ Error:"), "config edit needs an interactive terminal")
+			os.Exit(1)
+		}
+
+		path := config.ConfigFilePath()
+		if path == "" {
+			fmt.Println(color.RedString("❌ Error:"), "Could not determine the config file location")
+			os.Exit(1)
+		}
+
+		original, err := readConfigForEdit(path)
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		// Issues the file already had should not block saving other changes
+		known, _ := config.CheckConfigData(path, []byte(original))
+
+		reader := bufio.NewReader(os.Stdin)
+		content := original
+		for {
+			edited, err := ui.EditContent(content, filepath.Ext(path))
+			if err != nil {
+				fmt.Println(color.RedString("❌ Error:"), err)
+				os.Exit(1)
+			}
+
+			if edited == strings.TrimRight(original, "\n") {
+				fmt.Println(color.YellowString("No changes made."))
+				return
+			}
+
+			problems := configEditProblems(path, edited, known)
+			if len(problems) == 0 {
+				if err := writeEditedConfig(path, edited); err != nil {
+					fmt.Println(color.RedString("❌ Error:"), err)
+					os.Exit(1)
+				}
+				fmt.Println(color.GreenString("✅ Saved"), path)
+				return
+			}
+
+			fmt.Println(color.RedString("✗ The edited config was not saved:"))
+			for _, problem := range problems {
+				fmt.Println(color.YellowString("  - " + problem))
+			}
+
+			if !confirmReopen(reader, os.Stdout) {
+				fmt.Println("Discarded your changes;", path, "is unchanged.")
+				os.Exit(1)
+			}
+			content = edited
+		}
+	},
+}
+
+// readConfigForEdit returns the config file's contents, or the defaults
+// encoded for that file when it does not exist yet
+func readConfigForEdit(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data, err = config.EncodeConfig(path, config.DefaultConfig())
+		if err != nil {
+			return "", fmt.Errorf("failed to encode default config: %w", err)
+		}
+		return string(data) + "\n", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+	return string(data), nil
+}
+
+// configEditProblems lists why edited config contents can't be saved,
+// ignoring validation issues that were already present before editing
+func configEditProblems(path, edited string, known []string) []string {
+	issues, err := config.CheckConfigData(path, []byte(edited))
+	if err != nil {
+		return []string{fmt.Sprintf("Could not parse %s: %v", filepath.Base(path), err)}
+	}
+
+	existing := make(map[string]bool, len(known))
+	for _, issue := range known {
+		existing[issue] = true
+	}
+
+	var problems []string
+	for _, issue := range issues {
+		if !existing[issue] {
+			problems = append(problems, issue)
+		}
+	}
+	return problems
+}
+
+// writeEditedConfig writes the edited contents to the config file
+func writeEditedConfig(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// confirmReopen asks whether to go back to the editor; Enter means yes
+func confirmReopen(reader *bufio.Reader, out io.Writer) bool {
+	fmt.Fprint(out, "Reopen the editor to fix it? [Y/n]: ")
+	answer, err := reader.ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
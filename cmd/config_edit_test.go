@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestConfigEditProblems(t *testing.T) {
+	t.Setenv("NOIDEA_API_KEY", "test-key")
+
+	if problems := configEditProblems("config.json", `{"llm": {"enabled": true}}`, nil); len(problems) != 0 {
+		t.Errorf("valid config reported problems: %v", problems)
+	}
+
+	problems := configEditProblems("config.json", `{"llm": `, nil)
+	if len(problems) != 1 || !strings.Contains(problems[0], "Could not parse") {
+		t.Errorf("malformed config problems = %v", problems)
+	}
+
+	edited := `{"llm": {"enabled": true, "provider": "nope"}}`
+	problems = configEditProblems("config.json", edited, nil)
+	if len(problems) == 0 {
+		t.Fatal("expected an issue for an unknown provider")
+	}
+	if again := configEditProblems("config.json", edited, problems); len(again) != 0 {
+		t.Errorf("issues present before editing should be ignored, got %v", again)
+	}
+}
+
+func TestConfirmReopen(t *testing.T) {
+	tests := map[string]bool{"\n": true, "y\n": true, "YES\n": true, "n\n": false, "": false}
+	for input, want := range tests {
+		if got := confirmReopen(bufio.NewReader(strings.NewReader(input)), io.Discard); got != want {
+			t.Errorf("confirmReopen(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/git"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+// Health check statuses
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+var (
+	// Doctor command flags
+	doctorJSONFlag    bool
+	doctorOfflineFlag bool
+)
+
+// doctorCheck is the result of a single health check
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// doctorReport is the machine-readable form of the doctor output
+type doctorReport struct {
+	Status string        `json:"status"`
+	Checks []doctorCheck `json:"checks"`
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorJSONFlag, "json", false, "Print the health report as JSON")
+	doctorCmd.Flags().BoolVar(&doctorOfflineFlag, "offline", false, "Skip checks that need network access, such as API key validation")
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that noidea is set up correctly",
+	Long: `Run a series of health checks on your environment: Git, configuration,
+API key and installed hooks.
+
+The command exits with status 1 if any check fails, so it can be used in
+onboarding scripts and CI.
+
+Examples:
+  noidea doctor            # Human-readable report
+  noidea doctor --json     # Report as JSON: {status, checks: [{name, status, detail}]}
+  noidea doctor --offline  # Skip API key validation`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.LoadConfig()
+		report := runDoctorChecks(cfg)
+
+		if doctorJSONFlag {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Println(color.RedString("❌ Error:"), "Failed to encode report:", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printDoctorReport(report)
+		}
+
+		if report.Status == checkFail {
+			os.Exit(1)
+		}
+	},
+}
+
+// runDoctorChecks runs every health check and summarizes the overall status
+func runDoctorChecks(cfg config.Config) doctorReport {
+	var checks []doctorCheck
+
+	checks = append(checks, checkGitInstalled())
+
+	gitDir, err := git.FindGitDir()
+	if err != nil {
+		checks = append(checks, doctorCheck{"repository", checkWarn, "not inside a Git repository"})
+	} else {
+		checks = append(checks, doctorCheck{"repository", checkOK, gitDir})
+	}
+
+	checks = append(checks, checkConfig(cfg)...)
+
+	hooksDir := ""
+	if gitDir != "" {
+		hooksDir, _ = git.FindHooksDir()
+	}
+	for _, hook := range []string{"post-commit", "prepare-commit-msg"} {
+		checks = append(checks, checkHook(hooksDir, hook))
+	}
+
+	checks = append(checks, checkGitHubToken())
+
+	report := doctorReport{Status: checkOK, Checks: checks}
+	for _, check := range checks {
+		if check.Status == checkFail {
+			report.Status = checkFail
+			break
+		}
+	}
+
+	return report
+}
+
+// checkGitInstalled verifies the git executable is available
+func checkGitInstalled() doctorCheck {
+	output, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return doctorCheck{"git", checkFail, "git not found or not executable"}
+	}
+	return doctorCheck{"git", checkOK, strings.TrimSpace(string(output))}
+}
+
+// checkConfig validates the configuration and, when AI is enabled, the API key
+func checkConfig(cfg config.Config) []doctorCheck {
+	var checks []doctorCheck
+
+	if issues := config.ValidateConfig(cfg); len(issues) > 0 {
+		checks = append(checks, doctorCheck{"config", checkFail, strings.Join(issues, "; ")})
+	} else {
+		checks = append(checks, doctorCheck{"config", checkOK, "configuration is valid"})
+	}
+
+	if !cfg.LLM.Enabled {
+		checks = append(checks, doctorCheck{"api-key", checkSkip, "AI features disabled, the local engine will be used"})
+		return checks
+	}
+
+	if cfg.LLM.Provider == config.ProviderMock {
+		checks = append(checks, doctorCheck{"api-key", checkSkip, "the mock provider answers offline without an API key"})
+		return checks
+	}
+
+	if cfg.LLM.APIKey == "" {
+		checks = append(checks, doctorCheck{"api-key", checkFail,
+			fmt.Sprintf("AI is enabled but no API key is set for %s (run 'noidea config apikey')", cfg.LLM.Provider)})
+		return checks
+	}
+
+	if doctorOfflineFlag {
+		checks = append(checks, doctorCheck{"api-key", checkOK,
+			fmt.Sprintf("key for %s is set (not validated: --offline)", cfg.LLM.Provider)})
+		return checks
+	}
+
+	valid, err := secure.ValidateAPIKey(cfg.LLM.Provider, cfg.LLM.APIKey)
+	switch {
+	case err != nil:
+		checks = append(checks, doctorCheck{"api-key", checkWarn, fmt.Sprintf("could not validate key for %s: %v", cfg.LLM.Provider, err)})
+	case !valid:
+		checks = append(checks, doctorCheck{"api-key", checkFail, fmt.Sprintf("key for %s was rejected by the provider", cfg.LLM.Provider)})
+	default:
+		checks = append(checks, doctorCheck{"api-key", checkOK, fmt.Sprintf("key for %s is valid", cfg.LLM.Provider)})
+	}
+
+	return checks
+}
+
+// checkHook reports whether the given noidea hook is installed
+func checkHook(hooksDir, hook string) doctorCheck {
+	name := "hook:" + hook
+	if hooksDir == "" {
+		return doctorCheck{name, checkSkip, "not inside a Git repository"}
+	}
+
+	hookPath := filepath.Join(hooksDir, hook)
+	if _, err := os.Stat(hookPath); err != nil {
+		return doctorCheck{name, checkWarn, "not installed (run 'noidea init')"}
+	}
+	if !git.IsNoideaHook(hookPath) {
+		return doctorCheck{name, checkWarn, "a hook exists but was not installed by noidea"}
+	}
+	return doctorCheck{name, checkOK, hookPath}
+}
+
+// checkGitHubToken reports whether GitHub credentials are stored
+func checkGitHubToken() doctorCheck {
+	if _, err := secure.GetGitHubToken(); err != nil {
+		return doctorCheck{"github", checkSkip, "not authenticated (only needed for GitHub release features)"}
+	}
+	return doctorCheck{"github", checkOK, "token stored"}
+}
+
+// printDoctorReport prints the health report for humans
+func printDoctorReport(report doctorReport) {
+	fmt.Println(color.CyanString("🩺 noidea doctor"))
+	fmt.Println(color.HiBlackString(divider))
+
+	for _, check := range report.Checks {
+		var symbol string
+		switch check.Status {
+		case checkOK:
+			symbol = color.GreenString("✓")
+		case checkWarn:
+			symbol = color.YellowString("!")
+		case checkFail:
+			symbol = color.RedString("✗")
+		default:
+			symbol = color.HiBlackString("-")
+		}
+		fmt.Printf("%s %-24s %s\n", symbol, check.Name, check.Detail)
+	}
+
+	fmt.Println(color.HiBlackString(divider))
+	if report.Status == checkFail {
+		fmt.Println(color.RedString("Some checks failed."))
+	} else {
+		fmt.Println(color.GreenString("All required checks passed."))
+	}
+}
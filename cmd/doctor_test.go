@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+// doctorHelperEnv makes the test binary run `noidea doctor --json` in
+// TestDoctorHelper, so the exit status can be checked from the parent
+const doctorHelperEnv = "NOIDEA_DOCTOR_HELPER"
+
+func TestRunDoctorChecks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer secure.SetKeyring(secure.NewMemoryKeyring())()
+	chdirTestRepo(t)
+
+	cfg := config.DefaultConfig()
+	cfg.LLM.Enabled = true
+	cfg.LLM.Provider = "openai"
+	cfg.LLM.APIKey = ""
+
+	report := runDoctorChecks(cfg)
+	if report.Status != checkFail {
+		t.Errorf("status = %q, want %q when AI is enabled without a key", report.Status, checkFail)
+	}
+
+	var names []string
+	statuses := make(map[string]string)
+	for _, check := range report.Checks {
+		names = append(names, check.Name)
+		statuses[check.Name] = check.Status
+	}
+	if want := "git,repository,config,api-key,hook:post-commit,hook:prepare-commit-msg,github"; strings.Join(names, ",") != want {
+		t.Errorf("checks = %s, want %s", strings.Join(names, ","), want)
+	}
+	if statuses["repository"] != checkOK || statuses["api-key"] != checkFail || statuses["hook:post-commit"] != checkWarn || statuses["github"] != checkSkip {
+		t.Errorf("statuses = %v", statuses)
+	}
+
+	// The JSON form is {status, checks: [{name, status, detail}]}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Status string              `json:"status"`
+		Checks []map[string]string `json:"checks"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, check := range decoded.Checks {
+		if len(check) != 3 || check["name"] == "" || check["status"] == "" || check["detail"] == "" {
+			t.Errorf("check JSON = %v, want name, status and detail", check)
+		}
+	}
+
+	cfg.LLM.Enabled = false
+	if report := runDoctorChecks(cfg); report.Status != checkOK {
+		t.Errorf("status with AI disabled = %q, want %q", report.Status, checkOK)
+	}
+}
+
+// TestDoctorHelper is not a real test: it runs the doctor command as the
+// child process of TestDoctorExitStatus
+func TestDoctorHelper(t *testing.T) {
+	if os.Getenv(doctorHelperEnv) == "" {
+		t.Skip("only runs as a child of TestDoctorExitStatus")
+	}
+
+	rootCmd.SetArgs([]string{"doctor", "--json"})
+	rootCmd.Execute()
+	os.Exit(0)
+}
+
+func TestDoctorExitStatus(t *testing.T) {
+	// Keep the child away from real keys and settings
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "NOIDEA_") && !strings.Contains(strings.SplitN(kv, "=", 2)[0], "API_KEY") {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, "HOME="+t.TempDir(), "NOIDEA_KEYRING=memory", doctorHelperEnv+"=1", "NOIDEA_LLM_PROVIDER=openai")
+
+	for _, tt := range []struct {
+		enabled  string
+		status   string
+		exitCode int
+	}{
+		{"false", checkOK, 0},
+		{"true", checkFail, 1},
+	} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestDoctorHelper$")
+		cmd.Dir = t.TempDir()
+		cmd.Env = append(env, "NOIDEA_LLM_ENABLED="+tt.enabled)
+
+		output, err := cmd.Output()
+		exitCode := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		var report doctorReport
+		if err := json.Unmarshal(output, &report); err != nil {
+			t.Fatalf("AI enabled %s: output is not a JSON report: %v\n%s", tt.enabled, err, output)
+		}
+		if report.Status != tt.status || exitCode != tt.exitCode {
+			t.Errorf("AI enabled %s: status %q, exit code %d, want %q and %d", tt.enabled, report.Status, exitCode, tt.status, tt.exitCode)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/personality"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+	"github.com/AccursedGalaxy/noidea/internal/telemetry"
+)
+
+// engineFactory creates a feedback engine for a single provider/model/key
+type engineFactory func(provider, model, apiKey string) feedback.FeedbackEngine
+
+// newFeedbackEngine creates the configured engine, wrapping it with any
+// LLM.Fallbacks so a failing provider transparently falls through to the next
+func newFeedbackEngine(cfg config.Config, create engineFactory) feedback.FeedbackEngine {
+	feedback.MaxRetries = cfg.LLM.MaxRetries
+	feedback.MaxContextTokens = cfg.LLM.MaxContextTokens
+	feedback.OnRequest = nil
+	if cfg.Moai.Telemetry {
+		feedback.OnRequest = recordRequestTelemetry
+	}
+
+	// The mock provider never fails, so fallbacks are never needed
+	if len(cfg.LLM.Fallbacks) == 0 || feedback.UseMock(cfg.LLM.Provider) {
+		return create(cfg.LLM.Provider, cfg.LLM.Model, cfg.LLM.APIKey)
+	}
+
+	var engines []feedback.ProviderEngine
+	if cfg.LLM.APIKey != "" {
+		engines = append(engines, feedback.ProviderEngine{
+			Name:   providerName(cfg.LLM.Provider, cfg.LLM.Model),
+			Engine: create(cfg.LLM.Provider, cfg.LLM.Model, cfg.LLM.APIKey),
+		})
+	}
+
+	// Each fallback uses its own securely stored key
+	for _, fallback := range cfg.LLM.Fallbacks {
+		if fallback.Model == "" {
+			fallback.Model = config.ProviderModel(cfg, fallback.Provider)
+		}
+		// A model pinned in llm.provider_models must still satisfy the policy
+		if !cfg.Policy.AllowsModel(fallback.Model) {
+			if verboseFlag {
+				fmt.Fprintf(os.Stderr, "Skipping fallback %s: model not allowed by %s\n", providerName(fallback.Provider, fallback.Model), cfg.Policy.Path)
+			}
+			continue
+		}
+		apiKey, err := secure.GetAPIKey(fallback.Provider)
+		if err != nil || apiKey == "" {
+			if verboseFlag {
+				fmt.Fprintf(os.Stderr, "Skipping fallback %s: no stored API key\n", providerName(fallback.Provider, fallback.Model))
+			}
+			continue
+		}
+		engines = append(engines, feedback.ProviderEngine{
+			Name:   providerName(fallback.Provider, fallback.Model),
+			Engine: create(fallback.Provider, fallback.Model, apiKey),
+		})
+	}
+
+	// Without any usable key we end up with the local engine
+	if len(engines) == 0 {
+		return create(cfg.LLM.Provider, cfg.LLM.Model, "")
+	}
+
+	return feedback.NewFallbackFeedbackEngine(engines)
+}
+
+// recordRequestTelemetry stores a finished AI request in the local telemetry
+func recordRequestTelemetry(usage feedback.Usage) {
+	errorKind := ""
+	if usage.Err != nil {
+		errorKind = feedback.ErrorKind(usage.Err)
+	}
+	if err := telemetry.RecordRequest(usage.Request, usage.Provider, usage.Model, usage.PromptTokens, usage.CompletionTokens, errorKind); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to record telemetry: %v\n", err)
+	}
+}
+
+// reportAnsweringProvider prints which provider produced the response in verbose mode
+func reportAnsweringProvider(engine feedback.FeedbackEngine, cfg config.Config) {
+	if !verboseFlag {
+		return
+	}
+
+	name := providerName(cfg.LLM.Provider, cfg.LLM.Model)
+	switch e := engine.(type) {
+	case *feedback.FallbackFeedbackEngine:
+		name = e.AnsweredBy()
+	case *feedback.LocalFeedbackEngine:
+		name = "local"
+	case *feedback.MockFeedbackEngine:
+		name = "mock"
+	}
+
+	fmt.Fprintln(os.Stderr, color.HiBlackString("Answered by: "+name))
+}
+
+// offlineEngine reports whether an engine answers without calling a model,
+// so its responses are cheap and not worth caching
+func offlineEngine(engine feedback.FeedbackEngine) bool {
+	switch engine.(type) {
+	case *feedback.LocalFeedbackEngine, *feedback.MockFeedbackEngine:
+		return true
+	}
+	return false
+}
+
+// providerName formats a provider/model pair for display
+func providerName(provider, model string) string {
+	if model == "" {
+		return provider
+	}
+	return provider + "/" + model
+}
+
+// requireAllowedPersonality exits with an explanation when a personality chosen
+// on the command line is forbidden by the repository policy
+func requireAllowedPersonality(cfg config.Config, name string) {
+	if err := cfg.Policy.CheckPersonality(name); err != nil {
+		fmt.Println(color.RedString("❌ Error:"), err)
+		os.Exit(1)
+	}
+}
+
+// resolvePersonality turns "random" into a personality picked from the
+// loaded set, only among those the repository policy allows, so the pick
+// can be shown and checked like a named one
+func resolvePersonality(cfg config.Config, name string) string {
+	if !personality.IsRandom(name) {
+		return name
+	}
+
+	personalities, err := personality.LoadPersonalities(cfg.Moai.PersonalityFile)
+	if err != nil {
+		personalities = personality.DefaultPersonalities()
+	}
+	if picked := personalities.RandomName(func(candidate string) bool {
+		return cfg.Policy.CheckPersonality(candidate) == nil
+	}); picked != "" {
+		return picked
+	}
+	return name
+}
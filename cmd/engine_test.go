@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+func TestNewFeedbackEngineSkipsForbiddenFallbackModel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer secure.SetKeyring(secure.NewMemoryKeyring())()
+	for _, provider := range []string{"openai", "deepseek"} {
+		if err := secure.StoreAPIKey(provider, "sk-"+provider); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.LLM.APIKey = "xai-key"
+	cfg.LLM.Fallbacks = []config.LLMFallback{{Provider: "openai"}, {Provider: "deepseek", Model: "deepseek-chat"}}
+	cfg.LLM.ProviderModels = map[string]string{"openai": "gpt-4o"}
+	cfg.Policy = &config.RepoPolicy{Path: "policy.json", AllowedModels: []string{cfg.LLM.Model, "deepseek-chat"}}
+
+	var created []string
+	newFeedbackEngine(cfg, func(provider, model, apiKey string) feedback.FeedbackEngine {
+		created = append(created, provider+"/"+model)
+		return nil
+	})
+
+	want := []string{"xai/" + cfg.LLM.Model, "deepseek/deepseek-chat"}
+	if len(created) != len(want) || created[0] != want[0] || created[1] != want[1] {
+		t.Errorf("created engines %v, want %v", created, want)
+	}
+}
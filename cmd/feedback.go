@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/moai"
+	"github.com/AccursedGalaxy/noidea/internal/personality"
+)
+
+var (
+	// Feedback test flags
+	feedbackPersonalityFlag string
+	feedbackMessageFlag     string
+	feedbackDiffFlag        string
+	feedbackFileFlag        string
+)
+
+func init() {
+	rootCmd.AddCommand(feedbackCmd)
+	feedbackCmd.AddCommand(feedbackTestCmd)
+
+	feedbackTestCmd.Flags().StringVarP(&feedbackPersonalityFlag, "personality", "p", "", "Personality to test, or \"random\" (default: from config)")
+	feedbackTestCmd.Flags().StringVarP(&feedbackMessageFlag, "message", "m", "", "Commit message to give feedback on (required)")
+	feedbackTestCmd.Flags().StringVar(&feedbackDiffFlag, "diff", "", "Read a diff or diff summary for the commit from this `file` (\"-\" for stdin)")
+	feedbackTestCmd.Flags().StringVar(&feedbackFileFlag, "personality-file", "", "Load personalities from this `file` instead of the configured one")
+	feedbackTestCmd.MarkFlagRequired("message")
+}
+
+// feedbackCmd groups tools for commit feedback
+var feedbackCmd = &cobra.Command{
+	Use:   "feedback",
+	Short: "Work with Moai's commit feedback",
+	Long:  `Tools for the AI feedback Moai gives on commits.`,
+}
+
+// feedbackTestCmd previews a personality's feedback on a made-up commit
+var feedbackTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Preview a personality's feedback on a made-up commit",
+	Long: `Generate AI feedback for a commit message of your choice, without making
+a commit or needing a Git repository. Use it to try a personality while you
+tune its prompts in your personalities file.
+
+The personality's template is rendered and sent to the configured provider
+exactly as for a real commit, but without the recent-history context.
+
+Examples:
+  noidea feedback test -p snarky_reviewer -m "fix: stuff"
+  noidea feedback test -p my_persona -m "feat: add login" --diff changes.diff
+  git diff | noidea feedback test -m "refactor: tidy" --diff -
+  noidea feedback test -p my_persona -m "wip" --personality-file ./personalities.toml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.LoadConfig()
+		if feedbackFileFlag != "" {
+			if _, err := os.Stat(feedbackFileFlag); err != nil {
+				fmt.Println(color.RedString("❌ Error:"), "Personality file not found:", feedbackFileFlag)
+				os.Exit(1)
+			}
+			cfg.Moai.PersonalityFile = feedbackFileFlag
+		}
+
+		name, selected, err := testPersonality(cfg, feedbackPersonalityFlag)
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+		if feedbackPersonalityFlag != "" {
+			requireAllowedPersonality(cfg, name)
+		}
+
+		var diff string
+		if feedbackDiffFlag != "" {
+			diff, err = readFeedbackDiff(feedbackDiffFlag)
+			if err != nil {
+				fmt.Println(color.RedString("❌ Error:"), err)
+				os.Exit(1)
+			}
+		}
+
+		engine := newFeedbackEngine(cfg, func(provider, model, apiKey string) feedback.FeedbackEngine {
+			return feedback.NewFeedbackEngine(provider, model, apiKey, name, cfg.Moai.PersonalityFile)
+		})
+		if _, local := engine.(*feedback.LocalFeedbackEngine); local {
+			fmt.Println(color.RedString("❌ Error:"), "No AI provider is configured; personalities only apply to AI feedback (see 'noidea config apikey')")
+			os.Exit(1)
+		}
+
+		fmt.Printf("%s %s (%s)\n", color.CyanString("🧠 Personality:"), color.YellowString(name), selected.Name)
+		fmt.Printf("%s %s\n\n", color.CyanString("📝 Commit:"), feedbackMessageFlag)
+
+		response, err := engine.GenerateFeedback(feedback.CommitContext{
+			Message:   feedbackMessageFlag,
+			Timestamp: time.Now(),
+			Diff:      diff,
+		})
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), "Failed to generate feedback:", err)
+			os.Exit(1)
+		}
+
+		reportAnsweringProvider(engine, cfg)
+		fmt.Println(moai.Face(cfg.Moai.FacesMode, response))
+		fmt.Println(response)
+	},
+}
+
+// testPersonality resolves the personality to test. A name given on the
+// command line must exist, rather than quietly falling back to the default
+// like commit feedback, and a personality file that exists must load.
+func testPersonality(cfg config.Config, name string) (string, personality.Personality, error) {
+	personalities, err := personality.LoadPersonalities(cfg.Moai.PersonalityFile)
+	if err != nil {
+		if _, statErr := os.Stat(cfg.Moai.PersonalityFile); statErr == nil {
+			return "", personality.Personality{}, err
+		}
+	}
+
+	explicit := name != ""
+	if !explicit {
+		name = config.ScheduledPersonality(cfg, time.Now(), "")
+	}
+	name = resolvePersonality(cfg, name)
+
+	selected, err := personalities.GetPersonality(name)
+	if err != nil && explicit {
+		return "", personality.Personality{}, fmt.Errorf("%w (see 'noidea moai --list-personalities')", err)
+	}
+	if err != nil {
+		name = personalities.Default
+		selected, _ = personalities.GetPersonality(name)
+	}
+	if err := personality.ValidatePersonality(selected); err != nil {
+		return "", personality.Personality{}, fmt.Errorf("personality '%s' is invalid: %w", name, err)
+	}
+	return name, selected, nil
+}
+
+// readFeedbackDiff reads the --diff file, or stdin for "-"
+func readFeedbackDiff(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read diff: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+)
+
+func TestTestPersonality(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "personalities.toml")
+	data := []byte(`[personalities.pirate]
+name = "Pirate"
+description = "Arr"
+system_prompt = "You are a pirate reviewing commits."
+user_prompt_format = "Commit: {{.Message}}"
+max_tokens = 100
+temperature = 0.7
+`)
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Moai.PersonalityFile = file
+
+	if name, p, err := testPersonality(cfg, "pirate"); err != nil || name != "pirate" || p.Name != "Pirate" {
+		t.Errorf("testPersonality(pirate) = %q, %q, %v", name, p.Name, err)
+	}
+	if _, _, err := testPersonality(cfg, "parrot"); err == nil {
+		t.Error("an unknown personality on the command line should be an error")
+	}
+
+	// Without a name the configured personality is used, falling back to the default
+	cfg.Moai.Personality = "no_such_personality"
+	if name, _, err := testPersonality(cfg, ""); err != nil || name == "" {
+		t.Errorf("testPersonality() = %q, %v, want the default personality", name, err)
+	}
+}
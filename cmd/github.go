@@ -7,20 +7,30 @@ import (
 	"os/exec"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
 	"github.com/AccursedGalaxy/noidea/internal/github"
+	"github.com/AccursedGalaxy/noidea/internal/gitlab"
 	"github.com/AccursedGalaxy/noidea/internal/secure"
+	"github.com/AccursedGalaxy/noidea/internal/ui"
 )
 
+// githubRemoteFlag is the git remote whose repository releases are read from
+// and published to, e.g. upstream when origin is a fork
+var githubRemoteFlag string
+
 // githubCmd represents the github command
 var githubCmd = &cobra.Command{
 	Use:   "github",
 	Short: "GitHub integration commands",
 	Long:  `Commands for interacting with GitHub repositories and services.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applyReleaseTimeout(config.LoadConfig())
+	},
 }
 
 // githubAuthCmd represents the github auth command
@@ -84,7 +94,8 @@ var githubHookInstallCmd = &cobra.Command{
 	Short: "Install GitHub-related Git hooks",
 	Long:  `Install Git hooks for GitHub integration, such as automatic release creation.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runGitHubHookInstall()
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		runGitHubHookInstall(dryRun)
 	},
 }
 
@@ -93,14 +104,28 @@ var githubReleaseNotesCmd = &cobra.Command{
 	Use:   "notes",
 	Short: "Generate and update release notes",
 	Long: `Generate AI-enhanced release notes from commit messages and update GitHub release.
-This command uses LLM (if enabled) to create comprehensive, user-friendly release notes.`,
+This command uses LLM (if enabled) to create comprehensive, user-friendly release notes.
+
+Set release.provider to "gitlab" in the config to publish to GitLab instead.
+
+Use --tags to generate notes for several tags in one run; a summary of what was
+generated, kept, skipped or failed (and the estimated AI tokens) is printed at the end.
+
+Use --dry-run to print the final notes without creating or updating the release.
+
+Use --debug-raw to save the unprocessed AI response to release-notes-<tag>.raw.md
+and the cleaned notes to release-notes-<tag>.md in the current directory.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		tag, _ := cmd.Flags().GetString("tag")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		skipExisting, _ := cmd.Flags().GetBool("skip-existing")
 		useAI, _ := cmd.Flags().GetBool("ai")
 		skipApproval, _ := cmd.Flags().GetBool("skip-approval")
 		auto, _ := cmd.Flags().GetBool("auto")
 		waitForWorkflows, _ := cmd.Flags().GetBool("wait-for-workflows")
 		maxWaitSeconds, _ := cmd.Flags().GetInt("max-wait")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		debugRaw, _ := cmd.Flags().GetBool("debug-raw")
 
 		// If auto flag is provided, enable both AI and skip approval
 		if auto {
@@ -108,12 +133,22 @@ This command uses LLM (if enabled) to create comprehensive, user-friendly releas
 			skipApproval = true
 		}
 
-		runGitHubReleaseNotes(tag, useAI, skipApproval, waitForWorkflows, maxWaitSeconds)
+		if len(tags) > 0 {
+			if tag != "" {
+				fmt.Println("Error: use either --tag or --tags, not both")
+				os.Exit(1)
+			}
+			runGitHubBatchReleaseNotes(tags, useAI, skipApproval, skipExisting, waitForWorkflows, maxWaitSeconds, dryRun, debugRaw)
+			return
+		}
+
+		runGitHubReleaseNotes(tag, useAI, skipApproval, waitForWorkflows, maxWaitSeconds, dryRun, debugRaw)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(githubCmd)
+	githubCmd.PersistentFlags().StringVar(&githubRemoteFlag, "remote", "origin", "Git remote of the repository to use, e.g. upstream when origin is a fork")
 	githubCmd.AddCommand(githubAuthCmd)
 	githubCmd.AddCommand(githubStatusCmd)
 	githubCmd.AddCommand(githubLogoutCmd)
@@ -140,6 +175,13 @@ func init() {
 	githubReleaseNotesCmd.Flags().Bool("auto", false, "Automatically generate and update notes without interaction (enables --ai and --skip-approval)")
 	githubReleaseNotesCmd.Flags().Bool("wait-for-workflows", false, "Wait for GitHub Actions workflows to complete before generating notes")
 	githubReleaseNotesCmd.Flags().Int("max-wait", 300, "Maximum time in seconds to wait for workflows to complete (default: 5 minutes)")
+	githubReleaseNotesCmd.Flags().StringSlice("tags", nil, "Comma-separated tags to generate notes for in one batch run")
+	githubReleaseNotesCmd.Flags().Bool("skip-existing", false, "In batch runs, keep releases that already have notes")
+	githubReleaseNotesCmd.Flags().Bool("dry-run", false, "Print the final release notes without creating or updating the release")
+	githubReleaseNotesCmd.Flags().Bool("debug-raw", false, "Save the unprocessed AI response next to the cleaned notes in the current directory")
+
+	// Flags for hook install command
+	githubHookInstallCmd.Flags().Bool("dry-run", false, "Show what would be installed without writing any files")
 }
 
 // runGitHubAuth handles the GitHub authentication flow
@@ -148,6 +190,7 @@ func runGitHubAuth() {
 	fmt.Println("---------------------")
 	fmt.Println("This will store a GitHub Personal Access Token (PAT) for noidea to use.")
 	fmt.Println("To create a new token, visit: https://github.com/settings/tokens")
+	fmt.Println("(for GitHub Enterprise, run this inside a repository on your server)")
 	fmt.Println("Required scopes: repo, read:user")
 	fmt.Println()
 
@@ -178,7 +221,7 @@ func runGitHubAuth() {
 
 	// Validate the token
 	fmt.Println("Validating token...")
-	valid, userData, err := secure.ValidateGitHubToken(token)
+	valid, userData, err := secure.ValidateGitHubToken(github.RepoAPIURL(githubRemoteFlag), token)
 	if err != nil || !valid {
 		if err != nil {
 			fmt.Printf("Error validating token: %s\n", err)
@@ -218,7 +261,7 @@ func runGitHubStatus() {
 
 	// Token exists, validate it
 	fmt.Println("Checking GitHub authentication status...")
-	valid, userData, err := secure.ValidateGitHubToken(token)
+	valid, userData, err := secure.ValidateGitHubToken(github.RepoAPIURL(githubRemoteFlag), token)
 	if err != nil || !valid {
 		fmt.Println("Your GitHub token is invalid or expired.")
 		fmt.Println("Run 'noidea github auth' to re-authenticate.")
@@ -269,7 +312,7 @@ func runGitHubLogout() {
 // runGitHubCreateRelease handles creating a GitHub release
 func runGitHubCreateRelease(tag, name string, draft, prerelease bool) {
 	// Initialize GitHub client
-	client, err := github.NewClient()
+	client, err := github.NewRepoClient(githubRemoteFlag)
 	if err != nil {
 		fmt.Printf("Error: %s\n", err)
 		return
@@ -281,7 +324,7 @@ func runGitHubCreateRelease(tag, name string, draft, prerelease bool) {
 	}
 
 	// Get repository owner and name
-	owner, repo, err := github.ExtractRepoInfo("")
+	owner, repo, err := github.ExtractRepoInfo(githubRemoteFlag)
 	if err != nil {
 		fmt.Printf("Error: Failed to determine repository info: %s\n", err)
 		fmt.Println("Make sure you're in a GitHub repository with a valid remote.")
@@ -337,33 +380,39 @@ func generateReleaseNotes(tag string) (string, error) {
 }
 
 // runGitHubHookInstall installs GitHub hooks
-func runGitHubHookInstall() {
-	// Check GitHub authentication
+func runGitHubHookInstall(dryRun bool) {
+	// Check GitHub authentication; a dry run only previews, so it can go ahead without it
 	_, err := secure.GetGitHubToken()
-	if err != nil {
+	authenticated := err == nil
+	if !authenticated && !dryRun {
 		fmt.Println("GitHub authentication required to install hooks.")
 		fmt.Println("Run 'noidea github auth' to authenticate first.")
 		return
 	}
 
 	// Install post-tag hook
-	err = github.InstallPostTagHook()
+	err = github.InstallPostTagHook(dryRun)
 	if err != nil {
 		fmt.Printf("Error installing post-tag hook: %s\n", err)
 		return
 	}
 
+	if dryRun {
+		if !authenticated {
+			fmt.Println("Note: installing the hook requires GitHub authentication; run 'noidea github auth' first.")
+		}
+		fmt.Println("Dry run complete, no files were changed.")
+		return
+	}
+
 	fmt.Println("GitHub hooks installed successfully!")
 	fmt.Println("Now when you create a Git tag, a GitHub release will be created automatically.")
 }
 
 // runGitHubReleaseNotes handles generating and updating release notes
-func runGitHubReleaseNotes(tag string, forceAI bool, skipApproval bool, waitForWorkflows bool, maxWaitSeconds int) {
-	// Check if we're authenticated with GitHub
-	_, err := secure.GetGitHubToken()
-	if err != nil {
-		fmt.Println("GitHub authentication required.")
-		fmt.Println("Run 'noidea github auth' to authenticate.")
+func runGitHubReleaseNotes(tag string, forceAI bool, skipApproval bool, waitForWorkflows bool, maxWaitSeconds int, dryRun bool, debugRaw bool) {
+	// Check if we're authenticated with the release provider
+	if !releaseProviderAuthenticated() {
 		return
 	}
 
@@ -379,20 +428,18 @@ func runGitHubReleaseNotes(tag string, forceAI bool, skipApproval bool, waitForW
 		fmt.Printf("Using latest tag: %s\n", tag)
 	}
 
-	// Load config
-	cfg := config.LoadConfig()
-
-	// Override LLM enabled flag if forceAI is true
-	if forceAI {
-		cfg.LLM.Enabled = true
-	}
-
-	// Create release manager
-	manager, err := github.NewReleaseManager(cfg)
+	cfg, manager, err := newReleaseNotesManager(forceAI)
 	if err != nil {
 		fmt.Printf("Error creating release manager: %s\n", err)
 		return
 	}
+	manager.DryRun = dryRun
+	manager.DebugRaw = debugRaw
+
+	// The global --yes accepts the notes just like --skip-approval
+	if ui.AssumeYes {
+		skipApproval = true
+	}
 
 	if waitForWorkflows {
 		fmt.Printf("🚀 Starting release notes generation for %s with workflow check...\n", tag)
@@ -411,11 +458,90 @@ func runGitHubReleaseNotes(tag string, forceAI bool, skipApproval bool, waitForW
 
 	if err != nil {
 		fmt.Printf("\n❌ Error generating or updating release notes: %s\n", err)
-	} else if !skipApproval {
+	} else if !skipApproval && !dryRun {
 		fmt.Printf("\n🎉 Release notes for %s successfully updated!\n", tag)
 	}
 }
 
+// runGitHubBatchReleaseNotes generates release notes for several tags and
+// prints a summary of the run
+func runGitHubBatchReleaseNotes(tags []string, forceAI bool, skipApproval bool, skipExisting bool, waitForWorkflows bool, maxWaitSeconds int, dryRun bool, debugRaw bool) {
+	if !releaseProviderAuthenticated() {
+		return
+	}
+
+	cfg, manager, err := newReleaseNotesManager(forceAI)
+	if err != nil {
+		fmt.Printf("Error creating release manager: %s\n", err)
+		return
+	}
+	manager.DryRun = dryRun
+	manager.DebugRaw = debugRaw
+
+	if ui.AssumeYes {
+		skipApproval = true
+	}
+
+	fmt.Printf("🚀 Generating %s release notes for %d tags...\n", getGenerationTypeString(cfg.LLM.Enabled), len(tags))
+
+	report := manager.UpdateReleaseNotesBatch(tags, skipApproval, skipExisting, waitForWorkflows, maxWaitSeconds)
+
+	fmt.Println()
+	fmt.Print(report.Format())
+
+	if report.Count(github.BatchFailed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// applyReleaseTimeout sets how long GitHub and GitLab API calls wait for a
+// response from release.timeout
+func applyReleaseTimeout(cfg config.Config) {
+	github.Timeout = time.Duration(cfg.Release.Timeout) * time.Second
+}
+
+// newReleaseNotesManager loads the configuration and creates a release manager for it
+func newReleaseNotesManager(forceAI bool) (config.Config, *github.ReleaseManager, error) {
+	cfg := config.LoadConfig()
+	ui.MaxRetries = cfg.Moai.ApprovalRetries
+
+	// Override LLM enabled flag if forceAI is true
+	if forceAI {
+		cfg.LLM.Enabled = true
+	}
+
+	if cfg.Release.Provider == "gitlab" {
+		client, err := gitlab.NewClient(cfg.Release.GitLabURL, githubRemoteFlag)
+		if err != nil {
+			return cfg, nil, err
+		}
+		return cfg, github.NewReleaseManagerWithProvider(cfg, client), nil
+	}
+
+	manager, err := github.NewReleaseManager(cfg, githubRemoteFlag)
+	return cfg, manager, err
+}
+
+// releaseProviderAuthenticated reports whether a token is stored for the
+// configured release provider, telling the user how to add one if not
+func releaseProviderAuthenticated() bool {
+	if config.LoadConfig().Release.Provider == "gitlab" {
+		if _, err := secure.GetGitLabToken(); err != nil {
+			fmt.Println("GitLab authentication required.")
+			fmt.Println("Run 'noidea gitlab auth' to authenticate.")
+			return false
+		}
+		return true
+	}
+
+	if _, err := secure.GetGitHubToken(); err != nil {
+		fmt.Println("GitHub authentication required.")
+		fmt.Println("Run 'noidea github auth' to authenticate.")
+		return false
+	}
+	return true
+}
+
 // getLatestTag returns the latest tag in the Git repository
 func getLatestTag() (string, error) {
 	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0")
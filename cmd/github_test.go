@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+// runGit runs a git command in dir with a fixed identity and returns its trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=NoIdea Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=NoIdea Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// chdirTestRepo creates an empty git repository and makes it the working directory
+func chdirTestRepo(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return repo
+}
+
+// captureStdout returns what fn prints to os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		done <- buf.String()
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestGitHubHookInstallDryRunWithoutAuth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer secure.SetKeyring(secure.NewMemoryKeyring())()
+	repo := chdirTestRepo(t)
+	hookPath := filepath.Join(repo, ".git", "hooks", "post-tag")
+
+	output := captureStdout(t, func() { runGitHubHookInstall(true) })
+	if strings.Contains(output, "authentication required") {
+		t.Errorf("--dry-run should not stop for GitHub auth:\n%s", output)
+	}
+	for _, want := range []string{"Would write hook: " + hookPath, "noidea github auth", "Dry run complete"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("dry-run output is missing %q:\n%s", want, output)
+		}
+	}
+	if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+		t.Error("--dry-run should not write the post-tag hook")
+	}
+
+	// A real install still needs authentication
+	output = captureStdout(t, func() { runGitHubHookInstall(false) })
+	if !strings.Contains(output, "authentication required") {
+		t.Errorf("install without auth output = %q", output)
+	}
+	if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+		t.Error("install without auth should not write the post-tag hook")
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+// gitlabCmd represents the gitlab command
+var gitlabCmd = &cobra.Command{
+	Use:   "gitlab",
+	Short: "GitLab integration commands",
+	Long: `Commands for connecting noidea to GitLab.
+
+To publish release notes to GitLab, authenticate here and set release.provider
+to "gitlab" in the config, then use 'noidea github release notes' as usual.`,
+}
+
+// gitlabAuthCmd represents the gitlab auth command
+var gitlabAuthCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Authenticate with GitLab",
+	Long: `Authenticate with GitLab using a Personal Access Token.
+This command will securely store your GitLab token for future use.
+
+To create a new token, visit: <gitlab_url>/-/user_settings/personal_access_tokens
+Required scope: api`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runGitLabAuth()
+	},
+}
+
+// gitlabStatusCmd represents the gitlab status command
+var gitlabStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check GitLab authentication status",
+	Long:  `Check if you're authenticated with GitLab and display account information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runGitLabStatus()
+	},
+}
+
+// gitlabLogoutCmd represents the gitlab logout command
+var gitlabLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove stored GitLab credentials",
+	Long:  `Remove any stored GitLab Personal Access Tokens from your system.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runGitLabLogout()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gitlabCmd)
+	gitlabCmd.AddCommand(gitlabAuthCmd)
+	gitlabCmd.AddCommand(gitlabStatusCmd)
+	gitlabCmd.AddCommand(gitlabLogoutCmd)
+}
+
+// runGitLabAuth handles the GitLab authentication flow
+func runGitLabAuth() {
+	instanceURL := config.LoadConfig().Release.GitLabURL
+
+	fmt.Println("GitLab Authentication")
+	fmt.Println("---------------------")
+	fmt.Printf("This will store a GitLab Personal Access Token for %s.\n", instanceURL)
+	fmt.Printf("To create a new token, visit: %s/-/user_settings/personal_access_tokens\n", strings.TrimRight(instanceURL, "/"))
+	fmt.Println("Required scope: api")
+	fmt.Println()
+
+	// Ask if the user wants to proceed
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Would you like to proceed? (y/n): ")
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "y" && response != "yes" {
+		fmt.Println("Authentication cancelled.")
+		return
+	}
+
+	// Prompt for token
+	fmt.Print("Enter your GitLab Personal Access Token (input will be hidden): ")
+	tokenBytes, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println() // Add newline after hidden input
+	if err != nil {
+		fmt.Printf("Error reading token: %s\n", err)
+		return
+	}
+
+	token := strings.TrimSpace(string(tokenBytes))
+	if token == "" {
+		fmt.Println("Token cannot be empty. Authentication cancelled.")
+		return
+	}
+
+	// Validate the token
+	fmt.Println("Validating token...")
+	valid, userData, err := secure.ValidateGitLabToken(instanceURL, token)
+	if err != nil || !valid {
+		if err != nil {
+			fmt.Printf("Error validating token: %s\n", err)
+		} else {
+			fmt.Println("Invalid token. Please check your token and try again.")
+		}
+		return
+	}
+
+	// Store the token
+	if err := secure.StoreGitLabToken(token); err != nil {
+		fmt.Printf("Error storing token: %s\n", err)
+		return
+	}
+
+	username := "Unknown"
+	if userData != nil {
+		if login, ok := userData["username"].(string); ok {
+			username = login
+		}
+	}
+
+	fmt.Printf("Successfully authenticated as: %s\n", username)
+	fmt.Println("Your GitLab token has been securely stored.")
+}
+
+// runGitLabStatus checks and displays GitLab authentication status
+func runGitLabStatus() {
+	token, err := secure.GetGitLabToken()
+	if err != nil {
+		fmt.Println("Not authenticated with GitLab.")
+		fmt.Println("Run 'noidea gitlab auth' to authenticate.")
+		return
+	}
+
+	// Token exists, validate it
+	fmt.Println("Checking GitLab authentication status...")
+	valid, userData, err := secure.ValidateGitLabToken(config.LoadConfig().Release.GitLabURL, token)
+	if err != nil || !valid {
+		fmt.Println("Your GitLab token is invalid or expired.")
+		fmt.Println("Run 'noidea gitlab auth' to re-authenticate.")
+		return
+	}
+
+	fmt.Println("GitLab Authentication: ✅ Active")
+	if userData != nil {
+		if login, ok := userData["username"].(string); ok {
+			fmt.Printf("Username: %s\n", login)
+		}
+		if name, ok := userData["name"].(string); ok && name != "" {
+			fmt.Printf("Name: %s\n", name)
+		}
+	}
+}
+
+// runGitLabLogout removes stored GitLab credentials
+func runGitLabLogout() {
+	if _, err := secure.GetGitLabToken(); err != nil {
+		fmt.Println("No GitLab credentials found.")
+		return
+	}
+
+	// Confirm with the user
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Are you sure you want to remove your GitLab credentials? (y/n): ")
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "y" && response != "yes" {
+		fmt.Println("Operation cancelled.")
+		return
+	}
+
+	if err := secure.DeleteGitLabToken(); err != nil {
+		fmt.Printf("Error removing credentials: %s\n", err)
+		return
+	}
+
+	fmt.Println("GitLab credentials successfully removed.")
+}
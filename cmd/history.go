@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/history"
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyClearCacheCmd)
+	historyCmd.AddCommand(historyCacheInfoCmd)
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Manage the commit history cache",
+	Long: `Manage the cache noidea keeps in ~/.noidea/cache to read commit history
+quickly: commit details, computed summary statistics and AI insights.
+
+The cache is rebuilt on demand, so clearing it is always safe. Commits that
+no longer exist, for example after a rebase, are dropped automatically; clear
+the cache if summaries still look wrong.`,
+}
+
+var historyClearCacheCmd = &cobra.Command{
+	Use:   "clear-cache",
+	Short: "Delete the cached commits, summary statistics and AI insights",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		collector, err := history.NewHistoryCollector()
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		if err := collector.ClearCache(); err != nil {
+			fmt.Println(color.RedString("❌ Error:"), "Failed to clear the history cache:", err)
+			os.Exit(1)
+		}
+		fmt.Println(color.GreenString("✅ History cache cleared"))
+	},
+}
+
+var historyCacheInfoCmd = &cobra.Command{
+	Use:   "cache-info",
+	Short: "Show where the history cache is and how much it holds",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		collector, err := history.NewHistoryCollector()
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		fmt.Println(color.CyanString("🗂  History cache"))
+		fmt.Println(color.HiBlackString(divider))
+		for _, file := range collector.CacheInfo() {
+			fmt.Printf("%-20s %s\n", file.Name+":", file.Path)
+
+			switch {
+			case !file.Exists:
+				fmt.Printf("%-20s %s\n", "", color.HiBlackString("not created yet"))
+			case file.Corrupt:
+				fmt.Printf("%-20s %s, %s\n", "", formatBytes(file.Size),
+					color.YellowString("unreadable (rebuilt on next use, or run 'noidea history clear-cache')"))
+			default:
+				fmt.Printf("%-20s %s, %d entries\n", "", formatBytes(file.Size), file.Entries)
+			}
+		}
+	},
+}
+
+// formatBytes formats a file size such as "12.3 KB"
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size) / unit
+	for _, suffix := range []string{"KB", "MB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f GB", value)
+}
@@ -19,6 +19,7 @@ var (
 	enableInteractive bool
 	enableFullDiff    bool
 	forceFlag         bool
+	initDryRunFlag    bool
 )
 
 func init() {
@@ -26,6 +27,7 @@ func init() {
 	initCmd.Flags().BoolVarP(&enableInteractive, "interactive", "i", false, "Enable interactive mode for direct command usage")
 	initCmd.Flags().BoolVarP(&enableFullDiff, "full-diff", "f", false, "Include full diffs in commit message analysis")
 	initCmd.Flags().BoolVarP(&forceFlag, "force", "F", false, "Force installation even if checks fail")
+	initCmd.Flags().BoolVar(&initDryRunFlag, "dry-run", false, "Show which hooks would be installed without writing any files")
 
 	rootCmd.AddCommand(initCmd)
 }
@@ -44,15 +46,24 @@ var initCmd = &cobra.Command{
 			fmt.Println(color.YellowString("Warning:"), "Continuing anyway due to --force flag")
 		}
 
-		// Check if we're in a Git repository
-		gitDir, err := git.FindGitDir()
+		// Check if we're in a Git repository; linked worktrees share the main
+		// repository's hooks, so resolve the directory Git actually uses
+		hooksDir, err := git.FindHooksDir()
 		if err != nil {
 			fmt.Println(color.RedString("Error:"), "Not in a Git repository.")
 			os.Exit(1)
 		}
+		if git.IsLinkedWorktree() {
+			fmt.Println(color.CyanString("Note:"), "Linked worktree detected, hooks will apply to every worktree of this repository")
+		}
+
+		// In dry-run mode, only describe what would happen
+		if initDryRunFlag {
+			previewHookInstallation(hooksDir)
+			return
+		}
 
 		// Create hooks directory if it doesn't exist
-		hooksDir := filepath.Join(gitDir, "hooks")
 		if err := os.MkdirAll(hooksDir, 0755); err != nil {
 			fmt.Println(color.RedString("Error:"), "Failed to create hooks directory:", err)
 			os.Exit(1)
@@ -61,16 +72,14 @@ var initCmd = &cobra.Command{
 		// Check if hooks already exist and warn/backup if needed
 		if !forceFlag {
 			for _, hook := range []string{"post-commit", "prepare-commit-msg"} {
-				hookPath := filepath.Join(hooksDir, hook)
-				if _, err := os.Stat(hookPath); err == nil {
-					// Hook exists, create backup
-					backupPath := hookPath + ".bak"
+				backupPath, err := git.BackupHook(filepath.Join(hooksDir, hook))
+				if err != nil {
+					fmt.Println(color.RedString("Error:"), "Failed to backup existing hook:", err)
+					fmt.Println("Use --force to override without backup")
+					os.Exit(1)
+				}
+				if backupPath != "" {
 					fmt.Println(color.YellowString("Warning:"), "Existing", hook, "hook found, creating backup at", backupPath)
-					if err := os.Rename(hookPath, backupPath); err != nil {
-						fmt.Println(color.RedString("Error:"), "Failed to backup existing hook:", err)
-						fmt.Println("Use --force to override without backup")
-						os.Exit(1)
-					}
 				}
 			}
 		}
@@ -135,7 +144,7 @@ var initCmd = &cobra.Command{
 
 		// Load config and check if API key is set
 		cfg := config.LoadConfig()
-		if cfg.LLM.Enabled && cfg.LLM.APIKey == "" {
+		if cfg.LLM.Enabled && cfg.LLM.APIKey == "" && cfg.LLM.Provider != config.ProviderMock {
 			fmt.Println()
 			fmt.Println(color.YellowString("⚠️  Warning:"), "LLM is enabled but no API key is configured.")
 			fmt.Println("     For better commit message suggestions, configure your API key:")
@@ -151,6 +160,33 @@ var initCmd = &cobra.Command{
 	},
 }
 
+// previewHookInstallation prints the hooks init would install without touching the filesystem
+func previewHookInstallation(hooksDir string) {
+	fmt.Println(color.CyanString("Dry run:"), "no files will be changed")
+	fmt.Println()
+
+	postCommit, err := git.PostCommitHookScript()
+	if err != nil {
+		fmt.Println(color.RedString("Error:"), err)
+		os.Exit(1)
+	}
+	git.PreviewHookInstall(filepath.Join(hooksDir, "post-commit"), postCommit, !forceFlag)
+
+	prepareCommitMsg, err := git.PrepareCommitMsgHookScript()
+	if err != nil {
+		fmt.Println(color.RedString("Error:"), err)
+		os.Exit(1)
+	}
+	git.PreviewHookInstall(filepath.Join(hooksDir, "prepare-commit-msg"), prepareCommitMsg, !forceFlag)
+
+	fmt.Println("Would set git config:")
+	fmt.Printf("  noidea.suggest = %t\n", enableSuggestions)
+	if enableSuggestions {
+		fmt.Printf("  noidea.suggest.interactive = %t\n", enableInteractive)
+		fmt.Printf("  noidea.suggest.full-diff = %t\n", enableFullDiff)
+	}
+}
+
 // checkGitVersion verifies Git is installed and meets minimum requirements
 func checkGitVersion() error {
 	// Check if git is available
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+)
+
+var (
+	lintFileFlag   string
+	lintStrictFlag bool
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVarP(&lintFileFlag, "file", "F", "", "Read the message from this file, e.g. the commit-msg hook's argument (\"-\" for stdin)")
+	lintCmd.Flags().BoolVar(&lintStrictFlag, "strict", false, "Exit non-zero on warnings as well as errors")
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check a commit message against Conventional Commits",
+	Long: `Check a commit message against the Conventional Commits rules noidea uses
+when it writes messages: a known type prefix, a concise subject, a blank line
+before the body and "- " bullet points.
+
+The message is read from --file, from stdin when it is piped, or from the last
+commit. Types follow allowed_types and type_aliases from the config. Comment
+lines (the comment character followed by a space) and anything below git's
+scissors line are ignored, and merge, revert and fixup commits are skipped.
+
+Exits with status 1 if there are errors (or warnings, with --strict), so it
+can be used as a commit-msg hook.`,
+	Example: `  noidea lint                      # Lint the last commit
+  git log -1 --format=%B | noidea lint
+  noidea lint --file "$1"          # In .git/hooks/commit-msg`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.LoadConfig()
+
+		message, source, err := readLintMessage(lintFileFlag)
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		// A configured subject prefix comes before the type
+		commentChar := suggestionCommentChar(cfg)
+		if prefix, err := suggestionSubjectPrefix(cfg); err == nil {
+			message = conventional.TrimSubjectPrefix(conventional.CleanMessage(message, commentChar), prefix)
+		}
+
+		issues := conventional.Lint(message, config.CommitTypeRules(cfg), commentChar)
+		if len(issues) == 0 {
+			fmt.Println(color.GreenString("✅ %s follows Conventional Commits", source))
+			return
+		}
+
+		warnings := 0
+		for _, issue := range issues {
+			severity := color.RedString(issue.Severity)
+			if issue.Severity == conventional.SeverityWarning {
+				severity = color.YellowString(issue.Severity)
+				warnings++
+			}
+			fmt.Printf("line %d: %s: %s\n", issue.Line, severity, issue.Message)
+		}
+		fmt.Printf("%s: %d error(s), %d warning(s)\n", source, len(issues)-warnings, warnings)
+
+		if conventional.HasErrors(issues) || lintStrictFlag {
+			os.Exit(1)
+		}
+	},
+}
+
+// readLintMessage returns the message to lint and a description of where it
+// came from: the file, piped stdin, or the last commit
+func readLintMessage(file string) (string, string, error) {
+	if file == "-" || (file == "" && stdinIsPiped()) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return string(data), "Message", nil
+	}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read commit message: %w", err)
+		}
+		return string(data), "Message", nil
+	}
+
+	output, err := exec.Command("git", "log", "-1", "--pretty=%B").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read the last commit: %w", err)
+	}
+	return strings.TrimSpace(string(output)), "Last commit", nil
+}
+
+// stdinIsPiped reports whether stdin is a pipe or file rather than a terminal
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0 && (info.Mode()&os.ModeNamedPipe != 0 || info.Mode().IsRegular())
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -11,9 +13,13 @@ import (
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
 	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/git"
 	"github.com/AccursedGalaxy/noidea/internal/history"
 	"github.com/AccursedGalaxy/noidea/internal/moai"
 	"github.com/AccursedGalaxy/noidea/internal/personality"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+	"github.com/AccursedGalaxy/noidea/internal/suggestlog"
+	"github.com/AccursedGalaxy/noidea/internal/telemetry"
 )
 
 var (
@@ -29,6 +35,8 @@ var (
 	includeHistory bool
 	// Flag to enable debug mode
 	debugMode bool
+	// Flag to print the face and feedback as JSON
+	moaiJSONFlag bool
 )
 
 func init() {
@@ -37,16 +45,22 @@ func init() {
 	// Add flags
 	moaiCmd.Flags().BoolVarP(&useAI, "ai", "a", false, "Use AI to generate feedback")
 	moaiCmd.Flags().BoolVarP(&includeDiff, "diff", "d", false, "Include the diff in AI context")
-	moaiCmd.Flags().StringVarP(&personalityFlag, "personality", "p", "", "Personality to use for feedback (default: from config)")
+	moaiCmd.Flags().StringVarP(&personalityFlag, "personality", "p", "", "Personality to use for feedback, or \"random\" (default: from config)")
 	moaiCmd.Flags().BoolVarP(&listPersonalities, "list-personalities", "l", false, "List available personalities")
 	moaiCmd.Flags().BoolVarP(&includeHistory, "history", "H", false, "Include recent commit history context")
 	moaiCmd.Flags().BoolVarP(&debugMode, "debug", "D", false, "Enable debug mode to show detailed API information")
+	moaiCmd.Flags().BoolVar(&moaiJSONFlag, "json", false, "Print the face, feedback, personality and commit as JSON")
 }
 
 var moaiCmd = &cobra.Command{
-	Use:   "moai [commit message]",
+	Use:   "moai [commit message | commit hash]",
 	Short: "Display a Moai with feedback on your commit",
-	Long:  `Show a Moai face and random feedback about your most recent commit.`,
+	Long: `Show a Moai face and random feedback about your most recent commit.
+
+Pass a commit hash to get feedback on a specific historical commit instead.
+
+Use --json to get the face, feedback, personality and commit as a JSON object
+for editor integrations.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Load configuration
 		cfg := config.LoadConfig()
@@ -58,8 +72,20 @@ var moaiCmd = &cobra.Command{
 		}
 
 		var commitMsg string
+		var commitHash string
 		var commitDiff string
 
+		// A single hash-like argument is a commit to analyze
+		commit, err := commitFromArgs(args)
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+		if commit != nil {
+			showMoaiFeedback(cfg, commit.Message, commit.Hash, commit.DiffSummary)
+			return
+		}
+
 		// If commit message was provided as args, use it
 		if len(args) > 0 {
 			commitMsg = strings.Join(args, " ")
@@ -71,6 +97,21 @@ var moaiCmd = &cobra.Command{
 				commitMsg = "unknown commit"
 			} else {
 				commitMsg = strings.TrimSpace(string(output))
+				if head, err := exec.Command("git", "rev-parse", "HEAD").Output(); err == nil {
+					commitHash = strings.TrimSpace(string(head))
+				}
+
+				// Resolve a pending suggestion with what was actually committed
+				if cfg.Moai.LogSuggestions {
+					if err := suggestlog.RecordCommit(commitMsg); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: Failed to update suggestions log: %v\n", err)
+					}
+				}
+				if cfg.Moai.Telemetry {
+					if err := telemetry.RecordCommit(commitMsg); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: Failed to update telemetry: %v\n", err)
+					}
+				}
 			}
 		}
 
@@ -83,87 +124,149 @@ var moaiCmd = &cobra.Command{
 			}
 		}
 
-		// Get the Moai face
-		face := moai.GetRandomFace()
+		showMoaiFeedback(cfg, commitMsg, commitHash, commitDiff)
+	},
+}
 
-		// Override AI flag from config if set
-		if !useAI && cfg.LLM.Enabled {
-			useAI = true
+// commitFromArgs returns the commit named by a single hash-like argument, or
+// nil when args are a commit message. A hash that doesn't resolve is an error
+// rather than feedback on a message that happens to look like one.
+func commitFromArgs(args []string) (*history.CommitInfo, error) {
+	if len(args) != 1 || !commitHashRegex.MatchString(args[0]) {
+		return nil, nil
+	}
+	commit, err := history.GetCommitByHash(args[0], includeDiff)
+	if err != nil {
+		return nil, fmt.Errorf("not a commit: %s", args[0])
+	}
+	return &commit, nil
+}
+
+// moaiReaction is a Moai face with the feedback on a commit. With --json it
+// is printed as is, so field names are part of the output format.
+type moaiReaction struct {
+	Face        string `json:"face"`
+	Feedback    string `json:"feedback"`
+	Personality string `json:"personality"`
+	Commit      string `json:"commit"`
+	Hash        string `json:"hash,omitempty"`
+	Source      string `json:"source"`          // "ai" or "local"
+	Error       string `json:"error,omitempty"` // AI error that caused a local fallback
+}
+
+// showMoaiFeedback prints the Moai face with local or AI feedback for a commit
+func showMoaiFeedback(cfg config.Config, commitMsg, commitHash, commitDiff string) {
+	reaction := moaiFeedback(cfg, commitMsg, commitHash, commitDiff)
+
+	if moaiJSONFlag {
+		data, err := json.MarshalIndent(reaction, "", "  ")
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), "Failed to encode feedback:", err)
+			os.Exit(1)
 		}
+		fmt.Println(string(data))
+		return
+	}
 
-		// Get personality name, using flag if provided, otherwise from config
-		personalityName := cfg.Moai.Personality
-		if personalityFlag != "" {
-			personalityName = personalityFlag
+	// Display the commit message
+	fmt.Printf("%s  %s\n", reaction.Face, reaction.Commit)
+
+	if reaction.Source == "ai" {
+		fmt.Println(color.CyanString(reaction.Feedback))
+		return
+	}
+
+	fmt.Println(color.YellowString(reaction.Feedback))
+	if reaction.Error == "" {
+		return
+	}
+	fmt.Println(color.RedString("AI Error:"), reaction.Error)
+
+	// If debug mode is enabled, show more details
+	if debugMode {
+		fmt.Println(color.CyanString("\nDebug information:"))
+		fmt.Printf("Provider: %s\n", cfg.LLM.Provider)
+		fmt.Printf("Model: %s\n", cfg.LLM.Model)
+		apiKeyLength := 0
+		if cfg.LLM.APIKey != "" {
+			apiKeyLength = len(cfg.LLM.APIKey)
+			fmt.Printf("API key length: %d\n", apiKeyLength)
+
+			// Show a masked form of the API key for debugging
+			fmt.Printf("API key: %s\n", secure.Mask(cfg.LLM.APIKey))
+		} else {
+			fmt.Printf("API key length: 0 (no API key found)\n")
 		}
+	}
+}
 
-		// Display the commit message
-		fmt.Printf("%s  %s\n", face, commitMsg)
+// moaiFeedback picks a Moai face and generates local or AI feedback for a commit
+func moaiFeedback(cfg config.Config, commitMsg, commitHash, commitDiff string) moaiReaction {
+	// Override AI flag from config if set
+	if !useAI && cfg.LLM.Enabled {
+		useAI = true
+	}
 
-		// Generate feedback based on AI flag
-		if useAI {
-			// Create commit context
-			commitContext := feedback.CommitContext{
-				Message:   commitMsg,
-				Timestamp: time.Now(),
-				Diff:      commitDiff,
-			}
+	// Get personality name, using flag if provided, otherwise from the
+	// config's schedule or default
+	personalityName := config.ScheduledPersonality(cfg, time.Now(), git.RepoName())
+	if personalityFlag != "" {
+		personalityName = personalityFlag
+	}
+	personalityName = resolvePersonality(cfg, personalityName)
+	if personalityFlag != "" {
+		requireAllowedPersonality(cfg, personalityName)
+	}
 
-			// Add commit history context if requested
-			if includeHistory {
-				// Get commit history
-				recentCommits, recentStats, err := getCommitHistoryContext()
-				if err == nil && len(recentCommits) > 0 {
-					commitContext.CommitHistory = recentCommits
-					commitContext.CommitStats = recentStats
-				}
-			}
+	reaction := moaiReaction{
+		Personality: personalityName,
+		Commit:      commitMsg,
+		Hash:        commitHash,
+		Source:      "local",
+	}
 
-			// Create feedback engine based on configuration
-			engine := feedback.NewFeedbackEngine(
-				cfg.LLM.Provider,
-				cfg.LLM.Model,
-				cfg.LLM.APIKey,
-				personalityName,
-				cfg.Moai.PersonalityFile,
-			)
-
-			// Generate AI feedback
-			aiResponse, err := engine.GenerateFeedback(commitContext)
-			if err != nil {
-				// On error, fallback to local feedback
-				fmt.Println(color.YellowString(moai.GetRandomFeedback(commitMsg)))
-				fmt.Println(color.RedString("AI Error:"), err)
-
-				// If debug mode is enabled, show more details
-				if debugMode {
-					fmt.Println(color.CyanString("\nDebug information:"))
-					fmt.Printf("Provider: %s\n", cfg.LLM.Provider)
-					fmt.Printf("Model: %s\n", cfg.LLM.Model)
-					apiKeyLength := 0
-					if cfg.LLM.APIKey != "" {
-						apiKeyLength = len(cfg.LLM.APIKey)
-						fmt.Printf("API key length: %d\n", apiKeyLength)
-
-						// Show a short prefix of the API key for debugging
-						prefixLen := 10
-						if apiKeyLength < prefixLen {
-							prefixLen = apiKeyLength
-						}
-						fmt.Printf("API key prefix: %s\n", cfg.LLM.APIKey[:prefixLen])
-					} else {
-						fmt.Printf("API key length: 0 (no API key found)\n")
-					}
-				}
-			} else {
-				// Display AI-generated feedback
-				fmt.Println(color.CyanString(aiResponse))
-			}
-		} else {
-			// Use local feedback
-			fmt.Println(color.YellowString(moai.GetRandomFeedback(commitMsg)))
+	// The face is picked last, so the mood faces mode can match the feedback
+	if !useAI {
+		reaction.Feedback = moai.GetRandomFeedback(commitMsg)
+		reaction.Face = moai.Face(cfg.Moai.FacesMode, reaction.Feedback)
+		return reaction
+	}
+
+	// Create commit context
+	commitContext := feedback.CommitContext{
+		Message:   commitMsg,
+		Timestamp: time.Now(),
+		Diff:      commitDiff,
+	}
+
+	// Add commit history context if requested
+	if includeHistory {
+		recentCommits, recentStats, err := getCommitHistoryContext()
+		if err == nil && len(recentCommits) > 0 {
+			commitContext.CommitHistory = recentCommits
+			commitContext.CommitStats = recentStats
 		}
-	},
+	}
+
+	// Create feedback engine based on configuration
+	engine := newFeedbackEngine(cfg, func(provider, model, apiKey string) feedback.FeedbackEngine {
+		return feedback.NewFeedbackEngine(provider, model, apiKey, personalityName, cfg.Moai.PersonalityFile)
+	})
+
+	aiResponse, err := engine.GenerateFeedback(commitContext)
+	if err != nil {
+		// On error, fallback to local feedback
+		reaction.Feedback = moai.GetRandomFeedback(commitMsg)
+		reaction.Face = moai.Face(cfg.Moai.FacesMode, reaction.Feedback)
+		reaction.Error = err.Error()
+		return reaction
+	}
+
+	reportAnsweringProvider(engine, cfg)
+	reaction.Feedback = aiResponse
+	reaction.Face = moai.Face(cfg.Moai.FacesMode, aiResponse)
+	reaction.Source = "ai"
+	return reaction
 }
 
 // getCommitHistoryContext retrieves recent commit history for context
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+)
+
+func TestMoaiFeedbackLocal(t *testing.T) {
+	useAI, personalityFlag = false, ""
+	cfg := config.DefaultConfig()
+	cfg.LLM.Enabled = false
+
+	reaction := moaiFeedback(cfg, "fix: typo", "abc123", "")
+	if reaction.Source != "local" || reaction.Face == "" || reaction.Feedback == "" {
+		t.Errorf("unexpected local reaction: %+v", reaction)
+	}
+	if reaction.Commit != "fix: typo" || reaction.Hash != "abc123" {
+		t.Errorf("reaction lost the commit: %+v", reaction)
+	}
+
+	data, err := json.Marshal(reaction)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for _, key := range []string{"face", "feedback", "personality", "commit", "hash", "source"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("JSON missing %q: %s", key, data)
+		}
+	}
+	if _, ok := fields["error"]; ok {
+		t.Errorf("error should be omitted without an AI failure: %s", data)
+	}
+}
+
+func TestCommitFromArgs(t *testing.T) {
+	repo := chdirTestRepo(t)
+	commitFile(t, repo, "a.txt", "a", "feat: add a")
+	head := strings.TrimSpace(runGit(t, repo, "rev-parse", "--short", "HEAD"))
+
+	if commit, err := commitFromArgs([]string{head}); err != nil || commit == nil || commit.Message != "feat: add a" {
+		t.Errorf("commitFromArgs(%q) = %+v, %v", head, commit, err)
+	}
+	if commit, err := commitFromArgs([]string{"fix:", "typo"}); err != nil || commit != nil {
+		t.Errorf("a commit message was treated as a hash: %+v, %v", commit, err)
+	}
+	if _, err := commitFromArgs([]string{"deadbeef"}); err == nil || err.Error() != "not a commit: deadbeef" {
+		t.Errorf("an unknown hash should be an error, got %v", err)
+	}
+}
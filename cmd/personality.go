@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/personality"
+)
+
+var (
+	// Personality import flags
+	overwritePersonalities bool
+)
+
+func init() {
+	rootCmd.AddCommand(personalityCmd)
+	personalityCmd.AddCommand(personalityImportCmd)
+
+	personalityImportCmd.Flags().BoolVar(&overwritePersonalities, "overwrite", false, "Replace existing personalities that have the same name")
+}
+
+// personalityCmd represents the personality command
+var personalityCmd = &cobra.Command{
+	Use:   "personality",
+	Short: "Manage Moai personalities",
+	Long:  `Manage the personalities used for commit feedback, summaries and suggestions.`,
+}
+
+// personalityImportCmd imports personalities from a directory
+var personalityImportCmd = &cobra.Command{
+	Use:   "import <dir>",
+	Short: "Import all personality files from a directory",
+	Long: `Load every .toml and .json personality file in a directory, validate each
+personality and merge them into your personality file.
+
+Personalities whose name already exists are reported as conflicts and skipped
+unless --overwrite is given. New personalities are added after the contents of
+a TOML personality file, which keeps its comments. Replacing existing ones
+rewrites the whole file.
+
+Examples:
+  noidea personality import ./team-personalities
+  noidea personality import ./team-personalities --overwrite`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.LoadConfig()
+
+		result, err := personality.ImportPersonalities(args[0], cfg.Moai.PersonalityFile, overwritePersonalities)
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), "Failed to import personalities:", err)
+			os.Exit(1)
+		}
+
+		for _, name := range result.Imported {
+			fmt.Println(color.GreenString("✓"), "Imported", color.YellowString(name))
+		}
+
+		for _, name := range result.Conflicts {
+			fmt.Println(color.YellowString("⚠️ Conflict:"), name, "already exists (use --overwrite to replace it)")
+		}
+
+		invalidNames := make([]string, 0, len(result.Invalid))
+		for name := range result.Invalid {
+			invalidNames = append(invalidNames, name)
+		}
+		sort.Strings(invalidNames)
+		for _, name := range invalidNames {
+			fmt.Println(color.RedString("✗ Invalid:"), name+":", result.Invalid[name])
+		}
+
+		fmt.Println()
+		if len(result.Imported) == 0 {
+			fmt.Println(color.YellowString("No personalities were imported."))
+			return
+		}
+
+		fmt.Printf("%s %d personalities into %s\n",
+			color.GreenString("Imported"),
+			len(result.Imported),
+			cfg.Moai.PersonalityFile)
+	},
+}
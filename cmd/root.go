@@ -9,11 +9,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/fatih/color"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/interrupt"
 	"github.com/AccursedGalaxy/noidea/internal/secure"
+	"github.com/AccursedGalaxy/noidea/internal/ui"
 )
 
 // Version information
@@ -24,7 +28,11 @@ var (
 )
 
 // Flag variables
-var versionFlag bool
+var (
+	versionFlag bool
+	verboseFlag bool
+	noColorFlag bool
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -59,9 +67,24 @@ func init() {
 
 	// Add version flag
 	rootCmd.Flags().BoolVarP(&versionFlag, "version", "v", false, "Print version information and exit")
+	rootCmd.PersistentFlags().BoolVarP(&ui.AssumeYes, "yes", "y", false, "Automatically accept approval prompts")
+	rootCmd.PersistentFlags().BoolVar(&verboseFlag, "verbose", false, "Show additional details, such as which AI provider answered")
+	rootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "Print without colors or other terminal styling (or set NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&config.ConfigFile, "config", "", "Config file to use instead of ~/.noidea/config.json, e.g. a project's own settings")
 
 	// Check API key validity during startup, but only for certain commands
 	cobra.OnInitialize(func() {
+		if noColorFlag {
+			disableColors()
+		}
+
+		// Commands may change directory, so keep the config file's location
+		if config.ConfigFile != "" {
+			if abs, err := filepath.Abs(config.ConfigFile); err == nil {
+				config.ConfigFile = abs
+			}
+		}
+
 		// Only validate API key when using commands that need it
 		if len(os.Args) > 1 {
 			cmd := os.Args[1]
@@ -151,14 +174,31 @@ func loadEnvFiles() {
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// Cancel in-flight work and exit with 130 on Ctrl-C / SIGTERM
+	interrupt.Notify()
+
+	// https://no-color.org: any non-empty value turns colors off
+	if os.Getenv("NO_COLOR") != "" {
+		disableColors()
+	}
+
 	// This is a simple test comment to check commit message generation
 	err := rootCmd.Execute()
+	if interrupt.Interrupted() {
+		os.Exit(interrupt.ExitCode)
+	}
 	if err != nil {
 		fmt.Println(color.RedString("Error:"), err)
 		os.Exit(1)
 	}
 }
 
+// disableColors turns off ANSI colors and styling in all output
+func disableColors() {
+	color.NoColor = true
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
 // printVersion prints detailed version information
 func printVersion() {
 	fmt.Printf("noidea version %s\n", Version)
@@ -172,7 +212,7 @@ func validateApiKeyOnStartup() {
 	cfg := config.LoadConfig()
 
 	// Only check if LLM is enabled and API key is set
-	if cfg.LLM.Enabled && cfg.LLM.APIKey != "" {
+	if cfg.LLM.Enabled && cfg.LLM.APIKey != "" && cfg.LLM.Provider != config.ProviderMock {
 		// Try to validate the API key
 		isValid, err := secure.ValidateAPIKey(cfg.LLM.Provider, cfg.LLM.APIKey)
 		if err != nil {
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/git"
+	"github.com/AccursedGalaxy/noidea/internal/moai"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+// maxServeBodyBytes caps request bodies so a runaway client can't exhaust memory
+const maxServeBodyBytes = 10 << 20
+
+var (
+	servePortFlag  int
+	serveTokenFlag string
+)
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().IntVar(&servePortFlag, "port", 7331, "Port to listen on (always bound to 127.0.0.1)")
+	serveCmd.Flags().StringVar(&serveTokenFlag, "token", "", "Token clients must send (default: $NOIDEA_SERVE_TOKEN, or a random one)")
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve suggestions and feedback over a local HTTP API",
+	Long: `Start a small HTTP API on 127.0.0.1 for editor integrations.
+
+The configuration, API key and feedback engine are loaded once and reused, so
+plugins don't pay for a new process per request.
+
+Endpoints:
+  POST /suggest   Body is a git or unified diff, returns {"suggestion": "..."}
+  POST /feedback  Body is a commit message, returns the moai --json reaction
+
+Every request needs an "Authorization: Bearer <token>" header. The token comes
+from --token or NOIDEA_SERVE_TOKEN; without either a random token is printed
+at startup.`,
+	Example: `  noidea serve --port 7331
+  curl -s -H "Authorization: Bearer $TOKEN" --data-binary @<(git diff --staged) http://127.0.0.1:7331/suggest`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.LoadConfig()
+
+		token := serveTokenFlag
+		if token == "" {
+			token = os.Getenv("NOIDEA_SERVE_TOKEN")
+		}
+		generated := token == ""
+		if generated {
+			var err error
+			if token, err = randomServeToken(); err != nil {
+				fmt.Println(color.RedString("❌ Error:"), "Failed to generate a token:", err)
+				os.Exit(1)
+			}
+		}
+
+		listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(servePortFlag)))
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(os.Stderr, "Serving on http://%s\n", listener.Addr())
+		if generated {
+			fmt.Fprintf(os.Stderr, "Token: %s\n", token)
+		}
+
+		server := &http.Server{
+			Handler:           newServeHandler(cfg, token),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		if err := server.Serve(listener); err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+// serveHandler answers editor requests with a single, shared feedback engine
+type serveHandler struct {
+	cfg         config.Config
+	token       string
+	personality string
+	footers     []conventional.Footer
+
+	// engine is safe for concurrent use and shared by all requests
+	engine feedback.FeedbackEngine
+}
+
+// newServeHandler creates the HTTP handler for noidea serve
+func newServeHandler(cfg config.Config, token string) http.Handler {
+	personalityName := config.ScheduledPersonality(cfg, time.Now(), git.RepoName())
+	footers, err := suggestionFooters(cfg, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Ignoring configured footers: %v\n", err)
+	}
+
+	h := &serveHandler{
+		cfg:         cfg,
+		token:       token,
+		personality: personalityName,
+		footers:     footers,
+		engine: newFeedbackEngine(cfg, func(provider, model, apiKey string) feedback.FeedbackEngine {
+			return feedback.NewFeedbackEngine(provider, model, apiKey, personalityName, cfg.Moai.PersonalityFile)
+		}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/suggest", h.authorized(h.suggest))
+	mux.HandleFunc("/feedback", h.authorized(h.feedback))
+	return mux
+}
+
+// authorized wraps a POST endpoint with the method and bearer token checks
+func (h *serveHandler) authorized(next func(http.ResponseWriter, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeServeError(w, http.StatusMethodNotAllowed, "use POST")
+			return
+		}
+
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(h.token)) != 1 {
+			writeServeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxServeBodyBytes))
+		if err != nil {
+			writeServeError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		if strings.TrimSpace(string(body)) == "" {
+			writeServeError(w, http.StatusBadRequest, "request body is empty")
+			return
+		}
+
+		next(w, string(body))
+	}
+}
+
+// suggest returns a commit message suggestion for the diff in the body
+func (h *serveHandler) suggest(w http.ResponseWriter, diff string) {
+	// Editors may send patches from other tools as well as git diffs
+	diff = feedback.NormalizeDiff(diff)
+
+	// Secrets are refused here as in suggest, since there is no --force
+	if h.cfg.Moai.BlockSecrets {
+		if findings := secure.ScanDiffForSecrets(diff); len(findings) > 0 {
+			writeServeSecrets(w, findings)
+			return
+		}
+	}
+
+	ctx := suggestionContext(h.cfg, summarizeDiff(diff, h.cfg.Moai.MaxLinesPerFile))
+
+	// The branch can change while the server runs
+	prefix, err := suggestionSubjectPrefix(h.cfg)
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ctx.SubjectPrefix = prefix
+
+	generated, err := h.engine.GenerateCommitSuggestion(ctx)
+	if err != nil {
+		writeServeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeServeJSON(w, map[string]string{
+		"suggestion": conventional.AppendFooters(conventional.PrefixSubject(generated, prefix), h.footers),
+	})
+}
+
+// feedback returns a Moai reaction to the commit message in the body
+func (h *serveHandler) feedback(w http.ResponseWriter, commitMsg string) {
+	commitMsg = strings.TrimSpace(commitMsg)
+	reaction := moaiReaction{
+		Personality: h.personality,
+		Commit:      commitMsg,
+		Source:      "local",
+	}
+
+	if !h.cfg.LLM.Enabled {
+		reaction.Feedback = moai.GetRandomFeedback(commitMsg)
+		reaction.Face = moai.Face(h.cfg.Moai.FacesMode, reaction.Feedback)
+		writeServeJSON(w, reaction)
+		return
+	}
+
+	aiResponse, err := h.engine.GenerateFeedback(feedback.CommitContext{Message: commitMsg, Timestamp: time.Now()})
+	if err != nil {
+		reaction.Feedback = moai.GetRandomFeedback(commitMsg)
+		reaction.Error = err.Error()
+	} else {
+		reaction.Feedback = aiResponse
+		reaction.Source = "ai"
+	}
+	reaction.Face = moai.Face(h.cfg.Moai.FacesMode, reaction.Feedback)
+
+	writeServeJSON(w, reaction)
+}
+
+// writeServeJSON writes a 200 response with value as JSON
+func writeServeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeServeError writes {"error": message} with the given status
+func writeServeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// serveSecret is a secret found in a diff sent to /suggest
+type serveSecret struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"`
+	Excerpt string `json:"excerpt"`
+}
+
+// writeServeSecrets writes a 422 response listing the secrets found in a diff
+func writeServeSecrets(w http.ResponseWriter, findings []secure.SecretFinding) {
+	secrets := make([]serveSecret, 0, len(findings))
+	for _, f := range findings {
+		secrets = append(secrets, serveSecret{File: f.File, Line: f.Line, Kind: f.Kind, Excerpt: f.Excerpt})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   "possible secrets found in the diff",
+		"secrets": secrets,
+	})
+}
+
+// randomServeToken returns a random hex token for clients to authenticate with
+func randomServeToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/telemetry"
+)
+
+var statsExportOutput string
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.AddCommand(statsExportCmd)
+	statsCmd.AddCommand(statsClearCmd)
+
+	statsExportCmd.Flags().StringVarP(&statsExportOutput, "output", "o", "", "Write the export to this file instead of stdout")
+}
+
+// telemetryDisclosure says what is collected, for every place that mentions telemetry
+const telemetryDisclosure = `Telemetry is off unless "telemetry": true is set in the moai section of your
+config (or NOIDEA_TELEMETRY=true). It records the provider, model, token counts
+and error type of each AI request, and whether suggestions were accepted,
+edited or declined. It never records messages, diffs, keys or paths, and it is
+only stored in ~/.noidea/telemetry.jsonl: nothing is sent anywhere.`
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show local telemetry: token usage, errors and suggestion acceptance",
+	Long: "Summarize the local telemetry in ~/.noidea/telemetry.jsonl.\n\n" + telemetryDisclosure + `
+
+Use 'noidea stats export' to save the aggregate figures if you choose to share
+them, and 'noidea stats clear' to delete everything recorded.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.LoadConfig()
+
+		events, err := telemetry.Load()
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		if len(events) == 0 {
+			fmt.Println(color.YellowString("No telemetry recorded yet."))
+			if !cfg.Moai.Telemetry {
+				fmt.Println()
+				fmt.Println(telemetryDisclosure)
+			}
+			return
+		}
+
+		printTelemetryStats(telemetry.Summarize(events))
+		if !cfg.Moai.Telemetry {
+			fmt.Println()
+			fmt.Println(color.HiBlackString("Telemetry is currently off; these figures are from when it was on."))
+		}
+	},
+}
+
+var statsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export aggregate telemetry as JSON to share",
+	Long: `Write the aggregate figures shown by 'noidea stats' as JSON: request and
+token counts per model, error types and suggestion outcomes. Individual events
+are not included. Nothing is uploaded; share the file however you like.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		events, err := telemetry.Load()
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		data, err := json.MarshalIndent(telemetry.Summarize(events), "", "  ")
+		if err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		if statsExportOutput == "" {
+			fmt.Println(string(data))
+			return
+		}
+		if err := os.WriteFile(statsExportOutput, append(data, '\n'), 0644); err != nil {
+			fmt.Println(color.RedString("❌ Error:"), "Failed to write export:", err)
+			os.Exit(1)
+		}
+		fmt.Println(color.GreenString("✅ Telemetry exported to"), statsExportOutput)
+	},
+}
+
+var statsClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all recorded telemetry",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := telemetry.Clear(); err != nil {
+			fmt.Println(color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+		fmt.Println(color.GreenString("✅ Telemetry deleted"))
+	},
+}
+
+// printTelemetryStats prints request, token, error and acceptance figures
+func printTelemetryStats(stats telemetry.Stats) {
+	fmt.Println(color.CyanString("📊 Local telemetry"))
+	fmt.Println(color.HiBlackString(divider))
+	fmt.Printf("Period:    %s to %s\n", stats.Since.Format("2006-01-02"), stats.Until.Format("2006-01-02"))
+	fmt.Printf("Requests:  %d\n", stats.Requests)
+	fmt.Printf("Tokens:    %d\n", stats.TotalTokens)
+
+	if len(stats.Models) > 0 {
+		models := make([]string, 0, len(stats.Models))
+		for model := range stats.Models {
+			models = append(models, model)
+		}
+		sort.Strings(models)
+
+		fmt.Println()
+		fmt.Println("By model:")
+		for _, model := range models {
+			usage := stats.Models[model]
+			fmt.Printf("  %-32s %4d requests  %8d prompt + %6d completion tokens  %d errors\n",
+				model, usage.Requests, usage.PromptTokens, usage.CompletionTokens, usage.Errors)
+		}
+	}
+
+	if len(stats.Errors) > 0 {
+		fmt.Println()
+		fmt.Println("Errors:")
+		for _, kind := range telemetry.SortedKeys(stats.Errors) {
+			fmt.Printf("  %-18s %d\n", kind, stats.Errors[kind])
+		}
+	}
+
+	if len(stats.Outcomes) > 0 {
+		fmt.Println()
+		fmt.Println("Suggestions:")
+		fmt.Printf("  Accepted as-is:      %d\n", stats.Outcomes[telemetry.Accepted])
+		fmt.Printf("  Accepted with edits: %d\n", stats.Outcomes[telemetry.Edited])
+		fmt.Printf("  Declined:            %d\n", stats.Outcomes[telemetry.Declined])
+		if pending := stats.Outcomes[telemetry.Proposed]; pending > 0 {
+			fmt.Printf("  Not yet committed:   %d\n", pending)
+		}
+		fmt.Printf("  Acceptance rate:     %s\n", color.GreenString("%.0f%%", stats.Acceptance))
+	}
+}
@@ -17,8 +17,8 @@ package cmd
 // Example: feat(auth): implement password reset functionality
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -28,24 +28,61 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
 	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/git"
+	"github.com/AccursedGalaxy/noidea/internal/github"
 	"github.com/AccursedGalaxy/noidea/internal/history"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+	"github.com/AccursedGalaxy/noidea/internal/suggestlog"
+	"github.com/AccursedGalaxy/noidea/internal/telemetry"
+	"github.com/AccursedGalaxy/noidea/internal/truncate"
+	"github.com/AccursedGalaxy/noidea/internal/ui"
 )
 
 var (
 	// Suggest command flags
-	historyCountFlag  int
-	fullDiffFlag      bool
-	interactiveFlag   bool
-	commitMsgFileFlag string
-	quietFlag         bool // Flag for machine-readable output without UI elements
+	historyCountFlag   int
+	fullDiffFlag       bool
+	interactiveFlag    bool
+	commitMsgFileFlag  string
+	quietFlag          bool // Flag for machine-readable output without UI elements
+	historyBodiesFlag  bool // Include commit bodies in the history context
+	suggestForceFlag   bool // Proceed even if the secret scan finds something
+	squashBaseFlag     string
+	suggestRangeFlag   string // Existing commits to describe, "<base>..<head>"
+	withIssuesFlag     bool   // Include open issue and PR titles from GitHub
+	suggestProvider    string
+	suggestModel       string
+	suggestFooters     []string // Extra "Key: value" footers for this run
+	suggestStreamFlag  bool     // Print the suggestion as it is generated
+	suggestNoCacheFlag bool     // Always ask the model instead of reusing a cached suggestion
+	diffAlgorithmFlag  string   // git diff algorithm for the suggestion diff
+	functionCtxFlag    bool     // Show whole functions around each change
+	ignoreSpaceFlag    bool     // Ignore whitespace-only changes
+	contextLinesFlag   int      // Unchanged lines around each change
+	maxFileLinesFlag   int      // Diff lines of each file sent to the model
+	maxSubjectFlag     int      // Most characters in the suggested subject
+	suggestExplainFlag bool     // Explain why the suggestion was worded as it is
+	suggestFixupFlag   string   // Commit to target with a "fixup!" message
+	suggestDiffFile    string   // Diff to describe instead of the staged changes ("-" for stdin)
+	historyBranchFlag  string   // Branch to take the history context from
+	contextCommitsFlag int      // Recent commits whose diffs are sent as context
+	candidatesFlag     int      // Alternative suggestions to choose from in interactive mode
 
 	// Add divider constant here, grouped with other constants
 	divider = "------------------------------------------------------"
 )
 
+// maxRelatedIssues caps how many issue and PR titles are sent as context
+const maxRelatedIssues = 15
+
+// maxCandidates caps --candidates, since every candidate costs output tokens
+const maxCandidates = 5
+
 func init() {
 	rootCmd.AddCommand(suggestCmd)
+	suggestCmd.AddCommand(suggestStatsCmd)
 
 	// Add flags
 	suggestCmd.Flags().IntVarP(&historyCountFlag, "history", "n", 10, "Number of recent commits to analyze for context")
@@ -53,6 +90,28 @@ func init() {
 	suggestCmd.Flags().BoolVarP(&interactiveFlag, "interactive", "i", false, "Interactive mode to approve/reject suggestions")
 	suggestCmd.Flags().StringVarP(&commitMsgFileFlag, "file", "F", "", "Path to commit message file (for prepare-commit-msg hook)")
 	suggestCmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Output only the message without UI elements (for scripts)")
+	suggestCmd.Flags().BoolVarP(&historyBodiesFlag, "bodies", "b", false, "Include commit bodies from history to match the team's body style")
+	suggestCmd.Flags().BoolVar(&suggestForceFlag, "force", false, "Proceed even if possible secrets are found in the staged changes")
+	suggestCmd.Flags().BoolVar(&withIssuesFlag, "with-issues", false, "Include recent open GitHub issue and PR titles as context")
+	suggestCmd.Flags().StringVar(&suggestProvider, "provider", "", "Use this provider for this run instead of the configured one (xai, openai, deepseek)")
+	suggestCmd.Flags().StringVar(&suggestModel, "model", "", "Use this model for this run instead of the configured one")
+	suggestCmd.Flags().StringArrayVar(&suggestFooters, "footer", nil, "Add a `\"Key: value\"` footer such as Reviewed-by or Refs (repeatable)")
+	suggestCmd.Flags().BoolVar(&suggestStreamFlag, "stream", false, "Print the suggestion as it is generated")
+	suggestCmd.Flags().BoolVar(&suggestNoCacheFlag, "no-cache", false, "Generate a new suggestion even if the staged changes haven't changed")
+	suggestCmd.Flags().StringVar(&diffAlgorithmFlag, "diff-algorithm", "", "git diff algorithm for the analyzed diff: myers, minimal, patience or histogram")
+	suggestCmd.Flags().BoolVar(&functionCtxFlag, "function-context", false, "Show the whole function around each change to the model")
+	suggestCmd.Flags().BoolVarP(&ignoreSpaceFlag, "ignore-whitespace", "w", false, "Ignore whitespace-only changes such as reindents")
+	suggestCmd.Flags().IntVar(&contextLinesFlag, "context-lines", 3, "Unchanged lines shown around each change (0 for only changed lines)")
+	suggestCmd.Flags().IntVar(&maxFileLinesFlag, "max-lines-per-file", 50, "Diff lines of each file sent to the model (0 for no limit)")
+	suggestCmd.Flags().IntVar(&maxSubjectFlag, "max-subject", 0, "Most characters in the subject; longer subjects continue in the body (0 for no limit)")
+	suggestCmd.Flags().BoolVar(&suggestExplainFlag, "explain", false, "Explain the chosen type, scope and description (printed to stderr, never committed)")
+	suggestCmd.Flags().StringVar(&suggestFixupFlag, "fixup", "", "Output \"fixup! <subject>\" for `<commit>` instead of generating a message (for git rebase --autosquash)")
+	suggestCmd.Flags().StringVar(&suggestRangeFlag, "range", "", "Suggest one message for the commits in `<base>..<head>` instead of staged changes (e.g. to reword a stack's tip)")
+	suggestCmd.Flags().StringVar(&suggestDiffFile, "diff-file", "", "Suggest a message for the diff in this `file` (\"-\" for stdin) instead of staged changes")
+	suggestCmd.Flags().StringVar(&historyBranchFlag, "history-branch", "", "Take the recent-commit context from this `branch` instead of the current one")
+	suggestCmd.Flags().IntVar(&contextCommitsFlag, "context-commits", 0, "Also send truncated diffs of the last `N` commits, for changes that continue recent work (uses more tokens)")
+	suggestCmd.Flags().IntVar(&candidatesFlag, "candidates", 1, "Offer `N` alternative messages to choose from in interactive mode")
+	suggestCmd.Flags().StringVar(&squashBaseFlag, "squash", "", "Suggest one message for all commits on this branch since `<base>` (for git merge --squash)")
 }
 
 // suggestCmd represents the suggest command
@@ -67,87 +126,405 @@ Example:
   noidea suggest -p coder         # Get a suggestion using the "coder" personality
   noidea suggest -p silly         # Get a suggestion with a silly personality
   noidea suggest | git commit -F- # Pipe suggestion directly into git commit
+  noidea suggest --squash main    # Combined message for the branch's commits since main
+  noidea suggest --range main..feature  # One message describing an existing range
+  git diff main...feature | noidea suggest --diff-file -  # Describe any diff
+  noidea suggest --provider openai --model gpt-4o  # Try another model for one run
   git noidea suggest              # Use the git extension (if installed)`,
 	// Added this comment to test the improved commit message generation algorithm
 	Run: func(cmd *cobra.Command, args []string) {
-		// Load configuration
-		cfg := config.LoadConfig()
+		out := cmd.OutOrStdout()
 
-		// Get staged changes
-		diff, err := getStagedDiff()
+		// Load configuration
+		cfg, err := config.OverrideModel(config.LoadConfig(), suggestProvider, suggestModel)
 		if err != nil {
-			fmt.Println(color.RedString("❌ Error:"), "Failed to get staged changes:", err)
-			return
+			fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+		ui.MaxRetries = cfg.Moai.ApprovalRetries
+
+		// Each of these replaces the staged changes with something else
+		sources := 0
+		for _, flag := range []string{suggestFixupFlag, squashBaseFlag, suggestRangeFlag, suggestDiffFile} {
+			if flag != "" {
+				sources++
+			}
+		}
+		if sources > 1 {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), "Use only one of --fixup, --squash, --range and --diff-file")
+			os.Exit(1)
 		}
 
-		// Check if there are staged changes
-		if strings.TrimSpace(diff) == "" {
-			fmt.Println(color.YellowString("⚠️ No staged changes found. Stage files with 'git add' first."))
+		// A fixup message only names its target, so nothing is generated
+		if suggestFixupFlag != "" {
+			suggestFixup(out, suggestFixupFlag)
 			return
 		}
 
-		// Get recent commit history for context
-		commits, err := history.GetLastNCommits(historyCountFlag, false)
+		footers, err := suggestionFooters(cfg, suggestFooters)
 		if err != nil {
-			fmt.Println(color.YellowString("⚠️ Warning:"), "Failed to get commit history. Continuing with staged changes only.")
+			fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		subjectPrefix, err := suggestionSubjectPrefix(cfg)
+		if err != nil {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		maxSubject := cfg.Moai.MaxSubjectLength
+		if cmd.Flags().Changed("max-subject") {
+			if maxSubjectFlag != 0 && maxSubjectFlag < config.MinSubjectLength {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), fmt.Sprintf("--max-subject must be 0 or at least %d", config.MinSubjectLength))
+				os.Exit(1)
+			}
+			maxSubject = maxSubjectFlag
+		}
+
+		if contextCommitsFlag < 0 {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), "--context-commits can't be negative")
+			os.Exit(1)
+		}
+
+		if candidatesFlag < 1 || candidatesFlag > maxCandidates {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), fmt.Sprintf("--candidates must be between 1 and %d", maxCandidates))
+			os.Exit(1)
+		}
+
+		diffOptions, err := suggestionDiffOptions(cmd, cfg)
+		if err != nil {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		var diff string
+		var rangeSubjects []string
+		var historyFilter history.HistoryFilter
+		var rangeBase string
+
+		// Style context from another branch, e.g. the team's main branch while
+		// on a personal one; checked first so a typo isn't silently no context
+		if historyBranchFlag != "" {
+			if suggestRangeFlag != "" || squashBaseFlag != "" {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), "--history-branch can't be combined with --range or --squash, which take their context from the base")
+				os.Exit(1)
+			}
+			if _, err := history.ResolveCommit(historyBranchFlag); err != nil {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), fmt.Sprintf("Unknown branch '%s': it does not name a branch, tag or commit in this repository", historyBranchFlag))
+				os.Exit(1)
+			}
+			historyFilter.Branch = historyBranchFlag
+		}
+
+		if suggestRangeFlag != "" {
+			// Describe commits that already exist
+			diff, rangeSubjects, rangeBase, err = collectRangeChanges(suggestRangeFlag, diffOptions)
+			if err != nil {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+				os.Exit(1)
+			}
+			// Style context comes from before the range, not from the commits being described
+			historyFilter.Branch = rangeBase
+		} else if squashBaseFlag != "" {
+			// Describe the whole branch instead of the staged changes
+			diff, rangeSubjects, err = collectSquashChanges(squashBaseFlag, diffOptions)
+			if err != nil {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+				os.Exit(1)
+			}
+			// Take style context from the base so the branch's own commits aren't repeated
+			historyFilter.Branch = squashBaseFlag
+		} else if suggestDiffFile != "" {
+			// Describe a diff from elsewhere; git isn't asked for one
+			diff, err = readDiffFile(suggestDiffFile)
+			if err != nil {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+				os.Exit(1)
+			}
+		} else {
+			// Get staged changes
+			diff, err = getStagedDiff(diffOptions)
+			if err != nil {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to get staged changes:", err)
+				return
+			}
+
+			// With -w a whitespace-only change has an empty diff; describe it as-is
+			if strings.TrimSpace(diff) == "" && diffOptions.IgnoreWhitespace {
+				diff, err = getStagedDiff(git.DiffOptions{})
+				if err != nil {
+					fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to get staged changes:", err)
+					return
+				}
+			}
+
+			// Check if there are staged changes
+			if strings.TrimSpace(diff) == "" {
+				fmt.Fprintln(out, color.YellowString("⚠️ No staged changes found. Stage files with 'git add' first."))
+				return
+			}
+		}
+
+		// Subjects of existing commits may already carry a ticket key or gitmoji.
+		// The model shouldn't copy them, since the configured prefix is added later.
+		rangeSubjects = unprefixedSubjects(rangeSubjects, subjectPrefix)
+
+		// Guard against committing secrets when enabled
+		if cfg.Moai.BlockSecrets {
+			if findings := secure.ScanDiffForSecrets(diff); len(findings) > 0 {
+				reportSecretFindings(findings)
+				if !suggestForceFlag {
+					fmt.Fprintln(os.Stderr, "Remove the secrets from your staged changes, or re-run with --force to continue anyway.")
+					os.Exit(1)
+				}
+				fmt.Fprintln(os.Stderr, color.YellowString("Continuing because --force was given."))
+			}
+		}
+
+		// Staged changes in a repository without commits are its first commit,
+		// which has no history to take context from
+		initialCommit := suggestRangeFlag == "" && squashBaseFlag == "" && suggestDiffFile == "" && !git.HasCommits()
+
+		// Get recent commit history for context
+		var commits []history.CommitInfo
+		if !initialCommit {
+			if collector, cerr := history.NewHistoryCollector(); cerr == nil {
+				historyFilter.Count = historyCountFlag
+				commits, err = collector.GetCommitHistory(historyFilter)
+			} else {
+				err = cerr
+			}
+			if err != nil {
+				fmt.Fprintln(out, color.YellowString("⚠️ Warning:"), "Failed to get commit history. Continuing without it.")
+			}
 		}
 
 		// Extract commit messages and stats
+		includeBodies := historyBodiesFlag || cfg.Moai.HistoryBodies
 		var commitMessages []string
+		var commitBodies []string
 		for _, commit := range commits {
 			commitMessages = append(commitMessages, commit.Message)
+			if includeBodies {
+				commitBodies = append(commitBodies, commit.Body)
+			}
 		}
 
 		// Create a history collector to calculate stats
 		collector, _ := history.NewHistoryCollector()
 		stats := collector.CalculateStats(commits)
 
+		// Diffs of the latest commits, for changes that extend recent work
+		var contextCommits []string
+		if contextCommitsFlag > 0 && !initialCommit {
+			contextCommits, err = recentCommitDiffs(historyFilter.Branch, contextCommitsFlag)
+			if err != nil {
+				fmt.Fprintln(out, color.YellowString("⚠️ Warning:"), "Failed to get recent commit diffs. Continuing without them.")
+			}
+		}
+
+		// Open issues and PRs let the model reference the tracked work
+		var relatedIssues []string
+		if withIssuesFlag || cfg.Moai.WithIssues {
+			applyReleaseTimeout(cfg)
+			relatedIssues = fetchRelatedIssues()
+		}
+
+		// Progress goes to the terminal only; quiet output is just the message
+		progress := out
+		if quietFlag {
+			progress = io.Discard
+		}
+
 		// Print a divider
-		fmt.Println(color.HiBlackString(divider))
+		fmt.Fprintln(progress, color.HiBlackString(divider))
 
 		// Print analysis info
-		fmt.Printf("%s %s\n",
-			color.CyanString("🧠 Analyzing staged changes and"),
-			color.CyanString(fmt.Sprintf("%d recent commits", len(commitMessages))))
+		if suggestRangeFlag != "" {
+			fmt.Fprintf(progress, "%s %s\n",
+				color.CyanString("🧠 Analyzing"),
+				color.CyanString(fmt.Sprintf("%d commits in %s", len(rangeSubjects), suggestRangeFlag)))
+		} else if squashBaseFlag != "" {
+			fmt.Fprintf(progress, "%s %s\n",
+				color.CyanString("🧠 Analyzing"),
+				color.CyanString(fmt.Sprintf("%d commits since %s to squash", len(rangeSubjects), squashBaseFlag)))
+		} else if initialCommit {
+			fmt.Fprintln(progress, color.CyanString("🧠 Analyzing staged changes for the repository's first commit"))
+		} else if suggestDiffFile != "" {
+			fmt.Fprintf(progress, "%s %s\n",
+				color.CyanString(fmt.Sprintf("🧠 Analyzing the diff from %s and", diffFileName(suggestDiffFile))),
+				color.CyanString(fmt.Sprintf("%d recent commits", len(commitMessages))))
+		} else {
+			fmt.Fprintf(progress, "%s %s\n",
+				color.CyanString("🧠 Analyzing staged changes and"),
+				color.CyanString(fmt.Sprintf("%d recent commits", len(commitMessages))))
+		}
 
-		fmt.Printf("%s\n",
+		fmt.Fprintf(progress, "%s\n",
 			color.CyanString("Generating professional commit message suggestion..."))
 
 		// If using full diff, indicate that we're doing detailed code analysis
 		if fullDiffFlag {
-			fmt.Printf("%s\n",
+			fmt.Fprintf(progress, "%s\n",
 				color.CyanString("Performing detailed code analysis to identify specific changes..."))
 		}
 
 		// Create feedback engine based on config
-		engineProvider := cfg.LLM.Provider
-		engineModel := cfg.LLM.Model
-		apiKey := cfg.LLM.APIKey
-		personality := cfg.Moai.Personality
-		personalityFile := cfg.Moai.PersonalityFile
-
-		engine := feedback.NewFeedbackEngine(engineProvider, engineModel, apiKey, personality, personalityFile)
+		engine := newFeedbackEngine(cfg, func(provider, model, apiKey string) feedback.FeedbackEngine {
+			return feedback.NewFeedbackEngine(provider, model, apiKey, cfg.Moai.Personality, cfg.Moai.PersonalityFile)
+		})
 
 		// Create commit context for the suggestion
-		ctx := feedback.CommitContext{
-			Diff:          diff,
-			CommitHistory: commitMessages,
-			CommitStats:   stats,
-			Timestamp:     time.Now(),
-		}
+		ctx := suggestionContext(cfg, diff)
+		ctx.CommitHistory = commitMessages
+		ctx.CommitBodies = commitBodies
+		ctx.RangeCommits = rangeSubjects
+		ctx.ContextCommits = contextCommits
+		ctx.InitialCommit = initialCommit
+		ctx.RelatedIssues = relatedIssues
+		ctx.MaxSubject = maxSubject
+		ctx.SubjectPrefix = subjectPrefix
+		ctx.CommitStats = stats
 
 		// If fullDiffFlag is true, provide the entire diff, otherwise summarize
 		if !fullDiffFlag {
 			// Create a summarized version of the diff for conciseness
-			ctx.Diff = summarizeDiff(diff)
+			maxLines := cfg.Moai.MaxLinesPerFile
+			if cmd.Flags().Changed("max-lines-per-file") {
+				if maxFileLinesFlag < 0 {
+					fmt.Fprintln(out, color.RedString("❌ Error:"), "--max-lines-per-file can't be negative")
+					os.Exit(1)
+				}
+				maxLines = maxFileLinesFlag
+			}
+			ctx.Diff = summarizeDiff(diff, maxLines)
 		}
 
-		// Generate suggested commit message
-		suggestion, err := engine.GenerateCommitSuggestion(ctx)
+		// Generate suggested commit message, with the footers after the body.
+		// A Gerrit Change-Id is kept across regenerations. With out set, the
+		// message is streamed there, followed by any footers added here.
+		var changeID string
+
+		// An unchanged diff reuses the last suggestion for the same model, but
+		// regenerating always asks the model again. The key covers the diff
+		// the model sees and every prompt option, so changing any of them
+		// asks again. Alternatives are offered to pick from when the message is reviewed here
+		pickCandidates := candidatesFlag > 1 && interactiveFlag && !quietFlag
+		if candidatesFlag > 1 && !pickCandidates {
+			fmt.Fprintln(os.Stderr, color.YellowString("Warning: --candidates only applies with --interactive, showing one suggestion"))
+		}
+
+		var cacheKey string
+		if !offlineEngine(engine) && !suggestNoCacheFlag && !pickCandidates {
+			cacheKey = feedback.SuggestionCacheKey(ctx, cfg.LLM.Provider, cfg.LLM.Model)
+		}
+		useCache := cacheKey != ""
+
+		// decorate adds the branch prefix, footers and Change-Id to a generated message
+		decorate := func(generated string) string {
+			suggestion := conventional.AppendFooters(conventional.PrefixSubject(generated, subjectPrefix), footers)
+
+			if cfg.Moai.GerritChangeID && conventional.FooterValue(suggestion, "Change-Id") == "" {
+				if changeID == "" {
+					changeID = gerritChangeID(suggestion, commitMsgFileFlag)
+				}
+				if changeID != "" {
+					suggestion = conventional.AppendFooters(suggestion, []conventional.Footer{{Key: "Change-Id", Value: changeID}})
+				}
+			}
+			return suggestion
+		}
+
+		generateTo := func(out io.Writer) (string, error) {
+			var generated string
+			var err error
+			cached, hit := "", false
+			if useCache {
+				cached, hit = feedback.CachedSuggestion(cacheKey)
+				useCache = false
+			}
+
+			// The prefix depends on the branch, so it isn't part of the cached message
+			if out != nil && subjectPrefix != "" {
+				fmt.Fprint(out, subjectPrefix+" ")
+			}
+
+			switch {
+			case hit:
+				generated = cached
+				if !quietFlag {
+					fmt.Fprintln(os.Stderr, color.HiBlackString("Using the cached suggestion for these changes (--no-cache to regenerate)"))
+				}
+				if out != nil {
+					fmt.Fprint(out, generated)
+				}
+			case out != nil:
+				generated, err = feedback.StreamCommitSuggestion(engine, ctx, out)
+			default:
+				generated, err = engine.GenerateCommitSuggestion(ctx)
+			}
+			if err != nil {
+				return "", err
+			}
+			if !hit && cacheKey != "" {
+				_ = feedback.CacheSuggestion(cacheKey, generated)
+			}
+
+			prefixed := conventional.PrefixSubject(generated, subjectPrefix)
+			suggestion := decorate(generated)
+
+			if out != nil && strings.HasPrefix(suggestion, prefixed) {
+				fmt.Fprint(out, suggestion[len(prefixed):])
+			}
+			return suggestion, nil
+		}
+		generate := func() (string, error) {
+			return generateTo(nil)
+		}
+		generateCandidates := func() ([]string, error) {
+			generated, err := feedback.CommitSuggestions(engine, ctx, candidatesFlag)
+			if err != nil {
+				return nil, err
+			}
+			candidates := make([]string, len(generated))
+			for i, message := range generated {
+				candidates[i] = decorate(message)
+			}
+			return candidates, nil
+		}
+
+		// Streaming only applies when the message goes straight to the terminal.
+		// Quiet output is piped into git, so it waits for the cleaned-up message.
+		streaming := suggestStreamFlag && commitMsgFileFlag == "" && !quietFlag && !interactiveFlag
+
+		var suggestion string
+		var candidates []string
+		if streaming {
+			fmt.Fprintln(out, color.HiBlackString(divider))
+			fmt.Fprintln(out, color.GreenString("✨ Suggested commit message:"))
+			suggestion, err = streamSuggestion(out, generateTo)
+			if err == nil {
+				fmt.Fprintln(out, color.HiBlackString(divider))
+			}
+		} else if pickCandidates {
+			candidates, err = generateCandidates()
+			if err == nil {
+				suggestion = candidates[0]
+			}
+		} else {
+			suggestion, err = generate()
+		}
 		if err != nil {
-			fmt.Println(color.RedString("❌ Error:"), "Failed to generate suggestion:", err)
+			fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to generate suggestion:", err)
 			return
 		}
+		reportAnsweringProvider(engine, cfg)
+
+		// Suggestions that aren't reviewed here are resolved when the commit lands
+		if quietFlag || !interactiveFlag {
+			logSuggestion(cfg, suggestion, suggestlog.Proposed, "")
+		}
 
 		// Handle output based on flags
 		if quietFlag {
@@ -155,20 +532,22 @@ Example:
 			if commitMsgFileFlag != "" {
 				err := writeToCommitMsgFile(suggestion, commitMsgFileFlag)
 				if err != nil {
-					fmt.Println(color.RedString("❌ Error:"), "Failed to write commit message:", err)
+					fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to write commit message:", err)
 					return
 				}
 			} else {
 				// Just print the raw message for piping
-				fmt.Print(suggestion)
+				fmt.Fprint(out, suggestion)
 			}
-		} else {
+		} else if !streaming {
 			// Standard output with UI elements
-			fmt.Println(color.HiBlackString(divider))
+			fmt.Fprintln(out, color.HiBlackString(divider))
 
-			if interactiveFlag {
+			if len(candidates) > 1 {
+				handleCandidatePicker(out, cfg, candidates, commitMsgFileFlag, generateCandidates)
+			} else if interactiveFlag {
 				// Handle interactive mode
-				handleInteractiveMode(suggestion, commitMsgFileFlag)
+				handleInteractiveMode(out, cfg, suggestion, commitMsgFileFlag, generate, diff)
 			} else {
 				// Check if we're being called from a git hook (via --file flag)
 				isFromGitHook := commitMsgFileFlag != ""
@@ -177,74 +556,293 @@ Example:
 				// or if called directly by the user
 				if !isFromGitHook {
 					// Just print the suggestion
-					fmt.Println(color.GreenString("✨ Suggested commit message:"))
-
-					// Handle multi-line commit messages with better formatting
-					lines := strings.Split(suggestion, "\n")
-					if len(lines) > 1 {
-						// Print the first line (subject) in white
-						fmt.Println(color.HiWhiteString(lines[0]))
-
-						// Print the rest with proper formatting
-						for i := 1; i < len(lines); i++ {
-							if lines[i] == "" {
-								// Print empty lines as is
-								fmt.Println()
-							} else {
-								// Print content lines in white but not highlighted
-								fmt.Println(color.WhiteString(lines[i]))
-							}
-						}
-					} else {
-						// Single line message
-						fmt.Println(color.HiWhiteString(suggestion))
-					}
+					fmt.Fprintln(out, color.GreenString("✨ Suggested commit message:"))
 
-					fmt.Println(color.HiBlackString(divider))
+					printSuggestion(out, suggestion)
+					fmt.Fprintln(out, color.HiBlackString(divider))
+					printExplanation(suggestion, diff)
 				}
 
 				// If we have a commit message file, write to it
 				if commitMsgFileFlag != "" {
 					err := writeToCommitMsgFile(suggestion, commitMsgFileFlag)
 					if err != nil {
-						fmt.Println(color.RedString("❌ Error:"), "Failed to write commit message:", err)
+						fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to write commit message:", err)
 						return
 					}
 					// Success message with the complete commit message
-					fmt.Println(color.GreenString("✅ Commit message suggestion applied:"))
+					fmt.Fprintln(out, color.GreenString("✅ Commit message suggestion applied:"))
 
 					// Show the full message in the success notification
-					lines := strings.Split(suggestion, "\n")
-					if len(lines) > 1 {
-						// Print the first line (subject) in white
-						fmt.Println(color.HiWhiteString(lines[0]))
-
-						// Print the rest with proper formatting
-						for i := 1; i < len(lines); i++ {
-							if lines[i] == "" {
-								// Print empty lines as is
-								fmt.Println()
-							} else {
-								// Print content lines in white but not highlighted
-								fmt.Println(color.WhiteString(lines[i]))
-							}
-						}
-					} else {
-						// Single line message
-						fmt.Println(color.HiWhiteString(suggestion))
-					}
-
-					fmt.Println(color.HiBlackString(divider))
+					printSuggestion(out, suggestion)
+					fmt.Fprintln(out, color.HiBlackString(divider))
+					printExplanation(suggestion, diff)
 				}
 			}
 		}
+
+		// Without the usual preview, the explanation follows the raw output
+		if quietFlag || streaming {
+			printExplanation(suggestion, diff)
+		}
+	},
+}
+
+// suggestStatsCmd reports how past suggestions were received
+var suggestStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how often suggestions were accepted, edited, or declined",
+	Long: `Summarize the suggestions log (~/.noidea/suggestions.jsonl).
+
+Logging is opt-in: set "log_suggestions": true in the moai section of your
+config, or NOIDEA_LOG_SUGGESTIONS=true.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		out := cmd.OutOrStdout()
+		cfg := config.LoadConfig()
+
+		entries, err := suggestlog.Load()
+		if err != nil {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+			os.Exit(1)
+		}
+
+		if len(entries) == 0 {
+			fmt.Fprintln(out, color.YellowString("No suggestions logged yet."))
+			if !cfg.Moai.LogSuggestions {
+				fmt.Fprintln(out, "Enable logging with 'log_suggestions' in your config or NOIDEA_LOG_SUGGESTIONS=true.")
+			}
+			return
+		}
+
+		printSuggestionStats(out, suggestlog.Summarize(entries))
 	},
 }
 
+// printSuggestionStats prints acceptance figures and the most common edits to out
+func printSuggestionStats(out io.Writer, stats suggestlog.Stats) {
+	fmt.Fprintln(out, color.CyanString("📈 Suggestion statistics"))
+	fmt.Fprintln(out, color.HiBlackString(divider))
+	fmt.Fprintf(out, "Suggestions logged:  %d\n", stats.Total)
+	fmt.Fprintf(out, "Accepted as-is:      %d\n", stats.Outcomes[suggestlog.Accepted])
+	fmt.Fprintf(out, "Accepted with edits: %d\n", stats.Outcomes[suggestlog.Edited])
+	fmt.Fprintf(out, "Declined:            %d\n", stats.Outcomes[suggestlog.Declined])
+	if pending := stats.Outcomes[suggestlog.Proposed]; pending > 0 {
+		fmt.Fprintf(out, "Not yet committed:   %d\n", pending)
+	}
+	fmt.Fprintf(out, "Acceptance rate:     %s\n", color.GreenString("%.0f%%", stats.AcceptanceRate()))
+
+	if edits := stats.CommonEdits(); len(edits) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Common edits:")
+		for _, kind := range edits {
+			fmt.Fprintf(out, "  %-18s %d\n", kind, stats.Edits[kind])
+		}
+	}
+}
+
+// streamSuggestion prints the suggestion to out as it is generated. The
+// streamed text is the model's raw answer, so when cleaning it up changed the
+// message (a code fence, a preamble, the subject and body rules), the final
+// message is printed after it.
+func streamSuggestion(out io.Writer, generateTo func(io.Writer) (string, error)) (string, error) {
+	var streamed strings.Builder
+	suggestion, err := generateTo(io.MultiWriter(out, &streamed))
+	fmt.Fprintln(out)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(streamed.String()) != strings.TrimSpace(suggestion) {
+		fmt.Fprintln(out, color.HiBlackString(divider))
+		fmt.Fprintln(out, color.GreenString("✨ Cleaned-up commit message:"))
+		printSuggestion(out, suggestion)
+	}
+	return suggestion, nil
+}
+
+// collectSquashChanges returns the combined diff and commit subjects for every
+// commit on the current branch since it diverged from base
+func collectSquashChanges(base string, diffOptions git.DiffOptions) (string, []string, error) {
+	collector, err := history.NewHistoryCollector()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create history collector: %w", err)
+	}
+
+	changes, err := collector.CollectRange(base, "HEAD", true, diffOptions.Args()...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(changes.Commits) == 0 || strings.TrimSpace(changes.Diff) == "" {
+		return "", nil, fmt.Errorf("no changes on the current branch since '%s'", base)
+	}
+
+	return changes.Diff, changes.Subjects(), nil
+}
+
+// collectRangeChanges returns the combined diff and commit subjects for a
+// "<base>..<head>" range of existing commits, and the hash the range starts
+// after. "<base>...<head>" starts at their merge base and an empty head is HEAD.
+func collectRangeChanges(spec string, diffOptions git.DiffOptions) (string, []string, string, error) {
+	base, head, fromMergeBase, err := parseCommitRange(spec)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	collector, err := history.NewHistoryCollector()
+	if err != nil {
+		return "", nil, "", fmt.Errorf("failed to create history collector: %w", err)
+	}
+
+	changes, err := collector.CollectRange(base, head, fromMergeBase, diffOptions.Args()...)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	if len(changes.Commits) == 0 || strings.TrimSpace(changes.Diff) == "" {
+		return "", nil, "", fmt.Errorf("no changes in '%s'", spec)
+	}
+
+	return changes.Diff, changes.Subjects(), changes.Base, nil
+}
+
+// unprefixedSubjects strips the subject prefix, ticket keys and gitmoji from
+// the subjects of existing commits, so re-suggesting a message for a commit
+// that is being amended doesn't pile up prefixes
+func unprefixedSubjects(subjects []string, prefix string) []string {
+	stripped := make([]string, len(subjects))
+	for i, subject := range subjects {
+		stripped[i] = conventional.StripSubjectPrefixes(subject, prefix)
+	}
+	return stripped
+}
+
+// parseCommitRange splits "<base>..<head>" or "<base>...<head>" into its
+// revisions, reporting whether the range starts at their merge base
+func parseCommitRange(spec string) (base, head string, fromMergeBase bool, err error) {
+	separator := ".."
+	if strings.Contains(spec, "...") {
+		separator = "..."
+		fromMergeBase = true
+	}
+
+	base, head, ok := strings.Cut(spec, separator)
+	if !ok || base == "" || strings.HasPrefix(base, "-") || strings.HasPrefix(head, "-") {
+		return "", "", false, fmt.Errorf("invalid range '%s' (use <base>..<head>)", spec)
+	}
+	if head == "" {
+		head = "HEAD"
+	}
+
+	return base, head, fromMergeBase, nil
+}
+
+// suggestionFooters combines the configured footers with those given on the
+// command line; flags replace configured footers with the same key
+func suggestionFooters(cfg config.Config, flags []string) ([]conventional.Footer, error) {
+	var fromFlags []conventional.Footer
+	for _, flag := range flags {
+		footer, err := conventional.ParseFooter(flag)
+		if err != nil {
+			return nil, err
+		}
+		fromFlags = append(fromFlags, footer)
+	}
+
+	var footers []conventional.Footer
+	for _, footer := range config.CommitFooters(cfg) {
+		overridden := false
+		for _, flag := range fromFlags {
+			if strings.EqualFold(flag.Key, footer.Key) {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			footers = append(footers, footer)
+		}
+	}
+	return append(footers, fromFlags...), nil
+}
+
+// gerritChangeID reuses the Change-Id already in the commit message file, so
+// amended commits keep their Gerrit review, or computes a new one
+func gerritChangeID(message, msgFile string) string {
+	if msgFile != "" {
+		if data, err := os.ReadFile(msgFile); err == nil {
+			// A verbose diff below the scissors line isn't part of the message
+			existing, _ := conventional.SplitScissors(string(data))
+			if id := conventional.FooterValue(existing, "Change-Id"); id != "" {
+				return id
+			}
+		}
+	}
+
+	id, err := git.ComputeChangeID(message)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not compute a Change-Id: %v\n", err)
+		return ""
+	}
+	return id
+}
+
+// fetchRelatedIssues returns recent open issue and PR titles, or nothing when
+// GitHub isn't available; problems are reported on stderr so hooks keep working
+func fetchRelatedIssues() []string {
+	issues, err := github.RecentIssues(maxRelatedIssues)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Skipping GitHub issues: %v\n", err)
+		return nil
+	}
+
+	refs := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		refs = append(refs, issue.String())
+	}
+	return refs
+}
+
+// reportSecretFindings warns about likely secrets in the staged changes.
+// Output goes to stderr so it is visible even in quiet mode.
+func reportSecretFindings(findings []secure.SecretFinding) {
+	fmt.Fprintln(os.Stderr, color.RedString("🔒 Possible secrets found in staged changes:"))
+	for _, f := range findings {
+		fmt.Fprintf(os.Stderr, "  %s:%d  %s (%s)\n", f.File, f.Line, f.Kind, f.Excerpt)
+	}
+}
+
+// suggestionDiffOptions returns the configured git diff options, with any
+// given on the command line taking precedence
+func suggestionDiffOptions(cmd *cobra.Command, cfg config.Config) (git.DiffOptions, error) {
+	options := git.DiffOptionsFromConfig(cfg)
+	if cmd.Flags().Changed("diff-algorithm") {
+		options.Algorithm = strings.ToLower(diffAlgorithmFlag)
+	}
+	if cmd.Flags().Changed("function-context") {
+		options.FunctionContext = functionCtxFlag
+	}
+	if cmd.Flags().Changed("ignore-whitespace") {
+		options.IgnoreWhitespace = ignoreSpaceFlag
+	}
+	if cmd.Flags().Changed("context-lines") {
+		if contextLinesFlag < 0 {
+			return options, fmt.Errorf("--context-lines can't be negative")
+		}
+		options.ContextLines = &contextLinesFlag
+	}
+
+	if options.Algorithm != "" && !config.ValidDiffAlgorithm(options.Algorithm) {
+		return options, fmt.Errorf("unknown diff algorithm %q (use %s)",
+			options.Algorithm, strings.Join(config.DiffAlgorithms, ", "))
+	}
+	return options, nil
+}
+
 // getStagedDiff gets the diff of staged changes
-func getStagedDiff() (string, error) {
+func getStagedDiff(options git.DiffOptions) (string, error) {
 	// Use a more efficient approach with custom buffer sizing
-	cmd := exec.Command("git", "diff", "--staged")
+	args := append([]string{"diff", "--staged"}, options.Args()...)
+	cmd := exec.Command("git", args...)
 
 	// Create a buffer with reasonable initial size to reduce allocations
 	var outputBuffer strings.Builder
@@ -261,14 +859,118 @@ func getStagedDiff() (string, error) {
 	return outputBuffer.String(), nil
 }
 
-// summarizeDiff creates a concise version of the diff
-// It keeps file headers and a limited number of changed lines per file
-func summarizeDiff(diff string) string {
-	const maxLinesPerFile = 50 // Maximum number of diff lines to show per file
+// recentCommitDiffs returns the truncated "git show" output of the last count
+// commits on branch (the current one when empty), newest first
+func recentCommitDiffs(branch string, count int) ([]string, error) {
+	collector, err := history.NewHistoryCollector()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create history collector: %w", err)
+	}
 
+	commits, err := collector.GetCommitHistory(history.HistoryFilter{Count: count, Branch: branch, IncludeDiff: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	for _, commit := range commits {
+		if commit.DiffSummary != "" {
+			diffs = append(diffs, commit.DiffSummary)
+		}
+	}
+	return diffs, nil
+}
+
+// suggestionContext builds the context for a suggestion of diff with the
+// prompt options from cfg. Callers add the history and per-run options.
+func suggestionContext(cfg config.Config, diff string) feedback.CommitContext {
+	return feedback.CommitContext{
+		Diff:           diff,
+		NoteTests:      cfg.Moai.NoteTests,
+		ForceOnlyType:  cfg.Moai.ForceOnlyType,
+		TypeRules:      config.CommitTypeRules(cfg),
+		SmallDiffLines: cfg.Moai.SmallDiffLines,
+		BodyBullets:    suggestionBodyBullets(cfg),
+		MaxSubject:     cfg.Moai.MaxSubjectLength,
+		WrapBody:       cfg.Moai.WrapBody,
+		MaxLineLength:  cfg.Moai.MaxLineLength,
+		CommentChar:    suggestionCommentChar(cfg),
+		Timestamp:      time.Now(),
+	}
+}
+
+// suggestionSubjectPrefix renders moai.subject_prefix for the current
+// branch; a detached HEAD has no branch to take values from
+func suggestionSubjectPrefix(cfg config.Config) (string, error) {
+	branch, detached, err := git.CurrentBranch()
+	if err != nil || detached {
+		branch = ""
+	}
+	prefix, err := config.SubjectPrefix(cfg, branch)
+	if err != nil {
+		return "", fmt.Errorf("invalid moai.subject_prefix: %w", err)
+	}
+	return prefix, nil
+}
+
+// suggestionCommentChar returns the character comment lines in responses
+// start with: moai.comment_char, or git's core.commentChar
+func suggestionCommentChar(cfg config.Config) string {
+	if cfg.Moai.CommentChar != "" {
+		return cfg.Moai.CommentChar
+	}
+	return git.CommentChar()
+}
+
+// suggestionBodyBullets returns moai.max_body_bullets as a bullet limit for
+// suggestions, where 0 means no body at all
+func suggestionBodyBullets(cfg config.Config) int {
+	if cfg.Moai.MaxBodyBullets == 0 {
+		return feedback.NoBody
+	}
+	return cfg.Moai.MaxBodyBullets
+}
+
+// readDiffFile reads a diff from path, or from stdin for "-". Patches that
+// aren't git diffs are converted with feedback.NormalizeDiff, and input that
+// has no file diffs at all is rejected.
+func readDiffFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read diff: %w", err)
+	}
+
+	if strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("the diff from %s is empty", diffFileName(path))
+	}
+	diff := feedback.NormalizeDiff(string(data))
+	if !strings.Contains(diff, "diff --git ") {
+		return "", fmt.Errorf("%s doesn't contain a diff (expected git diff, git format-patch or diff -u output)", diffFileName(path))
+	}
+	return diff, nil
+}
+
+// diffFileName describes where readDiffFile reads from
+func diffFileName(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return path
+}
+
+// summarizeDiff creates a concise version of the diff
+// It keeps file headers and at most maxLinesPerFile changed lines per file
+// (0 for no limit)
+func summarizeDiff(diff string, maxLinesPerFile int) string {
 	// If the diff is small enough, just return it
 	lines := strings.Split(diff, "\n")
-	if len(lines) <= maxLinesPerFile*2 {
+	if maxLinesPerFile <= 0 || len(lines)/2 <= maxLinesPerFile {
 		return diff
 	}
 
@@ -281,7 +983,7 @@ func summarizeDiff(diff string) string {
 		if strings.HasPrefix(line, "diff --git") {
 			// If we were in a file and truncated it, add an indicator
 			if inFile && linesInCurrentFile >= maxLinesPerFile {
-				result.WriteString("... (additional lines omitted for brevity) ...\n\n")
+				result.WriteString(truncate.Marker + "\n\n")
 			}
 
 			// Reset for the next file
@@ -305,84 +1007,186 @@ func summarizeDiff(diff string) string {
 
 	// Add a final truncation notice if needed
 	if inFile && linesInCurrentFile >= maxLinesPerFile {
-		result.WriteString("... (additional lines omitted for brevity) ...\n")
+		result.WriteString(truncate.Marker + "\n")
 	}
 
 	return result.String()
 }
 
 // handleInteractiveMode presents the suggestion to the user and allows interaction
-func handleInteractiveMode(suggestion string, commitMsgFileFlag string) {
-	fmt.Println(color.GreenString("✨ Suggested commit message:"))
+func handleInteractiveMode(out io.Writer, cfg config.Config, suggestion string, commitMsgFileFlag string, regenerate func() (string, error), diff string) {
+	// Track the latest generated suggestion so edits are measured against it
+	latest := suggestion
+	message, accepted := ui.ApproveOrEditWith(suggestion, ui.ApproveOptions{
+		Display: func(content string) {
+			fmt.Fprintln(out, color.GreenString("✨ Suggested commit message:"))
+			printSuggestion(out, content)
+			fmt.Fprintln(out, color.HiBlackString(divider))
+			printExplanation(content, diff)
+		},
+		Regenerate: func() (string, error) {
+			regenerated, err := regenerate()
+			if err == nil {
+				latest = regenerated
+			}
+			return regenerated, err
+		},
+		Extension: ".txt",
+	})
 
-	// Handle multi-line commit messages with better formatting
-	lines := strings.Split(suggestion, "\n")
-	if len(lines) > 1 {
-		// Print the first line (subject) in white
-		fmt.Println(color.HiWhiteString(lines[0]))
-
-		// Print the rest with proper formatting
-		for i := 1; i < len(lines); i++ {
-			if lines[i] == "" {
-				// Print empty lines as is
-				fmt.Println()
-			} else {
-				// Print content lines in white but not highlighted
-				fmt.Println(color.WhiteString(lines[i]))
+	applyInteractiveChoice(out, cfg, latest, message, accepted, commitMsgFileFlag)
+}
+
+// handleCandidatePicker presents alternative suggestions as a numbered list
+// to choose one from, edit one, or regenerate them all
+func handleCandidatePicker(out io.Writer, cfg config.Config, candidates []string, commitMsgFileFlag string, regenerate func() ([]string, error)) {
+	// Track the latest candidates so edits are measured against the one picked
+	latest := candidates
+	message, index, accepted := ui.PickCandidate(candidates, ui.PickOptions{
+		Display: func(number int, candidate string) {
+			fmt.Fprintln(out, color.GreenString(fmt.Sprintf("✨ Suggestion %d:", number)))
+			printSuggestion(out, candidate)
+			fmt.Fprintln(out, color.HiBlackString(divider))
+		},
+		Regenerate: func() ([]string, error) {
+			regenerated, err := regenerate()
+			if err == nil && len(regenerated) > 0 {
+				latest = regenerated
 			}
+			return regenerated, err
+		},
+		Extension: ".txt",
+	})
+
+	applyInteractiveChoice(out, cfg, latest[index], message, accepted, commitMsgFileFlag)
+}
+
+// applyInteractiveChoice logs how a reviewed suggestion was received and
+// writes the accepted message or prints it to out
+func applyInteractiveChoice(out io.Writer, cfg config.Config, latest, message string, accepted bool, commitMsgFileFlag string) {
+	if !accepted {
+		logSuggestion(cfg, latest, suggestlog.Declined, "")
+		fmt.Fprintln(out, color.YellowString("Suggestion declined"))
+		return
+	}
+
+	outcome := suggestlog.Accepted
+	if message != latest {
+		outcome = suggestlog.Edited
+	}
+	logSuggestion(cfg, latest, outcome, message)
+
+	if commitMsgFileFlag != "" {
+		err := writeToCommitMsgFile(message, commitMsgFileFlag)
+		if err != nil {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to write commit message:", err)
+			return
 		}
+		fmt.Fprintln(out, color.GreenString("✅ Commit message accepted and applied"))
 	} else {
-		// Single line message
-		fmt.Println(color.HiWhiteString(suggestion))
+		fmt.Fprintln(out, color.GreenString("✅ Commit message accepted"))
+		// Print the bare message for piping
+		fmt.Fprintln(out, message)
 	}
+}
 
-	fmt.Println(color.HiBlackString(divider))
+// suggestFixup outputs a "fixup! <subject>" message for the target commit to
+// out, matching 'git commit --fixup' so 'git rebase --autosquash' picks it up
+func suggestFixup(out io.Writer, target string) {
+	hash, subject, err := git.CommitSubject(target)
+	if err != nil {
+		fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+		os.Exit(1)
+	}
+	if !git.IsAncestor(hash) {
+		fmt.Fprintln(os.Stderr, color.YellowString("⚠️ Warning:"), target, "is not on the current branch, so autosquash won't find it")
+	}
 
-	// Ask if the user wants to use this suggestion
-	fmt.Print(color.YellowString("Accept this suggestion? (Y/n/e): "))
-	reader := bufio.NewReader(os.Stdin)
-	response, _ := reader.ReadString('\n')
-	response = strings.ToLower(strings.TrimSpace(response))
+	message := "fixup! " + subject
 
-	// Default to yes if empty
-	if response == "" || response == "y" || response == "yes" {
-		if commitMsgFileFlag != "" {
-			err := writeToCommitMsgFile(suggestion, commitMsgFileFlag)
-			if err != nil {
-				fmt.Println(color.RedString("❌ Error:"), "Failed to write commit message:", err)
-				return
-			}
-			fmt.Println(color.GreenString("✅ Commit message accepted and applied"))
-		} else {
-			fmt.Println(color.GreenString("✅ Commit message accepted"))
-			// Print to stdout for piping
-			fmt.Println(suggestion)
-		}
-	} else if response == "e" || response == "edit" {
-		editedMsg := editSuggestion(suggestion)
-		if commitMsgFileFlag != "" {
-			err := writeToCommitMsgFile(editedMsg, commitMsgFileFlag)
-			if err != nil {
-				fmt.Println(color.RedString("❌ Error:"), "Failed to write commit message:", err)
-				return
-			}
-			fmt.Println(color.GreenString("✅ Edited commit message applied"))
+	switch {
+	case commitMsgFileFlag != "":
+		if err := writeToCommitMsgFile(message, commitMsgFileFlag); err != nil {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to write commit message:", err)
+			os.Exit(1)
+		}
+		if !quietFlag {
+			fmt.Fprintln(out, color.GreenString("✅ Fixup message applied:"), message)
+		}
+	case quietFlag:
+		fmt.Fprint(out, message)
+	default:
+		fmt.Fprintln(out, color.GreenString("✨ Suggested commit message:"))
+		printSuggestion(out, message)
+		fmt.Fprintln(out, color.HiBlackString(divider))
+	}
+}
+
+// printExplanation prints why a suggestion was worded as it is when --explain
+// is set. It goes to stderr so it never ends up in a piped or hook-written
+// commit message.
+func printExplanation(suggestion, diff string) {
+	if !suggestExplainFlag {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, color.CyanString("💡 Why this message:"))
+	for _, reason := range feedback.ExplainSuggestion(suggestion, diff) {
+		fmt.Fprintf(os.Stderr, "  • %s\n", reason)
+	}
+	fmt.Fprintln(os.Stderr, color.HiBlackString(divider))
+}
+
+// logSuggestion records a suggestion in the suggestions log and its outcome in
+// the telemetry, each when enabled
+func logSuggestion(cfg config.Config, suggestion, outcome, final string) {
+	if cfg.Moai.Telemetry {
+		if err := telemetry.RecordSuggestion(suggestion, outcome); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to record telemetry: %v\n", err)
+		}
+	}
+	if !cfg.Moai.LogSuggestions {
+		return
+	}
+	if err := suggestlog.Record(suggestion, outcome, final); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to log suggestion: %v\n", err)
+	}
+}
+
+// printSuggestion prints a commit message to out with the subject line highlighted
+func printSuggestion(out io.Writer, suggestion string) {
+	// Handle multi-line commit messages with better formatting
+	lines := strings.Split(suggestion, "\n")
+
+	// Print the first line (subject) in white
+	fmt.Fprintln(out, color.HiWhiteString(lines[0]))
+
+	// Print the rest with proper formatting
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "" {
+			// Print empty lines as is
+			fmt.Fprintln(out)
 		} else {
-			fmt.Println(color.GreenString("✅ Commit message edited"))
-			// Print to stdout for piping
-			fmt.Println(editedMsg)
+			// Print content lines in white but not highlighted
+			fmt.Fprintln(out, color.WhiteString(lines[i]))
 		}
-	} else {
-		fmt.Println(color.YellowString("Suggestion declined"))
 	}
 }
 
-// writeToCommitMsgFile writes the commit message to the specified file
+// writeToCommitMsgFile writes the commit message to the specified file. The
+// diff "git commit -v" puts below the scissors line is kept under the message.
 func writeToCommitMsgFile(message string, filePath string) error {
 	// Verify file exists before attempting to write
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	existing, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
 		return fmt.Errorf("commit message file does not exist: %s", filePath)
 	}
+	if err != nil {
+		return fmt.Errorf("failed to read commit message file: %w", err)
+	}
+	if _, verbose := conventional.SplitScissors(string(existing)); verbose != "" {
+		message = strings.TrimRight(message, "\n") + "\n\n" + verbose
+	}
 
 	// Open file with proper error handling
 	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC, 0644)
@@ -398,22 +1202,3 @@ func writeToCommitMsgFile(message string, filePath string) error {
 
 	return nil
 }
-
-// editSuggestion allows the user to edit the suggested commit message
-func editSuggestion(suggestion string) string {
-	fmt.Println(color.CyanString("✏️ Current suggestion:"))
-	fmt.Println(suggestion)
-	fmt.Println(color.CyanString("Enter your edited message (type 'done' on a new line when finished):"))
-
-	var lines []string
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "done" {
-			break
-		}
-		lines = append(lines, line)
-	}
-
-	return strings.Join(lines, "\n")
-}
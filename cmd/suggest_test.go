@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/git"
+	"github.com/AccursedGalaxy/noidea/internal/suggestlog"
+)
+
+func TestParseCommitRange(t *testing.T) {
+	tests := []struct {
+		spec          string
+		base, head    string
+		fromMergeBase bool
+	}{
+		{"main..feature", "main", "feature", false},
+		{"HEAD~3..", "HEAD~3", "HEAD", false},
+		{"main...feature", "main", "feature", true},
+	}
+	for _, tt := range tests {
+		base, head, fromMergeBase, err := parseCommitRange(tt.spec)
+		if err != nil {
+			t.Errorf("parseCommitRange(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if base != tt.base || head != tt.head || fromMergeBase != tt.fromMergeBase {
+			t.Errorf("parseCommitRange(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.spec, base, head, fromMergeBase, tt.base, tt.head, tt.fromMergeBase)
+		}
+	}
+
+	for _, spec := range []string{"main", "..feature", "--all..HEAD", "main..--all"} {
+		if _, _, _, err := parseCommitRange(spec); err == nil {
+			t.Errorf("parseCommitRange(%q) expected an error", spec)
+		}
+	}
+}
+
+func TestPrintSuggestionStats(t *testing.T) {
+	stats := suggestlog.Stats{
+		Total:    4,
+		Outcomes: map[string]int{suggestlog.Accepted: 2, suggestlog.Edited: 1, suggestlog.Declined: 1},
+		Edits:    map[string]int{},
+	}
+
+	var out bytes.Buffer
+	printSuggestionStats(&out, stats)
+
+	printed := stripANSIColors(out.String())
+	for _, want := range []string{"Suggestions logged:  4\n", "Declined:            1\n", "Acceptance rate:     75%\n"} {
+		if !strings.Contains(printed, want) {
+			t.Errorf("output is missing %q:\n%s", want, printed)
+		}
+	}
+	if strings.Contains(printed, "Not yet committed") {
+		t.Errorf("pending suggestions should only be listed when there are some:\n%s", printed)
+	}
+}
+
+func TestSummarizeDiff(t *testing.T) {
+	var diff strings.Builder
+	diff.WriteString("diff --git a/big.go b/big.go\n@@ -1,0 +1,30 @@\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&diff, "+line %d\n", i)
+	}
+
+	summary := summarizeDiff(diff.String(), 5)
+	if !strings.Contains(summary, "+line 4\n") || strings.Contains(summary, "+line 5\n") {
+		t.Errorf("summary should keep exactly 5 lines of the file:\n%s", summary)
+	}
+	if !strings.Contains(summary, "@@ -1,0 +1,30 @@") {
+		t.Error("summary should keep hunk headers")
+	}
+
+	// No limit, or a limit larger than the diff, keeps everything
+	for _, limit := range []int{0, 1 << 40} {
+		if got := summarizeDiff(diff.String(), limit); got != diff.String() {
+			t.Errorf("summarizeDiff(diff, %d) should return the full diff", limit)
+		}
+	}
+}
+
+func TestWriteToCommitMsgFileKeepsVerboseDiff(t *testing.T) {
+	verbose := "# ------------------------ >8 ------------------------\n# Do not modify or remove the line above.\ndiff --git a/x b/x\n+x\n"
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte("\n# Please enter the commit message\n"+verbose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeToCommitMsgFile("feat: add x\n", path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "feat: add x\n\n" + verbose; string(data) != want {
+		t.Errorf("commit message file = %q, want %q", data, want)
+	}
+
+	// Without a scissors line the file is replaced
+	plain := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(plain, []byte("old message\n# comment\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeToCommitMsgFile("fix: y", plain); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(plain); string(data) != "fix: y" {
+		t.Errorf("commit message file = %q, want %q", data, "fix: y")
+	}
+
+	if err := writeToCommitMsgFile("fix: z", filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing commit message file")
+	}
+}
+
+// echoEngine answers with the subject of the commit being reworded, like a
+// model that copies the existing message
+type echoEngine struct{ feedback.LocalFeedbackEngine }
+
+func (echoEngine) GenerateCommitSuggestion(ctx feedback.CommitContext) (string, error) {
+	return ctx.RangeCommits[0], nil
+}
+
+func TestAmendSuggestionPrefixIsStable(t *testing.T) {
+	prefix := "[JIRA-123]"
+	subject := "[OLD-7] ✨ feat: add login"
+
+	var suggestions []string
+	for run := 0; run < 2; run++ {
+		ctx := feedback.CommitContext{RangeCommits: unprefixedSubjects([]string{subject}, prefix)}
+		generated, err := echoEngine{}.GenerateCommitSuggestion(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		subject = conventional.PrefixSubject(generated, prefix)
+		suggestions = append(suggestions, subject)
+	}
+
+	if suggestions[0] != "[JIRA-123] feat: add login" || suggestions[1] != suggestions[0] {
+		t.Errorf("amending twice gave %q, want a stable \"[JIRA-123] feat: add login\"", suggestions)
+	}
+}
+
+// commitFile writes a file in repo and commits it with message
+func commitFile(t *testing.T, repo, name, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", name)
+	runGit(t, repo, "commit", "-q", "-m", message)
+}
+
+func TestSuggestSquash(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOIDEA_LLM_MOCK", "1")
+	repo := chdirTestRepo(t)
+	runGit(t, repo, "checkout", "-q", "-b", "main")
+	commitFile(t, repo, "base.txt", "base\n", "chore: add base")
+	runGit(t, repo, "checkout", "-q", "-b", "feature")
+	commitFile(t, repo, "export.go", "package main\n", "feat: add export")
+	commitFile(t, repo, "import.go", "package main\n", "feat: add import")
+
+	diff, subjects, err := collectSquashChanges("main", git.DiffOptions{})
+	if err != nil {
+		t.Fatalf("collectSquashChanges() error = %v", err)
+	}
+	if strings.Join(subjects, "|") != "feat: add export|feat: add import" {
+		t.Errorf("subjects = %q, want only the branch's commits", subjects)
+	}
+	if !strings.Contains(diff, "b/export.go") || !strings.Contains(diff, "b/import.go") || strings.Contains(diff, "base.txt") {
+		t.Errorf("diff should cover the branch's changes only:\n%s", diff)
+	}
+
+	// Staged changes don't matter when squashing
+	if err := os.WriteFile(filepath.Join(repo, "staged.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "staged.txt")
+
+	defer func() { squashBaseFlag, quietFlag, suggestStreamFlag = "", false, false }()
+	defer rootCmd.SetOut(nil)
+	defer rootCmd.SetArgs(nil)
+	for _, extra := range []string{"--stream=false", "--stream"} {
+		var out strings.Builder
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"suggest", "--squash", "main", "--quiet", extra})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		// Quiet output is piped into git, so it is exactly the message
+		if want := "feat: add 2 files\n\n- Add export.go\n- Add import.go"; out.String() != want {
+			t.Errorf("quiet output with %s = %q, want only the branch's message %q", extra, out.String(), want)
+		}
+	}
+
+	if _, _, err := collectSquashChanges("feature", git.DiffOptions{}); err == nil || !strings.Contains(err.Error(), "no changes") {
+		t.Errorf("squashing onto the current branch error = %v", err)
+	}
+}
+
+func TestReadDiffFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if _, err := readDiffFile(write("empty.diff", " \n\n")); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("empty input error = %v", err)
+	}
+	if _, err := readDiffFile(write("notes.txt", "just some notes\nnot a patch\n")); err == nil || !strings.Contains(err.Error(), "doesn't contain a diff") {
+		t.Errorf("non-diff input error = %v", err)
+	}
+	if _, err := readDiffFile(filepath.Join(dir, "missing.diff")); err == nil {
+		t.Error("a missing file should be an error")
+	}
+
+	// Plain diff -u output is converted to a git diff
+	unified := "--- config.yml.orig\t2024-05-01 10:00:00.000000000 +0200\n" +
+		"+++ config.yml\t2024-05-01 10:05:00.000000000 +0200\n" +
+		"@@ -1,2 +1,2 @@\n name: app\n-port: 80\n+port: 8080\n"
+	diff, err := readDiffFile(write("change.diff", unified))
+	if err != nil {
+		t.Fatalf("readDiffFile(diff -u) error = %v", err)
+	}
+	if !strings.HasPrefix(diff, "diff --git a/config.yml b/config.yml\n") || !strings.Contains(diff, "+port: 8080") {
+		t.Errorf("diff -u input should get a git diff header and keep its hunks:\n%s", diff)
+	}
+
+	// "-" reads the same diff from stdin
+	stdin, err := os.Open(filepath.Join(dir, "change.diff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	defer func(previous *os.File) { os.Stdin = previous }(os.Stdin)
+	os.Stdin = stdin
+	if fromStdin, err := readDiffFile("-"); err != nil || fromStdin != diff {
+		t.Errorf("readDiffFile(-) = %q, %v, want the same diff as the file", fromStdin, err)
+	}
+}
+
+func TestStreamSuggestion(t *testing.T) {
+	stream := func(raw, final string) func(io.Writer) (string, error) {
+		return func(w io.Writer) (string, error) {
+			fmt.Fprint(w, raw)
+			return final, nil
+		}
+	}
+
+	// The model's preamble and code fence are streamed as they arrive, then
+	// the cleaned-up message follows
+	var out bytes.Buffer
+	suggestion, err := streamSuggestion(&out, stream("Here's a commit message:\n```\nfeat: add login\n```", "feat: add login"))
+	if err != nil || suggestion != "feat: add login" {
+		t.Fatalf("streamSuggestion() = %q, %v", suggestion, err)
+	}
+	if !strings.Contains(out.String(), "Cleaned-up commit message:") || !strings.HasSuffix(out.String(), "\nfeat: add login\n") {
+		t.Errorf("output should end with the cleaned-up message:\n%s", out.String())
+	}
+
+	// A clean stream is printed once
+	out.Reset()
+	if _, err := streamSuggestion(&out, stream("feat: add login\n", "feat: add login")); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "feat: add login\n\n" {
+		t.Errorf("clean stream output = %q, want the message once", out.String())
+	}
+
+	if _, err := streamSuggestion(&out, func(io.Writer) (string, error) { return "", fmt.Errorf("boom") }); err == nil {
+		t.Error("streamSuggestion() should return the generation error")
+	}
+}
@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fatih/color"
@@ -16,6 +19,7 @@ import (
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
 	"github.com/AccursedGalaxy/noidea/internal/feedback"
+	"github.com/AccursedGalaxy/noidea/internal/git"
 	"github.com/AccursedGalaxy/noidea/internal/history"
 	"github.com/AccursedGalaxy/noidea/internal/personality"
 )
@@ -29,6 +33,12 @@ var (
 	aiInsightFlag         bool
 	personalityForSummary string
 	showCommitHistoryFlag bool
+	summaryJSONFlag       bool
+	summaryBranchFlag     string
+	summaryBaseBranchFlag string
+	summarySinceFlag      string
+	summaryUntilFlag      string
+	refreshInsightFlag    bool
 )
 
 func init() {
@@ -37,11 +47,20 @@ func init() {
 	// Add flags
 	summaryCmd.Flags().IntVarP(&daysFlag, "days", "d", 7, "Number of days to include in summary (default: 7, use 0 for all history)")
 	summaryCmd.Flags().BoolVarP(&allHistoryFlag, "all", "A", false, "Show complete repository history regardless of --days value")
-	summaryCmd.Flags().StringVarP(&exportFlag, "export", "e", "", "Export format: text, markdown, or html")
+	summaryCmd.Flags().StringVarP(&exportFlag, "export", "e", "", "Export format: text, markdown, html, json, csv (commits per day and hour range), or svg (a shareable stats card)")
 	summaryCmd.Flags().BoolVarP(&statsOnlyFlag, "stats-only", "s", false, "Show only statistics without AI insights")
 	summaryCmd.Flags().BoolVarP(&aiInsightFlag, "ai", "a", false, "Include AI insights (default: use config)")
-	summaryCmd.Flags().StringVarP(&personalityForSummary, "personality", "p", "", "Personality to use for insights (default: from config)")
+	summaryCmd.Flags().StringVarP(&personalityForSummary, "personality", "p", "", "Personality to use for insights, or \"random\" (default: from config)")
 	summaryCmd.Flags().BoolVarP(&showCommitHistoryFlag, "show-commits", "c", false, "Include detailed commit history in the output")
+	summaryCmd.Flags().BoolVar(&summaryJSONFlag, "json", false, "Print the summary as JSON: statistics, commits and AI insight")
+	summaryCmd.Flags().StringVar(&summaryBranchFlag, "branch", "", "Branch to summarize instead of the current one, alone or against --base-branch")
+	summaryCmd.Flags().StringVar(&summaryBaseBranchFlag, "base-branch", "", "Summarize only commits not in this base branch (git log base..branch)")
+	summaryCmd.Flags().BoolVar(&refreshInsightFlag, "refresh", false, "Generate a new AI insight even if one is cached for these commits")
+	// Older scripts still pass the original flag name
+	summaryCmd.Flags().BoolVar(&refreshInsightFlag, "refresh-insight", false, "Alias for --refresh")
+	summaryCmd.Flags().MarkHidden("refresh-insight")
+	summaryCmd.Flags().StringVar(&summarySinceFlag, "since", "", "Start of the summary window (YYYY-MM-DD or RFC3339)")
+	summaryCmd.Flags().StringVar(&summaryUntilFlag, "until", "", "End of the summary window, inclusive (YYYY-MM-DD or RFC3339, default: now)")
 }
 
 var summaryCmd = &cobra.Command{
@@ -57,8 +76,17 @@ Examples:
   noidea summary --days 30      # Show commits from the last 30 days
   noidea summary --all          # Show all repository history
   noidea summary --days 0       # Same as --all, shows all history
-  noidea summary --show-commits # Include detailed commit history in output`,
+  noidea summary --show-commits # Include detailed commit history in output
+  noidea summary --json         # Machine-readable summary for dashboards and CI
+  noidea summary --since 2024-01-01 --until 2024-01-14
+                                # Summarize a sprint window
+  noidea summary --branch feature/login --base-branch main --export markdown
+                                # Review-ready summary of a feature branch
+  noidea summary --branch develop --days 14
+                                # Recent activity on another branch`,
 	Run: func(cmd *cobra.Command, args []string) {
+		out := cmd.OutOrStdout()
+
 		// Load configuration
 		cfg := config.LoadConfig()
 
@@ -70,39 +98,117 @@ Examples:
 		if personalityForSummary != "" {
 			personalityName = personalityForSummary
 		}
+		personalityName = resolvePersonality(cfg, personalityName)
+		if personalityForSummary != "" {
+			requireAllowedPersonality(cfg, personalityName)
+		}
 
 		var commits []history.CommitInfo
 		var err error
 
-		// Check if user requested all history
-		if allHistoryFlag || daysFlag == 0 {
-			// Fetch all commits
-			commits, err = history.GetLastNCommits(1000, useAI)
+		// Summaries read history with a single git log, capped for large repositories
+		historyLimit := cfg.Moai.MaxHistoryCommits
+
+		// Branch mode summarizes only the commits unique to a branch, so the
+		// time window flags and the complete-history fallback don't apply
+		branchMode := summaryBaseBranchFlag != ""
+
+		// Without --base-branch, --branch reads another branch's history
+		// instead of HEAD's; check it first so a typo isn't an empty summary
+		var revArgs []string
+		if summaryBranchFlag != "" && !branchMode {
+			if _, err := history.ResolveCommit(summaryBranchFlag); err != nil {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), fmt.Sprintf("Unknown branch '%s': it does not name a branch, tag or commit in this repository", summaryBranchFlag))
+				os.Exit(1)
+			}
+			revArgs = []string{summaryBranchFlag, "--"}
+		}
+
+		// Range mode summarizes a fixed window such as a sprint
+		rangeMode := summarySinceFlag != "" || summaryUntilFlag != ""
+		var since, until time.Time
+		if rangeMode {
+			switch {
+			case branchMode:
+				err = fmt.Errorf("--since and --until can't be combined with --base-branch")
+			case allHistoryFlag || cmd.Flags().Changed("days"):
+				err = fmt.Errorf("--since and --until can't be combined with --days or --all")
+			default:
+				since, until, err = summaryDateRange(summarySinceFlag, summaryUntilFlag, time.Now())
+			}
 			if err != nil {
-				fmt.Println(color.RedString("Error:"), "Failed to retrieve commit history:", err)
+				fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+				os.Exit(1)
+			}
+		}
+
+		if rangeMode {
+			commits, err = history.LogCommits(historyLimit, append([]string{
+				"--since=" + since.Format(time.RFC3339), "--until=" + until.Format(time.RFC3339)}, revArgs...)...)
+			if err != nil {
+				fmt.Fprintln(out, color.RedString("Error:"), "Failed to retrieve commit history:", err)
+				return
+			}
+
+			if len(commits) == 0 && !summaryJSONFlag {
+				fmt.Fprintln(out, color.YellowString("No commits found in"),
+					color.CyanString(summaryRangeLabel(config.ResolveDateFormat(cfg.Moai.DateFormat))))
+				return
+			}
+		} else if branchMode {
+			branch := summaryBranchFlag
+			if branch == "" {
+				branch = "HEAD"
+			}
+
+			commits, err = history.LogCommitsBetween(summaryBaseBranchFlag, branch, historyLimit)
+			if err != nil {
+				fmt.Fprintln(out, color.RedString("❌ Error:"), err)
+				os.Exit(1)
+			}
+
+			if len(commits) == 0 && !summaryJSONFlag {
+				fmt.Fprintln(out, color.YellowString("No commits on"), color.CyanString(branch),
+					color.YellowString("that are not already in"), color.CyanString(summaryBaseBranchFlag))
+				return
+			}
+
+			// A branch summary is meant for review, so always list its commits
+			showCommitHistoryFlag = true
+		} else if allHistoryFlag || daysFlag == 0 {
+			// Fetch all commits, up to the cap
+			commits, err = history.LogCommits(historyLimit, revArgs...)
+			if err != nil {
+				fmt.Fprintln(out, color.RedString("Error:"), "Failed to retrieve commit history:", err)
 				return
 			}
 			// Set days to a large value to indicate complete history in the summary
 			daysFlag = 365 * 10 // 10 years, arbitrary large number
 		} else {
 			// Get commit data for the specified period
-			commits, err = history.GetCommitsFromLastNDays(daysFlag, useAI)
+			commits, err = history.LogCommits(historyLimit, append([]string{fmt.Sprintf("--since=%d.days.ago", daysFlag)}, revArgs...)...)
 			if err != nil {
-				fmt.Println(color.RedString("Error:"), "Failed to retrieve commit history:", err)
+				fmt.Fprintln(out, color.RedString("Error:"), "Failed to retrieve commit history:", err)
 				return
 			}
 
 			// Only show the fallback message and fetch all history if we truly have zero commits
 			if len(commits) == 0 {
 				// No commits in the specified time period, automatically fetch all history
-				fmt.Println(color.YellowString("No commits found in the last"),
+				// (kept off stdout in JSON mode so the output stays parseable)
+				notice := fmt.Sprintln(color.YellowString("No commits found in the last"),
 					color.CyanString(strconv.Itoa(daysFlag)),
 					color.YellowString("days. Showing complete history instead."))
+				if summaryJSONFlag {
+					fmt.Fprint(os.Stderr, notice)
+				} else {
+					fmt.Fprint(out, notice)
+				}
 
-				// Get all commits by using GetLastNCommits with a high number
-				commits, err = history.GetLastNCommits(1000, useAI)
+				// Get all commits, up to the cap
+				commits, err = history.LogCommits(historyLimit, revArgs...)
 				if err != nil {
-					fmt.Println(color.RedString("Error:"), "Failed to retrieve commit history:", err)
+					fmt.Fprintln(out, color.RedString("Error:"), "Failed to retrieve commit history:", err)
 					return
 				}
 
@@ -111,22 +217,36 @@ Examples:
 			}
 		}
 
+		// Say when the cap cut the history short (on stderr to keep JSON clean)
+		if historyLimit > 0 && len(commits) == historyLimit {
+			fmt.Fprintf(os.Stderr, "%s Only the latest %d commits are included (moai.max_history_commits)\n",
+				color.YellowString("Note:"), historyLimit)
+		}
+
 		// Use a direct Git command to get commits as a test
-		if len(commits) == 0 {
+		if len(commits) == 0 && !statsOnlyFlag && !branchMode && !rangeMode {
 			// Execute a direct Git command to see if we can get commits
-			cmd := exec.Command("git", "log", "--pretty=format:%s", "-n", "10")
-			out, err := cmd.Output()
-			if err == nil && len(out) > 0 {
+			gitLog := exec.Command("git", append([]string{"log", "--pretty=format:%s", "-n", "10"}, revArgs...)...)
+			recent, err := gitLog.Output()
+			if err == nil && len(recent) > 0 {
 				// We got direct git output but no commits from our history function
-				fmt.Println(color.YellowString("Warning:"), "Git history is available but our history collector couldn't retrieve it.")
-				fmt.Println(color.YellowString("Recent commits from Git:"))
-				fmt.Println(string(out))
+				fmt.Fprintln(out, color.YellowString("Warning:"), "Git history is available but our history collector couldn't retrieve it.")
+				fmt.Fprintln(out, color.YellowString("Recent commits from Git:"))
+				fmt.Fprintln(out, string(recent))
 			}
 		}
 
 		// Check if we have any commits after all attempts
 		if len(commits) == 0 {
-			fmt.Println(color.YellowString("No commits found in this repository."))
+			if summaryJSONFlag {
+				runStatsOnlySummary(out, commits, cfg)
+				return
+			}
+			if summaryBranchFlag != "" && !branchMode {
+				fmt.Fprintln(out, color.YellowString("No commits found on"), color.CyanString(summaryBranchFlag))
+				return
+			}
+			fmt.Fprintln(out, color.YellowString("No commits found in this repository."))
 			return
 		}
 
@@ -142,118 +262,26 @@ Examples:
 			}
 		}
 
+		// Stats-only mode uses the collector stats as-is, with no fallbacks
+		if statsOnlyFlag {
+			runStatsOnlySummary(out, commits, cfg)
+			return
+		}
+
 		// Generate statistics
 		// Directly get Git stats if collector doesn't provide data
 		collector, err := history.NewHistoryCollector()
 		if err != nil {
-			fmt.Println(color.RedString("Error:"), "Failed to create history collector:", err)
+			fmt.Fprintln(out, color.RedString("Error:"), "Failed to create history collector:", err)
 			return
 		}
-		stats := collector.CalculateStats(commits)
-
-		// Verify stats are not all zero
-		allZeros := true
-		if val, ok := stats["totalCommits"]; ok && val != nil {
-			if v, ok := val.(int); ok && v > 0 {
-				allZeros = false
-			}
-		}
-
-		// If stats appear to be all zeros but we have commits, try to get stats directly
-		if allZeros && len(commits) > 0 {
-			// Directly calculate basic stats
-			stats["totalCommits"] = len(commits)
-
-			// Calculate unique authors
-			authors := make(map[string]bool)
-			for _, commit := range commits {
-				authors[commit.Author] = true
-			}
-			stats["uniqueAuthors"] = len(authors)
-
-			// Calculate timespan in hours
-			if len(commits) >= 2 {
-				newest := commits[0].Timestamp
-				oldest := commits[len(commits)-1].Timestamp
-				timeSpan := newest.Sub(oldest).Hours()
-				stats["timeSpan"] = fmt.Sprintf("%.1f", timeSpan)
-			} else {
-				stats["timeSpan"] = "0.0"
-			}
-
-			// Calculate commits by day
-			commitsByDay := make(map[string]int)
-			for _, commit := range commits {
-				day := commit.Timestamp.Weekday().String()
-				commitsByDay[day]++
-			}
-			stats["commitsByDay"] = commitsByDay
-
-			// Calculate commits by hour range
-			commitsByHourRange := make(map[string]int)
-			for _, commit := range commits {
-				hour := commit.Timestamp.Hour()
-				var hourRange string
-
-				switch {
-				case hour >= 4 && hour < 8:
-					hourRange = "Morning (4-8)"
-				case hour >= 8 && hour < 12:
-					hourRange = "Work Hours (8-12)"
-				case hour >= 12 && hour < 16:
-					hourRange = "Afternoon (12-16)"
-				case hour >= 16 && hour < 20:
-					hourRange = "Evening (16-20)"
-				case hour >= 20 && hour < 24:
-					hourRange = "Late PM (20-24)"
-				default:
-					hourRange = "Night (0-4)"
-				}
-
-				commitsByHourRange[hourRange]++
-			}
-			stats["commitsByHourRange"] = commitsByHourRange
-
-			// Try to get file stats using git command
-			cmd := exec.Command("git", "diff", "--shortstat", commits[len(commits)-1].Hash, commits[0].Hash)
-			out, err := cmd.Output()
-			if err == nil {
-				// Parse output like: " 10 files changed, 100 insertions(+), 50 deletions(-)"
-				statStr := string(out)
-				filesRe := regexp.MustCompile(`(\d+) files? changed`)
-				addRe := regexp.MustCompile(`(\d+) insertions?\(\+\)`)
-				delRe := regexp.MustCompile(`(\d+) deletions?\(-\)`)
-
-				if matches := filesRe.FindStringSubmatch(statStr); len(matches) > 1 {
-					if val, err := strconv.Atoi(matches[1]); err == nil {
-						stats["filesChanged"] = val
-					}
-				}
-
-				if matches := addRe.FindStringSubmatch(statStr); len(matches) > 1 {
-					if val, err := strconv.Atoi(matches[1]); err == nil {
-						stats["linesAdded"] = val
-					}
-				}
-
-				if matches := delRe.FindStringSubmatch(statStr); len(matches) > 1 {
-					if val, err := strconv.Atoi(matches[1]); err == nil {
-						stats["linesRemoved"] = val
-					}
-				}
-
-				// Calculate net change
-				added := 0
-				if val, ok := stats["linesAdded"].(int); ok {
-					added = val
-				}
-
-				removed := 0
-				if val, ok := stats["linesRemoved"].(int); ok {
-					removed = val
-				}
 
-				stats["netChange"] = added - removed
+		// Reuse computed stats when the set of commits hasn't changed
+		stats, cached := collector.GetCachedStats(commits)
+		if !cached {
+			stats = computeSummaryStats(collector, commits)
+			if err := collector.CacheStats(commits, stats); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to cache summary stats: %v\n", err)
 			}
 		}
 
@@ -261,35 +289,254 @@ Examples:
 		statsSummary := formatStatsForDisplay(stats)
 
 		// Get list of commits
-		commitList := history.FormatCommitList(commits)
+		dateLayout := config.ResolveDateFormat(cfg.Moai.DateFormat)
+		commitList := history.FormatCommitListWithDateLayout(commits, dateLayout)
 
 		var aiInsight string
 		if useAI {
-			aiInsight, err = generateAIInsights(commits, stats, personalityName, cfg)
+			aiInsight, err = generateAIInsights(out, commits, stats, personalityName, cfg)
 			if err != nil {
-				fmt.Println(color.YellowString("Note:"), "Unable to generate AI insights:", err)
+				notice := fmt.Sprintln(color.YellowString("Note:"), "Unable to generate AI insights:", err)
+				if summaryJSONFlag {
+					fmt.Fprint(os.Stderr, notice)
+				} else {
+					fmt.Fprint(out, notice)
+				}
 			}
 		}
 
+		if summaryJSONFlag {
+			printSummaryJSON(out, commits, aiInsight, summaryHeader(dateLayout))
+			return
+		}
+
+		// Without AI insights, add a locally derived view of where the work went
+		if aiInsight == "" {
+			statsSummary += formatFocusForDisplay(commits) + formatReworkForDisplay(commits)
+		}
+
 		// Generate the complete summary
-		summary := formatSummary(statsSummary, commitList, aiInsight, daysFlag, showCommitHistoryFlag)
+		summary := formatSummary(out, statsSummary, commitList, aiInsight, summaryHeader(dateLayout), showCommitHistoryFlag)
 
 		// Export if requested, otherwise print to console
 		if exportFlag != "" {
-			if err := exportSummary(summary, exportFlag); err != nil {
-				fmt.Println(color.RedString("Error:"), "Failed to export summary:", err)
+			if err := exportSummary(summary, commits, aiInsight, exportFlag, dateLayout); err != nil {
+				fmt.Fprintln(out, color.RedString("Error:"), "Failed to export summary:", err)
 			} else {
-				fmt.Println(color.GreenString("Summary exported successfully."))
+				fmt.Fprintln(out, color.GreenString("Summary exported successfully."))
 			}
 		} else {
 			// Print to console
-			fmt.Println(summary)
+			fmt.Fprintln(out, summary)
 		}
 	},
 }
 
-// generateAIInsights creates AI-powered insights for the commit history
-func generateAIInsights(commits []history.CommitInfo, stats map[string]interface{}, personalityName string, cfg config.Config) (string, error) {
+// runStatsOnlySummary prints statistics computed in a single pass by the
+// history collector to out, either as the usual stats box or as JSON
+func runStatsOnlySummary(out io.Writer, commits []history.CommitInfo, cfg config.Config) {
+	collector, err := history.NewHistoryCollector()
+	if err != nil {
+		fmt.Fprintln(out, color.RedString("Error:"), "Failed to create history collector:", err)
+		os.Exit(1)
+	}
+
+	stats := collector.CalculateStats(commits)
+	stats["total_commits"] = len(commits)
+
+	// Only the stats object, with no commit list or AI fields
+	if summaryJSONFlag {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to encode statistics:", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(out, string(data))
+		return
+	}
+
+	dateLayout := config.ResolveDateFormat(cfg.Moai.DateFormat)
+
+	if len(commits) == 0 {
+		fmt.Fprintln(out, color.YellowString("No commits found in this repository."))
+		return
+	}
+
+	statsSummary := formatStatsForDisplay(displayStatsFromCollector(stats)) + formatFocusForDisplay(commits) + formatReworkForDisplay(commits)
+	commitList := history.FormatCommitListWithDateLayout(commits, dateLayout)
+	summary := formatSummary(out, statsSummary, commitList, "", summaryHeader(dateLayout), showCommitHistoryFlag)
+
+	if exportFlag != "" {
+		if err := exportSummary(summary, commits, "", exportFlag, dateLayout); err != nil {
+			fmt.Fprintln(out, color.RedString("Error:"), "Failed to export summary:", err)
+		} else {
+			fmt.Fprintln(out, color.GreenString("Summary exported successfully."))
+		}
+		return
+	}
+
+	fmt.Fprintln(out, summary)
+}
+
+// printSummaryJSON prints the summary report as JSON to out
+func printSummaryJSON(out io.Writer, commits []history.CommitInfo, insight, header string) {
+	data, err := summaryJSON(commits, insight, header)
+	if err != nil {
+		fmt.Fprintln(out, color.RedString("❌ Error:"), "Failed to encode summary:", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(out, string(data))
+}
+
+// displayStatsFromCollector maps collector stats onto the keys the summary
+// display expects, so stats-only output never depends on the git fallback
+func displayStatsFromCollector(stats map[string]interface{}) map[string]interface{} {
+	display := make(map[string]interface{})
+
+	display["totalCommits"] = stats["total_commits"]
+	display["uniqueAuthors"] = stats["unique_authors"]
+	if hours, ok := stats["time_span_hours"].(float64); ok {
+		display["timeSpan"] = fmt.Sprintf("%.1f", hours)
+	}
+
+	added, _ := stats["total_insertions"].(int)
+	removed, _ := stats["total_deletions"].(int)
+	display["filesChanged"] = stats["total_files_changed"]
+	display["linesAdded"] = added
+	display["linesRemoved"] = removed
+	display["netChange"] = added - removed
+
+	if byDay, ok := stats["commits_by_day"].(map[string]int); ok {
+		display["commitsByDay"] = byDay
+	}
+
+	if byHour, ok := stats["commits_by_hour"].(map[int]int); ok {
+		byRange := make(map[string]int)
+		for hour, count := range byHour {
+			byRange[hourRangeLabel(hour)] += count
+		}
+		display["commitsByHourRange"] = byRange
+	}
+
+	return display
+}
+
+// hourRangeLabel returns the summary display bucket for an hour of the day
+func hourRangeLabel(hour int) string {
+	switch {
+	case hour >= 4 && hour < 8:
+		return "Morning (4-8)"
+	case hour >= 8 && hour < 12:
+		return "Work Hours (8-12)"
+	case hour >= 12 && hour < 16:
+		return "Afternoon (12-16)"
+	case hour >= 16 && hour < 20:
+		return "Evening (16-20)"
+	case hour >= 20 && hour < 24:
+		return "Late PM (20-24)"
+	default:
+		return "Night (0-4)"
+	}
+}
+
+// computeSummaryStats aggregates the statistics shown in the summary
+func computeSummaryStats(collector *history.HistoryCollector, commits []history.CommitInfo) map[string]interface{} {
+	stats := collector.CalculateStats(commits)
+
+	// Verify stats are not all zero
+	allZeros := true
+	if val, ok := stats["totalCommits"]; ok && val != nil {
+		if v, ok := val.(int); ok && v > 0 {
+			allZeros = false
+		}
+	}
+
+	// If stats appear to be all zeros but we have commits, try to get stats directly
+	if allZeros && len(commits) > 0 {
+		// Directly calculate basic stats
+		stats["totalCommits"] = len(commits)
+
+		// Calculate unique authors
+		authors := make(map[string]bool)
+		for _, commit := range commits {
+			authors[commit.Author] = true
+		}
+		stats["uniqueAuthors"] = len(authors)
+
+		// Calculate timespan in hours
+		if len(commits) >= 2 {
+			newest := commits[0].Timestamp
+			oldest := commits[len(commits)-1].Timestamp
+			timeSpan := newest.Sub(oldest).Hours()
+			stats["timeSpan"] = fmt.Sprintf("%.1f", timeSpan)
+		} else {
+			stats["timeSpan"] = "0.0"
+		}
+
+		// Calculate commits by day
+		commitsByDay := make(map[string]int)
+		for _, commit := range commits {
+			day := commit.Timestamp.Weekday().String()
+			commitsByDay[day]++
+		}
+		stats["commitsByDay"] = commitsByDay
+
+		// Calculate commits by hour range
+		commitsByHourRange := make(map[string]int)
+		for _, commit := range commits {
+			commitsByHourRange[hourRangeLabel(commit.Timestamp.Hour())]++
+		}
+		stats["commitsByHourRange"] = commitsByHourRange
+
+		// Try to get file stats using git command
+		cmd := exec.Command("git", "diff", "--shortstat", commits[len(commits)-1].Hash, commits[0].Hash)
+		out, err := cmd.Output()
+		if err == nil {
+			// Parse output like: " 10 files changed, 100 insertions(+), 50 deletions(-)"
+			statStr := string(out)
+			filesRe := regexp.MustCompile(`(\d+) files? changed`)
+			addRe := regexp.MustCompile(`(\d+) insertions?\(\+\)`)
+			delRe := regexp.MustCompile(`(\d+) deletions?\(-\)`)
+
+			if matches := filesRe.FindStringSubmatch(statStr); len(matches) > 1 {
+				if val, err := strconv.Atoi(matches[1]); err == nil {
+					stats["filesChanged"] = val
+				}
+			}
+
+			if matches := addRe.FindStringSubmatch(statStr); len(matches) > 1 {
+				if val, err := strconv.Atoi(matches[1]); err == nil {
+					stats["linesAdded"] = val
+				}
+			}
+
+			if matches := delRe.FindStringSubmatch(statStr); len(matches) > 1 {
+				if val, err := strconv.Atoi(matches[1]); err == nil {
+					stats["linesRemoved"] = val
+				}
+			}
+
+			// Calculate net change
+			added := 0
+			if val, ok := stats["linesAdded"].(int); ok {
+				added = val
+			}
+
+			removed := 0
+			if val, ok := stats["linesRemoved"].(int); ok {
+				removed = val
+			}
+
+			stats["netChange"] = added - removed
+		}
+	}
+
+	return stats
+}
+
+// generateAIInsights creates AI-powered insights for the commit history,
+// sized to fit the summary printed to out
+func generateAIInsights(out io.Writer, commits []history.CommitInfo, stats map[string]interface{}, personalityName string, cfg config.Config) (string, error) {
 	// Check if we have any commits to analyze
 	if len(commits) == 0 {
 		// If no commits found, return a simple message
@@ -324,13 +571,29 @@ func generateAIInsights(commits []history.CommitInfo, stats map[string]interface
 		selectedPersonality, _ = personalities.GetPersonality("")
 	}
 
-	// Get terminal width for formatting constraints
-	width := 80
-	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
-		width = w
+	// Size the insight to the terminal box; piped output isn't wrapped, so
+	// 0 leaves the line length up to the model
+	maxLineWidth := 0
+	if width, ok := summaryWidth(out); ok {
+		// Account for box borders (typically 4 chars)
+		maxLineWidth = width - 8
+	}
+
+	// Identical inputs give an equivalent insight, so reuse a cached one.
+	// Without an API key the local engine answers, which is cheap and not
+	// worth caching, and neither is the mock engine.
+	collector, cacheErr := history.NewHistoryCollector()
+	useCache := cacheErr == nil && cfg.LLM.APIKey != "" && !feedback.UseMock(cfg.LLM.Provider)
+	cacheKey := history.InsightCacheKey(commits, personalityName, strconv.Itoa(maxLineWidth), cfg.LLM.Provider, cfg.LLM.Model)
+	if useCache && !refreshInsightFlag {
+		ttl := time.Duration(cfg.Moai.InsightCacheHours) * time.Hour
+		if insight, ok := collector.GetCachedInsight(cacheKey, ttl); ok {
+			if verboseFlag {
+				fmt.Fprintln(os.Stderr, "Using cached AI insight (run with --refresh to regenerate)")
+			}
+			return insight, nil
+		}
 	}
-	// Account for box borders (typically 4 chars)
-	maxLineWidth := width - 8
 
 	// Create a custom personality configuration for summary insights
 	customPersonality := selectedPersonality
@@ -339,8 +602,11 @@ func generateAIInsights(commits []history.CommitInfo, stats map[string]interface
 	customPersonality.MaxTokens = 400
 
 	// Create a tailored system prompt for terminal-friendly output
-	customPersonality.SystemPrompt = fmt.Sprintf(`You are a Git expert named Moai providing concise, actionable insights about commit history.
-Your output MUST fit in a terminal box with maximum line width of %d characters.
+	lineLimit := ""
+	if maxLineWidth > 0 {
+		lineLimit = fmt.Sprintf("\nYour output MUST fit in a terminal box with maximum line width of %d characters.", maxLineWidth)
+	}
+	customPersonality.SystemPrompt = fmt.Sprintf(`You are a Git expert named Moai providing concise, actionable insights about commit history.%s
 Format your response as:
 
 - 2-3 bullet points about commit message patterns
@@ -351,7 +617,7 @@ Use plain text formatting suitable for terminals - NO markdown headings or synta
 Keep each bullet point to 1-2 sentences maximum.
 Start each bullet with "• " and skip the introduction - go straight to insights.
 Maintain the personality tone (%s) but be extremely concise.`,
-		maxLineWidth,
+		lineLimit,
 		personalityName,
 	)
 
@@ -368,27 +634,141 @@ Provide CONCISE terminal-friendly insights focusing on patterns, quality, and ac
 	)
 
 	// Create feedback engine with the custom personality
-	engine := feedback.NewFeedbackEngineWithCustomPersonality(
-		cfg.LLM.Provider,
-		cfg.LLM.Model,
-		cfg.LLM.APIKey,
-		customPersonality,
-	)
+	engine := newFeedbackEngine(cfg, func(provider, model, apiKey string) feedback.FeedbackEngine {
+		return feedback.NewFeedbackEngineWithCustomPersonality(provider, model, apiKey, customPersonality)
+	})
 
 	// Generate AI insights
-	return engine.GenerateSummaryFeedback(summaryContext)
+	insights, err := engine.GenerateSummaryFeedback(summaryContext)
+	if err == nil {
+		reportAnsweringProvider(engine, cfg)
+		if useCache {
+			if err := collector.CacheInsight(cacheKey, insights); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to cache AI insight: %v\n", err)
+			}
+		}
+	}
+	return insights, err
 }
 
-// formatSummary combines all parts into a complete summary
-func formatSummary(stats, commits, aiInsights string, days int, showHistory bool) string {
-	var result strings.Builder
+// summaryHeader describes the range of history covered by the summary
+func summaryHeader(dateLayout string) string {
+	if summaryBaseBranchFlag != "" {
+		branch := summaryBranchFlag
+		if branch == "" {
+			branch = "HEAD"
+			if name, detached, err := git.CurrentBranch(); err == nil {
+				branch = name
+				if detached {
+					branch += " (detached HEAD)"
+				}
+			}
+		}
+		return fmt.Sprintf("Git Statistics: %s vs %s", branch, summaryBaseBranchFlag)
+	}
+
+	header := summaryPeriod(dateLayout)
+	if summaryBranchFlag != "" {
+		header += " on " + summaryBranchFlag
+	}
+	return "Git Statistics: " + header
+}
+
+// summaryPeriod describes the time window of a summary that isn't
+// comparing branches
+func summaryPeriod(dateLayout string) string {
+	if summarySinceFlag != "" || summaryUntilFlag != "" {
+		return summaryRangeLabel(dateLayout)
+	}
+
+	if daysFlag >= 365*10 || daysFlag == 0 {
+		return "Complete repository history"
+	}
+
+	return fmt.Sprintf("Last %d days (%s to %s)",
+		daysFlag,
+		time.Now().AddDate(0, 0, -daysFlag).Format(dateLayout),
+		time.Now().Format(dateLayout))
+}
+
+// parseSummaryDate parses a --since or --until value. A bare date is read in
+// local time; with endOfDay it means the last second of that day, so
+// "--until 2024-01-14" includes commits made on the 14th.
+func parseSummaryDate(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC3339", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return t, nil
+}
+
+// summaryDateRange resolves --since and --until into a window. A missing
+// --since starts at the beginning of history and a missing --until ends now.
+func summaryDateRange(sinceValue, untilValue string, now time.Time) (time.Time, time.Time, error) {
+	since, until := time.Unix(0, 0), now
+	var err error
+	if sinceValue != "" {
+		if since, err = parseSummaryDate(sinceValue, false); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("--since: %w", err)
+		}
+	}
+	if untilValue != "" {
+		if until, err = parseSummaryDate(untilValue, true); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("--until: %w", err)
+		}
+	}
+	if !since.Before(until) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--since (%s) must be before --until (%s)",
+			since.Format(time.RFC3339), until.Format(time.RFC3339))
+	}
+	return since, until, nil
+}
+
+// summaryRangeLabel describes the --since/--until window, e.g.
+// "2024-01-01 to 2024-01-14"
+func summaryRangeLabel(dateLayout string) string {
+	until := "now"
+	if t, err := parseSummaryDate(summaryUntilFlag, true); err == nil {
+		until = t.Format(dateLayout)
+	}
+	since, err := parseSummaryDate(summarySinceFlag, false)
+	if err != nil {
+		return "history up to " + until
+	}
+	return since.Format(dateLayout) + " to " + until
+}
+
+// summaryWidth returns the width of the terminal out writes to, or false when
+// out isn't a terminal or its size can't be determined, e.g. when piped, in CI
+// or when the output is captured
+func summaryWidth(out io.Writer) (int, bool) {
+	f, ok := out.(*os.File)
+	if !ok {
+		return 0, false
+	}
+	if w, _, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+		return w, true
+	}
+	return 0, false
+}
 
-	// Get terminal width for better formatting
-	width := 80
-	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
-		width = w
+// formatSummary combines all parts into a complete summary, in boxes sized to
+// the terminal behind out or as plain sections when there is no terminal to
+// size them to
+func formatSummary(out io.Writer, stats, commits, aiInsights, header string, showHistory bool) string {
+	width, ok := summaryWidth(out)
+	if !ok {
+		return formatPlainSummary(stats, commits, aiInsights, header, showHistory)
 	}
 
+	var result strings.Builder
+
 	// Create styled boxes
 	boxStylePrimary := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
@@ -407,15 +787,7 @@ func formatSummary(stats, commits, aiInsights string, days int, showHistory bool
 		Bold(true)
 
 	// Statistics section with combined date range and header
-	var statsHeader string
-	if days >= 365*10 || days == 0 {
-		statsHeader = subHeaderStyle.Render("Git Statistics: Complete repository history")
-	} else {
-		statsHeader = subHeaderStyle.Render(fmt.Sprintf("Git Statistics: Last %d days (%s to %s)",
-			days,
-			time.Now().AddDate(0, 0, -days).Format("2006-01-02"),
-			time.Now().Format("2006-01-02")))
-	}
+	statsHeader := subHeaderStyle.Render(header)
 	result.WriteString(statsHeader + "\n")
 	result.WriteString(boxStylePrimary.Render(stats))
 	result.WriteString("\n\n")
@@ -438,6 +810,28 @@ func formatSummary(stats, commits, aiInsights string, days int, showHistory bool
 	return result.String()
 }
 
+// formatPlainSummary lays the summary out as underlined sections without
+// boxes or a fixed width, so piped output isn't wrapped at an assumed size
+func formatPlainSummary(stats, commits, aiInsights, header string, showHistory bool) string {
+	var result strings.Builder
+
+	writeSection := func(title, body string) {
+		result.WriteString(title + "\n")
+		result.WriteString(strings.Repeat("-", utf8.RuneCountInString(title)) + "\n")
+		result.WriteString(strings.TrimRight(body, "\n") + "\n\n")
+	}
+
+	writeSection(header, stats)
+	if aiInsights != "" {
+		writeSection("AI Insights", aiInsights)
+	}
+	if showHistory {
+		writeSection("Commit History", commits)
+	}
+
+	return strings.TrimRight(result.String(), "\n")
+}
+
 // Format the stats sections in a more visually appealing way
 func formatStatsForDisplay(stats map[string]interface{}) string {
 	var result strings.Builder
@@ -540,6 +934,71 @@ func formatStatsForDisplay(stats map[string]interface{}) string {
 	return result.String()
 }
 
+// formatFocusForDisplay renders the local focus area heuristic for the stats box
+func formatFocusForDisplay(commits []history.CommitInfo) string {
+	focusLine := history.FormatFocusAreas("Focus", history.CalculateFocusAreas(commits, 4))
+	typesLine := history.FormatFocusAreas("Types", history.CalculateTypeDistribution(commits, 4))
+	if focusLine == "" && typesLine == "" {
+		return ""
+	}
+
+	var result strings.Builder
+	result.WriteString("\n")
+	result.WriteString(color.New(color.FgHiYellow, color.Bold).Sprint("🎯 Focus Areas:\n"))
+	if focusLine != "" {
+		result.WriteString(focusLine + "\n")
+	}
+	if typesLine != "" {
+		result.WriteString(typesLine + "\n")
+	}
+
+	return result.String()
+}
+
+// maxListedReverts caps how many reverts the rework section names
+const maxListedReverts = 5
+
+// formatReworkForDisplay reports the reverts among the commits, a sign of
+// churn, or nothing when there are none
+func formatReworkForDisplay(commits []history.CommitInfo) string {
+	reverts := history.FindReverts(commits)
+	if len(reverts) == 0 {
+		return ""
+	}
+
+	var result strings.Builder
+	result.WriteString("\n")
+	result.WriteString(color.New(color.FgHiYellow, color.Bold).Sprint("🔁 Rework:\n"))
+	result.WriteString(fmt.Sprintf("Reverts: %d of %d commits (%.0f%%)\n",
+		len(reverts), len(commits), float64(len(reverts))/float64(len(commits))*100))
+
+	for i, revert := range reverts {
+		if i == maxListedReverts {
+			result.WriteString(fmt.Sprintf("  ... and %d more\n", len(reverts)-maxListedReverts))
+			break
+		}
+
+		undone := revert.RevertedSubject
+		if revert.RevertedHash != "" {
+			undone = strings.TrimSpace(abbrevHash(revert.RevertedHash) + " " + undone)
+		}
+		if undone == "" {
+			undone = "an unnamed commit"
+		}
+		result.WriteString(fmt.Sprintf("  %s undid %s\n", color.HiBlackString(abbrevHash(revert.Hash)), undone))
+	}
+
+	return result.String()
+}
+
+// abbrevHash shortens a commit hash to the 8 characters the summary shows
+func abbrevHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
 // safeGetValue safely extracts a value from a map, returning defaultValue if nil or not found
 func safeGetValue(m map[string]interface{}, key string, defaultValue string) string {
 	if val, ok := m[key]; ok && val != nil {
@@ -548,10 +1007,11 @@ func safeGetValue(m map[string]interface{}, key string, defaultValue string) str
 	return defaultValue
 }
 
-// exportSummary exports the summary to a file in the requested format
-func exportSummary(summary, format string) error {
-	// Determine output filename
-	timestamp := time.Now().Format("2006-01-02")
+// exportSummary exports the summary to a file in the requested format. The
+// SVG card, CSV and JSON are built from the commits rather than the summary text.
+func exportSummary(summary string, commits []history.CommitInfo, insight, format, dateLayout string) error {
+	// Determine output filename, keeping separators like "/" out of the name
+	timestamp := filenameSafeDate(time.Now(), dateLayout)
 	var filename string
 
 	// Convert ANSI color codes to appropriate format
@@ -570,15 +1030,43 @@ func exportSummary(summary, format string) error {
 		filename = fmt.Sprintf("git-summary-%s.html", timestamp)
 		return os.WriteFile(filename, []byte(convertToHTML(plainSummary)), 0644)
 
+	case "csv":
+		filename = fmt.Sprintf("git-summary-%s.csv", timestamp)
+		data, err := summaryCSV(commits)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filename, data, 0644)
+
+	case "json":
+		filename = fmt.Sprintf("git-summary-%s.json", timestamp)
+		data, err := summaryJSON(commits, insight, summaryHeader(dateLayout))
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filename, append(data, '\n'), 0644)
+
+	case "svg":
+		filename = fmt.Sprintf("git-summary-%s.svg", timestamp)
+		return os.WriteFile(filename, []byte(renderSummaryCard(commits, summaryHeader(dateLayout))), 0644)
+
 	default:
 		return fmt.Errorf("unsupported export format: %s", format)
 	}
 }
 
+// filenameSafeDate formats a date for use in a file name
+func filenameSafeDate(t time.Time, layout string) string {
+	replacer := strings.NewReplacer("/", "-", "\\", "-", ":", "-", " ", "_")
+	return replacer.Replace(t.Format(layout))
+}
+
+// ansiEscapeRegex matches ANSI escape sequences such as color codes
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
 // stripANSIColors removes ANSI color codes from a string
 func stripANSIColors(s string) string {
-	// Simple regex to remove ANSI color codes
-	return color.New().SprintFunc()(s)
+	return ansiEscapeRegex.ReplaceAllString(s, "")
 }
 
 // convertToMarkdown converts the summary to Markdown format
@@ -592,7 +1080,7 @@ func convertToMarkdown(summary string) string {
 			result.WriteString(line + "\n")
 		} else if strings.HasPrefix(line, "📊 Git Activity Summary") {
 			result.WriteString("# " + strings.TrimPrefix(line, "📊 ") + "\n")
-		} else if strings.Contains(line, "Last") && strings.Contains(line, "days") {
+		} else if strings.HasPrefix(line, "Git Statistics:") {
 			result.WriteString("*" + line + "*\n\n")
 		} else {
 			result.WriteString(line + "\n")
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/AccursedGalaxy/noidea/internal/history"
+)
+
+// cardLanguageColors are the bar colors for the top languages, in rank order
+var cardLanguageColors = []string{"#58a6ff", "#3fb950", "#d29922", "#f778ba"}
+
+// renderSummaryCard draws the key statistics of a summary as a self-contained
+// SVG "stats card" for READMEs and social posts
+func renderSummaryCard(commits []history.CommitInfo, header string) string {
+	added, removed := 0, 0
+	for _, commit := range commits {
+		added += commit.Stats.Insertions
+		removed += commit.Stats.Deletions
+	}
+	languages := history.CalculateLanguages(commits, len(cardLanguageColors))
+
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="495" height="210" viewBox="0 0 495 210" role="img" aria-label="Git activity summary">` + "\n")
+	b.WriteString(`  <style>
+    .title { font: 600 18px 'Segoe UI', Ubuntu, sans-serif; fill: #58a6ff; }
+    .subtitle { font: 400 12px 'Segoe UI', Ubuntu, sans-serif; fill: #8b949e; }
+    .label { font: 400 14px 'Segoe UI', Ubuntu, sans-serif; fill: #c9d1d9; }
+    .value { font: 600 14px 'Segoe UI', Ubuntu, sans-serif; fill: #f0f6fc; }
+    .lang { font: 400 11px 'Segoe UI', Ubuntu, sans-serif; fill: #c9d1d9; }
+  </style>` + "\n")
+	b.WriteString(`  <rect x="0.5" y="0.5" width="494" height="209" rx="6" fill="#0d1117" stroke="#30363d"/>` + "\n")
+	b.WriteString(`  <text x="25" y="35" class="title">Git Activity</text>` + "\n")
+	fmt.Fprintf(&b, "  <text x=\"25\" y=\"55\" class=\"subtitle\">%s</text>\n",
+		html.EscapeString(strings.TrimPrefix(header, "Git Statistics: ")))
+
+	rows := []struct{ label, value string }{
+		{"Commits", fmt.Sprintf("%d", len(commits))},
+		{"Lines changed", fmt.Sprintf("+%d / -%d", added, removed)},
+		{"Longest streak", pluralDays(history.LongestStreak(commits))},
+	}
+	for i, row := range rows {
+		y := 85 + i*25
+		fmt.Fprintf(&b, "  <text x=\"25\" y=\"%d\" class=\"label\">%s</text>\n", y, row.label)
+		fmt.Fprintf(&b, "  <text x=\"200\" y=\"%d\" class=\"value\">%s</text>\n", y, html.EscapeString(row.value))
+	}
+
+	// Top languages as one stacked bar with a legend underneath
+	if len(languages) > 0 {
+		total := 0.0
+		for _, language := range languages {
+			total += language.Percent
+		}
+
+		x := 25.0
+		for i, language := range languages {
+			width := language.Percent / total * 445
+			fmt.Fprintf(&b, "  <rect x=\"%.1f\" y=\"160\" width=\"%.1f\" height=\"8\" fill=\"%s\"/>\n", x, width, cardLanguageColors[i])
+			x += width
+		}
+		for i, language := range languages {
+			lx := 25 + i*112
+			fmt.Fprintf(&b, "  <circle cx=\"%d\" cy=\"186\" r=\"4\" fill=\"%s\"/>\n", lx+4, cardLanguageColors[i])
+			fmt.Fprintf(&b, "  <text x=\"%d\" y=\"190\" class=\"lang\">%s %.0f%%</text>\n", lx+12, html.EscapeString(language.Name), language.Percent)
+		}
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// pluralDays formats a day count, e.g. "1 day" or "3 days"
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+
+	"github.com/AccursedGalaxy/noidea/internal/history"
+)
+
+// summaryCSVHeader names the CSV columns. Spreadsheets and scripts refer to
+// them, so only add to them.
+var summaryCSVHeader = []string{"date", "day_of_week", "hour_range", "commits", "lines_added", "lines_removed", "files_changed"}
+
+// csvBucket is one row of the CSV: the commits of one day in one of the
+// summary's hour ranges
+type csvBucket struct {
+	date      string
+	weekday   string
+	hourStart int // First hour of the range, for ordering
+	hourRange string
+
+	commits, added, removed, files int
+}
+
+// summaryCSV formats the commits as a tidy CSV with one row per day and
+// hour range, so commit-time patterns can be tracked in a spreadsheet.
+// Rows are in chronological order.
+func summaryCSV(commits []history.CommitInfo) ([]byte, error) {
+	buckets := make(map[string]*csvBucket)
+	for _, commit := range commits {
+		date := commit.Timestamp.Format("2006-01-02")
+		hourStart := commit.Timestamp.Hour() / 4 * 4
+		key := date + "/" + strconv.Itoa(hourStart)
+
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &csvBucket{
+				date:      date,
+				weekday:   commit.Timestamp.Weekday().String(),
+				hourStart: hourStart,
+				hourRange: hourRangeLabel(commit.Timestamp.Hour()),
+			}
+			buckets[key] = bucket
+		}
+		bucket.commits++
+		bucket.added += commit.Stats.Insertions
+		bucket.removed += commit.Stats.Deletions
+		bucket.files += commit.Stats.FilesChanged
+	}
+
+	rows := make([]*csvBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		rows = append(rows, bucket)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].date != rows[j].date {
+			return rows[i].date < rows[j].date
+		}
+		return rows[i].hourStart < rows[j].hourStart
+	})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(summaryCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.date,
+			row.weekday,
+			row.hourRange,
+			strconv.Itoa(row.commits),
+			strconv.Itoa(row.added),
+			strconv.Itoa(row.removed),
+			strconv.Itoa(row.files),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/history"
+)
+
+func TestSummaryCSV(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 9, 30, 0, 0, time.Local)
+	commits := []history.CommitInfo{
+		{Hash: "c", Timestamp: monday.Add(time.Hour), Stats: history.CommitStats{FilesChanged: 2, Insertions: 30, Deletions: 5}},
+		{Hash: "b", Timestamp: monday, Stats: history.CommitStats{FilesChanged: 1, Insertions: 4, Deletions: 0}},
+		{Hash: "a", Timestamp: monday.Add(-24*time.Hour + 12*time.Hour), Stats: history.CommitStats{FilesChanged: 1, Insertions: 1, Deletions: 1}},
+	}
+
+	data, err := summaryCSV(commits)
+	if err != nil {
+		t.Fatalf("summaryCSV() error = %v", err)
+	}
+
+	want := `date,day_of_week,hour_range,commits,lines_added,lines_removed,files_changed
+2025-03-02,Sunday,Late PM (20-24),1,1,1,1
+2025-03-03,Monday,Work Hours (8-12),2,34,5,3
+`
+	if got := string(data); got != want {
+		t.Errorf("summaryCSV() =\n%s\nwant\n%s", got, want)
+	}
+
+	if empty, _ := summaryCSV(nil); strings.Count(string(empty), "\n") != 1 {
+		t.Errorf("summaryCSV(nil) should only have the header, got %q", empty)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/history"
+)
+
+// summaryReport is the JSON form of a summary. Field names are part of the
+// output format that scripts depend on, so only add to them.
+type summaryReport struct {
+	Period             string          `json:"period"`
+	GeneratedAt        time.Time       `json:"generated_at"`
+	TotalCommits       int             `json:"total_commits"`
+	UniqueAuthors      int             `json:"unique_authors"`
+	LinesAdded         int             `json:"lines_added"`
+	LinesRemoved       int             `json:"lines_removed"`
+	FilesChanged       int             `json:"files_changed"`
+	CommitsByDay       map[string]int  `json:"commits_by_day"`
+	CommitsByHourRange map[string]int  `json:"commits_by_hour_range"`
+	Commits            []summaryCommit `json:"commits"`
+	RevertCommits      int             `json:"revert_commits"`
+	RevertedCommits    []string        `json:"reverted_commits"` // Hashes named by the reverts' messages
+	Insight            string          `json:"insight"`
+}
+
+// summaryCommit is a single commit in a summaryReport
+type summaryCommit struct {
+	Hash         string    `json:"hash"`
+	Author       string    `json:"author"`
+	Date         time.Time `json:"date"`
+	Message      string    `json:"message"`
+	LinesAdded   int       `json:"lines_added"`
+	LinesRemoved int       `json:"lines_removed"`
+	FilesChanged int       `json:"files_changed"`
+}
+
+// buildSummaryReport collects the summary statistics, commits and AI insight
+func buildSummaryReport(commits []history.CommitInfo, insight, header string) summaryReport {
+	report := summaryReport{
+		Period:             strings.TrimPrefix(header, "Git Statistics: "),
+		GeneratedAt:        time.Now(),
+		TotalCommits:       len(commits),
+		CommitsByDay:       make(map[string]int),
+		CommitsByHourRange: make(map[string]int),
+		Commits:            make([]summaryCommit, 0, len(commits)),
+		Insight:            stripANSIColors(insight),
+	}
+
+	authors := make(map[string]bool)
+	for _, commit := range commits {
+		authors[commit.Author] = true
+		report.LinesAdded += commit.Stats.Insertions
+		report.LinesRemoved += commit.Stats.Deletions
+		report.FilesChanged += commit.Stats.FilesChanged
+		report.CommitsByDay[commit.Timestamp.Weekday().String()]++
+		report.CommitsByHourRange[hourRangeLabel(commit.Timestamp.Hour())]++
+
+		report.Commits = append(report.Commits, summaryCommit{
+			Hash:         commit.Hash,
+			Author:       commit.Author,
+			Date:         commit.Timestamp,
+			Message:      commit.Message,
+			LinesAdded:   commit.Stats.Insertions,
+			LinesRemoved: commit.Stats.Deletions,
+			FilesChanged: commit.Stats.FilesChanged,
+		})
+	}
+	report.UniqueAuthors = len(authors)
+
+	reverts := history.FindReverts(commits)
+	report.RevertCommits = len(reverts)
+	report.RevertedCommits = make([]string, 0, len(reverts))
+	for _, revert := range reverts {
+		if revert.RevertedHash != "" {
+			report.RevertedCommits = append(report.RevertedCommits, revert.RevertedHash)
+		}
+	}
+
+	return report
+}
+
+// summaryJSON encodes a summary report as indented JSON
+func summaryJSON(commits []history.CommitInfo, insight, header string) ([]byte, error) {
+	return json.MarshalIndent(buildSummaryReport(commits, insight, header), "", "  ")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/history"
+)
+
+func TestSummaryJSON(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 9, 30, 0, 0, time.Local)
+	commits := []history.CommitInfo{
+		{Hash: "b", Author: "Ana", Timestamp: monday, Message: "feat: add login",
+			Stats: history.CommitStats{FilesChanged: 2, Insertions: 30, Deletions: 5}},
+		{Hash: "a", Author: "Ben", Timestamp: monday.Add(-24 * time.Hour), Message: "fix: typo",
+			Stats: history.CommitStats{FilesChanged: 1, Insertions: 1, Deletions: 1}},
+	}
+
+	data, err := summaryJSON(commits, "Nice work", "Git Statistics: Last 7 days")
+	if err != nil {
+		t.Fatalf("summaryJSON() error = %v", err)
+	}
+
+	var report map[string]interface{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"period":         "Last 7 days",
+		"total_commits":  float64(2),
+		"unique_authors": float64(2),
+		"lines_added":    float64(31),
+		"lines_removed":  float64(6),
+		"files_changed":  float64(3),
+		"insight":        "Nice work",
+	}
+	for key, value := range want {
+		if report[key] != value {
+			t.Errorf("%s = %v, want %v", key, report[key], value)
+		}
+	}
+
+	if byDay := report["commits_by_day"].(map[string]interface{}); byDay["Monday"] != float64(1) || byDay["Sunday"] != float64(1) {
+		t.Errorf("commits_by_day = %v", byDay)
+	}
+	if byHour := report["commits_by_hour_range"].(map[string]interface{}); byHour["Work Hours (8-12)"] != float64(2) {
+		t.Errorf("commits_by_hour_range = %v", byHour)
+	}
+	if list := report["commits"].([]interface{}); len(list) != 2 || list[0].(map[string]interface{})["hash"] != "b" {
+		t.Errorf("commits = %v", list)
+	}
+}
+
+func TestStatsOnlyJSONHasNoReportFields(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	summaryJSONFlag = true
+	defer func() { summaryJSONFlag = false }()
+
+	commits := []history.CommitInfo{
+		{Hash: "a", Author: "Ana", Timestamp: time.Date(2025, 3, 3, 9, 30, 0, 0, time.Local), Message: "feat: add login"},
+	}
+
+	var out bytes.Buffer
+	runStatsOnlySummary(&out, commits, config.DefaultConfig())
+
+	var stats map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	for _, key := range []string{"commits", "insight", "period", "generated_at"} {
+		if _, ok := stats[key]; ok {
+			t.Errorf("stats-only JSON should not have %q:\n%s", key, out.String())
+		}
+	}
+	if stats["total_commits"] != float64(1) {
+		t.Errorf("total_commits = %v, want 1", stats["total_commits"])
+	}
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/history"
+)
+
+func TestSummaryDateRange(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.Local)
+
+	since, until, err := summaryDateRange("2024-01-01", "2024-01-14", now)
+	if err != nil {
+		t.Fatalf("summaryDateRange() error = %v", err)
+	}
+	if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local); !since.Equal(want) {
+		t.Errorf("since = %v, want %v", since, want)
+	}
+	// A bare --until date includes the whole day
+	if want := time.Date(2024, 1, 14, 23, 59, 59, 0, time.Local); !until.Equal(want) {
+		t.Errorf("until = %v, want %v", until, want)
+	}
+
+	since, until, err = summaryDateRange("2024-01-10T09:00:00Z", "", now)
+	if err != nil {
+		t.Fatalf("summaryDateRange() error = %v", err)
+	}
+	if want := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC); !since.Equal(want) || !until.Equal(now) {
+		t.Errorf("range = %v - %v, want %v - %v", since, until, want, now)
+	}
+
+	for _, tc := range []struct{ since, until string }{
+		{"2024-01-14", "2024-01-01"},
+		{"2024-01-14", "2024-01-13"},
+		{"01/14/2024", ""},
+		{"", "yesterday"},
+	} {
+		if _, _, err := summaryDateRange(tc.since, tc.until, now); err == nil {
+			t.Errorf("summaryDateRange(%q, %q) expected an error", tc.since, tc.until)
+		}
+	}
+}
+
+func TestStatsOnlyJSONKeys(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	summaryJSONFlag = true
+	defer func() { summaryJSONFlag = false }()
+
+	monday := time.Date(2025, 3, 3, 9, 30, 0, 0, time.Local)
+	commits := []history.CommitInfo{
+		{Hash: "b", Author: "Ana", Timestamp: monday, Message: "feat: add login",
+			Files: []string{"login.go"}, Stats: history.CommitStats{FilesChanged: 1, Insertions: 30, Deletions: 5}},
+		{Hash: "a", Author: "Ben", Timestamp: monday.Add(-2 * time.Hour), Message: "fix: typo"},
+	}
+
+	var out bytes.Buffer
+	runStatsOnlySummary(&out, commits, config.DefaultConfig())
+
+	var stats map[string]json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	keys := make([]string, 0, len(stats))
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Scripts collecting CI metrics read these keys, so they only change on purpose
+	want := []string{
+		"author_distribution", "commits_by_day", "commits_by_hour", "revert_commits",
+		"time_span_hours", "total_commits", "total_deletions", "total_files_changed",
+		"total_insertions", "unique_authors",
+	}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("stats-only JSON keys = %v, want %v", keys, want)
+	}
+}
+
+func TestFormatPlainSummary(t *testing.T) {
+	summary := formatPlainSummary("Total Commits: 3\n", "- fix: a\n", "• Nice work", "Git Statistics", false)
+
+	want := "Git Statistics\n--------------\nTotal Commits: 3\n\nAI Insights\n-----------\n• Nice work"
+	if summary != want {
+		t.Errorf("formatPlainSummary() = %q, want %q", summary, want)
+	}
+	if strings.Contains(summary, "fix: a") {
+		t.Error("commit history should only be included when requested")
+	}
+}
+
+func TestSummaryOutputToWriter(t *testing.T) {
+	// Captured output isn't a terminal, so it gets the plain layout
+	var out bytes.Buffer
+	summary := formatSummary(&out, "Total Commits: 3\n", "", "", "Git Statistics", false)
+	if want := formatPlainSummary("Total Commits: 3\n", "", "", "Git Statistics", false); summary != want {
+		t.Errorf("formatSummary() = %q, want the plain layout %q", summary, want)
+	}
+
+	printSummaryJSON(&out, nil, "", "Git Statistics: Last 7 days")
+	var report summaryReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("printSummaryJSON() wrote invalid JSON: %v\n%s", err, out.String())
+	}
+	if report.Period != "Last 7 days" {
+		t.Errorf("period = %q, want %q", report.Period, "Last 7 days")
+	}
+}
+
+func TestStripANSIColors(t *testing.T) {
+	colored := "\x1b[1;36m📊 Summary\x1b[0m: \x1b[32m12 commits\x1b[0m"
+	if got := stripANSIColors(colored); got != "📊 Summary: 12 commits" {
+		t.Errorf("stripANSIColors() = %q", got)
+	}
+}
+
+func TestExportSummaryWritesPlainText(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	colored := "\x1b[1;36m📊 Summary\x1b[0m\n\x1b[32m12 commits\x1b[0m"
+	for _, format := range []string{"text", "markdown", "html"} {
+		if err := exportSummary(colored, nil, "", format, "2006-01-02"); err != nil {
+			t.Fatalf("exportSummary(%s) error = %v", format, err)
+		}
+	}
+
+	files, _ := filepath.Glob("git-summary-*")
+	if len(files) != 3 {
+		t.Fatalf("exported %d files, want 3: %v", len(files), files)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "\x1b") || strings.Contains(string(data), "[32m") {
+			t.Errorf("%s contains escape sequences:\n%s", file, data)
+		}
+	}
+}
+
+func TestSummaryBranchHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := chdirTestRepo(t)
+	runGit(t, repo, "checkout", "-q", "-b", "main")
+	// A file with the branch's name must not be mistaken for a path
+	commitFile(t, repo, "develop", "notes\n", "docs: add develop notes")
+	runGit(t, repo, "checkout", "-q", "-b", "develop")
+	commitFile(t, repo, "a.go", "package a\n", "feat: add a")
+	commitFile(t, repo, "b.go", "package b\n", "feat: add b")
+	runGit(t, repo, "checkout", "-q", "main")
+
+	defer func() {
+		summaryBranchFlag, allHistoryFlag, statsOnlyFlag, summaryJSONFlag, daysFlag = "", false, false, false, 7
+	}()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	defer rootCmd.SetOut(nil)
+	rootCmd.SetArgs([]string{"summary", "--branch", "develop", "--all", "--stats-only", "--json"})
+	defer rootCmd.SetArgs(nil)
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if stats["total_commits"] != float64(3) {
+		t.Errorf("total_commits = %v, want the 3 commits on develop while main is checked out", stats["total_commits"])
+	}
+
+	// suggest --history-branch reads its context the same way
+	collector, err := history.NewHistoryCollector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits, err := collector.GetCommitHistory(history.HistoryFilter{Branch: "develop", Count: 2})
+	if err != nil {
+		t.Fatalf("GetCommitHistory(develop) error = %v", err)
+	}
+	if len(commits) != 2 || commits[0].Message != "feat: add b" || commits[1].Message != "feat: add a" {
+		t.Errorf("develop history = %+v", commits)
+	}
+}
+
+func TestRefreshInsightAlias(t *testing.T) {
+	defer func() { refreshInsightFlag = false }()
+
+	flag := summaryCmd.Flags().Lookup("refresh-insight")
+	if flag == nil || !flag.Hidden {
+		t.Fatal("--refresh-insight should remain as a hidden alias")
+	}
+	if err := summaryCmd.Flags().Set("refresh-insight", "true"); err != nil || !refreshInsightFlag {
+		t.Errorf("--refresh-insight did not set the refresh flag: %v", err)
+	}
+}
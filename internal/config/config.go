@@ -3,34 +3,147 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/BurntSushi/toml"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+	"github.com/AccursedGalaxy/noidea/internal/personality"
 	"github.com/AccursedGalaxy/noidea/internal/secure"
 )
 
+// ProviderMock is the offline provider that answers with canned responses
+// built from the diff, for demos, CI and development without an API key
+const ProviderMock = "mock"
+
+// LLMFallback is a provider/model pair to try when the primary provider fails
+type LLMFallback struct {
+	Provider string `json:"provider" toml:"provider"`
+	Model    string `json:"model" toml:"model"`
+}
+
 // Config represents the application configuration
 type Config struct {
 	// LLM contains settings for the AI language model integration
 	LLM struct {
-		Enabled     bool    `json:"enabled"`
-		Provider    string  `json:"provider"`    // "xai", "openai", "deepseek"
-		APIKey      string  `json:"api_key"`     // API key for the language model provider
-		Model       string  `json:"model"`       // Model name to use
-		Temperature float64 `json:"temperature"` // Temperature for AI responses (0.0-1.0)
-	} `json:"llm"`
+		Enabled           bool          `json:"enabled" toml:"enabled"`
+		Provider          string        `json:"provider" toml:"provider"`                                         // "xai", "openai", "deepseek", "mock"
+		APIKey            string        `json:"api_key" toml:"api_key"`                                           // API key for the language model provider
+		Model             string        `json:"model" toml:"model"`                                               // Model name to use
+		Temperature       float64       `json:"temperature" toml:"temperature"`                                   // Temperature for AI responses (0.0-1.0)
+		Fallbacks         []LLMFallback `json:"fallbacks,omitempty" toml:"fallbacks,omitempty"`                   // Ordered providers to try if the primary fails
+		MaxRetries        int           `json:"max_retries" toml:"max_retries"`                                   // Retries after rate limits or server errors (0 = no retry)
+		MaxContextTokens  int           `json:"max_context_tokens" toml:"max_context_tokens"`                     // Context window in tokens (0 = known size for the model)
+		Keychain          string        `json:"keychain,omitempty" toml:"keychain,omitempty"`                     // macOS keychain for API keys (empty = default keychain)
+		KeyringCollection string        `json:"keyring_collection,omitempty" toml:"keyring_collection,omitempty"` // Linux Secret Service collection for API keys (empty = login)
+
+		// ProviderModels pins the model used with a provider ("openai") when
+		// no model is configured, instead of the provider's default
+		ProviderModels map[string]string `json:"provider_models,omitempty" toml:"provider_models,omitempty"`
+	} `json:"llm" toml:"llm"`
 
 	// Moai contains settings for the Moai feedback system
 	Moai struct {
-		UseLint         bool   `json:"use_lint"`         // Include linting feedback
-		FacesMode       string `json:"faces_mode"`       // "random", "sequential", "mood"
-		Personality     string `json:"personality"`      // Selected personality
-		PersonalityFile string `json:"personality_file"` // Custom personality definitions
-	} `json:"moai"`
+		UseLint         bool   `json:"use_lint" toml:"use_lint"`                 // Include linting feedback
+		FacesMode       string `json:"faces_mode" toml:"faces_mode"`             // "random", "sequential", "mood"
+		Personality     string `json:"personality" toml:"personality"`           // Selected personality
+		PersonalityFile string `json:"personality_file" toml:"personality_file"` // Custom personality definitions
+		HistoryBodies   bool   `json:"history_bodies" toml:"history_bodies"`     // Include commit bodies in suggestion history
+		DateFormat      string `json:"date_format" toml:"date_format"`           // Go layout or preset: "iso", "us", "eu", "uk"
+		BlockSecrets    bool   `json:"block_secrets" toml:"block_secrets"`       // Refuse to suggest when the staged diff contains secrets
+		NoteTests       bool   `json:"note_tests" toml:"note_tests"`             // Mention new or changed tests in suggested messages
+		ForceOnlyType   bool   `json:"force_only_type" toml:"force_only_type"`   // Always type docs-only changes "docs" and test-only changes "test"
+		ApprovalRetries int    `json:"approval_retries" toml:"approval_retries"` // Invalid answers allowed at approval prompts (0 = no retry)
+		LogSuggestions  bool   `json:"log_suggestions" toml:"log_suggestions"`   // Record suggestions and their outcomes in ~/.noidea/suggestions.jsonl
+		Telemetry       bool   `json:"telemetry" toml:"telemetry"`               // Keep local-only usage metrics in ~/.noidea/telemetry.jsonl
+
+		// PersonalitySchedule maps weekdays ("friday") or repositories
+		// ("repo:<name>") to the personality used there instead of Personality
+		PersonalitySchedule map[string]string `json:"personality_schedule,omitempty" toml:"personality_schedule,omitempty"`
+
+		// TypeAliases remaps commit types in suggestions (e.g. "build": "chore")
+		// and AllowedTypes restricts them to a fixed vocabulary
+		TypeAliases  map[string]string `json:"type_aliases,omitempty" toml:"type_aliases,omitempty"`
+		AllowedTypes []string          `json:"allowed_types,omitempty" toml:"allowed_types,omitempty"`
+
+		// Footers are trailers added to every suggested message, e.g.
+		// "Reviewed-by": "Jane <jane@example.com>"
+		Footers map[string]string `json:"footers,omitempty" toml:"footers,omitempty"`
+
+		// SubjectPrefix is put before every suggested subject, e.g.
+		// "[{{.Ticket}}]" for a ticket key taken from the branch name
+		SubjectPrefix string `json:"subject_prefix,omitempty" toml:"subject_prefix,omitempty"`
+
+		// CommentChar starts comment lines dropped from suggestions; empty
+		// uses git's core.commentChar
+		CommentChar string `json:"comment_char,omitempty" toml:"comment_char,omitempty"`
+
+		// GerritChangeID appends a Gerrit "Change-Id: I<hash>" footer to suggestions
+		GerritChangeID bool `json:"gerrit_change_id" toml:"gerrit_change_id"`
+
+		// WithIssues adds open GitHub issue and PR titles to suggestion context
+		WithIssues bool `json:"with_issues" toml:"with_issues"`
+
+		// SmallDiffLines is the changed-line threshold below which a
+		// single-file diff gets a minimal prompt (0 disables the fast path)
+		SmallDiffLines int `json:"small_diff_lines" toml:"small_diff_lines"`
+
+		// MaxBodyBullets is the most bullet points in a suggested message's
+		// body (0 = subject line only)
+		MaxBodyBullets int `json:"max_body_bullets" toml:"max_body_bullets"`
+
+		// MaxSubjectLength is enforced on suggested subjects, splitting
+		// longer ones into the body (0 = not enforced)
+		MaxSubjectLength int `json:"max_subject_length" toml:"max_subject_length"`
+
+		// WrapBody is the column suggested bodies are wrapped at, as git
+		// convention asks (0 = no wrapping)
+		WrapBody int `json:"wrap_body" toml:"wrap_body"`
+
+		// MaxHistoryCommits caps how many commits a summary reads, so all
+		// history stays fast on large repositories (0 = no cap)
+		MaxHistoryCommits int `json:"max_history_commits" toml:"max_history_commits"`
+
+		// InsightCacheHours is how long a summary reuses an AI insight for
+		// the same commits, personality and model (0 = until they change)
+		InsightCacheHours int `json:"insight_cache_hours" toml:"insight_cache_hours"`
+
+		// Options passed to git when collecting the diff for suggestions.
+		// DiffAlgorithm is one of DiffAlgorithms, empty for git's default.
+		DiffAlgorithm    string `json:"diff_algorithm,omitempty" toml:"diff_algorithm,omitempty"`
+		FunctionContext  bool   `json:"function_context" toml:"function_context"`   // Show whole functions around each change
+		IgnoreWhitespace bool   `json:"ignore_whitespace" toml:"ignore_whitespace"` // Ignore whitespace-only changes such as reindents
+		ContextLines     int    `json:"context_lines" toml:"context_lines"`         // Unchanged lines around each change (0 = only changed lines)
+
+		// MaxLinesPerFile is how many diff lines of each file are sent to the
+		// model, unless --full-diff is used (0 = no limit)
+		MaxLinesPerFile int `json:"max_lines_per_file" toml:"max_lines_per_file"`
+
+		// MaxLineLength cuts longer diff lines, such as minified code, before
+		// they are sent to the model (0 = no limit)
+		MaxLineLength int `json:"max_line_length" toml:"max_line_length"`
+	} `json:"moai" toml:"moai"`
+
+	// Release contains settings for publishing release notes
+	Release struct {
+		Provider  string `json:"provider" toml:"provider"`     // "github" or "gitlab"
+		GitLabURL string `json:"gitlab_url" toml:"gitlab_url"` // GitLab instance for the gitlab provider
+		Timeout   int    `json:"timeout" toml:"timeout"`       // Seconds to wait for each GitHub or GitLab API response (0 = no limit)
+
+		// Personality writes AI release notes in its voice; empty keeps them professional
+		Personality string `json:"personality,omitempty" toml:"personality,omitempty"`
+	} `json:"release" toml:"release"`
+
+	// Policy is the repository policy applied on load, nil when there is none
+	Policy *RepoPolicy `json:"-" toml:"-"`
 }
 
 // DefaultConfig returns a default configuration
@@ -42,11 +155,27 @@ func DefaultConfig() Config {
 	cfg.LLM.Provider = "xai"
 	cfg.LLM.Model = "grok-2-1212"
 	cfg.LLM.Temperature = 0.7
+	cfg.LLM.MaxRetries = 3
 
 	// Moai settings
 	cfg.Moai.UseLint = false
 	cfg.Moai.FacesMode = "random"
 	cfg.Moai.Personality = "professional_sass"
+	cfg.Moai.DateFormat = "iso"
+	cfg.Moai.ApprovalRetries = 3
+	cfg.Moai.SmallDiffLines = 10
+	cfg.Moai.MaxBodyBullets = 4
+	cfg.Moai.WrapBody = 72
+	cfg.Moai.MaxHistoryCommits = 1000
+	cfg.Moai.InsightCacheHours = 24
+	cfg.Moai.ContextLines = 3
+	cfg.Moai.MaxLinesPerFile = 50
+	cfg.Moai.MaxLineLength = 500
+
+	// Release settings
+	cfg.Release.Provider = "github"
+	cfg.Release.GitLabURL = "https://gitlab.com"
+	cfg.Release.Timeout = 10
 
 	// Get home directory for default personality file path
 	homeDir, err := os.UserHomeDir()
@@ -60,9 +189,98 @@ func DefaultConfig() Config {
 	return cfg
 }
 
+// ConfigFile, when set, is the config file loaded and saved instead of
+// ~/.noidea/config.json (set by --config)
+var ConfigFile string
+
 // LoadConfig loads the configuration from the default location or environment variables
 // If the config file doesn't exist, it returns the default config
 func LoadConfig() Config {
+	return LoadConfigFrom(ConfigFile)
+}
+
+// LoadConfigFrom loads the configuration like LoadConfig, but reads the
+// given JSON or TOML file instead of the user's config file. An empty path
+// reads the user's config file.
+func LoadConfigFrom(path string) Config {
+	// A repository policy always wins over the user's own settings
+	return applyRepoPolicy(loadUserConfig(sourceTracker{}, true, path))
+}
+
+// OverrideModel switches the provider and model for a single run. Changing the
+// provider replaces the configured model and key, which belong to the old one,
+// with the provider's own; an empty argument keeps the configured value. The
+// resulting model, including one pinned in llm.provider_models, must still
+// satisfy the repository policy.
+func OverrideModel(cfg Config, provider, model string) (Config, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider != "" && provider != cfg.LLM.Provider {
+		switch provider {
+		case "xai", "openai", "deepseek", ProviderMock:
+		default:
+			return cfg, fmt.Errorf("unknown provider '%s' (use xai, openai, deepseek or mock)", provider)
+		}
+		if !cfg.Policy.AllowsProvider(provider) {
+			return cfg, fmt.Errorf("provider '%s' is not allowed by %s", provider, cfg.Policy.Path)
+		}
+		cfg.LLM.Provider = provider
+		cfg.LLM.Model = cfg.Policy.ResolveModel(ProviderModel(cfg, provider))
+		cfg.LLM.APIKey = resolveAPIKey(provider)
+		if !cfg.Policy.AllowsModel(cfg.LLM.Model) {
+			return cfg, fmt.Errorf("model '%s' is not allowed by %s", cfg.LLM.Model, cfg.Policy.Path)
+		}
+	}
+
+	if model = strings.TrimSpace(model); model != "" {
+		if !cfg.Policy.AllowsModel(model) {
+			return cfg, fmt.Errorf("model '%s' is not allowed by %s", model, cfg.Policy.Path)
+		}
+		cfg.LLM.Model = model
+	}
+
+	return cfg, nil
+}
+
+// ProviderModel returns the model to use with provider when none is configured:
+// the one pinned in llm.provider_models, the default model for the default
+// provider, or empty to let the engine pick the provider's default
+func ProviderModel(cfg Config, provider string) string {
+	if model := strings.TrimSpace(cfg.LLM.ProviderModels[strings.ToLower(provider)]); model != "" {
+		return model
+	}
+	if defaults := DefaultConfig(); provider == defaults.LLM.Provider {
+		return defaults.LLM.Model
+	}
+	return ""
+}
+
+// LoadUserConfig loads the user's own configuration with environment overrides
+// but without any repository policy, shared or project config. Use it when the
+// result will be saved back.
+func LoadUserConfig() Config {
+	return loadUserConfig(sourceTracker{}, false, ConfigFile)
+}
+
+// loadUserConfig implements LoadUserConfig, recording in sources which layer
+// set each setting. With layered, the config from NOIDEA_CONFIG_URL is applied
+// beneath the user's file and a project's .noidea.json on top of it. A
+// non-empty file replaces the user's config file, and no project file is used.
+func loadUserConfig(sources sourceTracker, layered bool, file string) Config {
+	// A project's own settings override the user's
+	withProject := func(cfg Config) Config {
+		if !layered || file != "" {
+			return cfg
+		}
+		return applyProjectConfig(cfg, sources)
+	}
+
+	// Environment variables are the last layer on every path
+	withEnv := func(cfg Config) Config {
+		overridden := applyEnvironmentOverrides(cfg)
+		sources.record(cfg, overridden, SourceEnv)
+		return overridden
+	}
+
 	// Start with default config
 	cfg := DefaultConfig()
 
@@ -71,46 +289,75 @@ func LoadConfig() Config {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Could not determine user home directory: %v\n", err)
 		// Continue with defaults
-		return applyEnvironmentOverrides(cfg)
+		return withEnv(cfg)
 	}
 
-	// Config directory path
-	configDir := filepath.Join(homeDir, ".noidea")
+	// An organization's shared config sits beneath the user's own file
+	var sharedModel string
+	if layered {
+		if data, path, ok := loadSharedConfig(homeDir); ok {
+			defaults := cfg
+			cfg.LLM.Model = ""
+			if err := unmarshalConfig(path, data, &cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Could not parse shared config: %v\n", err)
+				cfg = defaults
+			} else {
+				sharedModel = cfg.LLM.Model
+				ensureDefaults(&cfg)
+				sources.record(defaults, cfg, SourceShared)
+				sources.recordFile(path, data, SourceShared)
+			}
+		}
+	}
+	base := cfg
 
-	// Config file path
-	configFile := filepath.Join(configDir, "config.json")
+	// Config file path, JSON or TOML
+	configFile := file
+	if configFile == "" {
+		configFile = configFilePath(filepath.Join(homeDir, ".noidea"))
+	}
 
 	// Check if config file exists
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Info: No config file found at %s, using defaults\n", configFile)
-		// Check also for .toml format for backward compatibility
-		tomlConfigFile := filepath.Join(configDir, "config.toml")
-		if _, err := os.Stat(tomlConfigFile); os.IsNotExist(err) {
-			return applyEnvironmentOverrides(cfg)
+		if file != "" {
+			// A file that was asked for by name is most likely a typo
+			fmt.Fprintf(os.Stderr, "Warning: Config file %s does not exist, using defaults\n", configFile)
+		} else {
+			fmt.Fprintf(os.Stderr, "Info: No config file found at %s, using defaults\n", configFile)
 		}
-		configFile = tomlConfigFile
+		return withEnv(withProject(cfg))
 	}
 
 	// Read config file
 	data, err := os.ReadFile(configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Could not read config file %s: %v\n", configFile, err)
-		return applyEnvironmentOverrides(cfg)
+		return withEnv(withProject(cfg))
 	}
 
+	// A file that picks another provider without a model must not inherit the
+	// default provider's model; ensureDefaults fills it in for the provider
+	cfg.LLM.Model = ""
+
 	// Parse config based on file extension
-	if filepath.Ext(configFile) == ".toml" {
-		// Handle TOML format if needed
-		fmt.Fprintf(os.Stderr, "Warning: TOML format not fully supported yet\n")
-		// TODO: Implement TOML parsing
-	} else {
-		// Parse JSON
-		if err := json.Unmarshal(data, &cfg); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Could not parse config file %s: %v\n", configFile, err)
-			// Continue with defaults
-			return applyEnvironmentOverrides(DefaultConfig())
-		}
+	if err := unmarshalConfig(configFile, data, &cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not parse config file %s: %v\n", configFile, err)
+		// Continue with defaults and the shared config
+		return withEnv(withProject(base))
+	}
+	// The shared config's model still applies while its provider is kept
+	if cfg.LLM.Model == "" && cfg.LLM.Provider == base.LLM.Provider {
+		cfg.LLM.Model = sharedModel
 	}
+	sources.record(base, cfg, SourceFile)
+	sources.recordFile(configFile, data, SourceFile)
+	cfg = withProject(cfg)
+
+	// Point the system keyring at the configured keychain or collection
+	secure.SetKeyringOptions(secure.KeyringOptions{
+		Keychain:   cfg.LLM.Keychain,
+		Collection: cfg.LLM.KeyringCollection,
+	})
 
 	// Try to load API key from secure storage if it's not already set
 	// Note: This happens BEFORE environment variable overrides to prioritize secure storage
@@ -119,6 +366,7 @@ func LoadConfig() Config {
 		apiKey, err := secure.GetAPIKey(provider)
 		if err == nil && apiKey != "" {
 			cfg.LLM.APIKey = apiKey
+			sources["llm.api_key"] = SourceKeyring
 		}
 	}
 
@@ -141,12 +389,14 @@ func LoadConfig() Config {
 	}
 
 	// Ensure all fields are set properly
+	beforeDefaults := cfg
 	ensureDefaults(&cfg)
+	sources.record(beforeDefaults, cfg, SourceDefault)
 
 	// Apply environment variable overrides
 	// Note: In a future version, you might want to reverse this priority
 	// by having secure storage override environment variables
-	return applyEnvironmentOverrides(cfg)
+	return withEnv(cfg)
 }
 
 // applyEnvironmentOverrides applies environment variable settings to override config file values
@@ -157,9 +407,20 @@ func applyEnvironmentOverrides(cfg Config) Config {
 	}
 
 	if val := os.Getenv("NOIDEA_LLM_PROVIDER"); val != "" {
+		// The configured model belongs to the configured provider
+		if val != cfg.LLM.Provider {
+			cfg.LLM.Model = ProviderModel(cfg, val)
+		}
 		cfg.LLM.Provider = val
 	}
 
+	// NOIDEA_LLM_MOCK switches to the offline mock provider
+	if val := os.Getenv("NOIDEA_LLM_MOCK"); val == "true" || val == "1" || val == "yes" {
+		cfg.LLM.Enabled = true
+		cfg.LLM.Provider = ProviderMock
+		cfg.LLM.Model = ""
+	}
+
 	// API keys from multiple possible environment variables
 	if val := os.Getenv("NOIDEA_API_KEY"); val != "" {
 		cfg.LLM.APIKey = strings.TrimSpace(val)
@@ -196,6 +457,11 @@ func applyEnvironmentOverrides(cfg Config) Config {
 		}
 	}
 
+	// Fallbacks as a comma-separated list of provider:model pairs
+	if val := os.Getenv("NOIDEA_LLM_FALLBACKS"); val != "" {
+		cfg.LLM.Fallbacks = ParseFallbacks(val)
+	}
+
 	// Moai settings
 	if val := os.Getenv("NOIDEA_USE_LINT"); val != "" {
 		cfg.Moai.UseLint = val == "true" || val == "1" || val == "yes"
@@ -213,6 +479,169 @@ func applyEnvironmentOverrides(cfg Config) Config {
 		cfg.Moai.PersonalityFile = val
 	}
 
+	if val := os.Getenv("NOIDEA_BLOCK_SECRETS"); val != "" {
+		cfg.Moai.BlockSecrets = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_DATE_FORMAT"); val != "" {
+		cfg.Moai.DateFormat = val
+	}
+
+	if val := os.Getenv("NOIDEA_HISTORY_BODIES"); val != "" {
+		cfg.Moai.HistoryBodies = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_NOTE_TESTS"); val != "" {
+		cfg.Moai.NoteTests = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_FORCE_ONLY_TYPE"); val != "" {
+		cfg.Moai.ForceOnlyType = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_LOG_SUGGESTIONS"); val != "" {
+		cfg.Moai.LogSuggestions = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_TELEMETRY"); val != "" {
+		cfg.Moai.Telemetry = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_WITH_ISSUES"); val != "" {
+		cfg.Moai.WithIssues = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_SUBJECT_PREFIX"); val != "" {
+		cfg.Moai.SubjectPrefix = val
+	}
+
+	if val := os.Getenv("NOIDEA_COMMENT_CHAR"); val != "" {
+		cfg.Moai.CommentChar = val
+	}
+
+	if val := os.Getenv("NOIDEA_GERRIT_CHANGE_ID"); val != "" {
+		cfg.Moai.GerritChangeID = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_ALLOWED_TYPES"); val != "" {
+		cfg.Moai.AllowedTypes = nil
+		for _, t := range strings.Split(val, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				cfg.Moai.AllowedTypes = append(cfg.Moai.AllowedTypes, strings.ToLower(t))
+			}
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_SMALL_DIFF_LINES"); val != "" {
+		if lines, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.SmallDiffLines = lines
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_MAX_BODY_BULLETS"); val != "" {
+		if bullets, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.MaxBodyBullets = bullets
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_MAX_SUBJECT_LENGTH"); val != "" {
+		if length, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.MaxSubjectLength = length
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_WRAP_BODY"); val != "" {
+		if column, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.WrapBody = column
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_MAX_HISTORY_COMMITS"); val != "" {
+		if limit, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.MaxHistoryCommits = limit
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_INSIGHT_CACHE_HOURS"); val != "" {
+		if hours, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.InsightCacheHours = hours
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_DIFF_ALGORITHM"); val != "" {
+		cfg.Moai.DiffAlgorithm = strings.ToLower(val)
+	}
+
+	if val := os.Getenv("NOIDEA_FUNCTION_CONTEXT"); val != "" {
+		cfg.Moai.FunctionContext = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_IGNORE_WHITESPACE"); val != "" {
+		cfg.Moai.IgnoreWhitespace = val == "true" || val == "1" || val == "yes"
+	}
+
+	if val := os.Getenv("NOIDEA_CONTEXT_LINES"); val != "" {
+		if lines, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.ContextLines = lines
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_MAX_LINES_PER_FILE"); val != "" {
+		if lines, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.MaxLinesPerFile = lines
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_MAX_LINE_LENGTH"); val != "" {
+		if length, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.MaxLineLength = length
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_RELEASE_PROVIDER"); val != "" {
+		cfg.Release.Provider = strings.ToLower(val)
+	}
+
+	if val := os.Getenv("NOIDEA_GITLAB_URL"); val != "" {
+		cfg.Release.GitLabURL = val
+	}
+
+	if val := os.Getenv("NOIDEA_RELEASE_PERSONALITY"); val != "" {
+		cfg.Release.Personality = val
+	}
+
+	if val := os.Getenv("NOIDEA_RELEASE_TIMEOUT"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil {
+			cfg.Release.Timeout = seconds
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_MAX_CONTEXT_TOKENS"); val != "" {
+		if tokens, err := strconv.Atoi(val); err == nil {
+			cfg.LLM.MaxContextTokens = tokens
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_KEYCHAIN"); val != "" {
+		cfg.LLM.Keychain = val
+	}
+
+	if val := os.Getenv("NOIDEA_KEYRING_COLLECTION"); val != "" {
+		cfg.LLM.KeyringCollection = val
+	}
+
+	if val := os.Getenv("NOIDEA_MAX_RETRIES"); val != "" {
+		if retries, err := strconv.Atoi(val); err == nil {
+			cfg.LLM.MaxRetries = retries
+		}
+	}
+
+	if val := os.Getenv("NOIDEA_APPROVAL_RETRIES"); val != "" {
+		if retries, err := strconv.Atoi(val); err == nil {
+			cfg.Moai.ApprovalRetries = retries
+		}
+	}
+
 	return cfg
 }
 
@@ -227,7 +656,7 @@ func ensureDefaults(cfg *Config) {
 	}
 
 	if cfg.LLM.Model == "" {
-		cfg.LLM.Model = defaultCfg.LLM.Model
+		cfg.LLM.Model = ProviderModel(*cfg, cfg.LLM.Provider)
 	}
 
 	if cfg.LLM.Temperature <= 0 || cfg.LLM.Temperature > 1.0 {
@@ -246,27 +675,113 @@ func ensureDefaults(cfg *Config) {
 	if cfg.Moai.PersonalityFile == "" {
 		cfg.Moai.PersonalityFile = defaultCfg.Moai.PersonalityFile
 	}
+
+	// Ensure Release defaults
+	if cfg.Release.Provider == "" {
+		cfg.Release.Provider = defaultCfg.Release.Provider
+	}
+
+	if cfg.Release.GitLabURL == "" {
+		cfg.Release.GitLabURL = defaultCfg.Release.GitLabURL
+	}
+
+	if cfg.Release.Timeout < 0 {
+		cfg.Release.Timeout = 0
+	}
+
+	if cfg.Moai.DateFormat == "" {
+		cfg.Moai.DateFormat = defaultCfg.Moai.DateFormat
+	}
+
+	if cfg.LLM.MaxRetries < 0 {
+		cfg.LLM.MaxRetries = 0
+	}
+
+	if cfg.LLM.MaxContextTokens < 0 {
+		cfg.LLM.MaxContextTokens = 0
+	}
+
+	if cfg.Moai.ApprovalRetries < 0 {
+		cfg.Moai.ApprovalRetries = 0
+	}
+
+	if cfg.Moai.SmallDiffLines < 0 {
+		cfg.Moai.SmallDiffLines = 0
+	}
+
+	if cfg.Moai.MaxBodyBullets < 0 {
+		cfg.Moai.MaxBodyBullets = 0
+	}
+
+	if cfg.Moai.MaxSubjectLength < 0 {
+		cfg.Moai.MaxSubjectLength = 0
+	}
+
+	if cfg.Moai.WrapBody < 0 {
+		cfg.Moai.WrapBody = 0
+	}
+
+	if cfg.Moai.MaxHistoryCommits < 0 {
+		cfg.Moai.MaxHistoryCommits = 0
+	}
+
+	if cfg.Moai.InsightCacheHours < 0 {
+		cfg.Moai.InsightCacheHours = 0
+	}
+
+	if cfg.Moai.ContextLines < 0 {
+		cfg.Moai.ContextLines = 0
+	}
+
+	if cfg.Moai.MaxLinesPerFile < 0 {
+		cfg.Moai.MaxLinesPerFile = 0
+	}
+
+	if cfg.Moai.MaxLineLength < 0 {
+		cfg.Moai.MaxLineLength = 0
+	}
+}
+
+// dateFormatPresets maps named date formats to Go time layouts
+var dateFormatPresets = map[string]string{
+	"iso": "2006-01-02",
+	"us":  "01/02/2006",
+	"eu":  "02.01.2006",
+	"uk":  "02/01/2006",
+}
+
+// ResolveDateFormat converts a date format preset or Go layout into a layout,
+// defaulting to ISO dates when unset
+func ResolveDateFormat(format string) string {
+	if format == "" {
+		return dateFormatPresets["iso"]
+	}
+
+	if layout, ok := dateFormatPresets[strings.ToLower(format)]; ok {
+		return layout
+	}
+
+	return format
 }
 
 // SaveConfig saves the configuration to the default location
 func SaveConfig(cfg Config) error {
-	// Get home directory
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+	// Keep writing the format the user chose
+	configFile := ConfigFile
+	if configFile == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		configFile = configFilePath(filepath.Join(homeDir, ".noidea"))
 	}
 
 	// Create config directory if it doesn't exist
-	configDir := filepath.Join(homeDir, ".noidea")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Config file path
-	configFile := filepath.Join(configDir, "config.json")
-
-	// Marshal config to JSON
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := marshalConfig(configFile, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to encode config: %w", err)
 	}
@@ -279,6 +794,74 @@ func SaveConfig(cfg Config) error {
 	return nil
 }
 
+// ConfigFilePath returns the user config file that is loaded and saved
+func ConfigFilePath() string {
+	if ConfigFile != "" {
+		return ConfigFile
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return configFilePath(filepath.Join(homeDir, ".noidea"))
+}
+
+// configFilePath picks config.json, or config.toml when only the TOML file exists
+func configFilePath(configDir string) string {
+	jsonFile := filepath.Join(configDir, "config.json")
+	if _, err := os.Stat(jsonFile); os.IsNotExist(err) {
+		tomlFile := filepath.Join(configDir, "config.toml")
+		if _, err := os.Stat(tomlFile); err == nil {
+			return tomlFile
+		}
+	}
+	return jsonFile
+}
+
+// unmarshalConfig decodes a config file as TOML or JSON based on its extension
+func unmarshalConfig(path string, data []byte, cfg *Config) error {
+	if filepath.Ext(path) == ".toml" {
+		return toml.Unmarshal(data, cfg)
+	}
+	return json.Unmarshal(data, cfg)
+}
+
+// marshalConfig encodes a config as TOML or JSON based on the file extension
+func marshalConfig(path string, cfg Config) ([]byte, error) {
+	if filepath.Ext(path) == ".toml" {
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return json.MarshalIndent(cfg, "", "  ")
+}
+
+// EncodeConfig encodes a config in the format of the given config file path
+func EncodeConfig(path string, cfg Config) ([]byte, error) {
+	return marshalConfig(path, cfg)
+}
+
+// CheckConfigData parses the contents of a config file the way LoadConfig
+// would and returns the validation issues. A file that does not parse is
+// returned as an error.
+func CheckConfigData(path string, data []byte) ([]string, error) {
+	cfg := DefaultConfig()
+	cfg.LLM.Model = ""
+	if err := unmarshalConfig(path, data, &cfg); err != nil {
+		return nil, err
+	}
+	ensureDefaults(&cfg)
+
+	// The key usually lives in the keyring or environment, not the file
+	if cfg.LLM.APIKey == "" {
+		cfg.LLM.APIKey = resolveAPIKey(cfg.LLM.Provider)
+	}
+
+	return ValidateConfig(cfg), nil
+}
+
 // ValidateConfig checks the configuration for errors or inconsistencies
 // Returns a list of issues or an empty slice if the config is valid
 func ValidateConfig(config Config) []string {
@@ -293,12 +876,18 @@ func ValidateConfig(config Config) []string {
 			"deepseek": true,
 		}
 
-		if !validProviders[config.LLM.Provider] {
+		if !validProviders[config.LLM.Provider] && config.LLM.Provider != ProviderMock {
 			issues = append(issues, fmt.Sprintf("Unknown provider: %s", config.LLM.Provider))
 		}
 
-		// Check that API key is set
-		if config.LLM.APIKey == "" {
+		for provider := range config.LLM.ProviderModels {
+			if !validProviders[strings.ToLower(provider)] {
+				issues = append(issues, fmt.Sprintf("Unknown provider in provider_models: %s", provider))
+			}
+		}
+
+		// Check that API key is set; the mock provider doesn't need one
+		if config.LLM.APIKey == "" && config.LLM.Provider != ProviderMock {
 			issues = append(issues, "API key is required when LLM is enabled")
 		}
 
@@ -321,7 +910,10 @@ func ValidateConfig(config Config) []string {
 	}
 
 	// Check that personality file exists if a custom personality is set
-	if config.Moai.Personality != "default" &&
+	_, builtIn := personality.DefaultPersonalities().Personalities[config.Moai.Personality]
+	if !builtIn &&
+		!personality.IsRandom(config.Moai.Personality) &&
+		config.Moai.Personality != "default" &&
 		config.Moai.Personality != "friendly" &&
 		config.Moai.Personality != "professional" &&
 		config.Moai.Personality != "sarcastic" {
@@ -332,9 +924,117 @@ func ValidateConfig(config Config) []string {
 		}
 	}
 
+	issues = append(issues, validateSchedule(config.Moai.PersonalitySchedule)...)
+
+	if name := config.Release.Personality; name != "" {
+		personalities, _ := personality.LoadPersonalities(config.Moai.PersonalityFile)
+		if _, err := personalities.GetPersonality(name); err != nil {
+			issues = append(issues, fmt.Sprintf("Unknown release personality: %s", name))
+		}
+	}
+
+	// Aliases must not point at a type the allowed list forbids
+	rules := CommitTypeRules(config)
+	for from, to := range config.Moai.TypeAliases {
+		if rules.Resolve(to) != strings.ToLower(to) {
+			issues = append(issues, fmt.Sprintf("Type alias %s -> %s targets a type not in allowed_types", from, to))
+		}
+	}
+
+	if config.Moai.DiffAlgorithm != "" && !ValidDiffAlgorithm(config.Moai.DiffAlgorithm) {
+		issues = append(issues, fmt.Sprintf("Unknown diff algorithm: %s (use %s)",
+			config.Moai.DiffAlgorithm, strings.Join(DiffAlgorithms, ", ")))
+	}
+
+	if !ValidReleaseProvider(config.Release.Provider) {
+		issues = append(issues, fmt.Sprintf("Unknown release provider: %s (use %s)",
+			config.Release.Provider, strings.Join(ReleaseProviders, ", ")))
+	}
+
+	if length := config.Moai.MaxSubjectLength; length > 0 && length < MinSubjectLength {
+		issues = append(issues, fmt.Sprintf("max_subject_length %d is too short for a type and description (use at least %d, or 0 for no limit)", length, MinSubjectLength))
+	}
+
+	if _, err := parseSubjectPrefix(config.Moai.SubjectPrefix); err != nil {
+		issues = append(issues, fmt.Sprintf("Invalid subject_prefix: %v", err))
+	}
+
+	if char := config.Moai.CommentChar; char != "" && (utf8.RuneCountInString(char) != 1 || strings.TrimSpace(char) == "") {
+		issues = append(issues, fmt.Sprintf("comment_char %q must be a single character other than a space", char))
+	}
+
+	for key, value := range config.Moai.Footers {
+		if !conventional.ValidFooterKey(key) {
+			issues = append(issues, fmt.Sprintf("Footer key %q must be a single word using '-' instead of spaces", key))
+		} else if strings.TrimSpace(value) == "" {
+			issues = append(issues, fmt.Sprintf("Footer %s has an empty value", key))
+		}
+	}
+
 	return issues
 }
 
+// MinSubjectLength is the shortest subject limit that leaves room for a type,
+// scope and a few words
+const MinSubjectLength = 20
+
+// DiffAlgorithms are the values git accepts for --diff-algorithm
+var DiffAlgorithms = []string{"myers", "minimal", "patience", "histogram"}
+
+// ValidDiffAlgorithm reports whether name is one of DiffAlgorithms
+func ValidDiffAlgorithm(name string) bool {
+	for _, algorithm := range DiffAlgorithms {
+		if name == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+// CommitTypeRules returns the commit type aliases and restrictions for suggestions
+func CommitTypeRules(cfg Config) conventional.Rules {
+	return conventional.Rules{
+		Aliases: cfg.Moai.TypeAliases,
+		Allowed: cfg.Moai.AllowedTypes,
+	}
+}
+
+// CommitFooters returns the configured footers ordered by key, skipping any
+// that ValidateConfig reports
+func CommitFooters(cfg Config) []conventional.Footer {
+	keys := make([]string, 0, len(cfg.Moai.Footers))
+	for key, value := range cfg.Moai.Footers {
+		if conventional.ValidFooterKey(key) && strings.TrimSpace(value) != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	footers := make([]conventional.Footer, 0, len(keys))
+	for _, key := range keys {
+		footers = append(footers, conventional.Footer{Key: key, Value: strings.TrimSpace(cfg.Moai.Footers[key])})
+	}
+	return footers
+}
+
+// ParseFallbacks parses a "provider:model,provider:model" list into fallbacks.
+// The model may be omitted to use the provider's default.
+func ParseFallbacks(value string) []LLMFallback {
+	var fallbacks []LLMFallback
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		provider, model, _ := strings.Cut(entry, ":")
+		fallbacks = append(fallbacks, LLMFallback{
+			Provider: strings.ToLower(strings.TrimSpace(provider)),
+			Model:    strings.TrimSpace(model),
+		})
+	}
+	return fallbacks
+}
+
 // ParseFloat parses a string to a float64 with a default value if parsing fails
 func ParseFloat(s string, defaultVal float64) float64 {
 	var f float64
@@ -356,8 +1056,8 @@ func SaveAPIKey(provider, apiKey string) error {
 		return fmt.Errorf("cannot save empty API key")
 	}
 
-	// Load current config
-	cfg := LoadConfig()
+	// Load current config, without the repository policy so it isn't persisted
+	cfg := LoadUserConfig()
 
 	// Update provider if necessary
 	if cfg.LLM.Provider != provider && provider != "" {
@@ -387,8 +1087,8 @@ func DeleteAPIKey(provider string) error {
 		fmt.Fprintf(os.Stderr, "Warning: Could not delete API key from secure storage: %v\n", err)
 	}
 
-	// Load current config
-	cfg := LoadConfig()
+	// Load current config, without the repository policy so it isn't persisted
+	cfg := LoadUserConfig()
 
 	// Check if we're deleting the current provider's key
 	if cfg.LLM.Provider == provider {
@@ -399,3 +1099,16 @@ func DeleteAPIKey(provider string) error {
 
 	return nil
 }
+
+// ReleaseProviders are the services release notes can be published to
+var ReleaseProviders = []string{"github", "gitlab"}
+
+// ValidReleaseProvider reports whether name is one of ReleaseProviders
+func ValidReleaseProvider(name string) bool {
+	for _, provider := range ReleaseProviders {
+		if name == provider {
+			return true
+		}
+	}
+	return false
+}
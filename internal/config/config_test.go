@@ -1,9 +1,16 @@
 package config
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -137,9 +144,8 @@ func TestApplyEnvironmentOverrides(t *testing.T) {
 	defer func() {
 		os.Clearenv()
 		for _, e := range origEnv {
-			pair := filepath.SplitList(e)
-			if len(pair) == 2 {
-				os.Setenv(pair[0], pair[1])
+			if key, value, ok := strings.Cut(e, "="); ok {
+				os.Setenv(key, value)
 			}
 		}
 	}()
@@ -194,3 +200,601 @@ func TestApplyEnvironmentOverrides(t *testing.T) {
 		t.Errorf("Expected Moai.PersonalityFile to be 'test-file.json', got '%s'", cfg.Moai.PersonalityFile)
 	}
 }
+
+func TestMockProviderOverride(t *testing.T) {
+	t.Setenv("NOIDEA_LLM_MOCK", "1")
+
+	cfg := DefaultConfig()
+	cfg.LLM.Enabled = false
+	cfg = applyEnvironmentOverrides(cfg)
+	if !cfg.LLM.Enabled || cfg.LLM.Provider != ProviderMock || cfg.LLM.Model != "" {
+		t.Errorf("NOIDEA_LLM_MOCK did not switch to the mock provider: %+v", cfg.LLM)
+	}
+
+	cfg.LLM.APIKey = ""
+	if issues := ValidateConfig(cfg); len(issues) != 0 {
+		t.Errorf("mock provider without a key should be valid, got %v", issues)
+	}
+}
+
+func TestResolveDateFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "2006-01-02"},
+		{"iso", "2006-01-02"},
+		{"US", "01/02/2006"},
+		{"eu", "02.01.2006"},
+		{"Jan 2, 2006", "Jan 2, 2006"}, // Custom layouts are passed through
+	}
+
+	for _, test := range tests {
+		if result := ResolveDateFormat(test.input); result != test.expected {
+			t.Errorf("ResolveDateFormat(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestParseFallbacks(t *testing.T) {
+	fallbacks := ParseFallbacks("openai:gpt-4o, DeepSeek ,")
+	if len(fallbacks) != 2 {
+		t.Fatalf("Expected 2 fallbacks, got %d", len(fallbacks))
+	}
+
+	if fallbacks[0].Provider != "openai" || fallbacks[0].Model != "gpt-4o" {
+		t.Errorf("Unexpected first fallback: %+v", fallbacks[0])
+	}
+
+	if fallbacks[1].Provider != "deepseek" || fallbacks[1].Model != "" {
+		t.Errorf("Unexpected second fallback: %+v", fallbacks[1])
+	}
+}
+
+func TestRepoPolicyAllows(t *testing.T) {
+	policy := &RepoPolicy{
+		Path:                 PolicyFile,
+		Provider:             "openai",
+		AllowedModels:        []string{"gpt-4o", "gpt-4o-mini"},
+		AllowedPersonalities: []string{"git_expert"},
+	}
+
+	if !policy.AllowsProvider("OpenAI") || policy.AllowsProvider("xai") {
+		t.Errorf("AllowsProvider should only permit the pinned provider")
+	}
+	if !policy.AllowsModel("gpt-4o-mini") || policy.AllowsModel("grok-2-1212") {
+		t.Errorf("AllowsModel should only permit listed models")
+	}
+	if policy.CheckPersonality("git_expert") != nil || policy.CheckPersonality("snarky_reviewer") == nil {
+		t.Errorf("CheckPersonality should only permit listed personalities")
+	}
+
+	var none *RepoPolicy
+	if !none.AllowsProvider("xai") || !none.AllowsModel("any") || none.CheckPersonality("any") != nil {
+		t.Errorf("a nil policy should allow everything")
+	}
+}
+
+func TestApplyRepoPolicyFallbacks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if err := os.MkdirAll(filepath.Join(repo, ".noidea"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	policy := `{"allowed_models": ["grok-2-1212", "gpt-4o"]}`
+	if err := os.WriteFile(filepath.Join(repo, PolicyFile), []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	cfg := DefaultConfig()
+	cfg.LLM.ProviderModels = map[string]string{"openai": "gpt-4o"}
+	cfg.LLM.Fallbacks = []LLMFallback{
+		{Provider: "openai"},                       // Resolves to the allowed pinned model
+		{Provider: "deepseek"},                     // Resolves to the engine default, which isn't listed
+		{Provider: "openai", Model: "gpt-4o-mini"}, // Explicitly forbidden
+		{Provider: "xai", Model: "grok-2-1212"},    // Explicitly allowed
+	}
+
+	got := applyRepoPolicy(cfg).LLM.Fallbacks
+	want := []LLMFallback{{Provider: "openai", Model: "gpt-4o"}, {Provider: "xai", Model: "grok-2-1212"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fallbacks = %+v, want %+v", got, want)
+	}
+}
+
+func TestScheduledPersonality(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Moai.PersonalitySchedule = map[string]string{
+		"Monday":      "supportive_mentor",
+		"friday":      "snarky_reviewer",
+		"repo:noidea": "git_expert",
+	}
+
+	monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	friday := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tuesday := time.Date(2026, 10, 13, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		repo string
+		want string
+	}{
+		{"weekday match", monday, "other", "supportive_mentor"},
+		{"weekday key is case-insensitive", friday, "", "snarky_reviewer"},
+		{"repository wins over weekday", friday, "noidea", "git_expert"},
+		{"no match uses default", tuesday, "other", cfg.Moai.Personality},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScheduledPersonality(cfg, tt.now, tt.repo); got != tt.want {
+				t.Errorf("ScheduledPersonality() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// A scheduled personality the repository policy forbids falls back to the default
+	cfg.Policy = &RepoPolicy{AllowedPersonalities: []string{cfg.Moai.Personality}}
+	if got := ScheduledPersonality(cfg, monday, ""); got != cfg.Moai.Personality {
+		t.Errorf("ScheduledPersonality() = %q, want policy-allowed default %q", got, cfg.Moai.Personality)
+	}
+
+	if issues := validateSchedule(map[string]string{"someday": "git_expert"}); len(issues) != 1 {
+		t.Errorf("validateSchedule should flag unknown keys, got %v", issues)
+	}
+}
+
+func TestValidateTypeAliases(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Moai.AllowedTypes = []string{"feat", "fix", "chore"}
+	cfg.Moai.TypeAliases = map[string]string{"build": "chore"}
+
+	for _, issue := range ValidateConfig(cfg) {
+		if strings.Contains(issue, "Type alias") {
+			t.Errorf("unexpected issue for a valid alias: %s", issue)
+		}
+	}
+
+	cfg.Moai.TypeAliases["style"] = "refactor"
+	found := false
+	for _, issue := range ValidateConfig(cfg) {
+		if strings.Contains(issue, "style -> refactor") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected an issue for an alias targeting a forbidden type")
+	}
+}
+
+func TestConfigTOMLRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if got := configFilePath(dir); filepath.Base(got) != "config.json" {
+		t.Errorf("configFilePath() with no files = %s, want config.json", got)
+	}
+
+	tomlFile := filepath.Join(dir, "config.toml")
+	data := []byte("[llm]\nprovider = \"openai\"\ntemperature = 0.3\n\n[moai]\nsmall_diff_lines = 0\n\n[moai.type_aliases]\nbuild = \"chore\"\n")
+	if err := os.WriteFile(tomlFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := configFilePath(dir); got != tomlFile {
+		t.Errorf("configFilePath() = %s, want %s", got, tomlFile)
+	}
+
+	cfg := DefaultConfig()
+	if err := unmarshalConfig(tomlFile, data, &cfg); err != nil {
+		t.Fatalf("unmarshalConfig() error = %v", err)
+	}
+	if cfg.LLM.Provider != "openai" || cfg.LLM.Temperature != 0.3 || cfg.Moai.SmallDiffLines != 0 {
+		t.Errorf("unexpected TOML values: %+v", cfg)
+	}
+	if cfg.Moai.TypeAliases["build"] != "chore" {
+		t.Errorf("type_aliases not decoded: %v", cfg.Moai.TypeAliases)
+	}
+	if cfg.Moai.FacesMode != DefaultConfig().Moai.FacesMode {
+		t.Errorf("absent keys should keep defaults, got faces_mode %q", cfg.Moai.FacesMode)
+	}
+
+	encoded, err := marshalConfig(tomlFile, cfg)
+	if err != nil {
+		t.Fatalf("marshalConfig() error = %v", err)
+	}
+	var decoded Config
+	if err := unmarshalConfig(tomlFile, encoded, &decoded); err != nil {
+		t.Fatalf("re-reading TOML: %v", err)
+	}
+	if decoded.LLM.Provider != cfg.LLM.Provider || decoded.Moai.FacesMode != cfg.Moai.FacesMode ||
+		decoded.Moai.TypeAliases["build"] != "chore" {
+		t.Errorf("round trip mismatch:\n%s", encoded)
+	}
+}
+
+func TestOverrideModel(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-openai")
+	cfg := DefaultConfig()
+	cfg.LLM.Provider = "xai"
+	cfg.LLM.Model = "grok-2-1212"
+	cfg.LLM.APIKey = "xai-key"
+
+	got, err := OverrideModel(cfg, "", "grok-beta")
+	if err != nil || got.LLM.Provider != "xai" || got.LLM.Model != "grok-beta" || got.LLM.APIKey != "xai-key" {
+		t.Errorf("model only: got %+v, %v", got.LLM, err)
+	}
+
+	got, err = OverrideModel(cfg, "OpenAI", "")
+	if err != nil || got.LLM.Provider != "openai" || got.LLM.Model != "" || got.LLM.APIKey != "sk-openai" {
+		t.Errorf("provider only: got %+v, %v", got.LLM, err)
+	}
+
+	if _, err := OverrideModel(cfg, "acme", ""); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+
+	cfg.Policy = &RepoPolicy{Path: "policy.json", AllowedProviders: []string{"xai"}}
+	if _, err := OverrideModel(cfg, "openai", "gpt-4o"); err == nil {
+		t.Error("expected the policy to reject a forbidden provider")
+	}
+}
+
+func TestOverrideModelProviderKeepsPolicyModel(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Policy = &RepoPolicy{Path: "policy.json", Model: "gpt-4o"}
+	got, err := OverrideModel(cfg, "openai", "")
+	if err != nil || got.LLM.Provider != "openai" || got.LLM.Model != "gpt-4o" {
+		t.Errorf("pinned model: got %+v, %v", got.LLM, err)
+	}
+
+	cfg.Policy = &RepoPolicy{Path: "policy.json", AllowedModels: []string{"gpt-4o-mini", "gpt-4o"}}
+	got, err = OverrideModel(cfg, "openai", "")
+	if err != nil || got.LLM.Model != "gpt-4o-mini" {
+		t.Errorf("allowed models: got %+v, %v", got.LLM, err)
+	}
+
+	if _, err := OverrideModel(cfg, "openai", "gpt-4-turbo"); err == nil {
+		t.Error("expected the policy to reject a forbidden model")
+	}
+}
+
+func TestProviderModel(t *testing.T) {
+	cfg := DefaultConfig()
+	if got := ProviderModel(cfg, "xai"); got != "grok-2-1212" {
+		t.Errorf("ProviderModel(xai) = %q, want the default model", got)
+	}
+	if got := ProviderModel(cfg, "openai"); got != "" {
+		t.Errorf("ProviderModel(openai) = %q, want empty for the provider default", got)
+	}
+
+	cfg.LLM.ProviderModels = map[string]string{"openai": "gpt-4o-mini"}
+	if got := ProviderModel(cfg, "openai"); got != "gpt-4o-mini" {
+		t.Errorf("ProviderModel(openai) = %q, want the pinned model", got)
+	}
+
+	t.Setenv("NOIDEA_LLM_PROVIDER", "openai")
+	t.Setenv("NOIDEA_MODEL", "")
+	if got := applyEnvironmentOverrides(cfg); got.LLM.Model != "gpt-4o-mini" {
+		t.Errorf("switching provider by environment kept model %q", got.LLM.Model)
+	}
+
+	got, err := OverrideModel(cfg, "openai", "")
+	if err != nil || got.LLM.Model != "gpt-4o-mini" {
+		t.Errorf("OverrideModel to openai: got model %q, %v", got.LLM.Model, err)
+	}
+
+	cfg.Policy = &RepoPolicy{Path: "policy.json", AllowedModels: []string{"gpt-4o"}}
+	got, err = OverrideModel(cfg, "openai", "")
+	if err != nil || got.LLM.Model != "gpt-4o" {
+		t.Errorf("pinned model outside the policy: got model %q, %v", got.LLM.Model, err)
+	}
+}
+
+func TestSourceTracker(t *testing.T) {
+	sources := sourceTracker{}
+	defaults := DefaultConfig()
+
+	// The file repeats the default temperature and changes the provider
+	fromFile := defaults
+	fromFile.LLM.Provider = "openai"
+	sources.record(defaults, fromFile, SourceFile)
+	sources.recordFile("config.json", []byte(`{"llm": {"provider": "openai", "temperature": 0.7}}`), SourceFile)
+
+	fromEnv := fromFile
+	fromEnv.LLM.Model = "gpt-4o"
+	sources.record(fromFile, fromEnv, SourceEnv)
+
+	want := map[string]string{
+		"llm.provider":     SourceFile,
+		"llm.temperature":  SourceFile,
+		"llm.model":        SourceEnv,
+		"moai.faces_mode":  SourceDefault,
+		"release.provider": SourceDefault,
+	}
+	got := map[string]Setting{}
+	for _, setting := range sources.settings(fromEnv) {
+		got[setting.Path] = setting
+	}
+	for path, source := range want {
+		if got[path].Source != source {
+			t.Errorf("%s: source = %q, want %q", path, got[path].Source, source)
+		}
+	}
+	if got["llm.model"].Value != "gpt-4o" {
+		t.Errorf("llm.model: value = %q, want gpt-4o", got["llm.model"].Value)
+	}
+}
+
+func TestCommitFooters(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Moai.Footers = map[string]string{"Refs": "ABC-12", "Reviewed-by": "Jane", "Bad Key": "x"}
+
+	footers := CommitFooters(cfg)
+	if len(footers) != 2 || footers[0].Key != "Refs" || footers[1].Key != "Reviewed-by" {
+		t.Errorf("CommitFooters() = %+v, want Refs and Reviewed-by in order", footers)
+	}
+
+	found := false
+	for _, issue := range ValidateConfig(cfg) {
+		if strings.Contains(issue, "Bad Key") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected an issue for an invalid footer key")
+	}
+}
+
+func TestSubjectPrefix(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Moai.SubjectPrefix = "[{{.Ticket}}]"
+
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"feature/JIRA-123-login", "[JIRA-123]"},
+		{"main", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got, err := SubjectPrefix(cfg, tt.branch); err != nil || got != tt.want {
+			t.Errorf("SubjectPrefix(%q) = %q, %v, want %q", tt.branch, got, err, tt.want)
+		}
+	}
+
+	cfg.Moai.SubjectPrefix = "{{.Branch}}:"
+	if got, _ := SubjectPrefix(cfg, "hotfix"); got != "hotfix:" {
+		t.Errorf("SubjectPrefix({{.Branch}}) = %q, want %q", got, "hotfix:")
+	}
+
+	cfg.Moai.SubjectPrefix = "[{{.Ticket}"
+	if _, err := SubjectPrefix(cfg, "main"); err == nil {
+		t.Error("expected an error for an unclosed action")
+	}
+}
+
+func TestSchemaCoversConfig(t *testing.T) {
+	var paths []string
+	var walk func(reflect.Type, string)
+	walk = func(typ reflect.Type, path string) {
+		if path != "" {
+			paths = append(paths, path)
+		}
+		if typ.Kind() == reflect.Slice {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if name := jsonName(typ.Field(i)); name != "" {
+				walk(typ.Field(i).Type, joinPath(path, name))
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+
+	for _, path := range paths {
+		if _, ok := schemaFields[path]; !ok {
+			t.Errorf("schemaFields has no entry for %s", path)
+		}
+	}
+	if len(paths) != len(schemaFields) {
+		t.Errorf("schemaFields has %d entries for %d config fields; remove stale ones", len(schemaFields), len(paths))
+	}
+
+	llm := Schema()["properties"].(map[string]interface{})["llm"].(map[string]interface{})
+	provider := llm["properties"].(map[string]interface{})["provider"].(map[string]interface{})
+	if provider["default"] != DefaultConfig().LLM.Provider || len(provider["enum"].([]string)) != 4 {
+		t.Errorf("unexpected provider schema: %v", provider)
+	}
+}
+
+func TestSharedConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	shared := `{"llm": {"provider": "openai", "model": "gpt-4o-mini"}, "moai": {"faces_mode": "mood", "allowed_types": ["feat", "fix"]}}`
+	available := true
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !available {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, shared)
+	}))
+	defer server.Close()
+	t.Setenv("NOIDEA_CONFIG_URL", server.URL+"/noidea.json")
+
+	// The user's file overrides the shared faces mode and keeps its model
+	configDir := filepath.Join(home, ".noidea")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"moai": {"faces_mode": "sequential"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sources := sourceTracker{}
+	cfg := loadUserConfig(sources, true, "")
+	if cfg.LLM.Provider != "openai" || cfg.LLM.Model != "gpt-4o-mini" || len(cfg.Moai.AllowedTypes) != 2 {
+		t.Errorf("shared settings not applied: %+v", cfg.LLM)
+	}
+	if cfg.Moai.FacesMode != "sequential" {
+		t.Errorf("faces_mode = %q, want the user's sequential", cfg.Moai.FacesMode)
+	}
+	if sources["llm.provider"] != SourceShared || sources["moai.faces_mode"] != SourceFile {
+		t.Errorf("sources = %v", sources)
+	}
+	if saved := LoadUserConfig(); saved.LLM.Provider == "openai" {
+		t.Error("LoadUserConfig should leave the shared config out of what is saved")
+	}
+
+	// A fresh cached copy is used without fetching again
+	loadUserConfig(sourceTracker{}, true, "")
+	if requests != 1 {
+		t.Errorf("server saw %d requests, want 1 within the TTL", requests)
+	}
+
+	// Once stale, a failed fetch falls back to the cached copy
+	cachePath := sharedConfigCachePath(home, server.URL+"/noidea.json")
+	stale := time.Now().Add(-2 * SharedConfigTTL)
+	if err := os.Chtimes(cachePath, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	available = false
+	if cfg := loadUserConfig(sourceTracker{}, true, ""); cfg.LLM.Model != "gpt-4o-mini" {
+		t.Errorf("stale cache not used after a failed fetch: %+v", cfg.LLM)
+	}
+}
+
+func TestCheckConfigData(t *testing.T) {
+	t.Setenv("NOIDEA_API_KEY", "test-key")
+
+	issues, err := CheckConfigData("config.json", []byte(`{"llm": {"enabled": true, "provider": "openai"}}`))
+	if err != nil {
+		t.Fatalf("CheckConfigData() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+
+	issues, err = CheckConfigData("config.toml", []byte("[llm]\nenabled = true\nprovider = \"nope\"\n"))
+	if err != nil {
+		t.Fatalf("CheckConfigData() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Error("expected an issue for an unknown provider")
+	}
+
+	if _, err := CheckConfigData("config.json", []byte(`{"llm": {`)); err == nil {
+		t.Error("expected a parse error for malformed JSON")
+	}
+}
+
+func TestLoadConfigFrom(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("NOIDEA_CONFIG_URL", "")
+
+	configDir := filepath.Join(home, ".noidea")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"moai": {"personality": "supportive_mentor"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	project := filepath.Join(t.TempDir(), "project.toml")
+	if err := os.WriteFile(project, []byte("[moai]\npersonality = \"snarky_reviewer\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg := LoadConfigFrom(project); cfg.Moai.Personality != "snarky_reviewer" {
+		t.Errorf("LoadConfigFrom() personality = %q, want the project's", cfg.Moai.Personality)
+	}
+	if cfg := LoadConfigFrom(""); cfg.Moai.Personality != "supportive_mentor" {
+		t.Errorf("LoadConfigFrom(\"\") personality = %q, want the user's", cfg.Moai.Personality)
+	}
+
+	// --config also decides where settings are saved
+	ConfigFile = project
+	defer func() { ConfigFile = "" }()
+	if got := ConfigFilePath(); got != project {
+		t.Errorf("ConfigFilePath() = %s, want %s", got, project)
+	}
+	cfg := LoadUserConfig()
+	cfg.Moai.FacesMode = "mood"
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if saved := LoadConfig(); saved.Moai.FacesMode != "mood" || saved.Moai.Personality != "snarky_reviewer" {
+		t.Errorf("settings were not saved to the --config file: %+v", saved.Moai)
+	}
+}
+
+func TestProjectConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("NOIDEA_CONFIG_URL", "")
+
+	configDir := filepath.Join(home, ".noidea")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	global := `{"llm": {"provider": "openai", "model": "gpt-4o"}, "moai": {"personality": "supportive_mentor", "faces_mode": "mood"}}`
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(global), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The project file is found from a subdirectory
+	project := t.TempDir()
+	subdir := filepath.Join(project, "src", "pkg")
+	if err := os.MkdirAll(subdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	local := "[llm]\napi_key = \"committed-key\"\n\n[moai]\npersonality = \"snarky_reviewer\"\nsubject_prefix = \"[{{.Ticket}}]\"\n"
+	if err := os.WriteFile(filepath.Join(project, ".noidea.toml"), []byte(local), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindProjectConfig(subdir); got != filepath.Join(project, ".noidea.toml") {
+		t.Errorf("FindProjectConfig() = %q", got)
+	}
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(subdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	sources := sourceTracker{}
+	cfg := loadUserConfig(sources, true, "")
+	if cfg.Moai.Personality != "snarky_reviewer" || cfg.Moai.SubjectPrefix != "[{{.Ticket}}]" {
+		t.Errorf("project settings not applied: %+v", cfg.Moai)
+	}
+	if cfg.Moai.FacesMode != "mood" || cfg.LLM.Model != "gpt-4o" {
+		t.Errorf("global settings the project doesn't set were lost: faces %q, model %q", cfg.Moai.FacesMode, cfg.LLM.Model)
+	}
+	if cfg.LLM.APIKey == "committed-key" || sources["llm.api_key"] == SourceProject {
+		t.Error("the API key must not come from the project config")
+	}
+	if sources["moai.personality"] != SourceProject || sources["moai.faces_mode"] != SourceFile {
+		t.Errorf("unexpected sources: personality %q, faces_mode %q", sources["moai.personality"], sources["moai.faces_mode"])
+	}
+
+	// Saving must not copy project settings into the global file
+	if cfg := LoadUserConfig(); cfg.Moai.Personality != "supportive_mentor" {
+		t.Errorf("LoadUserConfig() personality = %q, want the global one", cfg.Moai.Personality)
+	}
+}
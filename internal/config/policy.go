@@ -0,0 +1,185 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+// PolicyFile is the repository-relative path of the AI usage policy
+const PolicyFile = ".noidea/policy.json"
+
+// RepoPolicy restricts which providers, models and personalities may be used
+// in a repository. It overrides the user's own configuration.
+type RepoPolicy struct {
+	Path                 string   `json:"-"`                               // Where the policy was loaded from
+	Provider             string   `json:"provider,omitempty"`              // Provider every AI call must use
+	Model                string   `json:"model,omitempty"`                 // Model every AI call must use
+	AllowedProviders     []string `json:"allowed_providers,omitempty"`     // Providers that may be used, empty for any
+	AllowedModels        []string `json:"allowed_models,omitempty"`        // Models that may be used, empty for any
+	AllowedPersonalities []string `json:"allowed_personalities,omitempty"` // Personalities that may be used, empty for any
+}
+
+// AllowsProvider reports whether the policy permits the given provider.
+// A nil policy allows everything.
+func (p *RepoPolicy) AllowsProvider(provider string) bool {
+	if p == nil {
+		return true
+	}
+	if p.Provider != "" && !strings.EqualFold(p.Provider, provider) {
+		return false
+	}
+	return listAllows(p.AllowedProviders, provider)
+}
+
+// AllowsModel reports whether the policy permits the given model
+func (p *RepoPolicy) AllowsModel(model string) bool {
+	if p == nil {
+		return true
+	}
+	if p.Model != "" && p.Model != model {
+		return false
+	}
+	return listAllows(p.AllowedModels, model)
+}
+
+// ResolveModel returns the model to use in place of model: the one the policy
+// pins, the first allowed model when model isn't allowed, or model itself
+func (p *RepoPolicy) ResolveModel(model string) string {
+	switch {
+	case p == nil:
+		return model
+	case p.Model != "":
+		return p.Model
+	case !p.AllowsModel(model) && len(p.AllowedModels) > 0:
+		return p.AllowedModels[0]
+	}
+	return model
+}
+
+// CheckPersonality returns an error explaining why a personality may not be used
+func (p *RepoPolicy) CheckPersonality(name string) error {
+	if p == nil || listAllows(p.AllowedPersonalities, name) {
+		return nil
+	}
+	return fmt.Errorf("personality '%s' is not allowed by %s (allowed: %s)",
+		name, p.Path, strings.Join(p.AllowedPersonalities, ", "))
+}
+
+// listAllows reports whether value appears in list, treating an empty list as "anything"
+func listAllows(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadRepoPolicy reads the policy for the current repository, if there is one
+func LoadRepoPolicy() (*RepoPolicy, error) {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, nil // Not in a repository, so no policy applies
+	}
+
+	path := filepath.Join(strings.TrimSpace(string(output)), PolicyFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	policy := &RepoPolicy{Path: path}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return policy, nil
+}
+
+// applyRepoPolicy pins the provider and model required by the repository
+// policy and drops any fallbacks it forbids. A policy that can't be read
+// disables AI features rather than risk sending code to an unapproved endpoint.
+func applyRepoPolicy(cfg Config) Config {
+	policy, err := LoadRepoPolicy()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; AI features are disabled for this repository\n", err)
+		cfg.LLM.Enabled = false
+		return cfg
+	}
+	if policy == nil {
+		return cfg
+	}
+	cfg.Policy = policy
+
+	provider := cfg.LLM.Provider
+	switch {
+	case policy.Provider != "":
+		provider = policy.Provider
+	case !policy.AllowsProvider(provider):
+		provider = policy.AllowedProviders[0]
+	}
+
+	if provider != cfg.LLM.Provider {
+		cfg.LLM.Provider = provider
+		// The user's model and key belong to their own provider
+		cfg.LLM.Model = ProviderModel(cfg, provider)
+		cfg.LLM.APIKey = resolveAPIKey(provider)
+	}
+
+	cfg.LLM.Model = policy.ResolveModel(cfg.LLM.Model)
+
+	var fallbacks []LLMFallback
+	for _, fb := range cfg.LLM.Fallbacks {
+		// A fallback without a model runs the provider's pinned or default one
+		if fb.Model == "" {
+			fb.Model = ProviderModel(cfg, fb.Provider)
+		}
+		if policy.AllowsProvider(fb.Provider) && policy.AllowsModel(fb.Model) {
+			fallbacks = append(fallbacks, fb)
+		}
+	}
+	cfg.LLM.Fallbacks = fallbacks
+
+	if policy.CheckPersonality(cfg.Moai.Personality) != nil {
+		cfg.Moai.Personality = policy.AllowedPersonalities[0]
+	}
+
+	// Release notes fall back to their professional voice
+	if policy.CheckPersonality(cfg.Release.Personality) != nil {
+		cfg.Release.Personality = ""
+	}
+
+	return cfg
+}
+
+// resolveAPIKey looks up the key for a provider the same way LoadConfig does:
+// provider-specific environment variable, then NOIDEA_API_KEY, then secure storage
+func resolveAPIKey(provider string) string {
+	envKeys := map[string]string{
+		"xai":      "XAI_API_KEY",
+		"openai":   "OPENAI_API_KEY",
+		"deepseek": "DEEPSEEK_API_KEY",
+	}
+	if val := os.Getenv(envKeys[provider]); envKeys[provider] != "" && val != "" {
+		return strings.TrimSpace(val)
+	}
+	if val := os.Getenv("NOIDEA_API_KEY"); val != "" {
+		return strings.TrimSpace(val)
+	}
+	if key, err := secure.GetAPIKey(provider); err == nil {
+		return key
+	}
+	return ""
+}
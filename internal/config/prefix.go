@@ -0,0 +1,47 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// ticketRegex matches issue keys such as JIRA-123 in branch names
+var ticketRegex = regexp.MustCompile(`[A-Z][A-Z0-9]+-[0-9]+`)
+
+// parseSubjectPrefix parses moai.subject_prefix. Using a value the branch
+// doesn't have is an error when rendering, not an empty string.
+func parseSubjectPrefix(prefix string) (*template.Template, error) {
+	return template.New("subject_prefix").Option("missingkey=error").Parse(prefix)
+}
+
+// SubjectPrefix renders moai.subject_prefix for a branch: {{.Branch}} is the
+// branch name and {{.Ticket}} the first issue key in it, such as JIRA-123 in
+// "feature/JIRA-123-login". A prefix that needs a value the branch doesn't
+// have, like a ticket on main or any branch on a detached HEAD (an empty
+// branch), renders as "" instead of "[]".
+func SubjectPrefix(cfg Config, branch string) (string, error) {
+	if strings.TrimSpace(cfg.Moai.SubjectPrefix) == "" {
+		return "", nil
+	}
+
+	tmpl, err := parseSubjectPrefix(cfg.Moai.SubjectPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	values := make(map[string]string)
+	if branch != "" {
+		values["Branch"] = branch
+	}
+	if ticket := ticketRegex.FindString(branch); ticket != "" {
+		values["Ticket"] = ticket
+	}
+
+	var prefix strings.Builder
+	if err := tmpl.Execute(&prefix, values); err != nil {
+		// A value the branch doesn't have leaves subjects unprefixed
+		return "", nil
+	}
+	return strings.TrimSpace(prefix.String()), nil
+}
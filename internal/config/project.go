@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectConfigFiles are the names of a project's own config file, looked
+// for in the current directory and each of its parents
+var ProjectConfigFiles = []string{".noidea.json", ".noidea.toml"}
+
+// FindProjectConfig returns the project config file closest to dir, or ""
+// when neither dir nor any of its parents has one
+func FindProjectConfig(dir string) string {
+	for {
+		for _, name := range ProjectConfigFiles {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// applyProjectConfig merges the project config file found from the current
+// directory on top of cfg. A project can't set the API key, which keeps
+// coming from secure storage or the environment.
+func applyProjectConfig(cfg Config, sources sourceTracker) Config {
+	dir, err := os.Getwd()
+	if err != nil {
+		return cfg
+	}
+	path := FindProjectConfig(dir)
+	if path == "" {
+		return cfg
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not read project config %s: %v\n", path, err)
+		return cfg
+	}
+
+	before := cfg
+	cfg.LLM.Model = ""
+	if err := unmarshalConfig(path, data, &cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not parse project config %s: %v\n", path, err)
+		return before
+	}
+
+	// A key committed to a repository would be shared with everyone who clones it
+	if cfg.LLM.APIKey != before.LLM.APIKey {
+		fmt.Fprintf(os.Stderr, "Warning: Ignoring llm.api_key in %s; store keys with 'noidea config apikey'\n", path)
+		cfg.LLM.APIKey = before.LLM.APIKey
+	}
+
+	// The model belongs to the provider unless the project names both
+	if cfg.LLM.Model == "" {
+		if cfg.LLM.Provider == before.LLM.Provider {
+			cfg.LLM.Model = before.LLM.Model
+		} else {
+			cfg.LLM.Model = ProviderModel(cfg, cfg.LLM.Provider)
+		}
+	}
+
+	apiKeySource, hadSource := sources["llm.api_key"]
+	sources.record(before, cfg, SourceProject)
+	sources.recordFile(path, data, SourceProject)
+	if hadSource {
+		sources["llm.api_key"] = apiKeySource
+	} else {
+		delete(sources, "llm.api_key")
+	}
+
+	return cfg
+}
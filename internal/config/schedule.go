@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// repoSchedulePrefix marks a personality schedule key that matches a repository name
+const repoSchedulePrefix = "repo:"
+
+// ScheduledPersonality returns the personality to use at the given time in the
+// named repository. Entries in Moai.PersonalitySchedule are keyed by weekday
+// ("monday") or by repository ("repo:noidea"); a repository match wins over
+// the weekday. Without a match, or when the repository policy forbids the
+// scheduled personality, the default personality is used.
+func ScheduledPersonality(cfg Config, now time.Time, repo string) string {
+	var byRepo, byDay string
+	for key, name := range cfg.Moai.PersonalitySchedule {
+		switch {
+		case repo != "" && strings.EqualFold(key, repoSchedulePrefix+repo):
+			byRepo = name
+		case strings.EqualFold(key, now.Weekday().String()):
+			byDay = name
+		}
+	}
+
+	for _, name := range []string{byRepo, byDay} {
+		if name != "" && cfg.Policy.CheckPersonality(name) == nil {
+			return name
+		}
+	}
+
+	return cfg.Moai.Personality
+}
+
+// validateSchedule reports schedule keys that are neither a weekday nor a repository
+func validateSchedule(schedule map[string]string) []string {
+	var issues []string
+	for key := range schedule {
+		if strings.HasPrefix(strings.ToLower(key), repoSchedulePrefix) {
+			continue
+		}
+		if _, ok := parseWeekday(key); !ok {
+			issues = append(issues, fmt.Sprintf("Unknown personality schedule key: %s (use a weekday or repo:<name>)", key))
+		}
+	}
+	return issues
+}
+
+// parseWeekday converts a weekday name such as "Monday" into a time.Weekday
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// schemaField describes a config setting for the JSON Schema
+type schemaField struct {
+	Description string
+	Enum        []string
+	Minimum     *float64
+	Maximum     *float64
+}
+
+// bound returns a pointer for schema minimum and maximum values
+func bound(v float64) *float64 {
+	return &v
+}
+
+// schemaFields documents every setting by its JSON path. TestSchemaCoversConfig
+// fails when a field is added to Config without an entry here.
+var schemaFields = map[string]schemaField{
+	"llm":                    {Description: "Settings for the AI language model integration"},
+	"llm.enabled":            {Description: "Enable AI features"},
+	"llm.provider":           {Description: "AI provider to use; mock answers offline without an API key", Enum: []string{"xai", "openai", "deepseek", "mock"}},
+	"llm.api_key":            {Description: "API key for the provider; prefer 'noidea config apikey' to store it securely"},
+	"llm.model":              {Description: "Model name to use with the provider"},
+	"llm.temperature":        {Description: "Randomness of AI responses", Minimum: bound(0), Maximum: bound(1)},
+	"llm.fallbacks":          {Description: "Ordered providers to try if the primary provider fails"},
+	"llm.fallbacks.provider": {Description: "Fallback provider", Enum: []string{"xai", "openai", "deepseek"}},
+	"llm.fallbacks.model":    {Description: "Fallback model, empty for the provider's default"},
+	"llm.max_retries":        {Description: "Retries with exponential backoff after rate limits or server errors", Minimum: bound(0)},
+	"llm.max_context_tokens": {Description: "Context window of the model in tokens, which sizes the diff sent for suggestions (0 = known size for the model)", Minimum: bound(0)},
+	"llm.keychain":           {Description: "macOS keychain for stored API keys, a name like \"login\" or a path (default: the default keychain)"},
+	"llm.keyring_collection": {Description: "Linux Secret Service collection for stored API keys (default: login)"},
+	"llm.provider_models":    {Description: "Preferred model per provider (\"openai\" = \"gpt-4o-mini\"), used when llm.model is empty"},
+
+	"moai":                      {Description: "Settings for Moai feedback and commit suggestions"},
+	"moai.use_lint":             {Description: "Include linting results in feedback"},
+	"moai.faces_mode":           {Description: "How Moai faces are chosen", Enum: []string{"random", "sequential", "mood"}},
+	"moai.personality":          {Description: "Default personality for feedback"},
+	"moai.personality_file":     {Description: "File with custom personality definitions"},
+	"moai.history_bodies":       {Description: "Include commit bodies from history in suggestion context"},
+	"moai.date_format":          {Description: "Date format for summaries: iso, us, eu, uk or a Go layout"},
+	"moai.block_secrets":        {Description: "Refuse to suggest when the staged diff looks like it contains secrets"},
+	"moai.note_tests":           {Description: "Mention new or changed tests in suggested messages"},
+	"moai.force_only_type":      {Description: "Always use the docs type for documentation-only changes and test for test-only changes, instead of only asking the model to"},
+	"moai.approval_retries":     {Description: "Invalid answers allowed at approval prompts before cancelling", Minimum: bound(0)},
+	"moai.log_suggestions":      {Description: "Record suggestions and their outcomes in ~/.noidea/suggestions.jsonl"},
+	"moai.telemetry":            {Description: "Keep local-only metrics on token usage, error types and suggestion acceptance in ~/.noidea/telemetry.jsonl (never sent anywhere)"},
+	"moai.personality_schedule": {Description: "Personalities by weekday (\"friday\") or repository (\"repo:<name>\")"},
+	"moai.type_aliases":         {Description: "Commit types to rewrite in suggestions, e.g. build -> chore"},
+	"moai.allowed_types":        {Description: "The only commit types suggestions may use"},
+	"moai.footers":              {Description: "Footers added to every suggestion, e.g. Reviewed-by"},
+	"moai.subject_prefix":       {Description: "Put before every suggested subject; {{.Branch}} is the branch name and {{.Ticket}} the issue key in it, e.g. [{{.Ticket}}]"},
+	"moai.comment_char":         {Description: "Character starting comment lines dropped from suggestions; empty uses git's core.commentChar"},
+	"moai.gerrit_change_id":     {Description: "Add a Gerrit Change-Id footer to suggestions"},
+	"moai.with_issues":          {Description: "Add open GitHub issue and PR titles to suggestion context"},
+	"moai.small_diff_lines":     {Description: "Changed-line threshold for the short single-file prompt (0 disables it)", Minimum: bound(0)},
+	"moai.max_body_bullets":     {Description: "Most bullet points in a suggested message's body (0 = subject line only)", Minimum: bound(0)},
+	"moai.max_subject_length":   {Description: "Most characters in a suggested subject; longer ones continue in the body (0 = not enforced)", Minimum: bound(0)},
+	"moai.wrap_body":            {Description: "Column suggested bodies are wrapped at (0 = no wrapping)", Minimum: bound(0)},
+	"moai.max_history_commits":  {Description: "Most commits a summary reads, newest first (0 = no cap)", Minimum: bound(0)},
+	"moai.insight_cache_hours":  {Description: "Hours a summary reuses a cached AI insight for the same commits (0 = until the commits change)", Minimum: bound(0)},
+	"moai.diff_algorithm":       {Description: "git diff algorithm for suggestion diffs, empty for git's default", Enum: []string{"", "myers", "minimal", "patience", "histogram"}},
+	"moai.function_context":     {Description: "Show the whole function around each change in suggestion diffs (git diff --function-context)"},
+	"moai.context_lines":        {Description: "Unchanged lines shown around each change in suggestion diffs (git diff -U, 0 = only changed lines)", Minimum: bound(0)},
+	"moai.max_lines_per_file":   {Description: "Diff lines of each file sent to the model for suggestions (0 = no limit)", Minimum: bound(0)},
+	"moai.max_line_length":      {Description: "Characters of each diff line sent to the model; longer lines are cut (0 = no limit)", Minimum: bound(0)},
+	"moai.ignore_whitespace":    {Description: "Ignore whitespace-only changes such as reindents in suggestion diffs (git diff -w)"},
+
+	"release":             {Description: "Settings for publishing release notes"},
+	"release.provider":    {Description: "Service release notes are published to", Enum: []string{"github", "gitlab"}},
+	"release.gitlab_url":  {Description: "GitLab instance used by the gitlab release provider"},
+	"release.personality": {Description: "Personality whose voice AI release notes are written in (empty = professional)"},
+	"release.timeout":     {Description: "Seconds to wait for each GitHub or GitLab API response (0 = no limit)", Minimum: bound(0)},
+}
+
+// Schema returns a JSON Schema describing the config file, with the default
+// configuration's values as defaults
+func Schema() map[string]interface{} {
+	schema := schemaFor(reflect.TypeOf(Config{}), reflect.ValueOf(DefaultConfig()), "")
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "noidea configuration"
+
+	// Editors read "$schema" from the file itself to find this schema
+	schema["properties"].(map[string]interface{})["$schema"] = map[string]interface{}{"type": "string"}
+	return schema
+}
+
+// schemaFor describes a type; value holds its default when valid
+func schemaFor(t reflect.Type, value reflect.Value, path string) map[string]interface{} {
+	schema := map[string]interface{}{}
+	if field, ok := schemaFields[path]; ok {
+		schema["description"] = field.Description
+		if len(field.Enum) > 0 {
+			schema["enum"] = field.Enum
+		}
+		if field.Minimum != nil {
+			schema["minimum"] = *field.Minimum
+		}
+		if field.Maximum != nil {
+			schema["maximum"] = *field.Maximum
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		schema["type"] = "object"
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			name := jsonName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			var fieldValue reflect.Value
+			if value.IsValid() {
+				fieldValue = value.Field(i)
+			}
+			properties[name] = schemaFor(t.Field(i).Type, fieldValue, joinPath(path, name))
+		}
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+		return schema
+	case reflect.Slice:
+		schema["type"] = "array"
+		schema["items"] = schemaFor(t.Elem(), reflect.Value{}, path)
+		delete(schema["items"].(map[string]interface{}), "description")
+		return schema
+	case reflect.Map:
+		schema["type"] = "object"
+		schema["additionalProperties"] = schemaFor(t.Elem(), reflect.Value{}, "")
+		return schema
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int:
+		schema["type"] = "integer"
+	case reflect.Float64:
+		schema["type"] = "number"
+	case reflect.String:
+		schema["type"] = "string"
+	}
+
+	if value.IsValid() {
+		schema["default"] = value.Interface()
+	}
+	return schema
+}
+
+// jsonName returns the JSON key of a struct field, or "" if it isn't serialized
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// joinPath appends a key to a dotted schema path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
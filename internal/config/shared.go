@@ -0,0 +1,122 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SharedConfigTTL is how long a fetched shared config is used before it is
+// fetched again
+const SharedConfigTTL = time.Hour
+
+// sharedConfigTimeout bounds fetching the shared config, so a slow server
+// doesn't hold up every command
+const sharedConfigTimeout = 10 * time.Second
+
+// maxSharedConfigSize caps the shared config download
+const maxSharedConfigSize = 1 << 20
+
+// failedSharedFetches remembers URLs that couldn't be fetched in this
+// process, so loading the config again doesn't wait or warn again
+var failedSharedFetches = map[string]bool{}
+
+// sharedConfigURL returns the organization-wide base config set by
+// NOIDEA_CONFIG_URL, or "" when there is none
+func sharedConfigURL() string {
+	return strings.TrimSpace(os.Getenv("NOIDEA_CONFIG_URL"))
+}
+
+// sharedConfigCachePath returns where the shared config from rawURL is
+// cached. The name is keyed by the URL, so changing it fetches afresh, and
+// keeps the URL's extension, which selects JSON or TOML.
+func sharedConfigCachePath(homeDir, rawURL string) string {
+	ext := ".json"
+	if parsed, err := url.Parse(rawURL); err == nil && strings.EqualFold(path.Ext(parsed.Path), ".toml") {
+		ext = ".toml"
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(homeDir, ".noidea", "cache", "shared-config-"+hex.EncodeToString(sum[:6])+ext)
+}
+
+// loadSharedConfig returns the shared config from NOIDEA_CONFIG_URL and the
+// cache path naming its format. A cached copy younger than SharedConfigTTL is
+// used as is; otherwise the config is fetched, and when that fails a stale
+// cached copy is used with a warning. ok is false when there is no shared
+// config to apply.
+func loadSharedConfig(homeDir string) (data []byte, cachePath string, ok bool) {
+	rawURL := sharedConfigURL()
+	if rawURL == "" {
+		return nil, "", false
+	}
+
+	cachePath = sharedConfigCachePath(homeDir, rawURL)
+	info, statErr := os.Stat(cachePath)
+	if statErr == nil && time.Since(info.ModTime()) < SharedConfigTTL {
+		if cached, err := os.ReadFile(cachePath); err == nil {
+			return cached, cachePath, true
+		}
+	}
+
+	if failedSharedFetches[rawURL] {
+		cached, err := os.ReadFile(cachePath)
+		return cached, cachePath, err == nil
+	}
+
+	fetched, err := fetchSharedConfig(rawURL, cachePath)
+	if err == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+			_ = os.WriteFile(cachePath, fetched, 0644)
+		}
+		return fetched, cachePath, true
+	}
+	failedSharedFetches[rawURL] = true
+
+	if cached, readErr := os.ReadFile(cachePath); readErr == nil && statErr == nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch shared config from %s: %v; using the copy cached %s\n",
+			rawURL, err, info.ModTime().Format("2006-01-02 15:04"))
+		return cached, cachePath, true
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: Could not fetch shared config from %s: %v; ignoring it\n", rawURL, err)
+	return nil, "", false
+}
+
+// fetchSharedConfig downloads the shared config and checks that it parses,
+// so a broken file never replaces a good cached copy
+func fetchSharedConfig(rawURL, cachePath string) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return nil, fmt.Errorf("NOIDEA_CONFIG_URL must be an http or https URL")
+	}
+
+	client := &http.Client{Timeout: sharedConfigTimeout}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSharedConfigSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var check Config
+	if err := unmarshalConfig(cachePath, data, &check); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+// Layers a setting's value can come from, from lowest to highest priority
+const (
+	SourceDefault = "default"
+	SourceShared  = "shared"
+	SourceFile    = "file"
+	SourceProject = "project"
+	SourceKeyring = "keyring"
+	SourceEnv     = "env"
+	SourceRepo    = "repo"
+)
+
+// Setting is a config value and the layer that set it
+type Setting struct {
+	Path   string // JSON path, e.g. "llm.provider"
+	Value  string
+	Source string
+}
+
+// sourceTracker maps each setting's path to the layer that last set it
+type sourceTracker map[string]string
+
+// record marks the settings that differ between before and after as set by source
+func (s sourceTracker) record(before, after Config, source string) {
+	old := flattenConfig(before)
+	for path, value := range flattenConfig(after) {
+		if old[path] != value {
+			s[path] = source
+		}
+	}
+}
+
+// recordFile marks every setting present in a config file as set by
+// source, including those that repeat the default
+func (s sourceTracker) recordFile(path string, data []byte, source string) {
+	var raw map[string]interface{}
+	var err error
+	if filepath.Ext(path) == ".toml" {
+		err = toml.Unmarshal(data, &raw)
+	} else {
+		err = json.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return
+	}
+
+	present := map[string]bool{}
+	collectKeys(raw, "", present)
+	for setting := range flattenConfig(DefaultConfig()) {
+		if present[setting] {
+			s[setting] = source
+		}
+	}
+}
+
+// collectKeys adds the dotted path of every key in a decoded file to present
+func collectKeys(raw map[string]interface{}, path string, present map[string]bool) {
+	for key, value := range raw {
+		keyPath := joinPath(path, key)
+		present[keyPath] = true
+		if nested, ok := value.(map[string]interface{}); ok {
+			collectKeys(nested, keyPath, present)
+		}
+	}
+}
+
+// settings lists every setting of cfg in path order with its source
+func (s sourceTracker) settings(cfg Config) []Setting {
+	values := flattenConfig(cfg)
+	var settings []Setting
+	for path, value := range values {
+		source := s[path]
+		if source == "" {
+			source = SourceDefault
+		}
+		if path == "llm.api_key" {
+			value = secure.Mask(value)
+		}
+		settings = append(settings, Setting{Path: path, Value: value, Source: source})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+	return settings
+}
+
+// LoadConfigSources loads the configuration like LoadConfig and reports each
+// setting's effective value and whether it came from the defaults, the shared
+// config, the config file, the keyring, the environment or the repository policy
+func LoadConfigSources() (Config, []Setting) {
+	sources := sourceTracker{}
+	user := loadUserConfig(sources, true, ConfigFile)
+	cfg := applyRepoPolicy(user)
+	sources.record(user, cfg, SourceRepo)
+	return cfg, sources.settings(cfg)
+}
+
+// flattenConfig formats every setting of cfg by its JSON path. Lists and maps
+// are single settings, formatted as JSON.
+func flattenConfig(cfg Config) map[string]string {
+	values := map[string]string{}
+	flattenValue(reflect.ValueOf(cfg), "", values)
+	return values
+}
+
+// flattenValue adds the settings below value to values
+func flattenValue(value reflect.Value, path string, values map[string]string) {
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if name := jsonName(value.Type().Field(i)); name != "" {
+				flattenValue(value.Field(i), joinPath(path, name), values)
+			}
+		}
+	case reflect.Slice, reflect.Map:
+		if value.Len() == 0 {
+			values[path] = ""
+			return
+		}
+		encoded, _ := json.Marshal(value.Interface())
+		values[path] = string(encoded)
+	default:
+		values[path] = fmt.Sprint(value.Interface())
+	}
+}
@@ -0,0 +1,185 @@
+// Package conventional parses and normalizes Conventional Commit subjects
+// such as "feat(auth)!: add token refresh"
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Types are the commit types recognized by the Conventional Commits convention
+var Types = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// subjectRegex matches "type(scope)!: description"
+var subjectRegex = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?\s*:\s*(.*)$`)
+
+// Subject is a parsed conventional commit subject line
+type Subject struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// Parse splits a subject line into its conventional commit parts. It reports
+// false when the line does not start with a type prefix.
+func Parse(line string) (Subject, bool) {
+	matches := subjectRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return Subject{}, false
+	}
+
+	return Subject{
+		Type:        strings.ToLower(matches[1]),
+		Scope:       matches[2],
+		Breaking:    matches[3] != "",
+		Description: strings.TrimSpace(matches[4]),
+	}, true
+}
+
+// String formats the subject as "type(scope)!: description"
+func (s Subject) String() string {
+	var b strings.Builder
+	b.WriteString(s.Type)
+	if s.Scope != "" {
+		b.WriteString("(" + s.Scope + ")")
+	}
+	if s.Breaking {
+		b.WriteString("!")
+	}
+	b.WriteString(": ")
+	b.WriteString(s.Description)
+	return b.String()
+}
+
+// IsKnownType reports whether t is one of the standard conventional commit types
+func IsKnownType(t string) bool {
+	return containsFold(Types, t)
+}
+
+// TypeOf returns the lowercased type of a commit message's subject, or "" if
+// the subject doesn't follow the convention
+func TypeOf(message string) string {
+	subject, ok := Parse(strings.SplitN(message, "\n", 2)[0])
+	if !ok {
+		return ""
+	}
+	return subject.Type
+}
+
+// Rules remaps and restricts the commit types used in generated messages
+type Rules struct {
+	// Aliases maps a type to the one that should be used instead, e.g. build -> chore
+	Aliases map[string]string
+	// Allowed lists the only types that may be used; empty allows any type
+	Allowed []string
+}
+
+// IsZero reports whether the rules leave types unchanged
+func (r Rules) IsZero() bool {
+	return len(r.Aliases) == 0 && len(r.Allowed) == 0
+}
+
+// Resolve maps a type through the aliases and onto the allowed set. A type
+// that is still not allowed falls back to "chore" when that is allowed, or
+// to the first allowed type otherwise.
+func (r Rules) Resolve(t string) string {
+	t = strings.ToLower(t)
+	if alias := r.alias(t); alias != "" {
+		t = alias
+	}
+
+	if len(r.Allowed) == 0 || containsFold(r.Allowed, t) {
+		return t
+	}
+	if containsFold(r.Allowed, "chore") {
+		return "chore"
+	}
+	return strings.ToLower(r.Allowed[0])
+}
+
+// alias returns the type an alias maps t to, or "" if t has no alias
+func (r Rules) alias(t string) string {
+	for from, to := range r.Aliases {
+		if strings.EqualFold(from, t) {
+			return strings.ToLower(to)
+		}
+	}
+	return ""
+}
+
+// Apply rewrites the type of a commit message's subject to follow the rules,
+// leaving the scope, description and body untouched
+func (r Rules) Apply(message string) string {
+	if r.IsZero() {
+		return message
+	}
+
+	lines := strings.SplitN(message, "\n", 2)
+	subject, ok := Parse(lines[0])
+	if !ok {
+		return message
+	}
+
+	subject.Type = r.Resolve(subject.Type)
+	lines[0] = subject.String()
+	return strings.Join(lines, "\n")
+}
+
+// PrefixSubject puts prefix, such as a ticket key, before the subject line of
+// a message. Prefixes the subject already has, such as a ticket key from an
+// earlier run on another branch or a gitmoji, are replaced, so applying it
+// again to its own result changes nothing.
+func PrefixSubject(message, prefix string) string {
+	if prefix == "" || message == "" {
+		return message
+	}
+	rest := StripSubjectPrefixes(message, prefix)
+	if strings.HasPrefix(rest, prefix) {
+		// Nothing but the prefix
+		return rest
+	}
+	return prefix + " " + rest
+}
+
+// knownPrefixRegex matches a prefix tools put before a conventional subject:
+// a ticket key such as "[JIRA-123]" or "JIRA-123:", or a gitmoji as a
+// ":sparkles:" shortcode or an emoji
+var knownPrefixRegex = regexp.MustCompile(`^(?:\[[A-Z][A-Z0-9]*-\d+\]:?|[A-Z][A-Z0-9]*-\d+:?|:[a-z0-9_+-]+:|[\x{1F000}-\x{1FAFF}\x{2600}-\x{27BF}\x{2B00}-\x{2BFF}]\x{FE0F}?)[ \t]+`)
+
+// StripSubjectPrefixes removes prefix and any ticket keys and gitmoji from
+// the start of a message's subject line, so a message that was already
+// prefixed can be used as context or prefixed again without doubling them
+func StripSubjectPrefixes(message, prefix string) string {
+	for {
+		trimmed := message
+		if prefix != "" && strings.HasPrefix(trimmed, prefix) {
+			trimmed = strings.TrimLeft(trimmed[len(prefix):], " \t")
+		} else if match := knownPrefixRegex.FindString(trimmed); match != "" {
+			trimmed = trimmed[len(match):]
+		}
+		if trimmed == message || strings.TrimSpace(trimmed) == "" {
+			return message
+		}
+		message = trimmed
+	}
+}
+
+// TrimSubjectPrefix removes a prefix added by PrefixSubject, so the rest of
+// the subject can be parsed
+func TrimSubjectPrefix(message, prefix string) string {
+	if prefix == "" || !strings.HasPrefix(message, prefix) {
+		return message
+	}
+	return strings.TrimLeft(message[len(prefix):], " ")
+}
+
+// containsFold reports whether list contains value, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
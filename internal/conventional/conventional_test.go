@@ -0,0 +1,143 @@
+package conventional
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   Subject
+		wantOK bool
+	}{
+		{"feat: add login", Subject{Type: "feat", Description: "add login"}, true},
+		{"Fix(auth)!: drop tokens", Subject{Type: "fix", Scope: "auth", Breaking: true, Description: "drop tokens"}, true},
+		{"chore :tidy", Subject{Type: "chore", Description: "tidy"}, true},
+		{"Update README", Subject{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Parse(tt.line)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRulesApply(t *testing.T) {
+	rules := Rules{
+		Aliases: map[string]string{"build": "chore", "Style": "refactor"},
+		Allowed: []string{"feat", "fix", "chore", "refactor", "docs"},
+	}
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"build(deps): bump cobra", "chore(deps): bump cobra"},
+		{"style: format imports\n\n- run gofmt", "refactor: format imports\n\n- run gofmt"},
+		{"perf!: cache stats", "chore!: cache stats"},
+		{"feat: add login", "feat: add login"},
+		{"Update README", "Update README"},
+	}
+
+	for _, tt := range tests {
+		if got := rules.Apply(tt.message); got != tt.want {
+			t.Errorf("Apply(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+
+	noChore := Rules{Allowed: []string{"feat", "fix"}}
+	if got := noChore.Resolve("docs"); got != "feat" {
+		t.Errorf("Resolve without chore = %q, want first allowed type", got)
+	}
+
+	if got := (Rules{}).Apply("build: x"); got != "build: x" {
+		t.Errorf("zero rules should leave messages unchanged, got %q", got)
+	}
+}
+
+func TestAppendFooters(t *testing.T) {
+	footers := []Footer{{Key: "Reviewed-by", Value: "Jane <jane@example.com>"}, {Key: "Refs", Value: "ABC-12"}}
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"fix: typo", "fix: typo\n\nReviewed-by: Jane <jane@example.com>\nRefs: ABC-12"},
+		{"feat: add login\n\n- add form\n- add handler\n",
+			"feat: add login\n\n- add form\n- add handler\n\nReviewed-by: Jane <jane@example.com>\nRefs: ABC-12"},
+		{"feat: add login\n\n- add form\n\nCloses #12",
+			"feat: add login\n\n- add form\n\nCloses #12\nReviewed-by: Jane <jane@example.com>\nRefs: ABC-12"},
+		{"fix: typo\n\nRefs: ABC-12", "fix: typo\n\nRefs: ABC-12\nReviewed-by: Jane <jane@example.com>"},
+	}
+
+	for _, tt := range tests {
+		if got := AppendFooters(tt.message, footers); got != tt.want {
+			t.Errorf("AppendFooters(%q) =\n%q\nwant\n%q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestParseFooter(t *testing.T) {
+	footer, err := ParseFooter("Change-Id: I8473b95934b5732ac55d26311a706c9c2bde9940")
+	if err != nil || footer.Key != "Change-Id" || footer.Value != "I8473b95934b5732ac55d26311a706c9c2bde9940" {
+		t.Errorf("ParseFooter() = %+v, %v", footer, err)
+	}
+
+	for _, bad := range []string{"Reviewed by: Jane", "Refs", "Refs:", "-x: y"} {
+		if _, err := ParseFooter(bad); err == nil {
+			t.Errorf("ParseFooter(%q) should fail", bad)
+		}
+	}
+}
+
+func TestFooterValue(t *testing.T) {
+	message := "fix: typo\n\nCloses #12\nChange-Id: I0123abcd"
+	if got := FooterValue(message, "change-id"); got != "I0123abcd" {
+		t.Errorf("FooterValue(Change-Id) = %q", got)
+	}
+	if got := FooterValue(message, "Refs"); got != "" {
+		t.Errorf("FooterValue(Refs) = %q, want empty", got)
+	}
+}
+
+func TestPrefixSubject(t *testing.T) {
+	if got := PrefixSubject("feat: add login\n\n- add form", "[JIRA-123]"); got != "[JIRA-123] feat: add login\n\n- add form" {
+		t.Errorf("PrefixSubject() = %q", got)
+	}
+	if got := PrefixSubject("[JIRA-123] feat: add login", "[JIRA-123]"); got != "[JIRA-123] feat: add login" {
+		t.Errorf("PrefixSubject() added the prefix twice: %q", got)
+	}
+	if got := PrefixSubject("[OLD-7] :sparkles: feat: add login", "[JIRA-123]"); got != "[JIRA-123] feat: add login" {
+		t.Errorf("PrefixSubject() kept stale prefixes: %q", got)
+	}
+	if got := PrefixSubject("[JIRA-123]", "[JIRA-123]"); got != "[JIRA-123]" {
+		t.Errorf("PrefixSubject() of the bare prefix = %q", got)
+	}
+	if got := TrimSubjectPrefix("[JIRA-123] feat: add login", "[JIRA-123]"); got != "feat: add login" {
+		t.Errorf("TrimSubjectPrefix() = %q", got)
+	}
+}
+
+func TestStripSubjectPrefixes(t *testing.T) {
+	tests := []struct {
+		message string
+		prefix  string
+		want    string
+	}{
+		{"feat: add login", "", "feat: add login"},
+		{"[JIRA-123] feat: add login\n\n- [ABC-1] stays", "[JIRA-123]", "feat: add login\n\n- [ABC-1] stays"},
+		{"✨ feat: add login", "", "feat: add login"},
+		{"⚡️ perf: cache lookups", "", "perf: cache lookups"},
+		{":bug: ABC-42: fix: handle nil", "", "fix: handle nil"},
+		{"[JIRA-123] [JIRA-123] feat: add login", "[JIRA-123]", "feat: add login"},
+		{"🚀 [OPS-1] chore: deploy", "🚀", "chore: deploy"},
+		{"API design notes", "", "API design notes"},
+		{"[JIRA-123]", "", "[JIRA-123]"},
+	}
+
+	for _, tt := range tests {
+		if got := StripSubjectPrefixes(tt.message, tt.prefix); got != tt.want {
+			t.Errorf("StripSubjectPrefixes(%q, %q) = %q, want %q", tt.message, tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package conventional
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// footerKeyRegex matches a footer token: a word using "-" in place of spaces,
+// or the special "BREAKING CHANGE" token
+var footerKeyRegex = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9-]*|BREAKING CHANGE)$`)
+
+// footerLineRegex matches "Token: value" and "Token #value" footer lines
+var footerLineRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|BREAKING CHANGE)(?:: | #)(.+)$`)
+
+// Footer is a trailer such as "Reviewed-by: Jane <jane@example.com>"
+type Footer struct {
+	Key   string
+	Value string
+}
+
+// String formats the footer as "Key: value"
+func (f Footer) String() string {
+	return f.Key + ": " + f.Value
+}
+
+// ValidFooterKey reports whether key can be used as a footer token
+func ValidFooterKey(key string) bool {
+	return footerKeyRegex.MatchString(key)
+}
+
+// ParseFooter parses a "Key: value" string into a footer
+func ParseFooter(s string) (Footer, error) {
+	key, value, found := strings.Cut(s, ":")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !found || value == "" {
+		return Footer{}, fmt.Errorf("footer %q must look like \"Key: value\"", s)
+	}
+	if !ValidFooterKey(key) {
+		return Footer{}, fmt.Errorf("footer key %q must be a single word using '-' instead of spaces", key)
+	}
+	return Footer{Key: key, Value: value}, nil
+}
+
+// FooterValue returns the value of the first footer named key in a commit
+// message, or "" when there is none
+func FooterValue(message, key string) string {
+	for _, line := range strings.Split(message, "\n") {
+		matches := footerLineRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if matches != nil && strings.EqualFold(matches[1], key) {
+			return strings.TrimSpace(matches[2])
+		}
+	}
+	return ""
+}
+
+// IsFooterLine reports whether a line looks like a footer such as
+// "Reviewed-by: Jane" or "Closes #12"
+func IsFooterLine(line string) bool {
+	return footerLineRegex.MatchString(strings.TrimSpace(line))
+}
+
+// AppendFooters adds footers to the end of a commit message. They join an
+// existing footer paragraph such as "Closes #12", or start a new paragraph
+// after the body. Footers already present are not repeated.
+func AppendFooters(message string, footers []Footer) string {
+	message = strings.TrimRight(message, "\n")
+	if len(footers) == 0 {
+		return message
+	}
+
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+
+	// The subject on its own is never a footer paragraph
+	hasFooters := len(paragraphs) > 1
+	for _, line := range strings.Split(last, "\n") {
+		if !IsFooterLine(line) {
+			hasFooters = false
+			break
+		}
+	}
+
+	var added []string
+	for _, footer := range footers {
+		line := footer.String()
+		if hasFooters && containsLine(last, line) || containsLine(strings.Join(added, "\n"), line) {
+			continue
+		}
+		added = append(added, line)
+	}
+	if len(added) == 0 {
+		return message
+	}
+
+	if hasFooters {
+		return message + "\n" + strings.Join(added, "\n")
+	}
+	return message + "\n\n" + strings.Join(added, "\n")
+}
+
+// containsLine reports whether text has a line equal to line
+func containsLine(text, line string) bool {
+	for _, l := range strings.Split(text, "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package conventional
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Severities of lint issues
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+const (
+	// SubjectGuideLength is the subject length suggestions aim for
+	SubjectGuideLength = 50
+	// SubjectMaxLength is the length above which git tools truncate subjects
+	SubjectMaxLength = 72
+)
+
+// scissorsMarker is git's scissors line after the comment character
+const scissorsMarker = " ------------------------ >8 ------------------------"
+
+// scissorsLine starts the part of a commit message file that git discards
+const scissorsLine = "#" + scissorsMarker
+
+// isScissorsLine reports whether line is git's scissors line, whatever
+// comment character core.commentChar sets
+func isScissorsLine(line string) bool {
+	prefix, ok := strings.CutSuffix(strings.TrimRight(line, "\r"), scissorsMarker)
+	return ok && prefix != "" && !strings.ContainsAny(prefix, " \t")
+}
+
+// IsCommentLine reports whether a commit message line is a comment: the
+// comment character ("#" when empty) followed by whitespace or nothing.
+// Lines such as "#123" or "##" are content.
+func IsCommentLine(line, commentChar string) bool {
+	if commentChar == "" {
+		commentChar = "#"
+	}
+	rest, ok := strings.CutPrefix(line, commentChar)
+	if !ok {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(rest)
+	return rest == "" || unicode.IsSpace(next)
+}
+
+// SplitScissors splits the contents of a commit message file at git's
+// scissors line, which "git commit -v" puts above the diff. It returns the
+// text above the line and the line with everything below it, or "" when
+// there is no scissors line.
+func SplitScissors(content string) (string, string) {
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if isScissorsLine(strings.TrimSuffix(line, "\n")) {
+			return content[:offset], content[offset:]
+		}
+		offset += len(line)
+	}
+	return content, ""
+}
+
+// Issue is a problem Lint found in a commit message
+type Issue struct {
+	Severity string
+	Line     int // 1-based line in the cleaned-up message
+	Message  string
+}
+
+// String formats the issue as "line N: severity: message"
+func (i Issue) String() string {
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Severity, i.Message)
+}
+
+// CleanMessage removes what git strips from a commit message file: comment
+// lines starting with commentChar ("#" when empty), everything below the
+// scissors line and surrounding blank lines
+func CleanMessage(message, commentChar string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if isScissorsLine(line) {
+			break
+		}
+		if IsCommentLine(line, commentChar) {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Lint checks a commit message against the Conventional Commits rules noidea
+// follows when it writes messages: a known type prefix, a concise subject, a
+// blank line before the body and "- " bullets. With rules, the type must also
+// be one of the allowed types. Comment lines start with commentChar, "#" when empty.
+func Lint(message string, rules Rules, commentChar string) []Issue {
+	message = CleanMessage(message, commentChar)
+	if strings.TrimSpace(message) == "" {
+		return []Issue{{Severity: SeverityError, Line: 1, Message: "commit message is empty"}}
+	}
+
+	lines := strings.Split(message, "\n")
+	if isGeneratedSubject(lines[0]) {
+		return nil
+	}
+	issues := lintSubject(lines[0], rules)
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		issues = append(issues, Issue{SeverityError, 2, "separate the subject from the body with a blank line"})
+	}
+
+	for i := 2; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "" || IsFooterLine(line):
+		case line == "-":
+			issues = append(issues, Issue{SeverityWarning, i + 1, "bullet point is empty"})
+		case strings.HasPrefix(line, "* "), strings.HasPrefix(line, "•"):
+			issues = append(issues, Issue{SeverityWarning, i + 1, "start bullet points with \"- \""})
+		case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "- "):
+			issues = append(issues, Issue{SeverityWarning, i + 1, "put a space after the \"-\" of a bullet point"})
+		}
+	}
+
+	return issues
+}
+
+// lintSubject checks the subject line
+func lintSubject(line string, rules Rules) []Issue {
+	var issues []Issue
+
+	subject, ok := Parse(line)
+	if !ok {
+		return append(issues, Issue{SeverityError, 1, fmt.Sprintf("subject must start with a type such as \"feat: \" or \"fix(scope): \" (one of %s)", strings.Join(Types, ", "))})
+	}
+
+	valid := IsKnownType(subject.Type)
+	if len(rules.Allowed) > 0 {
+		valid = containsFold(rules.Allowed, subject.Type)
+	}
+
+	switch alias := rules.alias(subject.Type); {
+	case alias != "":
+		severity := SeverityWarning
+		if !valid {
+			severity = SeverityError
+		}
+		issues = append(issues, Issue{severity, 1, fmt.Sprintf("this project uses %q instead of %q", alias, subject.Type)})
+	case !valid && len(rules.Allowed) > 0:
+		issues = append(issues, Issue{SeverityError, 1, fmt.Sprintf("type %q is not allowed here (use %s)", subject.Type, strings.Join(rules.Allowed, ", "))})
+	case !valid:
+		issues = append(issues, Issue{SeverityError, 1, fmt.Sprintf("unknown type %q (use %s)", subject.Type, strings.Join(Types, ", "))})
+	}
+
+	if subject.Description == "" {
+		return append(issues, Issue{SeverityError, 1, "subject has no description after the type"})
+	}
+
+	// Spacing and case of the prefix, e.g. "Feat :" instead of "feat: "
+	if prefix := strings.TrimSuffix(subject.String(), subject.Description); !strings.HasPrefix(line, prefix) {
+		issues = append(issues, Issue{SeverityWarning, 1, fmt.Sprintf("write the prefix as %q", prefix)})
+	}
+
+	switch length := utf8.RuneCountInString(line); {
+	case length > SubjectMaxLength:
+		issues = append(issues, Issue{SeverityError, 1, fmt.Sprintf("subject is %d characters; keep it under %d", length, SubjectMaxLength)})
+	case length > SubjectGuideLength:
+		issues = append(issues, Issue{SeverityWarning, 1, fmt.Sprintf("subject is %d characters; aim for about %d", length, SubjectGuideLength)})
+	}
+
+	if strings.HasSuffix(subject.Description, ".") {
+		issues = append(issues, Issue{SeverityWarning, 1, "subject should not end with a period"})
+	}
+
+	if first, _ := utf8.DecodeRuneInString(subject.Description); unicode.IsUpper(first) && !isAcronym(subject.Description) {
+		issues = append(issues, Issue{SeverityWarning, 1, "start the description with a lowercase letter"})
+	}
+
+	return issues
+}
+
+// isGeneratedSubject reports whether a subject was written by git itself,
+// such as a merge, a revert or an autosquash commit
+func isGeneratedSubject(subject string) bool {
+	for _, prefix := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAcronym reports whether a description starts with an all-caps word such
+// as "API" or "README", which keeps its capitals
+func isAcronym(description string) bool {
+	word := strings.FieldsFunc(description, func(r rune) bool { return !unicode.IsLetter(r) })
+	if len(word) == 0 || utf8.RuneCountInString(word[0]) < 2 {
+		return false
+	}
+	return strings.ToUpper(word[0]) == word[0]
+}
+
+// HasErrors reports whether any issue is an error rather than a warning
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package conventional
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		rules       Rules
+		commentChar string
+		want        []string // Expected "severity: message" prefixes, in order
+	}{
+		{
+			name:    "valid with body and footer",
+			message: "feat(auth): add token refresh\n\n- refresh before expiry\n\nRefs: #12\n",
+		},
+		{
+			name:    "comments and scissors are ignored",
+			message: "fix: handle empty diff\n# Please enter the commit message\n" + scissorsLine + "\ndiff --git a/x b/x\n",
+		},
+		{
+			name:    "issue references are content, not comments",
+			message: "fix: close the session leak\n\n#123 was caused by the cache\n",
+		},
+		{
+			name:    "issue reference right after the subject",
+			message: "fix: close the session leak\n#123\n",
+			want:    []string{"error: separate the subject from the body"},
+		},
+		{
+			name:        "core.commentChar",
+			message:     "fix: handle empty diff\n; Please enter the commit message\n",
+			commentChar: ";",
+		},
+		{
+			name:        "hash lines are content with another comment character",
+			message:     "fix: handle empty diff\n# heading\n",
+			commentChar: ";",
+			want:        []string{"error: separate the subject from the body"},
+		},
+		{
+			name:    "git generated subjects are skipped",
+			message: "Merge branch 'main' into feature",
+		},
+		{
+			name:    "empty",
+			message: "# only a comment\n",
+			want:    []string{"error: commit message is empty"},
+		},
+		{
+			name:    "missing type",
+			message: "Add token refresh",
+			want:    []string{"error: subject must start with a type"},
+		},
+		{
+			name:    "unknown type",
+			message: "feature: add token refresh",
+			want:    []string{"error: unknown type \"feature\""},
+		},
+		{
+			name:    "formatting",
+			message: "Fix : Handle empty diff.\nbody\n* item\n-item\n-",
+			want: []string{
+				"warning: write the prefix as \"fix: \"",
+				"warning: subject should not end with a period",
+				"warning: start the description with a lowercase letter",
+				"error: separate the subject from the body",
+				"warning: start bullet points with",
+				"warning: put a space after",
+				"warning: bullet point is empty",
+			},
+		},
+		{
+			name:    "acronyms keep their capitals",
+			message: "docs: README covers install",
+		},
+		{
+			name:    "long subject",
+			message: "fix: " + strings.Repeat("a", 70),
+			want:    []string{"error: subject is 75 characters"},
+		},
+		{
+			name:    "aliases and allowed types",
+			message: "build: bump go",
+			rules:   Rules{Aliases: map[string]string{"build": "chore"}, Allowed: []string{"feat", "fix", "chore"}},
+			want:    []string{"error: this project uses \"chore\" instead of \"build\""},
+		},
+		{
+			name:    "type outside allowed list",
+			message: "perf: speed up diff",
+			rules:   Rules{Allowed: []string{"feat", "fix"}},
+			want:    []string{"error: type \"perf\" is not allowed here"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := Lint(tt.message, tt.rules, tt.commentChar)
+			if len(issues) != len(tt.want) {
+				t.Fatalf("Lint() = %v, want %d issues", issues, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := issues[i].Severity + ": " + issues[i].Message; !strings.HasPrefix(got, want) {
+					t.Errorf("issue %d = %q, want prefix %q", i, got, want)
+				}
+			}
+			if HasErrors(issues) != strings.Contains(strings.Join(tt.want, "\n"), "error:") {
+				t.Errorf("HasErrors() = %v for %v", HasErrors(issues), issues)
+			}
+		})
+	}
+}
+
+func TestSplitScissors(t *testing.T) {
+	verbose := scissorsLine + "\n# Do not modify or remove the line above.\ndiff --git a/x b/x\n"
+	tests := []struct {
+		name    string
+		content string
+		message string
+		rest    string
+	}{
+		{"no scissors", "fix: a\n# comment\n", "fix: a\n# comment\n", ""},
+		{"verbose", "\n# Please enter the commit message\n" + verbose, "\n# Please enter the commit message\n", verbose},
+		{"comment char", "fix: a\n;" + scissorsMarker + "\ndiff\n", "fix: a\n", ";" + scissorsMarker + "\ndiff\n"},
+		{"not a scissors line", "fix: a\n  #" + scissorsMarker + "\n", "fix: a\n  #" + scissorsMarker + "\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, rest := SplitScissors(tt.content)
+			if message != tt.message || rest != tt.rest {
+				t.Errorf("SplitScissors() = %q, %q, want %q, %q", message, rest, tt.message, tt.rest)
+			}
+		})
+	}
+}
+
+func TestCleanMessage(t *testing.T) {
+	tests := []struct {
+		message, commentChar, want string
+	}{
+		{"fix: a\n\n# Please enter the commit message\n#\n", "", "fix: a"},
+		{"fix: a\n\n#123 and ##2 stay\n", "", "fix: a\n\n#123 and ##2 stay"},
+		{"fix: a\n\n; comment\n# kept\n", ";", "fix: a\n\n# kept"},
+		{"fix: a  \n" + scissorsLine + "\ndiff\n", "", "fix: a"},
+	}
+	for _, tt := range tests {
+		if got := CleanMessage(tt.message, tt.commentChar); got != tt.want {
+			t.Errorf("CleanMessage(%q, %q) = %q, want %q", tt.message, tt.commentChar, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package feedback
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// File categories of a diff, in the order they are described
+const (
+	CategoryDoc    = "doc"
+	CategoryCode   = "code"
+	CategoryBuild  = "build"
+	CategoryScript = "script"
+	CategoryConfig = "config"
+	CategoryTest   = "test"
+)
+
+// FileCategories lists every file category in display order
+var FileCategories = []string{CategoryDoc, CategoryCode, CategoryBuild, CategoryScript, CategoryConfig, CategoryTest}
+
+// categoryLabels names each category in prompts and reports
+var categoryLabels = map[string]string{
+	CategoryDoc:    "Documentation files",
+	CategoryCode:   "Code files",
+	CategoryBuild:  "Build files",
+	CategoryScript: "Script files",
+	CategoryConfig: "Config files",
+	CategoryTest:   "Test files",
+}
+
+// CategoryLabel returns the display name of a file category
+func CategoryLabel(category string) string {
+	return categoryLabels[category]
+}
+
+// extensionCategories maps file extensions to categories
+var extensionCategories = map[string]string{
+	// Documentation files
+	".md": CategoryDoc, ".txt": CategoryDoc, ".rst": CategoryDoc, ".adoc": CategoryDoc,
+	".markdown": CategoryDoc, ".wiki": CategoryDoc, ".org": CategoryDoc,
+
+	// Source code files
+	".go": CategoryCode, ".js": CategoryCode, ".ts": CategoryCode, ".py": CategoryCode,
+	".java": CategoryCode, ".c": CategoryCode, ".cpp": CategoryCode, ".cc": CategoryCode,
+	".h": CategoryCode, ".hpp": CategoryCode, ".cs": CategoryCode, ".rb": CategoryCode,
+	".php": CategoryCode, ".swift": CategoryCode, ".kt": CategoryCode, ".rs": CategoryCode,
+
+	// Configuration files
+	".json": CategoryConfig, ".yaml": CategoryConfig, ".yml": CategoryConfig, ".toml": CategoryConfig,
+	".ini": CategoryConfig, ".xml": CategoryConfig, ".properties": CategoryConfig, ".conf": CategoryConfig,
+
+	// Build files
+	".bazel": CategoryBuild, ".bzl": CategoryBuild, ".mk": CategoryBuild,
+
+	// Script files
+	".sh": CategoryScript, ".bash": CategoryScript, ".zsh": CategoryScript,
+	".bat": CategoryScript, ".cmd": CategoryScript, ".ps1": CategoryScript,
+}
+
+// fileCategory returns the category of a path, or "" if it has none
+func fileCategory(path string) string {
+	baseName := filepath.Base(path)
+
+	// Special file handling for common non-extension files
+	if baseName == "Makefile" || baseName == "Dockerfile" ||
+		baseName == "CMakeLists.txt" || strings.HasPrefix(baseName, "Jenkinsfile") {
+		return CategoryBuild
+	}
+	if isTestFile(path) {
+		return CategoryTest
+	}
+	return extensionCategories[filepath.Ext(path)]
+}
+
+// DiffFiles describes the files a diff touches. Paths are sorted.
+type DiffFiles struct {
+	Changed    []string            `json:"changed"`
+	Added      []string            `json:"added"`
+	Modified   []string            `json:"modified"`
+	Deleted    []string            `json:"deleted"`
+	Binary     []string            `json:"binary"`     // Files git shows as binary, whatever the operation
+	Categories map[string][]string `json:"categories"` // By category, e.g. CategoryCode
+	Additions  int                 `json:"additions"`  // Changed text lines; binary files aren't counted
+	Deletions  int                 `json:"deletions"`
+}
+
+// analyzeDiffFiles lists the files in a diff by category and operation and
+// counts the changed lines of text files
+func analyzeDiffFiles(diff string) DiffFiles {
+	changed := make(map[string]bool)
+	binary := make(map[string]bool)
+	added := make(map[string]bool)
+	modified := make(map[string]bool)
+	deleted := make(map[string]bool)
+	categories := make(map[string]map[string]bool)
+
+	var files DiffFiles
+	currentFile := ""
+	inBinaryPatch := false
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git") {
+			inBinaryPatch = false
+			parts := strings.Fields(line)
+			if len(parts) >= 3 {
+				currentFile = strings.TrimPrefix(parts[2], "a/")
+				changed[currentFile] = true
+
+				if category := fileCategory(currentFile); category != "" {
+					if categories[category] == nil {
+						categories[category] = make(map[string]bool)
+					}
+					categories[category][currentFile] = true
+				}
+			}
+		} else if strings.HasPrefix(line, "new file mode") {
+			added[currentFile] = true
+		} else if strings.HasPrefix(line, "deleted file mode") {
+			deleted[currentFile] = true
+		} else if isBinaryMarker(line) && currentFile != "" {
+			binary[currentFile] = true
+			inBinaryPatch = line == binaryPatchMarker
+		} else if inBinaryPatch {
+			// Encoded contents, not lines of the file
+			continue
+		} else if currentFile != "" && !deleted[currentFile] && !added[currentFile] {
+			modified[currentFile] = true
+		}
+
+		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+			files.Additions++
+		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
+			files.Deletions++
+		}
+	}
+
+	// A file is only modified if it wasn't added or deleted
+	for file := range added {
+		delete(modified, file)
+	}
+	for file := range deleted {
+		delete(modified, file)
+	}
+
+	files.Changed = sortedPaths(changed)
+	files.Added = sortedPaths(added)
+	files.Modified = sortedPaths(modified)
+	files.Deleted = sortedPaths(deleted)
+	files.Binary = sortedPaths(binary)
+	files.Categories = make(map[string][]string)
+	for category, paths := range categories {
+		files.Categories[category] = sortedPaths(paths)
+	}
+
+	return files
+}
+
+// describe summarizes the files for the suggestion prompt
+func (f DiffFiles) describe() string {
+	var result strings.Builder
+	fmt.Fprintf(&result, "- Total files changed: %d (%d added, %d modified, %d deleted)\n",
+		len(f.Changed), len(f.Added), len(f.Modified), len(f.Deleted))
+	fmt.Fprintf(&result, "- Lines: +%d, -%d\n", f.Additions, f.Deletions)
+	if len(f.Binary) > 0 {
+		fmt.Fprintf(&result, "- Binary files changed: %s (no line changes shown)\n", strings.Join(f.Binary, ", "))
+	}
+	result.WriteString("\n")
+
+	for _, category := range FileCategories {
+		if paths := f.Categories[category]; len(paths) > 0 {
+			fmt.Fprintf(&result, "%s: %s\n", categoryLabels[category], strings.Join(paths, ", "))
+		}
+	}
+
+	result.WriteString("\nFile operations:\n")
+	for _, op := range []struct {
+		label string
+		paths []string
+	}{{"Added", f.Added}, {"Modified", f.Modified}, {"Deleted", f.Deleted}} {
+		if len(op.paths) > 0 {
+			fmt.Fprintf(&result, "%s: %s\n", op.label, strings.Join(op.paths, ", "))
+		}
+	}
+
+	return result.String()
+}
+
+// DiffAnalysis is everything noidea reads from a diff before asking for a
+// commit message: the files involved and the declarations that changed
+type DiffAnalysis struct {
+	Files      DiffFiles           `json:"files"`
+	Functions  map[string]string   `json:"functions"`  // Declaration -> "+" added or "-" removed
+	Imports    []string            `json:"imports"`    // Added or removed imports
+	Variables  map[string]string   `json:"variables"`  // Name -> assigned value
+	Package    string              `json:"package"`    // Last package declaration changed
+	Interfaces map[string][]string `json:"interfaces"` // Interface -> changed methods
+	Structs    map[string][]string `json:"structs"`    // Struct -> changed fields
+	Constants  map[string]string   `json:"constants"`  // Name -> value
+}
+
+// AnalyzeDiff runs the diff analysis used for suggestions without calling a
+// model, to show what a suggestion is based on. Any patch format that
+// NormalizeDiff understands can be analyzed.
+func AnalyzeDiff(diff string) DiffAnalysis {
+	diff = NormalizeDiff(diff)
+	semantics := extractCodeSemantics(diff)
+	structure := analyzeCodeStructure(diff)
+
+	analysis := DiffAnalysis{Files: analyzeDiffFiles(diff)}
+	analysis.Functions, _ = semantics["functions"].(map[string]string)
+	analysis.Imports, _ = semantics["imports"].([]string)
+	analysis.Variables, _ = semantics["variables"].(map[string]string)
+	analysis.Package, _ = structure["package"].(string)
+	analysis.Interfaces, _ = structure["interfaces"].(map[string][]string)
+	analysis.Structs, _ = structure["structs"].(map[string][]string)
+	analysis.Constants, _ = structure["constants"].(map[string]string)
+
+	return analysis
+}
+
+// sortedPaths returns the keys of a set in order
+func sortedPaths(set map[string]bool) []string {
+	paths := make([]string, 0, len(set))
+	for path := range set {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
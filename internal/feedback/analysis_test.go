@@ -0,0 +1,71 @@
+package feedback
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeDiff(t *testing.T) {
+	diff := `diff --git a/auth/login.go b/auth/login.go
+new file mode 100644
+--- /dev/null
++++ b/auth/login.go
+@@ -0,0 +1,9 @@
++package auth
++
++import "net/http"
++
++type Session struct {
++	Token string
++}
++
++func Login(w http.ResponseWriter, r *http.Request) {}
+diff --git a/auth/login_test.go b/auth/login_test.go
+index 1111111..2222222 100644
+--- a/auth/login_test.go
++++ b/auth/login_test.go
+@@ -1,2 +1,2 @@
+-func TestOld(t *testing.T) {}
++func TestLogin(t *testing.T) {}
+diff --git a/README.md b/README.md
+deleted file mode 100644
+--- a/README.md
++++ /dev/null
+@@ -1 +0,0 @@
+-# Auth
+`
+
+	analysis := AnalyzeDiff(diff)
+	files := analysis.Files
+
+	if !reflect.DeepEqual(files.Added, []string{"auth/login.go"}) ||
+		!reflect.DeepEqual(files.Modified, []string{"auth/login_test.go"}) ||
+		!reflect.DeepEqual(files.Deleted, []string{"README.md"}) {
+		t.Errorf("operations = added %v, modified %v, deleted %v", files.Added, files.Modified, files.Deleted)
+	}
+	wantCategories := map[string][]string{
+		CategoryCode: {"auth/login.go"},
+		CategoryTest: {"auth/login_test.go"},
+		CategoryDoc:  {"README.md"},
+	}
+	if !reflect.DeepEqual(files.Categories, wantCategories) {
+		t.Errorf("Categories = %v, want %v", files.Categories, wantCategories)
+	}
+	if files.Additions != 10 || files.Deletions != 2 {
+		t.Errorf("lines = +%d -%d, want +10 -2", files.Additions, files.Deletions)
+	}
+
+	wantFunctions := map[string]string{"func Login": "+", "func TestLogin": "+", "func TestOld": "-"}
+	if !reflect.DeepEqual(analysis.Functions, wantFunctions) {
+		t.Errorf("Functions = %v, want %v", analysis.Functions, wantFunctions)
+	}
+	if !reflect.DeepEqual(analysis.Imports, []string{"net/http"}) {
+		t.Errorf("Imports = %v", analysis.Imports)
+	}
+	if !reflect.DeepEqual(analysis.Structs, map[string][]string{"Session": {"Token"}}) {
+		t.Errorf("Structs = %v", analysis.Structs)
+	}
+	if analysis.Package != "auth" {
+		t.Errorf("Package = %q, want auth", analysis.Package)
+	}
+}
@@ -0,0 +1,40 @@
+package feedback
+
+import "strings"
+
+// Markers git writes for a binary file instead of its lines. Without --binary
+// a diff only says the file differs; with it (and in format-patch output) the
+// marker is followed by the file's contents in base85.
+const (
+	binaryDiffMarker  = "Binary files "
+	binaryPatchMarker = "GIT binary patch"
+)
+
+// isBinaryMarker reports whether a diff line says its file is binary
+func isBinaryMarker(line string) bool {
+	return strings.HasPrefix(line, binaryDiffMarker) || line == binaryPatchMarker
+}
+
+// stripBinaryPatches drops the encoded contents that follow "GIT binary patch"
+// lines, keeping the marker, so binary data isn't sent to the model
+func stripBinaryPatches(diff string) string {
+	if !strings.Contains(diff, binaryPatchMarker) {
+		return diff
+	}
+
+	lines := strings.Split(diff, "\n")
+	kept := lines[:0]
+	inPatch := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git"):
+			inPatch = false
+		case inPatch:
+			continue
+		case line == binaryPatchMarker:
+			inPatch = true
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
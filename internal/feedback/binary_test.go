@@ -0,0 +1,61 @@
+package feedback
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// binaryDiff changes a text file, adds an image with --binary and modifies a
+// binary without it
+const binaryDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1 +1,2 @@
+ package main
++// logo
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..3333333
+GIT binary patch
+literal 12
+TcmZ?wbhEHbRA6Xe-+%xB
+
+literal 0
+HcmV?d00001
+
+diff --git a/app.bin b/app.bin
+index 4444444..5555555 100644
+Binary files a/app.bin and b/app.bin differ
+`
+
+func TestAnalyzeDiffFilesBinary(t *testing.T) {
+	files := analyzeDiffFiles(binaryDiff)
+
+	if want := []string{"app.bin", "logo.png"}; !reflect.DeepEqual(files.Binary, want) {
+		t.Errorf("Binary = %v, want %v", files.Binary, want)
+	}
+	if files.Additions != 1 || files.Deletions != 0 {
+		t.Errorf("lines = +%d -%d, want +1 -0", files.Additions, files.Deletions)
+	}
+	if want := []string{"logo.png"}; !reflect.DeepEqual(files.Added, want) {
+		t.Errorf("Added = %v, want %v", files.Added, want)
+	}
+	if !strings.Contains(files.describe(), "Binary files changed: app.bin, logo.png") {
+		t.Errorf("describe() doesn't list the binary files:\n%s", files.describe())
+	}
+}
+
+func TestStripBinaryPatches(t *testing.T) {
+	stripped := stripBinaryPatches(binaryDiff)
+
+	if strings.Contains(stripped, "literal") || strings.Contains(stripped, "TcmZ") {
+		t.Errorf("stripBinaryPatches() kept encoded contents:\n%s", stripped)
+	}
+	for _, kept := range []string{"+// logo", "GIT binary patch", "diff --git a/app.bin b/app.bin", "Binary files a/app.bin and b/app.bin differ"} {
+		if !strings.Contains(stripped, kept) {
+			t.Errorf("stripBinaryPatches() dropped %q", kept)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package feedback
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+)
+
+// defaultBodyBullets is the most bullet points asked for without a limit
+const defaultBodyBullets = 4
+
+// bulletRange describes how many bullet points to ask for, e.g. "2-4 bullet points"
+func bulletRange(limit int) string {
+	switch {
+	case limit == 1:
+		return "1 bullet point"
+	case limit == 2:
+		return "2 bullet points"
+	case limit > 2:
+		return fmt.Sprintf("2-%d bullet points", limit)
+	default:
+		return fmt.Sprintf("2-%d bullet points", defaultBodyBullets)
+	}
+}
+
+// limitBody keeps the first limit bullet points of a message's body, or
+// drops the body entirely for NoBody. Issue footers are kept either way, and
+// a limit of 0 leaves the message alone.
+func limitBody(message string, limit int) string {
+	if limit == 0 {
+		return message
+	}
+	subject, rest, found := strings.Cut(message, "\n")
+	if !found {
+		return message
+	}
+
+	var body, footers []string
+	bullets := 0
+	dropping := false
+	for _, line := range strings.Split(rest, "\n") {
+		switch {
+		case issueFooterRegex.MatchString(line):
+			footers = append(footers, line)
+			continue
+		case strings.HasPrefix(line, "- "):
+			bullets++
+			dropping = limit == NoBody || bullets > limit
+		case line == "":
+			dropping = false
+		case limit == NoBody:
+			// Prose is part of the body too
+			dropping = true
+		}
+		if !dropping {
+			body = append(body, line)
+		}
+	}
+
+	result := subject
+	if text := strings.TrimSpace(strings.Join(body, "\n")); text != "" {
+		result += "\n\n" + text
+	}
+	if len(footers) > 0 {
+		result += "\n\n" + strings.Join(footers, "\n")
+	}
+	return result
+}
+
+// bulletMarkerRegex matches the marker of a bullet or numbered list item
+var bulletMarkerRegex = regexp.MustCompile(`^(?:[-*•] |\d+[.)] )`)
+
+// wrapBody wraps the body lines of a message at width columns on word
+// boundaries, indenting the continuation lines of a bullet under its text.
+// The subject, a closing paragraph of footers and indented lines such as
+// code are left alone, and a width of 0 leaves the message alone.
+func wrapBody(message string, width int) string {
+	if width <= 0 {
+		return message
+	}
+	subject, rest, found := strings.Cut(message, "\n")
+	if !found {
+		return message
+	}
+
+	lines := strings.Split(rest, "\n")
+	footers := trailingFooters(lines)
+
+	var wrapped []string
+	for i, line := range lines {
+		if i >= footers || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") ||
+			utf8.RuneCountInString(line) <= width {
+			wrapped = append(wrapped, line)
+			continue
+		}
+		wrapped = append(wrapped, wrapLine(line, width)...)
+	}
+	return subject + "\n" + strings.Join(wrapped, "\n")
+}
+
+// trailingFooters returns the index of the first line of a closing
+// paragraph made only of footers, or len(lines) if there is none
+func trailingFooters(lines []string) int {
+	start := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "" && start == len(lines):
+			// Trailing blank lines
+		case line == "":
+			return start
+		case !conventional.IsFooterLine(line):
+			return len(lines)
+		default:
+			start = i
+		}
+	}
+	return start
+}
+
+// wrapLine splits a line at spaces into lines of at most width columns. A
+// word longer than width gets a line of its own.
+func wrapLine(line string, width int) []string {
+	marker := bulletMarkerRegex.FindString(line)
+	indent := strings.Repeat(" ", utf8.RuneCountInString(marker))
+
+	var lines []string
+	current, empty := marker, true
+	for _, word := range strings.Fields(line[len(marker):]) {
+		switch {
+		case empty:
+			current += word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > width:
+			lines = append(lines, current)
+			current = indent + word
+		default:
+			current += " " + word
+		}
+		empty = false
+	}
+	return append(lines, current)
+}
@@ -0,0 +1,71 @@
+package feedback
+
+import "testing"
+
+func TestLimitBody(t *testing.T) {
+	message := "feat(auth): add login\n\n- add form\n- add handler\ncontinued here\n- add session\n\nCloses #12"
+
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		{"no limit", 0, message},
+		{"under the limit", 5, message},
+		{"trims extra bullets", 1, "feat(auth): add login\n\n- add form\n\nCloses #12"},
+		{"drops continuation lines", 2, "feat(auth): add login\n\n- add form\n- add handler\ncontinued here\n\nCloses #12"},
+		{"no body keeps footers", NoBody, "feat(auth): add login\n\nCloses #12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limitBody(message, tt.limit); got != tt.want {
+				t.Errorf("limitBody(%d) = %q, want %q", tt.limit, got, tt.want)
+			}
+		})
+	}
+
+	if got := limitBody("fix: typo", NoBody); got != "fix: typo" {
+		t.Errorf("a subject line should be unchanged, got %q", got)
+	}
+}
+
+func TestFinishSuggestionCountsTestBullet(t *testing.T) {
+	ctx := CommitContext{
+		Diff:        "diff --git a/auth/login.go b/auth/login.go\n+code\ndiff --git a/auth/login_test.go b/auth/login_test.go\n+test\n",
+		NoteTests:   true,
+		BodyBullets: 2,
+	}
+
+	got := finishSuggestion(ctx, "feat(auth): add login\n\n- add form\n- add handler\n- add session")
+	want := "feat(auth): add login\n\n- add form\n- add handler"
+	if got != want {
+		t.Errorf("finishSuggestion() = %q, want %q", got, want)
+	}
+
+	ctx.BodyBullets = NoBody
+	if got := finishSuggestion(ctx, "feat(auth): add login\n\n- add form"); got != "feat(auth): add login with tests" {
+		t.Errorf("finishSuggestion() with NoBody = %q", got)
+	}
+}
+
+func TestWrapBody(t *testing.T) {
+	message := "feat(auth): add a subject line that is long enough to go past the wrap column\n\n" +
+		"Sessions now expire after an hour of inactivity instead of a day.\n\n" +
+		"- refresh tokens before they expire so long-running requests keep working\n" +
+		"    const sessionTimeout = time.Hour // indented code is never wrapped here\n\n" +
+		"Co-authored-by: Jane Doe <jane.doe@example.com>, Someone Else <someone@example.com>"
+
+	want := "feat(auth): add a subject line that is long enough to go past the wrap column\n\n" +
+		"Sessions now expire after an hour of inactivity\ninstead of a day.\n\n" +
+		"- refresh tokens before they expire so\n  long-running requests keep working\n" +
+		"    const sessionTimeout = time.Hour // indented code is never wrapped here\n\n" +
+		"Co-authored-by: Jane Doe <jane.doe@example.com>, Someone Else <someone@example.com>"
+
+	if got := wrapBody(message, 50); got != want {
+		t.Errorf("wrapBody() =\n%s\nwant\n%s", got, want)
+	}
+	if got := wrapBody(message, 0); got != message {
+		t.Errorf("wrapBody() with width 0 changed the message:\n%s", got)
+	}
+}
@@ -0,0 +1,86 @@
+package feedback
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SuggestionCacheTTL is how long a cached suggestion is reused for the same diff
+const SuggestionCacheTTL = 10 * time.Minute
+
+// cachedSuggestion is the last generated suggestion and what it was generated for
+type cachedSuggestion struct {
+	Key        string    `json:"key"`
+	Suggestion string    `json:"suggestion"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SuggestionCacheKey identifies a suggestion by everything in ctx that shapes
+// the prompt and the model that saw it. The whole context is hashed, except
+// when it was created, so a new prompt option changes the key without being
+// listed here.
+func SuggestionCacheKey(ctx CommitContext, provider, model string) string {
+	ctx.Timestamp = time.Time{}
+	data, err := json.Marshal(ctx)
+	if err != nil {
+		// Unhashable stats never match, so the suggestion isn't reused
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(provider + "\x00" + model + "\x00" + string(data)))
+	return hex.EncodeToString(sum[:])
+}
+
+// CachedSuggestion returns the last suggestion if it was generated for key
+// within SuggestionCacheTTL
+func CachedSuggestion(key string) (string, bool) {
+	path := suggestionCachePath()
+	if path == "" {
+		return "", false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	var cached cachedSuggestion
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return "", false
+	}
+	if cached.Key != key || time.Since(cached.CreatedAt) > SuggestionCacheTTL {
+		return "", false
+	}
+
+	return cached.Suggestion, true
+}
+
+// CacheSuggestion stores a suggestion as the last one generated for key
+func CacheSuggestion(key, suggestion string) error {
+	path := suggestionCachePath()
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(cachedSuggestion{Key: key, Suggestion: suggestion, CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// suggestionCachePath returns the cache file for the last suggestion
+func suggestionCachePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".noidea", "cache", "suggestion.json")
+}
@@ -0,0 +1,71 @@
+package feedback
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuggestionCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	ctx := CommitContext{Diff: "diff --git a/x b/x"}
+	key := SuggestionCacheKey(ctx, "openai", "gpt-4o")
+	if _, hit := CachedSuggestion(key); hit {
+		t.Fatal("empty cache should miss")
+	}
+
+	if err := CacheSuggestion(key, "fix: typo"); err != nil {
+		t.Fatalf("CacheSuggestion() error = %v", err)
+	}
+	if got, hit := CachedSuggestion(key); !hit || got != "fix: typo" {
+		t.Errorf("CachedSuggestion() = %q, %v", got, hit)
+	}
+
+	if _, hit := CachedSuggestion(SuggestionCacheKey(ctx, "openai", "gpt-4o-mini")); hit {
+		t.Error("another model should miss the cache")
+	}
+	if _, hit := CachedSuggestion(SuggestionCacheKey(CommitContext{Diff: "diff --git a/y b/y"}, "openai", "gpt-4o")); hit {
+		t.Error("another diff should miss the cache")
+	}
+
+	// A plain run followed by one with --max-subject asks the model again
+	limited := ctx
+	limited.MaxSubject = 50
+	if _, hit := CachedSuggestion(SuggestionCacheKey(limited, "openai", "gpt-4o")); hit {
+		t.Error("another subject limit should miss the cache")
+	}
+
+	// The creation time isn't part of the prompt
+	later := ctx
+	later.Timestamp = time.Now()
+	if _, hit := CachedSuggestion(SuggestionCacheKey(later, "openai", "gpt-4o")); !hit {
+		t.Error("the same context at another time should hit the cache")
+	}
+}
+
+func TestSuggestionCacheKeyCoversPromptOptions(t *testing.T) {
+	base := CommitContext{Diff: "diff --git a/x b/x", CommitHistory: []string{"feat: add x"}}
+	key := SuggestionCacheKey(base, "openai", "gpt-4o")
+
+	options := map[string]func(*CommitContext){
+		"MaxSubject":     func(c *CommitContext) { c.MaxSubject = 50 },
+		"SubjectPrefix":  func(c *CommitContext) { c.SubjectPrefix = "[ABC-1]" },
+		"TypeRules":      func(c *CommitContext) { c.TypeRules.Allowed = []string{"feat", "fix"} },
+		"NoteTests":      func(c *CommitContext) { c.NoteTests = true },
+		"RelatedIssues":  func(c *CommitContext) { c.RelatedIssues = []string{"#1 Login (issue)"} },
+		"RangeCommits":   func(c *CommitContext) { c.RangeCommits = []string{"fix: a"} },
+		"ContextCommits": func(c *CommitContext) { c.ContextCommits = []string{"commit abc"} },
+		"BodyBullets":    func(c *CommitContext) { c.BodyBullets = 3 },
+		"WrapBody":       func(c *CommitContext) { c.WrapBody = 72 },
+		"CommentChar":    func(c *CommitContext) { c.CommentChar = ";" },
+		"CommitHistory":  func(c *CommitContext) { c.CommitHistory = append(c.CommitHistory, "fix: b") },
+	}
+	for name, change := range options {
+		ctx := base
+		ctx.CommitHistory = append([]string(nil), base.CommitHistory...)
+		change(&ctx)
+		if SuggestionCacheKey(ctx, "openai", "gpt-4o") == key {
+			t.Errorf("changing %s should change the cache key", name)
+		}
+	}
+}
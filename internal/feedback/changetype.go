@@ -0,0 +1,59 @@
+package feedback
+
+import (
+	"strings"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+)
+
+// onlyType returns the commit type implied by a diff that touches a single
+// kind of file: "docs" when every file is documentation and "test" when every
+// file is a test. Mixed changes return "".
+func (f DiffFiles) onlyType() string {
+	if len(f.Changed) == 0 {
+		return ""
+	}
+	switch len(f.Changed) {
+	case len(f.Categories[CategoryDoc]):
+		return "docs"
+	case len(f.Categories[CategoryTest]):
+		return "test"
+	}
+	return ""
+}
+
+// onlyTypeHint asks the model for the type of a docs-only or test-only
+// change, which it otherwise tends to label feat or fix
+func onlyTypeHint(diff string) string {
+	switch analyzeDiffFiles(diff).onlyType() {
+	case "docs":
+		return `
+Every changed file is documentation, so use the "docs" commit type, not feat or fix.
+`
+	case "test":
+		return `
+Every changed file is a test, so use the "test" commit type, not feat or fix.
+`
+	}
+	return ""
+}
+
+// forceOnlyType rewrites the type of a suggestion for a docs-only or
+// test-only diff, keeping the scope, description and body. A subject without
+// a type prefix gets one.
+func forceOnlyType(message, diff string) string {
+	commitType := analyzeDiffFiles(diff).onlyType()
+	if commitType == "" || message == "" {
+		return message
+	}
+
+	lines := strings.SplitN(message, "\n", 2)
+	subject, ok := conventional.Parse(lines[0])
+	if !ok {
+		subject = conventional.Subject{Description: strings.TrimSpace(lines[0])}
+	}
+
+	subject.Type = commitType
+	lines[0] = subject.String()
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,39 @@
+package feedback
+
+import "testing"
+
+func TestForceOnlyType(t *testing.T) {
+	onlyDocs := "diff --git a/README.md b/README.md\n+docs\ndiff --git a/docs/guide.md b/docs/guide.md\n+docs\n"
+	onlyTests := "diff --git a/auth/login_test.go b/auth/login_test.go\n+test\n"
+	mixed := "diff --git a/README.md b/README.md\n+docs\ndiff --git a/auth/login.go b/auth/login.go\n+code\n"
+
+	tests := []struct {
+		name    string
+		message string
+		diff    string
+		want    string
+	}{
+		{"docs only", "feat(readme): describe install\n\n- add steps", onlyDocs, "docs(readme): describe install\n\n- add steps"},
+		{"tests only", "fix(auth)!: cover login", onlyTests, "test(auth)!: cover login"},
+		{"already right", "docs: describe install", onlyDocs, "docs: describe install"},
+		{"no prefix", "Describe install", onlyDocs, "docs: Describe install"},
+		{"mixed", "feat(auth): add login", mixed, "feat(auth): add login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := forceOnlyType(tt.message, tt.diff); got != tt.want {
+				t.Errorf("forceOnlyType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnlyTypeHint(t *testing.T) {
+	if hint := onlyTypeHint("diff --git a/README.md b/README.md\n+docs\n"); hint == "" {
+		t.Error("onlyTypeHint() is empty for a docs-only diff")
+	}
+	if hint := onlyTypeHint("diff --git a/main.go b/main.go\n+code\n"); hint != "" {
+		t.Errorf("onlyTypeHint() = %q for a code diff, want none", hint)
+	}
+}
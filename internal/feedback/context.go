@@ -0,0 +1,61 @@
+package feedback
+
+import "strings"
+
+// DefaultContextTokens is the context window assumed for models missing from
+// modelContextTokens. It is deliberately small so unknown models never get
+// an oversized prompt.
+const DefaultContextTokens = 8192
+
+// MaxContextTokens overrides the context window of every model when greater
+// than zero. Callers set it from LLM.MaxContextTokens.
+var MaxContextTokens = 0
+
+// reservedTokens covers the system prompt and the response, which share the
+// context window with the user prompt
+const reservedTokens = 1000
+
+// modelContextTokens maps model name prefixes to their context windows. The
+// longest matching prefix wins, so "gpt-4o" is not treated as "gpt-4".
+var modelContextTokens = map[string]int{
+	"gpt-3.5-turbo":     16385,
+	"gpt-4":             8192,
+	"gpt-4-32k":         32768,
+	"gpt-4-turbo":       128000,
+	"gpt-4o":            128000,
+	"gpt-4.1":           1047576,
+	"o1":                200000,
+	"o3":                200000,
+	"o4-mini":           200000,
+	"grok-2":            131072,
+	"grok-3":            131072,
+	"grok-beta":         131072,
+	"deepseek-chat":     65536,
+	"deepseek-reasoner": 65536,
+}
+
+// ContextTokens returns the context window for a model: the configured
+// override, the known size for the model, or DefaultContextTokens
+func ContextTokens(model string) int {
+	if MaxContextTokens > 0 {
+		return MaxContextTokens
+	}
+
+	model = strings.ToLower(model)
+	tokens, matched := DefaultContextTokens, ""
+	for prefix, size := range modelContextTokens {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(matched) {
+			tokens, matched = size, prefix
+		}
+	}
+	return tokens
+}
+
+// promptTokenBudget is how many tokens the user prompt may use with a model
+func promptTokenBudget(model string) int {
+	budget := ContextTokens(model) - reservedTokens
+	if budget < reservedTokens {
+		return reservedTokens
+	}
+	return budget
+}
@@ -0,0 +1,135 @@
+package feedback
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContextTokens(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{"gpt-3.5-turbo", 16385},
+		{"gpt-4", 8192},
+		{"gpt-4o-mini", 128000},
+		{"gpt-4-turbo-preview", 128000},
+		{"grok-2-1212", 131072},
+		{"DeepSeek-Chat", 65536},
+		{"some-local-model", DefaultContextTokens},
+		{"", DefaultContextTokens},
+	}
+	for _, tt := range tests {
+		if got := ContextTokens(tt.model); got != tt.want {
+			t.Errorf("ContextTokens(%q) = %d, want %d", tt.model, got, tt.want)
+		}
+	}
+
+	defer func(previous int) { MaxContextTokens = previous }(MaxContextTokens)
+	MaxContextTokens = 32000
+	if got := ContextTokens("gpt-3.5-turbo"); got != 32000 {
+		t.Errorf("ContextTokens() with an override = %d, want 32000", got)
+	}
+}
+
+func TestSuggestionPromptFitsBudget(t *testing.T) {
+	var diff strings.Builder
+	diff.WriteString("diff --git a/data.txt b/data.txt\n--- a/data.txt\n+++ b/data.txt\n@@ -0,0 +1,20000 @@\n")
+	for i := 0; i < 20000; i++ {
+		diff.WriteString("+some generated line of data\n")
+	}
+	ctx := CommitContext{Diff: diff.String()}
+
+	small := buildSuggestionPrompt(ctx, promptTokenBudget("gpt-3.5-turbo"))
+	large := buildSuggestionPrompt(ctx, promptTokenBudget("grok-2"))
+
+	if len(small) > promptTokenBudget("gpt-3.5-turbo")*4 {
+		t.Errorf("prompt for gpt-3.5-turbo is %d chars, over its budget", len(small))
+	}
+	if len(small) >= len(large) {
+		t.Errorf("prompt for a 16k model (%d chars) should be shorter than for a 128k model (%d chars)", len(small), len(large))
+	}
+}
+
+func TestFormatContextCommits(t *testing.T) {
+	newest := "commit 2222\n\n    feat: add export\n\ndiff --git a/export.go b/export.go\n+func Export() {}"
+	older := "commit 1111\n\n    feat: add import\n\ndiff --git a/import.go b/import.go\n+func Import() {}"
+
+	section := formatContextCommits([]string{newest, older}, 10000)
+	if !strings.Contains(section, "feat: add export") || !strings.Contains(section, "feat: add import") {
+		t.Errorf("formatContextCommits() left out a commit:\n%s", section)
+	}
+	if strings.Index(section, "add export") > strings.Index(section, "add import") {
+		t.Errorf("formatContextCommits() didn't keep the newest commit first:\n%s", section)
+	}
+
+	section = formatContextCommits([]string{newest, older}, len(section)-10)
+	if !strings.Contains(section, "feat: add export") || strings.Contains(section, "feat: add import") {
+		t.Errorf("formatContextCommits() should drop the older commit that doesn't fit:\n%s", section)
+	}
+
+	if section := formatContextCommits([]string{newest}, 20); section != "" {
+		t.Errorf("formatContextCommits() with no room = %q, want empty", section)
+	}
+}
+
+func TestInitialCommitPrompt(t *testing.T) {
+	ctx := CommitContext{
+		Diff:          "diff --git a/main.go b/main.go\nnew file mode 100644\n--- /dev/null\n+++ b/main.go\n@@ -0,0 +1,3 @@\n+package main\n+\n+func main() {}",
+		InitialCommit: true,
+	}
+
+	prompt := buildSuggestionPrompt(ctx, promptTokenBudget(""))
+	if !strings.Contains(prompt, "FIRST commit of a new repository") {
+		t.Errorf("prompt for a first commit doesn't say so:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "Past commit messages") {
+		t.Errorf("prompt for a first commit asks to match past commits:\n%s", prompt)
+	}
+
+	suggestion, err := NewLocalFeedbackEngine().GenerateCommitSuggestion(ctx)
+	if err != nil || suggestion != "chore: initial commit" {
+		t.Errorf("local suggestion for a first commit = %q, %v", suggestion, err)
+	}
+}
+
+func TestSuggestionPromptCommitBodies(t *testing.T) {
+	ctx := CommitContext{
+		Diff:          "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b",
+		CommitHistory: []string{"feat: add export", "fix: handle empty input"},
+	}
+
+	withoutBodies := buildSuggestionPrompt(ctx, promptTokenBudget("gpt-4o"))
+	if !strings.Contains(withoutBodies, "1. feat: add export\n2. fix: handle empty input\n") {
+		t.Errorf("prompt is missing the commit history:\n%s", withoutBodies)
+	}
+
+	ctx.CommitBodies = []string{"- Write CSV and JSON\n- Add a --format flag", ""}
+	withBodies := buildSuggestionPrompt(ctx, promptTokenBudget("gpt-4o"))
+	want := "1. feat: add export\n   - Write CSV and JSON\n   - Add a --format flag\n2. fix: handle empty input\n"
+	if !strings.Contains(withBodies, want) {
+		t.Errorf("prompt should list each body under its subject, want %q in:\n%s", want, withBodies)
+	}
+}
+
+func TestFormatCommitListWithBodiesBudget(t *testing.T) {
+	commits := make([]string, 7)
+	bodies := make([]string, 7)
+	for i := range commits {
+		commits[i] = "chore: step"
+		bodies[i] = strings.Repeat("x", 500)
+	}
+	// Leave one character of the 2000 budget when the sixth body comes up
+	bodies[4] = strings.Repeat("x", 399)
+
+	list := formatCommitListWithBodies(commits, bodies)
+	if got := strings.Count(list, "x"); got > 2000 {
+		t.Errorf("bodies use %d characters, over the 2000 budget", got)
+	}
+	if got := strings.Count(list, "..."); got != 4 {
+		t.Errorf("want the four long bodies truncated, got %d ellipses:\n%s", got, list)
+	}
+	if !strings.HasSuffix(list, "6. chore: step\n7. chore: step\n") {
+		t.Errorf("subjects past the budget should be listed without bodies:\n%s", list[len(list)-100:])
+	}
+}
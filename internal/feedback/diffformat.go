@@ -0,0 +1,125 @@
+package feedback
+
+import (
+	"fmt"
+	"strings"
+)
+
+// devNull is the path unified diffs use for a file that doesn't exist
+const devNull = "/dev/null"
+
+// NormalizeDiff rewrites a patch into the "diff --git" form the diff parsers
+// expect. Plain unified diffs (diff -u, diff -ruN, svn or review tools) get a
+// git header per file, and git format-patch output loses its email headers,
+// messages and signatures. A git diff is returned unchanged.
+func NormalizeDiff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") {
+			return stripPatchMail(lines)
+		}
+	}
+	return convertUnifiedDiff(lines)
+}
+
+// stripPatchMail keeps only the diffs of git format-patch output, dropping the
+// mail headers and commit message before each diff and the signature after it
+func stripPatchMail(lines []string) string {
+	if !strings.HasPrefix(lines[0], "From ") {
+		return strings.Join(lines, "\n")
+	}
+
+	var result []string
+	inDiff := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inDiff = true
+		case line == "-- " || strings.HasPrefix(line, "From "):
+			// The signature ends a patch, and the next starts with "From <hash>"
+			inDiff = false
+		}
+		if inDiff {
+			result = append(result, line)
+		}
+	}
+	return strings.Join(result, "\n") + "\n"
+}
+
+// convertUnifiedDiff adds a git header before each file of a plain unified
+// diff and drops everything that isn't part of a file's diff
+func convertUnifiedDiff(lines []string) string {
+	var result strings.Builder
+	inFile := false
+	for i, line := range lines {
+		if isUnifiedHeader(lines, i) {
+			oldPath, oldMissing := headerPath(line, "--- ")
+			newPath, newMissing := headerPath(lines[i+1], "+++ ")
+			path := diffPath(oldPath, newPath)
+
+			fmt.Fprintf(&result, "diff --git a/%s b/%s\n", path, path)
+			if oldMissing {
+				result.WriteString("new file mode 100644\n")
+			} else if newMissing {
+				result.WriteString("deleted file mode 100644\n")
+			}
+			inFile = true
+		}
+		if !inFile {
+			continue
+		}
+
+		// Lines of a file's diff are headers, hunks or "\ No newline at end of file";
+		// anything else, such as "Index:" or "Only in", is between files
+		if line == "" || strings.ContainsAny(line[:1], " +-@\\") {
+			result.WriteString(line + "\n")
+		}
+	}
+	return result.String()
+}
+
+// isUnifiedHeader reports whether lines[i] starts a file header: "--- old",
+// "+++ new" and the first hunk
+func isUnifiedHeader(lines []string, i int) bool {
+	return i+2 < len(lines) &&
+		strings.HasPrefix(lines[i], "--- ") &&
+		strings.HasPrefix(lines[i+1], "+++ ") &&
+		strings.HasPrefix(lines[i+2], "@@ ")
+}
+
+// headerPath returns the path of a "--- " or "+++ " line, without the
+// timestamp diff adds after a tab, and whether the file is missing on that
+// side. diff -N marks a missing file with the epoch as its timestamp instead
+// of naming /dev/null.
+func headerPath(line, prefix string) (string, bool) {
+	path, timestamp, _ := strings.Cut(strings.TrimPrefix(line, prefix), "\t")
+	path = strings.Trim(path, `"`)
+	epoch := strings.HasPrefix(timestamp, "1970-01-01") || strings.HasPrefix(timestamp, "1969-12-31")
+	return path, path == devNull || epoch
+}
+
+// diffPath names the file of a header as git would: the path relative to the
+// compared trees, e.g. "src/main.go" for "a/src/main.go" against
+// "b/src/main.go" or "old/src/main.go" against "new/src/main.go"
+func diffPath(oldPath, newPath string) string {
+	oldRoot, oldRest, _ := strings.Cut(oldPath, "/")
+	newRoot, newRest, _ := strings.Cut(newPath, "/")
+
+	switch {
+	case oldPath == devNull:
+		// A new file only shows one tree, so only git's own prefix is known
+		if newRoot == "b" && newRest != "" {
+			return newRest
+		}
+		return newPath
+	case newPath == devNull:
+		if oldRoot == "a" && oldRest != "" {
+			return oldRest
+		}
+		return oldPath
+	case oldRoot != newRoot && oldRest != "" && oldRest == newRest:
+		return newRest
+	default:
+		return newPath
+	}
+}
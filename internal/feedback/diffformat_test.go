@@ -0,0 +1,116 @@
+package feedback
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDiff(t *testing.T) {
+	gitDiff := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-old\n+new\n"
+	if got := NormalizeDiff(gitDiff); got != gitDiff {
+		t.Errorf("a git diff should be unchanged, got:\n%s", got)
+	}
+
+	plain := `diff -ruN old/src/main.go new/src/main.go
+--- old/src/main.go	2024-05-01 10:00:00.000000000 +0200
++++ new/src/main.go	2024-05-02 10:00:00.000000000 +0200
+@@ -1,2 +1,2 @@
+ package main
+-func Old() {}
++func New() {}
+Only in old: notes.txt
+--- /dev/null
++++ b/docs/guide.md
+@@ -0,0 +1 @@
++# Guide
+--- old/setup.sh	2024-05-01 10:00:00.000000000 +0200
++++ new/setup.sh	1970-01-01 01:00:00.000000000 +0100
+@@ -1 +0,0 @@
+-echo hi
+\ No newline at end of file
+`
+	want := `diff --git a/src/main.go b/src/main.go
+--- old/src/main.go	2024-05-01 10:00:00.000000000 +0200
++++ new/src/main.go	2024-05-02 10:00:00.000000000 +0200
+@@ -1,2 +1,2 @@
+ package main
+-func Old() {}
++func New() {}
+diff --git a/docs/guide.md b/docs/guide.md
+new file mode 100644
+--- /dev/null
++++ b/docs/guide.md
+@@ -0,0 +1 @@
++# Guide
+diff --git a/setup.sh b/setup.sh
+deleted file mode 100644
+--- old/setup.sh	2024-05-01 10:00:00.000000000 +0200
++++ new/setup.sh	1970-01-01 01:00:00.000000000 +0100
+@@ -1 +0,0 @@
+-echo hi
+\ No newline at end of file
+
+`
+	if got := NormalizeDiff(plain); got != want {
+		t.Errorf("NormalizeDiff(plain) =\n%s\nwant:\n%s", got, want)
+	}
+
+	files := analyzeDiffFiles(NormalizeDiff(plain))
+	if !reflect.DeepEqual(files.Added, []string{"docs/guide.md"}) ||
+		!reflect.DeepEqual(files.Modified, []string{"src/main.go"}) ||
+		!reflect.DeepEqual(files.Deleted, []string{"setup.sh"}) {
+		t.Errorf("operations = added %v, modified %v, deleted %v", files.Added, files.Modified, files.Deleted)
+	}
+}
+
+func TestNormalizeDiffFormatPatch(t *testing.T) {
+	patch := `From 1234567890abcdef1234567890abcdef12345678 Mon Sep 17 00:00:00 2001
+From: Jane <jane@example.com>
+Subject: [PATCH] fix: handle --- in messages
+
++++ this line is part of the message
+---
+ main.go | 2 +-
+ 1 file changed, 1 insertion(+), 1 deletion(-)
+
+diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-old
++new
+-- 
+2.45.0
+`
+	got := NormalizeDiff(patch)
+	if !strings.HasPrefix(got, "diff --git a/main.go b/main.go\n") {
+		t.Errorf("the mail headers and message should be dropped, got:\n%s", got)
+	}
+	if strings.Contains(got, "2.45.0") || strings.Contains(got, "-- \n") {
+		t.Errorf("the signature should be dropped, got:\n%s", got)
+	}
+
+	files := analyzeDiffFiles(got)
+	if files.Additions != 1 || files.Deletions != 1 {
+		t.Errorf("lines = +%d -%d, want +1 -1", files.Additions, files.Deletions)
+	}
+}
+
+func TestDiffPath(t *testing.T) {
+	tests := []struct{ old, new, want string }{
+		{"a/src/x.go", "b/src/x.go", "src/x.go"},
+		{"old/src/x.go", "new/src/x.go", "src/x.go"},
+		{"src/x.go", "src/x.go", "src/x.go"},
+		{"x.go.orig", "x.go", "x.go"},
+		{devNull, "b/x.go", "x.go"},
+		{devNull, "src/x.go", "src/x.go"},
+		{"a/x.go", devNull, "x.go"},
+	}
+	for _, tt := range tests {
+		if got := diffPath(tt.old, tt.new); got != tt.want {
+			t.Errorf("diffPath(%q, %q) = %q, want %q", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
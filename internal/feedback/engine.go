@@ -1,20 +1,41 @@
 package feedback
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"strings"
 	"time"
 
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
 	"github.com/AccursedGalaxy/noidea/internal/personality"
 )
 
+// NoBody as CommitContext.BodyBullets limits suggestions to a subject line
+const NoBody = -1
+
 // CommitContext contains information about a commit
 type CommitContext struct {
-	Message       string
-	Timestamp     time.Time
-	Diff          string                 // Optional
-	CommitHistory []string               // Recent commit messages
-	CommitStats   map[string]interface{} // Stats about recent commits
+	Message        string
+	Timestamp      time.Time
+	Diff           string                 // Optional
+	CommitHistory  []string               // Recent commit messages
+	CommitBodies   []string               // Optional bodies matching CommitHistory entries
+	RangeCommits   []string               // Subjects of already-made commits being combined into one message
+	InitialCommit  bool                   // The changes are the repository's first commit
+	ContextCommits []string               // Truncated "git show" output of recent commits, newest first
+	NoteTests      bool                   // Acknowledge new or changed tests in suggested messages
+	ForceOnlyType  bool                   // Always use "docs" or "test" for documentation- or test-only diffs
+	TypeRules      conventional.Rules     // Commit type aliases and restrictions for suggestions
+	RelatedIssues  []string               // Open issue and PR titles, e.g. "#12 Fix login (issue)"
+	SmallDiffLines int                    // Max changed lines for the small-diff fast path, 0 to disable
+	BodyBullets    int                    // Most bullet points in a suggested body, 0 for no limit or NoBody
+	MaxSubject     int                    // Most characters in the subject, prefix included, 0 for no limit
+	WrapBody       int                    // Column to wrap body lines at, 0 to leave them
+	MaxLineLength  int                    // Longest diff line sent to the model, longer ones are cut; 0 for no limit
+	CommentChar    string                 // Starts comment lines to drop from responses, "" for "#"
+	SubjectPrefix  string                 // Prefix the caller puts before the subject, e.g. "[JIRA-123]"; the model leaves it out
+	CommitStats    map[string]interface{} // Stats about recent commits
 }
 
 // FeedbackEngine defines the interface for generating commit feedback
@@ -29,6 +50,47 @@ type FeedbackEngine interface {
 	GenerateCommitSuggestion(context CommitContext) (string, error)
 }
 
+// StreamingEngine is implemented by engines that can write a commit
+// suggestion to out while it is being generated
+type StreamingEngine interface {
+	GenerateCommitSuggestionStream(context CommitContext, out io.Writer) (string, error)
+}
+
+// StreamCommitSuggestion generates a commit suggestion, streaming it to out when
+// the engine supports that and writing the finished message otherwise
+func StreamCommitSuggestion(engine FeedbackEngine, ctx CommitContext, out io.Writer) (string, error) {
+	if streaming, ok := engine.(StreamingEngine); ok {
+		return streaming.GenerateCommitSuggestionStream(ctx, out)
+	}
+
+	suggestion, err := engine.GenerateCommitSuggestion(ctx)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(out, suggestion)
+	return suggestion, nil
+}
+
+// CandidateEngine is implemented by engines that can generate several
+// alternative commit suggestions in one request
+type CandidateEngine interface {
+	GenerateCommitSuggestions(context CommitContext, n int) ([]string, error)
+}
+
+// CommitSuggestions generates up to n distinct commit suggestions. Engines
+// that can't offer alternatives return their single suggestion.
+func CommitSuggestions(engine FeedbackEngine, ctx CommitContext, n int) ([]string, error) {
+	if candidates, ok := engine.(CandidateEngine); ok && n > 1 {
+		return candidates.GenerateCommitSuggestions(ctx, n)
+	}
+
+	suggestion, err := engine.GenerateCommitSuggestion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []string{suggestion}, nil
+}
+
 // EngineName returns a string identifier for an engine type
 type EngineName string
 
@@ -41,10 +103,17 @@ const (
 	EngineOpenAI EngineName = "openai"
 	// DeepSeek feedback engine
 	EngineDeepSeek EngineName = "deepseek"
+	// Mock feedback engine with canned offline responses
+	EngineMock EngineName = "mock"
 )
 
 // NewFeedbackEngine creates a new feedback engine based on the provided configuration
 func NewFeedbackEngine(provider string, model string, apiKey string, personalityName string, personalityFile string) FeedbackEngine {
+	// The mock engine needs no key and makes no requests
+	if UseMock(provider) {
+		return NewMockFeedbackEngine()
+	}
+
 	// No API key means we have to use the local engine
 	if apiKey == "" {
 		log.Println("No API key provided, falling back to local feedback engine")
@@ -65,6 +134,11 @@ func NewFeedbackEngine(provider string, model string, apiKey string, personality
 
 // NewFeedbackEngineWithCustomPersonality creates a feedback engine using a custom personality configuration
 func NewFeedbackEngineWithCustomPersonality(provider string, model string, apiKey string, customPersonality personality.Personality) FeedbackEngine {
+	// The mock engine needs no key and makes no requests
+	if UseMock(provider) {
+		return NewMockFeedbackEngine()
+	}
+
 	// No API key means we have to use the local engine
 	if apiKey == "" {
 		log.Println("No API key provided, falling back to local feedback engine")
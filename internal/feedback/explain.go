@@ -0,0 +1,190 @@
+package feedback
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+)
+
+// diffFacts is what ExplainSuggestion reads from a diff
+type diffFacts struct {
+	files     []string
+	added     int
+	removed   int
+	functions []string // Functions and types the diff adds
+}
+
+// collectDiffFacts counts the files, changed lines and new declarations in a diff
+func collectDiffFacts(diff string) diffFacts {
+	var facts diffFacts
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git"):
+			if parts := strings.Fields(line); len(parts) >= 4 {
+				facts.files = append(facts.files, strings.TrimPrefix(parts[3], "b/"))
+			}
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+func "):
+			name := strings.TrimSpace(strings.Split(strings.TrimPrefix(line, "+func "), "(")[0])
+			if name != "" {
+				facts.functions = append(facts.functions, name)
+			}
+			facts.added++
+		case strings.HasPrefix(line, "+type "):
+			if fields := strings.Fields(strings.TrimPrefix(line, "+type ")); len(fields) > 0 {
+				facts.functions = append(facts.functions, fields[0])
+			}
+			facts.added++
+		case strings.HasPrefix(line, "+"):
+			facts.added++
+		case strings.HasPrefix(line, "-"):
+			facts.removed++
+		}
+	}
+	return facts
+}
+
+// ExplainSuggestion returns a short rationale for the type, scope and
+// description of a suggested commit message, based on the diff it was
+// generated from. It uses only local analysis, so it costs no extra request.
+func ExplainSuggestion(message, diff string) []string {
+	facts := collectDiffFacts(diff)
+	subjectLine, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+
+	var reasons []string
+	subject, ok := conventional.Parse(subjectLine)
+	if !ok {
+		reasons = append(reasons, "The subject has no conventional type prefix, so it can't be classified as feat, fix, docs and so on.")
+	} else {
+		reasons = append(reasons, explainType(subject.Type, facts))
+		reasons = append(reasons, explainScope(subject.Scope, facts.files))
+		if subject.Breaking {
+			reasons = append(reasons, "Marked breaking with \"!\": the change alters behavior that existing users rely on.")
+		}
+	}
+
+	reasons = append(reasons, fmt.Sprintf("The description summarizes %s (+%d/-%d lines).",
+		pluralize(len(facts.files), "changed file"), facts.added, facts.removed))
+
+	if strings.TrimSpace(body) != "" {
+		reasons = append(reasons, "A body lists the key changes because the diff is too large to describe in one line.")
+	} else if len(facts.files) > 3 || facts.added+facts.removed > 100 {
+		reasons = append(reasons, "No body, although the change is large enough that a few bullet points would help reviewers.")
+	}
+
+	return reasons
+}
+
+// explainType says what in the diff supports a commit type, or what the
+// diff suggests instead
+func explainType(commitType string, facts diffFacts) string {
+	tests, otherChanges := diffTestFiles(diffHeaders(facts.files))
+	docsOnly := len(facts.files) > 0
+	for _, file := range facts.files {
+		ext := strings.ToLower(filepath.Ext(file))
+		if ext != ".md" && ext != ".txt" && ext != ".rst" {
+			docsOnly = false
+		}
+	}
+
+	switch {
+	case commitType == "docs" && docsOnly:
+		return "Type \"docs\": only documentation files changed."
+	case commitType == "test" && len(tests) > 0 && !otherChanges:
+		return "Type \"test\": only test files changed."
+	case docsOnly:
+		return fmt.Sprintf("Type %q was chosen, although only documentation files changed, which usually means \"docs\".", commitType)
+	case len(tests) > 0 && !otherChanges:
+		return fmt.Sprintf("Type %q was chosen, although only test files changed, which usually means \"test\".", commitType)
+	case commitType == "feat" && len(facts.functions) > 0:
+		return fmt.Sprintf("Type \"feat\": the diff adds new code (%s).", listNames(facts.functions, 3))
+	case commitType == "feat":
+		return fmt.Sprintf("Type \"feat\": the change adds behavior rather than correcting it (%s added).", pluralize(facts.added, "line"))
+	case commitType == "refactor" && facts.removed >= facts.added:
+		return "Type \"refactor\": code is restructured or removed without adding features."
+	case commitType == "refactor":
+		return "Type \"refactor\": existing code changes shape without adding features."
+	case commitType == "fix":
+		return fmt.Sprintf("Type \"fix\": a focused change (+%d/-%d lines) to existing code that corrects behavior.", facts.added, facts.removed)
+	default:
+		return fmt.Sprintf("Type %q: %s.", commitType, typeMeaning(commitType))
+	}
+}
+
+// typeMeaning describes what a conventional commit type is used for
+func typeMeaning(commitType string) string {
+	meanings := map[string]string{
+		"feat":     "adds a new feature",
+		"fix":      "corrects a bug",
+		"docs":     "changes documentation only",
+		"style":    "changes formatting without affecting behavior",
+		"refactor": "restructures code without changing behavior",
+		"perf":     "improves performance",
+		"test":     "adds or updates tests",
+		"build":    "affects the build system or dependencies",
+		"ci":       "changes CI configuration",
+		"chore":    "maintenance that doesn't touch source or tests",
+		"revert":   "reverts an earlier commit",
+	}
+	if meaning, ok := meanings[commitType]; ok {
+		return meaning
+	}
+	return "a project-specific type"
+}
+
+// explainScope relates the scope to where the changed files live
+func explainScope(scope string, files []string) string {
+	dirs := make(map[string]int)
+	for _, file := range files {
+		dirs[path.Dir(filepath.ToSlash(file))]++
+	}
+
+	if scope == "" {
+		if len(dirs) > 1 {
+			return fmt.Sprintf("No scope: the changes span %d directories.", len(dirs))
+		}
+		return "No scope: the change is small enough not to need one."
+	}
+
+	matching := 0
+	for _, file := range files {
+		if strings.Contains(strings.ToLower(filepath.ToSlash(file)), strings.ToLower(scope)) {
+			matching++
+		}
+	}
+	if matching > 0 {
+		return fmt.Sprintf("Scope %q: %d of %s are under or named after %s.", scope, matching, pluralize(len(files), "changed file"), scope)
+	}
+	return fmt.Sprintf("Scope %q names the area the change affects; no file path contains it.", scope)
+}
+
+// diffHeaders rebuilds "diff --git" lines for files so diffTestFiles can classify them
+func diffHeaders(files []string) string {
+	var b strings.Builder
+	for _, file := range files {
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", file, file)
+	}
+	return b.String()
+}
+
+// listNames joins up to max names, noting how many more there are
+func listNames(names []string, max int) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	if len(sorted) <= max {
+		return strings.Join(sorted, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(sorted[:max], ", "), len(sorted)-max)
+}
+
+// pluralize formats a count with a noun, adding "s" when needed
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,44 @@
+package feedback
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplainSuggestion(t *testing.T) {
+	diff := `diff --git a/internal/auth/token.go b/internal/auth/token.go
+--- a/internal/auth/token.go
++++ b/internal/auth/token.go
+@@ -1,3 +1,8 @@
++func RefreshToken(t string) string {
++	return t
++}
+diff --git a/internal/auth/token_test.go b/internal/auth/token_test.go
+--- a/internal/auth/token_test.go
++++ b/internal/auth/token_test.go
+@@ -1 +1,2 @@
++func TestRefreshToken(t *testing.T) {}
+`
+
+	reasons := strings.Join(ExplainSuggestion("feat(auth): add token refresh", diff), "\n")
+	for _, want := range []string{
+		`Type "feat": the diff adds new code (RefreshToken, TestRefreshToken)`,
+		`Scope "auth": 2 of 2 changed files`,
+		"2 changed files (+4/-0 lines)",
+	} {
+		if !strings.Contains(reasons, want) {
+			t.Errorf("explanation missing %q:\n%s", want, reasons)
+		}
+	}
+
+	docs := "diff --git a/README.md b/README.md\n+++ b/README.md\n+More docs\n"
+	reasons = strings.Join(ExplainSuggestion("feat: update readme", docs), "\n")
+	if !strings.Contains(reasons, `usually means "docs"`) {
+		t.Errorf("expected a hint that docs-only changes are \"docs\":\n%s", reasons)
+	}
+
+	reasons = strings.Join(ExplainSuggestion("Update things", docs), "\n")
+	if !strings.Contains(reasons, "no conventional type prefix") {
+		t.Errorf("expected a note about the missing type:\n%s", reasons)
+	}
+}
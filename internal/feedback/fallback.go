@@ -0,0 +1,126 @@
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/AccursedGalaxy/noidea/internal/interrupt"
+)
+
+// ProviderEngine pairs a feedback engine with the provider/model it talks to
+type ProviderEngine struct {
+	Name   string // e.g. "openai/gpt-4o"
+	Engine FeedbackEngine
+}
+
+// FallbackFeedbackEngine tries a list of engines in order until one succeeds.
+// It is safe for concurrent use.
+type FallbackFeedbackEngine struct {
+	engines []ProviderEngine
+
+	mu         sync.Mutex
+	answeredBy string
+}
+
+// NewFallbackFeedbackEngine creates an engine that falls through the given
+// engines in order, returning the first successful response
+func NewFallbackFeedbackEngine(engines []ProviderEngine) *FallbackFeedbackEngine {
+	return &FallbackFeedbackEngine{engines: engines}
+}
+
+// AnsweredBy returns the name of the provider that produced the last response
+func (e *FallbackFeedbackEngine) AnsweredBy() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.answeredBy
+}
+
+// GenerateFeedback tries each engine in order for commit feedback
+func (e *FallbackFeedbackEngine) GenerateFeedback(ctx CommitContext) (string, error) {
+	return e.try(func(engine FeedbackEngine) (string, error) {
+		return engine.GenerateFeedback(ctx)
+	})
+}
+
+// GenerateSummaryFeedback tries each engine in order for summary insights
+func (e *FallbackFeedbackEngine) GenerateSummaryFeedback(ctx CommitContext) (string, error) {
+	return e.try(func(engine FeedbackEngine) (string, error) {
+		return engine.GenerateSummaryFeedback(ctx)
+	})
+}
+
+// GenerateCommitSuggestion tries each engine in order for a commit suggestion
+func (e *FallbackFeedbackEngine) GenerateCommitSuggestion(ctx CommitContext) (string, error) {
+	return e.try(func(engine FeedbackEngine) (string, error) {
+		return engine.GenerateCommitSuggestion(ctx)
+	})
+}
+
+// GenerateCommitSuggestions tries each engine in order for alternative
+// commit suggestions
+func (e *FallbackFeedbackEngine) GenerateCommitSuggestions(ctx CommitContext, n int) ([]string, error) {
+	var suggestions []string
+	_, err := e.try(func(engine FeedbackEngine) (string, error) {
+		var err error
+		suggestions, err = CommitSuggestions(engine, ctx, n)
+		return "", err
+	})
+	return suggestions, err
+}
+
+// GenerateCommitSuggestionStream tries each engine in order, streaming the
+// suggestion to out. Once an engine has written output, its failure is
+// returned rather than mixing a second provider's text into the stream.
+func (e *FallbackFeedbackEngine) GenerateCommitSuggestionStream(ctx CommitContext, out io.Writer) (string, error) {
+	written := &countingWriter{w: out}
+	return e.try(func(engine FeedbackEngine) (string, error) {
+		if written.n > 0 {
+			return "", errStreamStarted
+		}
+		return StreamCommitSuggestion(engine, ctx, written)
+	})
+}
+
+// errStreamStarted stops the fallback chain after a provider failed mid-stream
+var errStreamStarted = errors.New("output was already streamed by a failed provider")
+
+// countingWriter records how many bytes were written through it
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// try runs generate against each engine until one succeeds
+func (e *FallbackFeedbackEngine) try(generate func(FeedbackEngine) (string, error)) (string, error) {
+	if len(e.engines) == 0 {
+		return "", fmt.Errorf("no providers configured")
+	}
+
+	var failures []string
+	for _, pe := range e.engines {
+		result, err := generate(pe.Engine)
+		if err == nil {
+			e.mu.Lock()
+			e.answeredBy = pe.Name
+			e.mu.Unlock()
+			return result, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", pe.Name, err))
+
+		// Don't fall through to other providers once the user has cancelled
+		if interrupt.Interrupted() {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("all providers failed (%s)", strings.Join(failures, "; "))
+}
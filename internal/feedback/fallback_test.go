@@ -0,0 +1,96 @@
+package feedback
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubEngine answers every request with a fixed response or error
+type stubEngine struct {
+	response string
+	err      error
+	calls    int
+}
+
+func (s *stubEngine) GenerateFeedback(CommitContext) (string, error) {
+	s.calls++
+	return s.response, s.err
+}
+
+func (s *stubEngine) GenerateSummaryFeedback(ctx CommitContext) (string, error) {
+	return s.GenerateFeedback(ctx)
+}
+
+func (s *stubEngine) GenerateCommitSuggestion(ctx CommitContext) (string, error) {
+	return s.GenerateFeedback(ctx)
+}
+
+func TestFallbackFeedbackEngine(t *testing.T) {
+	tests := []struct {
+		name         string
+		engines      []*stubEngine
+		want         string
+		wantErr      []string // Substrings the aggregated error must contain
+		wantAnswered string
+		wantCalls    []int
+	}{
+		{
+			name:         "primary answers",
+			engines:      []*stubEngine{{response: "from a"}, {response: "from b"}},
+			want:         "from a",
+			wantAnswered: "a",
+			wantCalls:    []int{1, 0},
+		},
+		{
+			name:         "primary fails and a fallback answers",
+			engines:      []*stubEngine{{err: errors.New("rate limited")}, {response: "from b"}},
+			want:         "from b",
+			wantAnswered: "b",
+			wantCalls:    []int{1, 1},
+		},
+		{
+			name:      "all fail",
+			engines:   []*stubEngine{{err: errors.New("rate limited")}, {err: errors.New("bad key")}},
+			wantErr:   []string{"all providers failed", "a: rate limited", "b: bad key"},
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:    "no providers",
+			wantErr: []string{"no providers configured"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var engines []ProviderEngine
+			for i, stub := range tt.engines {
+				engines = append(engines, ProviderEngine{Name: string(rune('a' + i)), Engine: stub})
+			}
+			engine := NewFallbackFeedbackEngine(engines)
+
+			got, err := engine.GenerateCommitSuggestion(CommitContext{})
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error %q does not contain %q", err, want)
+					}
+				}
+			} else if err != nil || got != tt.want {
+				t.Errorf("GenerateCommitSuggestion() = %q, %v; want %q", got, err, tt.want)
+			}
+
+			if answered := engine.AnsweredBy(); answered != tt.wantAnswered {
+				t.Errorf("AnsweredBy() = %q, want %q", answered, tt.wantAnswered)
+			}
+			for i, stub := range tt.engines {
+				if stub.calls != tt.wantCalls[i] {
+					t.Errorf("engine %d called %d times, want %d", i, stub.calls, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
@@ -39,6 +39,34 @@ func (e *LocalFeedbackEngine) GenerateSummaryFeedback(ctx CommitContext) (string
 
 // GenerateCommitSuggestion creates a simple commit message suggestion based on diff stats
 func (e *LocalFeedbackEngine) GenerateCommitSuggestion(ctx CommitContext) (string, error) {
+	suggestion, err := e.suggestFromDiff(ctx)
+	if err != nil {
+		return suggestion, err
+	}
+	return finishLocalSuggestion(suggestion, ctx), nil
+}
+
+// finishLocalSuggestion applies the context's body, type and subject rules to a
+// suggestion that was made without a model
+func finishLocalSuggestion(suggestion string, ctx CommitContext) string {
+	suggestion = limitBody(suggestion, ctx.BodyBullets)
+	if ctx.NoteTests {
+		suggestion = limitBody(noteTests(suggestion, ctx.Diff), ctx.BodyBullets)
+	}
+	if ctx.ForceOnlyType {
+		suggestion = forceOnlyType(suggestion, ctx.Diff)
+	}
+	suggestion = limitSubject(ctx.TypeRules.Apply(suggestion), subjectLimit(ctx), ctx.BodyBullets == NoBody)
+	return wrapBody(suggestion, ctx.WrapBody)
+}
+
+// suggestFromDiff derives a commit message from the files and functions in the diff
+func (e *LocalFeedbackEngine) suggestFromDiff(ctx CommitContext) (string, error) {
+	// The first commit is conventionally just that
+	if ctx.InitialCommit {
+		return "chore: initial commit", nil
+	}
+
 	// Extract file paths from the diff
 	lines := strings.Split(ctx.Diff, "\n")
 	var filesChanged []string
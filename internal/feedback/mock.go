@@ -0,0 +1,106 @@
+package feedback
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MockEnvVar forces the mock engine for every provider when set to a true value
+const MockEnvVar = "NOIDEA_LLM_MOCK"
+
+// UseMock reports whether engines for provider should be replaced by the
+// mock engine, either because it is the "mock" provider or because
+// NOIDEA_LLM_MOCK is set
+func UseMock(provider string) bool {
+	if strings.EqualFold(provider, string(EngineMock)) {
+		return true
+	}
+	val := os.Getenv(MockEnvVar)
+	return val == "true" || val == "1" || val == "yes"
+}
+
+// MockFeedbackEngine answers with canned responses built from the diff
+// analysis. It never makes a network request and always returns the same
+// output for the same input, for demos, CI and development without an API key.
+type MockFeedbackEngine struct{}
+
+// NewMockFeedbackEngine creates a new mock feedback engine
+func NewMockFeedbackEngine() *MockFeedbackEngine {
+	return &MockFeedbackEngine{}
+}
+
+// GenerateFeedback describes the commit's diff instead of asking a model about it
+func (e *MockFeedbackEngine) GenerateFeedback(ctx CommitContext) (string, error) {
+	subject, _, _ := strings.Cut(strings.TrimSpace(ctx.Message), "\n")
+	files := analyzeDiffFiles(NormalizeDiff(ctx.Diff))
+	if len(files.Changed) == 0 {
+		return fmt.Sprintf("[mock] Reviewed %q.", subject), nil
+	}
+	return fmt.Sprintf("[mock] Reviewed %q: %s changed (+%d/-%d).",
+		subject, pluralize(len(files.Changed), "file"), files.Additions, files.Deletions), nil
+}
+
+// GenerateSummaryFeedback counts the commits in the summary
+func (e *MockFeedbackEngine) GenerateSummaryFeedback(ctx CommitContext) (string, error) {
+	return fmt.Sprintf("[mock] Summary of %s.", pluralize(len(ctx.CommitHistory), "commit")), nil
+}
+
+// GenerateCommitSuggestion guesses a conventional commit message from the
+// files in the diff
+func (e *MockFeedbackEngine) GenerateCommitSuggestion(ctx CommitContext) (string, error) {
+	if ctx.InitialCommit {
+		return finishLocalSuggestion("chore: initial commit", ctx), nil
+	}
+
+	files := analyzeDiffFiles(NormalizeDiff(ctx.Diff))
+	return finishLocalSuggestion(mockSuggestion(files), ctx), nil
+}
+
+// mockSuggestion builds a subject from the commit type guess and a body
+// listing what happened to each file
+func mockSuggestion(files DiffFiles) string {
+	if len(files.Changed) == 0 {
+		return "chore: update files"
+	}
+
+	commitType := files.onlyType()
+	if commitType == "" {
+		switch {
+		case len(files.Added) == len(files.Changed):
+			commitType = "feat"
+		case len(files.Deleted) == len(files.Changed):
+			commitType = "refactor"
+		default:
+			commitType = "chore"
+		}
+	}
+
+	verb := "update"
+	switch {
+	case len(files.Added) == len(files.Changed):
+		verb = "add"
+	case len(files.Deleted) == len(files.Changed):
+		verb = "remove"
+	}
+
+	subject := fmt.Sprintf("%s: %s %s", commitType, verb, pluralize(len(files.Changed), "file"))
+	if len(files.Changed) == 1 {
+		subject = fmt.Sprintf("%s: %s %s", commitType, verb, filepath.Base(files.Changed[0]))
+	}
+
+	var body []string
+	for _, op := range []struct {
+		verb  string
+		paths []string
+	}{{"Add", files.Added}, {"Update", files.Modified}, {"Remove", files.Deleted}} {
+		for _, path := range op.paths {
+			body = append(body, "- "+op.verb+" "+path)
+		}
+	}
+	if len(body) < 2 {
+		return subject
+	}
+	return subject + "\n\n" + strings.Join(body, "\n")
+}
@@ -0,0 +1,61 @@
+package feedback
+
+import "testing"
+
+func TestMockFeedbackEngine(t *testing.T) {
+	engine := NewFeedbackEngine("mock", "", "", "", "")
+	if _, ok := engine.(*MockFeedbackEngine); !ok {
+		t.Fatalf("NewFeedbackEngine(mock) = %T, want *MockFeedbackEngine", engine)
+	}
+
+	added := "diff --git a/auth/login.go b/auth/login.go\nnew file mode 100644\n--- /dev/null\n+++ b/auth/login.go\n+package auth\n"
+	mixed := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n-old\n+new\n" +
+		"diff --git a/old.go b/old.go\ndeleted file mode 100644\n--- a/old.go\n+++ /dev/null\n-gone\n"
+
+	tests := []struct {
+		name string
+		ctx  CommitContext
+		want string
+	}{
+		{"new file", CommitContext{Diff: added}, "feat: add login.go"},
+		{"mixed", CommitContext{Diff: mixed}, "chore: update 2 files\n\n- Update main.go\n- Remove old.go"},
+		{"docs", CommitContext{Diff: "diff --git a/README.md b/README.md\n+docs\n"}, "docs: update README.md"},
+		{"no body", CommitContext{Diff: mixed, BodyBullets: NoBody}, "chore: update 2 files"},
+		{"initial", CommitContext{Diff: added, InitialCommit: true}, "chore: initial commit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.GenerateCommitSuggestion(tt.ctx)
+			if err != nil {
+				t.Fatalf("GenerateCommitSuggestion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateCommitSuggestion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	feedback, err := engine.GenerateFeedback(CommitContext{Message: "fix: login\n\nbody", Diff: mixed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[mock] Reviewed "fix: login": 2 files changed (+1/-2).`; feedback != want {
+		t.Errorf("GenerateFeedback() = %q, want %q", feedback, want)
+	}
+}
+
+func TestUseMock(t *testing.T) {
+	t.Setenv(MockEnvVar, "")
+	if UseMock("openai") {
+		t.Error("UseMock(openai) = true without NOIDEA_LLM_MOCK")
+	}
+	if !UseMock("Mock") {
+		t.Error("UseMock(Mock) = false")
+	}
+
+	t.Setenv(MockEnvVar, "1")
+	if !UseMock("openai") {
+		t.Error("UseMock(openai) = false with NOIDEA_LLM_MOCK=1")
+	}
+}
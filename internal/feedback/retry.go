@@ -0,0 +1,59 @@
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"github.com/AccursedGalaxy/noidea/internal/interrupt"
+)
+
+// DefaultMaxRetries is how often a rate-limited or failed request is retried
+// unless configured otherwise
+const DefaultMaxRetries = 3
+
+// MaxRetries is how many times a request that hit a rate limit or a server
+// error is retried before giving up. Callers set it from LLM.MaxRetries.
+var MaxRetries = DefaultMaxRetries
+
+// retryBaseDelay is the wait before the first retry; it doubles each attempt
+var retryBaseDelay = time.Second
+
+// withRetry runs call, retrying with exponential backoff while it fails with a
+// transient error. Other errors, such as a bad request or an invalid key, are
+// returned immediately.
+func withRetry[T any](call func() (T, error)) (T, error) {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		result, err := call()
+		if err == nil || attempt >= MaxRetries || !isTransient(err) {
+			return result, err
+		}
+
+		fmt.Fprintf(os.Stderr, "Provider is busy (%v), retrying in %s...\n", err, delay)
+		select {
+		case <-time.After(delay):
+		case <-interrupt.Context().Done():
+			return result, err
+		}
+		delay *= 2
+	}
+}
+
+// isTransient reports whether err is a rate limit or server error worth retrying
+func isTransient(err error) bool {
+	status := 0
+	var apiErr *openai.APIError
+	var requestErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.HTTPStatusCode
+	case errors.As(err, &requestErr):
+		status = requestErr.HTTPStatusCode
+	}
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
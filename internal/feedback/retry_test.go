@@ -0,0 +1,66 @@
+package feedback
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestWithRetry(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	tests := []struct {
+		name      string
+		status    int
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"recovers from rate limit", http.StatusTooManyRequests, 2, 3, false},
+		{"recovers from server error", http.StatusServiceUnavailable, 1, 2, false},
+		{"gives up after max retries", http.StatusBadGateway, 10, DefaultMaxRetries + 1, true},
+		{"does not retry unauthorized", http.StatusUnauthorized, 10, 1, true},
+		{"does not retry bad request", http.StatusBadRequest, 10, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				if calls <= tt.failures {
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, `{"error":{"message":"try later","type":"server_error"}}`)
+					return
+				}
+				fmt.Fprint(w, `{"choices":[{"index":0,"message":{"role":"assistant","content":"fix: typo"}}]}`)
+			}))
+			defer server.Close()
+
+			config := openai.DefaultConfig("test-key")
+			config.BaseURL = server.URL
+			engine := &UnifiedFeedbackEngine{
+				client:   openai.NewClientWithConfig(config),
+				model:    "test-model",
+				provider: ProviderOpenAI,
+			}
+
+			suggestion, err := engine.GenerateCommitSuggestion(CommitContext{Diff: "diff --git a/x b/x"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !tt.wantErr && suggestion != "fix: typo" {
+				t.Errorf("suggestion = %q", suggestion)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package feedback
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isSmallDiff reports whether the staged changes are trivial enough to skip
+// the full diff analysis: a single file with at most ctx.SmallDiffLines
+// added or removed lines. A threshold of 0 disables the fast path, and
+// combined commits and requested commit diffs always get the full analysis.
+func isSmallDiff(ctx CommitContext) bool {
+	if ctx.SmallDiffLines <= 0 || len(ctx.RangeCommits) > 0 || len(ctx.ContextCommits) > 0 {
+		return false
+	}
+
+	files, changed := 0, 0
+	for _, line := range strings.Split(ctx.Diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git"):
+			files++
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			// File headers, not content
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+			changed++
+		}
+	}
+
+	return files == 1 && changed > 0 && changed <= ctx.SmallDiffLines
+}
+
+// buildSmallDiffPrompt creates a minimal prompt for a trivial change, sending
+// just the diff and a few recent subjects for style
+func buildSmallDiffPrompt(ctx CommitContext) string {
+	prompt := fmt.Sprintf(`Suggest a BRIEF, single-line commit message for this small change:
+
+%s
+`, ctx.Diff)
+
+	prompt += suggestionGuidance(ctx)
+
+	if len(ctx.CommitHistory) > 0 {
+		recent := ctx.CommitHistory
+		if len(recent) > 3 {
+			recent = recent[:3]
+		}
+		prompt += fmt.Sprintf(`
+Recent commit messages, for style only:
+%s`, formatCommitList(recent))
+	}
+
+	return prompt
+}
@@ -0,0 +1,94 @@
+package feedback
+
+import (
+	"strings"
+	"testing"
+)
+
+const smallDiff = `diff --git a/README.md b/README.md
+index 1111111..2222222 100644
+--- a/README.md
++++ b/README.md
+@@ -1,3 +1,3 @@
+ # noidea
+-A Git extension
++A Git extension with a Moai
+ that judges your commits.
+`
+
+// largeDiff repeats a multi-file change so the full analysis has real work to do
+var largeDiff = strings.Repeat(`diff --git a/internal/app/server.go b/internal/app/server.go
+index 1111111..2222222 100644
+--- a/internal/app/server.go
++++ b/internal/app/server.go
+@@ -10,6 +10,12 @@ func Start() error {
++func (s *Server) Shutdown(ctx context.Context) error {
++	return s.http.Shutdown(ctx)
++}
++
++type Options struct {
++	Port int
++}
+ 	return nil
+`, 3) + smallDiff
+
+func TestIsSmallDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  CommitContext
+		want bool
+	}{
+		{"single small file", CommitContext{Diff: smallDiff, SmallDiffLines: 10}, true},
+		{"threshold disabled", CommitContext{Diff: smallDiff}, false},
+		{"over threshold", CommitContext{Diff: smallDiff, SmallDiffLines: 1}, false},
+		{"multiple files", CommitContext{Diff: largeDiff, SmallDiffLines: 100}, false},
+		{"combined commits", CommitContext{Diff: smallDiff, SmallDiffLines: 10, RangeCommits: []string{"fix: a"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSmallDiff(tt.ctx); got != tt.want {
+				t.Errorf("isSmallDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmallDiffPromptIsShorter(t *testing.T) {
+	ctx := CommitContext{Diff: smallDiff, CommitHistory: []string{"docs: tweak intro"}}
+
+	small := buildSmallDiffPrompt(ctx)
+	full := buildSuggestionPrompt(ctx, promptTokenBudget(""))
+
+	if len(small) >= len(full) {
+		t.Errorf("small-diff prompt (%d chars) should be shorter than the full prompt (%d chars)", len(small), len(full))
+	}
+	if !strings.Contains(small, "+A Git extension with a Moai") {
+		t.Error("small-diff prompt should include the diff")
+	}
+}
+
+// BenchmarkSuggestionPrompt compares building the full analysis prompt with
+// the small-diff fast path for the same trivial change
+func BenchmarkSuggestionPrompt(b *testing.B) {
+	ctx := CommitContext{
+		Diff:          smallDiff,
+		CommitHistory: []string{"docs: tweak intro", "fix: guard nil config", "feat: add summary"},
+	}
+
+	b.Run("full", func(b *testing.B) {
+		var prompt string
+		for i := 0; i < b.N; i++ {
+			prompt = buildSuggestionPrompt(ctx, promptTokenBudget(""))
+		}
+		b.ReportMetric(float64(len(prompt)/4), "tokens/op")
+	})
+
+	b.Run("small", func(b *testing.B) {
+		var prompt string
+		for i := 0; i < b.N; i++ {
+			prompt = buildSmallDiffPrompt(ctx)
+		}
+		b.ReportMetric(float64(len(prompt)/4), "tokens/op")
+	})
+}
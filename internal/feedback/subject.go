@@ -0,0 +1,68 @@
+package feedback
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+)
+
+// subjectEllipsis marks where an overlong subject was split
+const subjectEllipsis = "…"
+
+// subjectLimit returns the longest subject the engine may suggest: the
+// configured limit less the room the caller's prefix takes, or 0 for none
+func subjectLimit(ctx CommitContext) int {
+	if ctx.MaxSubject <= 0 {
+		return 0
+	}
+	limit := ctx.MaxSubject
+	if ctx.SubjectPrefix != "" {
+		limit -= utf8.RuneCountInString(ctx.SubjectPrefix) + 1
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	return limit
+}
+
+// limitSubject shortens a subject longer than limit characters. It is split
+// after the last word that fits, or inside a word when no word of the
+// description fits, and marked with an ellipsis, and the rest
+// opens the body after a matching ellipsis, the way GitHub splits long pull
+// request titles. With noBody the rest is dropped. A limit of 0 leaves the
+// message alone.
+func limitSubject(message string, limit int, noBody bool) string {
+	subject, body, _ := strings.Cut(message, "\n")
+	if limit <= 0 || utf8.RuneCountInString(subject) <= limit {
+		return message
+	}
+
+	// The type and scope stay whole on the subject line
+	descStart := 0
+	if parsed, ok := conventional.Parse(subject); ok {
+		descStart = utf8.RuneCountInString(subject) - utf8.RuneCountInString(parsed.Description)
+	}
+
+	runes := []rune(subject)
+	cut := limit - 1 // Room for the ellipsis
+	if space := strings.LastIndex(string(runes[:cut+1]), " "); space > 0 {
+		// Split between words rather than inside one, unless only the type would be left
+		if words := utf8.RuneCountInString(subject[:space]); words > descStart {
+			cut = words
+		}
+	}
+
+	head := strings.TrimRight(string(runes[:cut]), " ,;")
+	rest := strings.TrimSpace(string(runes[cut:]))
+	result := head + subjectEllipsis
+	if noBody {
+		return result
+	}
+
+	result += "\n\n" + subjectEllipsis + rest
+	if body = strings.TrimSpace(body); body != "" {
+		result += "\n\n" + body
+	}
+	return result
+}
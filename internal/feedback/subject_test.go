@@ -0,0 +1,36 @@
+package feedback
+
+import "testing"
+
+func TestLimitSubject(t *testing.T) {
+	long := "feat(auth): add token refresh and session expiry handling\n\n- add refresh"
+
+	tests := []struct {
+		name    string
+		message string
+		limit   int
+		noBody  bool
+		want    string
+	}{
+		{"fits", "fix: typo", 20, false, "fix: typo"},
+		{"no limit", long, 0, false, long},
+		{"split between words", long, 40, false, "feat(auth): add token refresh and…\n\n…session expiry handling\n\n- add refresh"},
+		{"no body", long, 40, true, "feat(auth): add token refresh and…"},
+		{"one long word", "docs: supercalifragilisticexpialidocious", 20, false, "docs: supercalifrag…\n\n…ilisticexpialidocious"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limitSubject(tt.message, tt.limit, tt.noBody); got != tt.want {
+				t.Errorf("limitSubject() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubjectLimitLeavesRoomForPrefix(t *testing.T) {
+	ctx := CommitContext{MaxSubject: 72, SubjectPrefix: "[JIRA-123]"}
+	if got := subjectLimit(ctx); got != 61 {
+		t.Errorf("subjectLimit() = %d, want 61", got)
+	}
+}
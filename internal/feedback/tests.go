@@ -0,0 +1,75 @@
+package feedback
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// mentionsTestsRegex matches messages that already talk about tests
+var mentionsTestsRegex = regexp.MustCompile(`(?i)\btest`)
+
+// isTestFile reports whether a path looks like a test file in common ecosystems
+func isTestFile(path string) bool {
+	base := filepath.Base(path)
+	slashed := "/" + filepath.ToSlash(path)
+
+	return strings.Contains(base, "_test.") ||
+		strings.Contains(base, ".test.") ||
+		strings.Contains(base, ".spec.") ||
+		strings.HasPrefix(base, "test_") ||
+		strings.Contains(slashed, "/test/") ||
+		strings.Contains(slashed, "/tests/") ||
+		strings.Contains(slashed, "/__tests__/")
+}
+
+// diffTestFiles returns the test files touched by a diff and whether the
+// diff touches anything besides tests
+func diffTestFiles(diff string) (tests []string, hasOtherChanges bool) {
+	for _, line := range strings.Split(diff, "\n") {
+		if !strings.HasPrefix(line, "diff --git") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			continue
+		}
+		file := strings.TrimPrefix(parts[2], "a/")
+		if isTestFile(file) {
+			tests = append(tests, file)
+		} else {
+			hasOtherChanges = true
+		}
+	}
+	return tests, hasOtherChanges
+}
+
+// noteTests makes sure a commit message acknowledges tests that come with a
+// code change. Single-line messages get "with tests" on the subject, multi-line
+// messages get a bullet naming the test files. Messages that already mention
+// tests, or changes that are only tests, are left alone.
+func noteTests(message, diff string) string {
+	tests, hasOtherChanges := diffTestFiles(diff)
+	if len(tests) == 0 || !hasOtherChanges || message == "" {
+		return message
+	}
+	if mentionsTestsRegex.MatchString(message) {
+		return message
+	}
+
+	subject, body, hasBody := strings.Cut(message, "\n")
+	if !hasBody || strings.TrimSpace(body) == "" {
+		return strings.TrimRight(subject, ". ") + " with tests"
+	}
+
+	names := make([]string, 0, 2)
+	for _, file := range tests {
+		if len(names) == 2 {
+			names = append(names, "...")
+			break
+		}
+		names = append(names, filepath.Base(file))
+	}
+
+	return strings.TrimRight(message, "\n") + "\n- add tests in " + strings.Join(names, ", ")
+}
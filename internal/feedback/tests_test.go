@@ -0,0 +1,43 @@
+package feedback
+
+import "testing"
+
+func TestNoteTests(t *testing.T) {
+	codeAndTest := "diff --git a/auth/login.go b/auth/login.go\n+code\ndiff --git a/auth/login_test.go b/auth/login_test.go\n+test\n"
+	onlyTests := "diff --git a/auth/login_test.go b/auth/login_test.go\n+test\n"
+	onlyCode := "diff --git a/auth/login.go b/auth/login.go\n+code\n"
+
+	tests := []struct {
+		name    string
+		message string
+		diff    string
+		want    string
+	}{
+		{"single line", "feat(auth): add login", codeAndTest, "feat(auth): add login with tests"},
+		{"multi line", "feat(auth): add login\n\n- add form", codeAndTest, "feat(auth): add login\n\n- add form\n- add tests in login_test.go"},
+		{"already mentions tests", "feat(auth): add login and tests", codeAndTest, "feat(auth): add login and tests"},
+		{"only tests", "test(auth): cover login", onlyTests, "test(auth): cover login"},
+		{"no tests", "feat(auth): add login", onlyCode, "feat(auth): add login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := noteTests(tt.message, tt.diff); got != tt.want {
+				t.Errorf("noteTests() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	for _, path := range []string{"pkg/a_test.go", "src/app.test.ts", "web/app.spec.js", "tests/test_api.py", "src/__tests__/x.js"} {
+		if !isTestFile(path) {
+			t.Errorf("isTestFile(%q) = false, want true", path)
+		}
+	}
+	for _, path := range []string{"cmd/suggest.go", "docs/testing.md", "contest/main.go"} {
+		if isTestFile(path) {
+			t.Errorf("isTestFile(%q) = true, want false", path)
+		}
+	}
+}
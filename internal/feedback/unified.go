@@ -1,16 +1,21 @@
 package feedback
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	openai "github.com/sashabaranov/go-openai"
 
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+	"github.com/AccursedGalaxy/noidea/internal/interrupt"
 	"github.com/AccursedGalaxy/noidea/internal/personality"
+	"github.com/AccursedGalaxy/noidea/internal/truncate"
 )
 
 // ProviderConfig contains configuration for different LLM providers
@@ -41,7 +46,19 @@ var (
 	}
 )
 
-// UnifiedFeedbackEngine generates feedback using any OpenAI-compatible API
+// sharedHTTPClient is used by every engine so API calls reuse keep-alive
+// connections, including across engines for different providers
+var sharedHTTPClient = &http.Client{
+	Transport: func() http.RoundTripper {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConnsPerHost = 8
+		return transport
+	}(),
+}
+
+// UnifiedFeedbackEngine generates feedback using any OpenAI-compatible API.
+// It is safe for concurrent use, so batch commands and noidea serve create
+// one and reuse it for every request.
 type UnifiedFeedbackEngine struct {
 	client            *openai.Client
 	model             string
@@ -49,37 +66,15 @@ type UnifiedFeedbackEngine struct {
 	personalityName   string
 	personalityFile   string
 	customPersonality *personality.Personality // Custom personality configuration if provided
+
+	// The personality is loaded from personalityFile on first use
+	personalityOnce sync.Once
+	personality     personality.Personality
 }
 
 // NewUnifiedFeedbackEngine creates a new unified feedback engine
 func NewUnifiedFeedbackEngine(provider string, model string, apiKey string, personalityName string, personalityFile string) *UnifiedFeedbackEngine {
-	var providerConfig ProviderConfig
-
-	// Select provider configuration
-	switch provider {
-	case "xai":
-		providerConfig = ProviderXAI
-	case "openai":
-		providerConfig = ProviderOpenAI
-	case "deepseek":
-		providerConfig = ProviderDeepSeek
-	default:
-		// Default to OpenAI if unknown provider
-		providerConfig = ProviderOpenAI
-	}
-
-	// Use provider's default model if none specified
-	if model == "" {
-		model = providerConfig.DefaultModel
-	}
-
-	// Configure the client
-	config := openai.DefaultConfig(apiKey)
-	if providerConfig.BaseURL != "" {
-		config.BaseURL = providerConfig.BaseURL
-	}
-
-	client := openai.NewClientWithConfig(config)
+	providerConfig, model, client := newProviderClient(provider, model, apiKey)
 	return &UnifiedFeedbackEngine{
 		client:          client,
 		model:           model,
@@ -91,6 +86,24 @@ func NewUnifiedFeedbackEngine(provider string, model string, apiKey string, pers
 
 // NewUnifiedFeedbackEngineWithCustomPersonality creates a new unified feedback engine with a custom personality
 func NewUnifiedFeedbackEngineWithCustomPersonality(provider string, model string, apiKey string, customPersonality personality.Personality) *UnifiedFeedbackEngine {
+	providerConfig, model, client := newProviderClient(provider, model, apiKey)
+	engine := &UnifiedFeedbackEngine{
+		client:          client,
+		model:           model,
+		provider:        providerConfig,
+		personalityName: customPersonality.Name,
+		personalityFile: "", // Not used when passing custom personality
+	}
+
+	// Store the custom personality for later use
+	engine.customPersonality = &customPersonality
+
+	return engine
+}
+
+// newProviderClient returns the configuration for a provider, the model to
+// use (the provider's default when model is empty) and an API client for it
+func newProviderClient(provider, model, apiKey string) (ProviderConfig, string, *openai.Client) {
 	var providerConfig ProviderConfig
 
 	// Select provider configuration
@@ -116,37 +129,39 @@ func NewUnifiedFeedbackEngineWithCustomPersonality(provider string, model string
 	if providerConfig.BaseURL != "" {
 		config.BaseURL = providerConfig.BaseURL
 	}
+	config.HTTPClient = sharedHTTPClient
 
-	client := openai.NewClientWithConfig(config)
-	engine := &UnifiedFeedbackEngine{
-		client:          client,
-		model:           model,
-		provider:        providerConfig,
-		personalityName: customPersonality.Name,
-		personalityFile: "", // Not used when passing custom personality
+	return providerConfig, model, openai.NewClientWithConfig(config)
+}
+
+// selectedPersonality returns the custom personality, or the configured one
+// from the personality file, loading the file only once
+func (e *UnifiedFeedbackEngine) selectedPersonality() personality.Personality {
+	if e.customPersonality != nil {
+		return *e.customPersonality
 	}
 
-	// Store the custom personality for later use
-	engine.customPersonality = &customPersonality
+	e.personalityOnce.Do(func() {
+		// Load personality configuration
+		personalities, err := personality.LoadPersonalities(e.personalityFile)
+		if err != nil {
+			// Fall back to default personalities if there's an error
+			personalities = personality.DefaultPersonalities()
+		}
 
-	return engine
+		// Get the selected personality
+		e.personality, err = personalities.GetPersonality(e.personalityName)
+		if err != nil {
+			// Fall back to default personality
+			e.personality, _ = personalities.GetPersonality("")
+		}
+	})
+	return e.personality
 }
 
 // GenerateFeedback implements the FeedbackEngine interface
 func (e *UnifiedFeedbackEngine) GenerateFeedback(ctx CommitContext) (string, error) {
-	// Load personality configuration
-	personalities, err := personality.LoadPersonalities(e.personalityFile)
-	if err != nil {
-		// Fall back to default personalities if there's an error
-		personalities = personality.DefaultPersonalities()
-	}
-
-	// Get the selected personality
-	personalityConfig, err := personalities.GetPersonality(e.personalityName)
-	if err != nil {
-		// Fall back to default personality
-		personalityConfig, _ = personalities.GetPersonality("")
-	}
+	personalityConfig := e.selectedPersonality()
 
 	// Create personality context for template rendering
 	personalityCtx := personality.Context{
@@ -184,7 +199,10 @@ func (e *UnifiedFeedbackEngine) GenerateFeedback(ctx CommitContext) (string, err
 	}
 
 	// Send the request to the API
-	response, err := e.client.CreateChatCompletion(context.Background(), request)
+	response, err := withRetry(func() (openai.ChatCompletionResponse, error) {
+		return e.client.CreateChatCompletion(interrupt.Context(), request)
+	})
+	e.report(RequestFeedback, response.Usage, err)
 	if err != nil {
 		return "", fmt.Errorf("%s API error: %w", e.provider.Name, err)
 	}
@@ -199,27 +217,7 @@ func (e *UnifiedFeedbackEngine) GenerateFeedback(ctx CommitContext) (string, err
 
 // GenerateSummaryFeedback provides insights for a weekly summary or on-demand analysis
 func (e *UnifiedFeedbackEngine) GenerateSummaryFeedback(ctx CommitContext) (string, error) {
-	var personalityConfig personality.Personality
-	var err error
-
-	// Use custom personality if provided
-	if e.customPersonality != nil {
-		personalityConfig = *e.customPersonality
-	} else {
-		// Load personality configuration from file
-		personalities, err := personality.LoadPersonalities(e.personalityFile)
-		if err != nil {
-			// Fall back to default personalities if there's an error
-			personalities = personality.DefaultPersonalities()
-		}
-
-		// Get the selected personality
-		personalityConfig, err = personalities.GetPersonality(e.personalityName)
-		if err != nil {
-			// Fall back to default personality
-			personalityConfig, _ = personalities.GetPersonality("")
-		}
-	}
+	personalityConfig := e.selectedPersonality()
 
 	// Create a custom system prompt for summaries or on-demand feedback
 	systemPrompt := personalityConfig.SystemPrompt
@@ -344,7 +342,10 @@ Respond with thoughtful analysis and actionable suggestions:`,
 	}
 
 	// Send the request to the API
-	response, err := e.client.CreateChatCompletion(context.Background(), request)
+	response, err := withRetry(func() (openai.ChatCompletionResponse, error) {
+		return e.client.CreateChatCompletion(interrupt.Context(), request)
+	})
+	e.report(RequestSummary, response.Usage, err)
 	if err != nil {
 		return "", fmt.Errorf("%s API error: %w", e.provider.Name, err)
 	}
@@ -359,225 +360,214 @@ Respond with thoughtful analysis and actionable suggestions:`,
 
 // GenerateCommitSuggestion creates an AI-generated commit message based on staged changes
 func (e *UnifiedFeedbackEngine) GenerateCommitSuggestion(ctx CommitContext) (string, error) {
-	// Load personality configuration - only needed for fallback, not for styling
-	personalities, err := personality.LoadPersonalities(e.personalityFile)
-	if err != nil {
-		// Fall back to default personalities if there's an error
-		personalities = personality.DefaultPersonalities()
-	}
+	request := e.suggestionRequest(ctx)
 
-	// Get the selected personality - we'll only use this for basic functionality
-	_, err = personalities.GetPersonality(e.personalityName)
+	// Send the request to the API
+	response, err := withRetry(func() (openai.ChatCompletionResponse, error) {
+		return e.client.CreateChatCompletion(interrupt.Context(), request)
+	})
+	e.report(RequestSuggestion, response.Usage, err)
 	if err != nil {
-		// Just make sure we have a valid personality, but won't use its styling
-		_, _ = personalities.GetPersonality("")
+		return "", fmt.Errorf("%s API error: %w", e.provider.Name, err)
 	}
 
-	// Use a custom system prompt focused on commit message generation
-	// This override ensures professional commit messages regardless of personality
-	systemPrompt := `You are a professional Git expert who writes clear, precise, and effective commit messages.
-Your task is to suggest a commit message that accurately describes the changes.
-Follow these guidelines:
-1. Use conventional commits format for the subject line: type(scope): description
-2. Subject line should ideally be around 50 characters - aim for this as a guideline, but prioritize clarity and completeness over strict length
-3. For SUBSTANTIAL changes (multiple files or significant code changes), ALWAYS add a blank line followed by 2-4 bullet points explaining key changes
-4. Use present tense imperative mood (e.g., "fix bug" not "fixes bug")
-5. The subject line should focus on the most significant aspect of the change
-6. Include scope in parentheses when appropriate: type(scope): description
-7. Common types: feat, fix, docs, style, refactor, test, chore
-8. Make bullet points start with "- " and be concise but descriptive
-9. If changes affect more than 3 files or have >100 line changes, DEFINITELY use a multi-line format
-10. Respond with ONLY the commit message, no explanations
-
-For small changes, a single line is sufficient.
-For major changes (>100 lines or multiple files), ALWAYS use multi-line format with bullet points.`
-
-	// TOKEN LIMIT MANAGEMENT
-	// We'll analyze the diff first, then include only what fits in the token limit
-	// Maximum estimated tokens we want to send (leaving room for overhead and system message)
-	const maxTokens = 100000
-
-	// Simple diff parser to count lines and identify files
-	lines := strings.Split(ctx.Diff, "\n")
-	currentFile := ""
-
-	// Track different types of files
-	docFiles := make(map[string]bool)    // Documentation files (.md, .txt, etc)
-	codeFiles := make(map[string]bool)   // Source code files (.go, .js, etc)
-	configFiles := make(map[string]bool) // Configuration files (.json, .yaml, etc)
-	buildFiles := make(map[string]bool)  // Build files (Makefile, CMakeLists.txt, etc)
-	testFiles := make(map[string]bool)   // Test files (*_test.go, etc)
-	scriptFiles := make(map[string]bool) // Scripts (.sh, .bat, etc)
+	// Extract the response content
+	if len(response.Choices) > 0 {
+		return finishSuggestion(ctx, response.Choices[0].Message.Content), nil
+	}
 
-	// Track file operations
-	changedFiles := make(map[string]bool)
-	addedFiles := make(map[string]bool)
-	modifiedFiles := make(map[string]bool)
-	deletedFiles := make(map[string]bool)
+	return "", fmt.Errorf("no response from %s API", e.provider.Name)
+}
 
-	var totalAdditions, totalDeletions int
+// GenerateCommitSuggestions asks the model for n alternative commit messages
+// in one request. Duplicates are dropped, so fewer may be returned.
+func (e *UnifiedFeedbackEngine) GenerateCommitSuggestions(ctx CommitContext, n int) ([]string, error) {
+	request := e.suggestionRequest(ctx)
+	request.N = n
 
-	// File extension categorization maps for better maintainability
-	extensionCategories := map[string]map[string]bool{
-		"doc":    docFiles,
-		"code":   codeFiles,
-		"config": configFiles,
-		"build":  buildFiles,
-		"script": scriptFiles,
+	response, err := withRetry(func() (openai.ChatCompletionResponse, error) {
+		return e.client.CreateChatCompletion(interrupt.Context(), request)
+	})
+	e.report(RequestSuggestion, response.Usage, err)
+	if err != nil {
+		return nil, fmt.Errorf("%s API error: %w", e.provider.Name, err)
 	}
 
-	// Map extensions to categories
-	extensionMap := map[string]string{
-		// Documentation files
-		".md": "doc", ".txt": "doc", ".rst": "doc", ".adoc": "doc",
-		".markdown": "doc", ".wiki": "doc", ".org": "doc",
-
-		// Source code files
-		".go": "code", ".js": "code", ".ts": "code", ".py": "code",
-		".java": "code", ".c": "code", ".cpp": "code", ".cc": "code",
-		".h": "code", ".hpp": "code", ".cs": "code", ".rb": "code",
-		".php": "code", ".swift": "code", ".kt": "code", ".rs": "code",
+	suggestions := uniqueSuggestions(ctx, response.Choices)
+	if len(suggestions) == 0 {
+		return nil, fmt.Errorf("no response from %s API", e.provider.Name)
+	}
+	return suggestions, nil
+}
 
-		// Configuration files
-		".json": "config", ".yaml": "config", ".yml": "config", ".toml": "config",
-		".ini": "config", ".xml": "config", ".properties": "config", ".conf": "config",
+// uniqueSuggestions cleans up each choice, dropping empty and repeated ones
+func uniqueSuggestions(ctx CommitContext, choices []openai.ChatCompletionChoice) []string {
+	var suggestions []string
+	seen := make(map[string]bool)
+	for _, choice := range choices {
+		suggestion := finishSuggestion(ctx, choice.Message.Content)
+		if strings.TrimSpace(suggestion) == "" || seen[suggestion] {
+			continue
+		}
+		seen[suggestion] = true
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
 
-		// Build files
-		".bazel": "build", ".bzl": "build", ".mk": "build",
+// GenerateCommitSuggestionStream creates a commit message like
+// GenerateCommitSuggestion, writing the text to out as it arrives. The
+// returned message is cleaned up and may differ slightly from what was written.
+func (e *UnifiedFeedbackEngine) GenerateCommitSuggestionStream(ctx CommitContext, out io.Writer) (string, error) {
+	request := e.suggestionRequest(ctx)
+	request.Stream = true
 
-		// Script files
-		".sh": "script", ".bash": "script", ".zsh": "script",
-		".bat": "script", ".cmd": "script", ".ps1": "script",
+	stream, err := withRetry(func() (*openai.ChatCompletionStream, error) {
+		return e.client.CreateChatCompletionStream(interrupt.Context(), request)
+	})
+	if err != nil {
+		e.report(RequestSuggestion, openai.Usage{}, err)
+		return "", fmt.Errorf("%s API error: %w", e.provider.Name, err)
 	}
+	defer stream.Close()
 
-	// Process the diff to collect information
-	for _, line := range lines {
-		if strings.HasPrefix(line, "diff --git") {
-			parts := strings.Fields(line)
-			if len(parts) >= 3 {
-				filePath := strings.TrimPrefix(parts[2], "a/")
-				currentFile = filePath
-				changedFiles[filePath] = true
-
-				// Categorize by file type
-				ext := filepath.Ext(filePath)
-				baseName := filepath.Base(filePath)
-
-				// Special file handling for common non-extension files
-				if baseName == "Makefile" || baseName == "Dockerfile" ||
-					baseName == "CMakeLists.txt" || strings.HasPrefix(baseName, "Jenkinsfile") {
-					buildFiles[filePath] = true
-				} else if strings.Contains(filePath, "_test.") {
-					// Test files get special handling
-					testFiles[filePath] = true
-				} else if category, found := extensionMap[ext]; found {
-					// Use the extension map for categorization
-					extensionCategories[category][filePath] = true
-				}
-			}
-		} else if strings.HasPrefix(line, "new file mode") {
-			addedFiles[currentFile] = true
-		} else if strings.HasPrefix(line, "deleted file mode") {
-			deletedFiles[currentFile] = true
-		} else if !deletedFiles[currentFile] && !addedFiles[currentFile] {
-			modifiedFiles[currentFile] = true
+	// Streamed responses don't include token usage
+	var raw strings.Builder
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
 		}
-
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
-			totalAdditions++
-		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
-			totalDeletions++
+		if err != nil {
+			e.report(RequestSuggestion, openai.Usage{}, err)
+			return "", fmt.Errorf("%s API error: %w", e.provider.Name, err)
+		}
+		if len(response.Choices) == 0 {
+			continue
 		}
-	}
 
-	// Remove files from modified if they're added or deleted
-	for file := range addedFiles {
-		delete(modifiedFiles, file)
+		delta := response.Choices[0].Delta.Content
+		raw.WriteString(delta)
+		fmt.Fprint(out, delta)
 	}
-	for file := range deletedFiles {
-		delete(modifiedFiles, file)
+
+	if raw.Len() == 0 {
+		err := fmt.Errorf("no response from %s API", e.provider.Name)
+		e.report(RequestSuggestion, openai.Usage{}, err)
+		return "", err
 	}
+	e.report(RequestSuggestion, openai.Usage{}, nil)
 
-	// Create a summarized diff analysis
-	diffAnalysis := fmt.Sprintf("- Total files changed: %d (%d added, %d modified, %d deleted)\n",
-		len(changedFiles), len(addedFiles), len(modifiedFiles), len(deletedFiles))
-	diffAnalysis += fmt.Sprintf("- Lines: +%d, -%d\n\n", totalAdditions, totalDeletions)
+	return finishSuggestion(ctx, raw.String()), nil
+}
 
-	// Add file categories analysis
-	if len(docFiles) > 0 {
-		fileList := make([]string, 0, len(docFiles))
-		for file := range docFiles {
-			fileList = append(fileList, file)
+// suggestionRequest builds the chat request for a commit message suggestion
+func (e *UnifiedFeedbackEngine) suggestionRequest(ctx CommitContext) openai.ChatCompletionRequest {
+	// Encoded binary contents are noise to the model; the markers say enough.
+	// Minified or generated lines are cut so one can't fill the prompt.
+	ctx.Diff = truncate.Lines(stripBinaryPatches(ctx.Diff), ctx.MaxLineLength)
+	if len(ctx.ContextCommits) > 0 {
+		commits := make([]string, len(ctx.ContextCommits))
+		for i, commit := range ctx.ContextCommits {
+			commits[i] = truncate.Lines(commit, ctx.MaxLineLength)
 		}
-		diffAnalysis += fmt.Sprintf("Documentation files: %s\n", strings.Join(fileList, ", "))
+		ctx.ContextCommits = commits
 	}
 
-	if len(codeFiles) > 0 {
-		fileList := make([]string, 0, len(codeFiles))
-		for file := range codeFiles {
-			fileList = append(fileList, file)
-		}
-		diffAnalysis += fmt.Sprintf("Code files: %s\n", strings.Join(fileList, ", "))
+	// Use a custom system prompt focused on commit message generation
+	// This override ensures professional commit messages regardless of personality
+	// The body rules follow the configured bullet limit
+	bodyRule := fmt.Sprintf("For SUBSTANTIAL changes (multiple files or significant code changes), ALWAYS add a blank line followed by %s explaining key changes", bulletRange(ctx.BodyBullets))
+	sizeRule := "If changes affect more than 3 files or have >100 line changes, DEFINITELY use a multi-line format"
+	lengthRule := "Subject line should ideally be around 50 characters - aim for this as a guideline, but prioritize clarity and completeness over strict length"
+	if limit := subjectLimit(ctx); limit > 0 {
+		lengthRule = fmt.Sprintf("Subject line MUST be at most %d characters, including the type and scope - choose shorter words rather than going over", limit)
+	}
+	closing := `For small changes, a single line is sufficient.
+For major changes (>100 lines or multiple files), ALWAYS use multi-line format with bullet points.`
+	if ctx.BodyBullets == NoBody {
+		bodyRule = "Write ONLY a subject line, never a body, whatever the size of the change"
+		sizeRule = "For large changes, name the most significant change in the subject line"
+		closing = "Never add a body or bullet points."
 	}
 
-	if len(buildFiles) > 0 {
-		fileList := make([]string, 0, len(buildFiles))
-		for file := range buildFiles {
-			fileList = append(fileList, file)
-		}
-		diffAnalysis += fmt.Sprintf("Build files: %s\n", strings.Join(fileList, ", "))
-	}
+	systemPrompt := fmt.Sprintf(`You are a professional Git expert who writes clear, precise, and effective commit messages.
+Your task is to suggest a commit message that accurately describes the changes.
+Follow these guidelines:
+1. Use conventional commits format for the subject line: type(scope): description
+2. %s
+3. %s
+4. Use present tense imperative mood (e.g., "fix bug" not "fixes bug")
+5. The subject line should focus on the most significant aspect of the change
+6. Include scope in parentheses when appropriate: type(scope): description
+7. Common types: feat, fix, docs, style, refactor, test, chore
+8. Make bullet points start with "- " and be concise but descriptive
+9. %s
+10. Respond with ONLY the commit message, no explanations
 
-	if len(scriptFiles) > 0 {
-		fileList := make([]string, 0, len(scriptFiles))
-		for file := range scriptFiles {
-			fileList = append(fileList, file)
-		}
-		diffAnalysis += fmt.Sprintf("Script files: %s\n", strings.Join(fileList, ", "))
+%s`, lengthRule, bodyRule, sizeRule, closing)
+
+	// Past subjects may carry ticket prefixes; the caller adds the right one
+	if ctx.SubjectPrefix != "" {
+		systemPrompt += fmt.Sprintf(`
+Start the subject line with the type. Do NOT add a ticket or issue prefix such as %q: it is added automatically.`, ctx.SubjectPrefix)
 	}
 
-	if len(configFiles) > 0 {
-		fileList := make([]string, 0, len(configFiles))
-		for file := range configFiles {
-			fileList = append(fileList, file)
-		}
-		diffAnalysis += fmt.Sprintf("Config files: %s\n", strings.Join(fileList, ", "))
+	// Trivial changes get a minimal prompt; the full analysis is noise for them
+	var userPrompt string
+	if isSmallDiff(ctx) {
+		userPrompt = buildSmallDiffPrompt(ctx)
+	} else {
+		userPrompt = buildSuggestionPrompt(ctx, promptTokenBudget(e.model))
 	}
 
-	if len(testFiles) > 0 {
-		fileList := make([]string, 0, len(testFiles))
-		for file := range testFiles {
-			fileList = append(fileList, file)
-		}
-		diffAnalysis += fmt.Sprintf("Test files: %s\n", strings.Join(fileList, ", "))
+	// Create the chat completion request
+	return openai.ChatCompletionRequest{
+		Model: e.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: systemPrompt,
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: userPrompt,
+			},
+		},
+		Temperature: 0.3, // Slightly higher temperature for more nuanced messages
+		MaxTokens:   250, // Increased token limit to accommodate multi-line messages
+		N:           1,
 	}
 
-	// Add operations analysis
-	diffAnalysis += "\nFile operations:\n"
+}
 
-	if len(addedFiles) > 0 {
-		fileList := make([]string, 0, len(addedFiles))
-		for file := range addedFiles {
-			fileList = append(fileList, file)
-		}
-		diffAnalysis += fmt.Sprintf("Added: %s\n", strings.Join(fileList, ", "))
+// finishSuggestion cleans up a raw model response into the suggested message
+func finishSuggestion(ctx CommitContext, raw string) string {
+	// Clean up the response and extract only the actual commit message
+	suggestion := limitBody(extractCommitMessage(raw, ctx.CommentChar), ctx.BodyBullets)
+	if ctx.NoteTests {
+		// A bullet about tests counts toward the limit
+		suggestion = limitBody(noteTests(suggestion, ctx.Diff), ctx.BodyBullets)
 	}
-
-	if len(modifiedFiles) > 0 {
-		fileList := make([]string, 0, len(modifiedFiles))
-		for file := range modifiedFiles {
-			fileList = append(fileList, file)
-		}
-		diffAnalysis += fmt.Sprintf("Modified: %s\n", strings.Join(fileList, ", "))
+	if ctx.ForceOnlyType {
+		suggestion = forceOnlyType(suggestion, ctx.Diff)
 	}
 
-	if len(deletedFiles) > 0 {
-		fileList := make([]string, 0, len(deletedFiles))
-		for file := range deletedFiles {
-			fileList = append(fileList, file)
-		}
-		diffAnalysis += fmt.Sprintf("Deleted: %s\n", strings.Join(fileList, ", "))
-	}
+	suggestion = limitSubject(ctx.TypeRules.Apply(suggestion), subjectLimit(ctx), ctx.BodyBullets == NoBody)
+	return wrapBody(suggestion, ctx.WrapBody)
+}
+
+// buildSuggestionPrompt analyzes the diff and builds the full user prompt for
+// a commit message suggestion, keeping it within maxTokens
+func buildSuggestionPrompt(ctx CommitContext, maxTokens int) string {
+	// TOKEN LIMIT MANAGEMENT
+	// We'll analyze the diff first, then include only what fits in the token limit.
+	// maxTokens is the model's context window less room for the system message
+	// and the response (see promptTokenBudget).
+
+	// Summarize which files changed and how
+	files := analyzeDiffFiles(ctx.Diff)
+	diffAnalysis := files.describe()
 
 	// Create the diff context: Now with smart truncation
 	// Estimate tokens: ~4 chars per token as a conservative estimate
@@ -593,7 +583,7 @@ Here's an analysis of the staged changes:
 	truncatedDiff := ctx.Diff
 	if len(truncatedDiff) > maxDiffChars {
 		// Extract the beginning of the diff with meaningful changes
-		fileCount := len(changedFiles)
+		fileCount := len(files.Changed)
 
 		// For repositories with many files, limit to showing the first few most important files
 		if fileCount > 5 {
@@ -630,7 +620,7 @@ Here's a sample of the staged changes:
 	}
 
 	// Create a user prompt focused on commit message generation with emphasis on changes
-	isSubstantialChange := len(changedFiles) > 2 || totalAdditions+totalDeletions > 50
+	isSubstantialChange := len(files.Changed) > 2 || files.Additions+files.Deletions > 50
 
 	// Limit commit history to save tokens
 	var commitHistoryStr string
@@ -641,7 +631,11 @@ Here's a sample of the staged changes:
 		if len(historyToUse) > historyLimit {
 			historyToUse = historyToUse[:historyLimit]
 		}
-		commitHistoryStr = formatCommitList(historyToUse)
+		if len(ctx.CommitBodies) > 0 {
+			commitHistoryStr = formatCommitListWithBodies(historyToUse, ctx.CommitBodies)
+		} else {
+			commitHistoryStr = formatCommitList(historyToUse)
+		}
 	} else {
 		commitHistoryStr = "(No recent commit history available)"
 	}
@@ -672,25 +666,55 @@ CODE STRUCTURE ANALYSIS:
 %s`, structureAnalysis)
 	}
 
-	// Add commit history at the end with lowest priority
-	if len(basePrompt) < (maxTokens * 3 / 4) {
+	// When combining existing commits, the whole set must be summarized as one change
+	if len(ctx.RangeCommits) > 0 {
+		basePrompt += fmt.Sprintf(`
+These changes come from the following commits, which will be combined into ONE commit.
+Write a single cohesive message that summarizes them all rather than repeating each one:
+%s`, formatCommitList(ctx.RangeCommits))
+	}
+
+	// Nudge the model to credit tests that accompany a code change
+	if tests := len(files.Categories[CategoryTest]); ctx.NoteTests && tests > 0 && tests < len(files.Changed) {
+		basePrompt += `
+These changes include new or updated tests alongside the code. Acknowledge them in the
+message: end a single-line subject with "with tests", or add a bullet point about test coverage.
+`
+	}
+
+	// Issue references and type restrictions apply to every prompt
+	basePrompt += suggestionGuidance(ctx)
+
+	// Recent changes help when this one extends them, as far as they fit
+	basePrompt += formatContextCommits(ctx.ContextCommits, maxTokens*2-len(basePrompt))
+
+	// Add commit history at the end with lowest priority; a first commit has none
+	if !ctx.InitialCommit && len(basePrompt) < (maxTokens*3/4) {
 		basePrompt += fmt.Sprintf(`
 Past commit messages for limited context (do not rely heavily on these patterns):
 %s`, commitHistoryStr)
 	}
 
 	// Add instructions based on change size
-	if isSubstantialChange {
+	if isSubstantialChange && ctx.BodyBullets == NoBody {
+		userPrompt = basePrompt + fmt.Sprintf(`
+
+This is a SUBSTANTIAL change affecting %d files with %d insertions and %d deletions.
+Even so, write ONLY a single subject line following conventional commit format (type(scope): description), with no body.
+
+Based primarily on the ACTUAL CODE CHANGES shown above, suggest the subject line that best captures the most important change:`,
+			len(files.Changed), files.Additions, files.Deletions)
+	} else if isSubstantialChange {
 		userPrompt = basePrompt + fmt.Sprintf(`
 
 This is a SUBSTANTIAL change affecting %d files with %d insertions and %d deletions.
 Therefore, please provide a multi-line commit message with:
 1. A clear, concise subject line following conventional commit format (type(scope): description)
 2. A blank line
-3. 2-4 bullet points that summarize the key components or areas changed
+3. %s that summarize the key components or areas changed
 
 Based primarily on the ACTUAL CODE CHANGES shown above, create a detailed commit message that accurately captures the scope and meaning of these changes:`,
-			len(changedFiles), totalAdditions, totalDeletions)
+			len(files.Changed), files.Additions, files.Deletions, bulletRange(ctx.BodyBullets))
 	} else {
 		userPrompt = basePrompt + `
 
@@ -700,45 +724,72 @@ Based primarily on the ACTUAL CODE CHANGES shown above, suggest a BRIEF, CONCISE
 	// Ensure final prompt isn't too large
 	if len(userPrompt) > maxTokens*4 {
 		// Truncate with a note about truncation
-		userPrompt = TruncateWithEllipsis(userPrompt, maxTokens*4-100) + "\n\n[Note: Some context was truncated due to size constraints]"
+		userPrompt = TruncateWithEllipsis(userPrompt, maxTokens*4-100) + "\n" + truncate.Notice()
 	}
 
-	// Create the chat completion request
-	request := openai.ChatCompletionRequest{
-		Model: e.model,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleSystem,
-				Content: systemPrompt,
-			},
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: userPrompt,
-			},
-		},
-		Temperature: 0.3, // Slightly higher temperature for more nuanced messages
-		MaxTokens:   250, // Increased token limit to accommodate multi-line messages
-		N:           1,
-	}
+	return userPrompt
+}
 
-	// Send the request to the API
-	response, err := e.client.CreateChatCompletion(context.Background(), request)
-	if err != nil {
-		return "", fmt.Errorf("%s API error: %w", e.provider.Name, err)
+// formatContextCommits returns the prompt section for the diffs of recent
+// commits, newest first, adding commits while the section stays within
+// maxChars. Older commits that don't fit are left out.
+func formatContextCommits(commits []string, maxChars int) string {
+	const header = `
+The staged changes may continue this recent work. These are the latest commits, newest first,
+for context only; describe the staged changes, not these commits:
+`
+	section := header
+	for _, commit := range commits {
+		commit = strings.TrimSpace(stripBinaryPatches(commit))
+		if commit == "" {
+			continue
+		}
+		entry := "\n" + commit + "\n"
+		if len(section)+len(entry) > maxChars {
+			break
+		}
+		section += entry
 	}
 
-	// Extract the response content
-	if len(response.Choices) > 0 {
-		// Get the raw response
-		rawSuggestion := response.Choices[0].Message.Content
+	if section == header {
+		return ""
+	}
+	return section
+}
 
-		// Clean up the response and extract only the actual commit message
-		suggestion := extractCommitMessage(rawSuggestion)
+// suggestionGuidance returns the prompt sections for related issues and
+// commit type restrictions, shared by the full and small-diff prompts
+func suggestionGuidance(ctx CommitContext) string {
+	// Docs-only and test-only changes are often mislabeled feat
+	guidance := onlyTypeHint(ctx.Diff)
+
+	// There is no history to match, and the first commit has its own conventions
+	if ctx.InitialCommit {
+		guidance += `
+This is the FIRST commit of a new repository. Write an initial-commit message: "chore: initial commit"
+for a bare skeleton, or a subject that summarizes the project being set up from the added files,
+such as "chore: scaffold Go CLI with cobra and config loading".
+`
+	}
+
+	// Tracked work lets the model reuse the team's phrasing and reference issues
+	if len(ctx.RelatedIssues) > 0 {
+		guidance += fmt.Sprintf(`
+Open issues and pull requests in this repository:
+%s
+If the changes clearly resolve one of these issues, add a "Closes #N" footer after a blank line.
+Do not reference an issue unless the changes obviously relate to it.
+`, strings.Join(ctx.RelatedIssues, "\n"))
+	}
 
-		return suggestion, nil
+	// Teams with a restricted type vocabulary need the output to conform
+	if len(ctx.TypeRules.Allowed) > 0 {
+		guidance += fmt.Sprintf(`
+Only use these commit types: %s
+`, strings.Join(ctx.TypeRules.Allowed, ", "))
 	}
 
-	return "", fmt.Errorf("no response from %s API", e.provider.Name)
+	return guidance
 }
 
 // TruncateWithEllipsis truncates a string to maxLen and adds an ellipsis
@@ -752,7 +803,10 @@ func TruncateWithEllipsis(s string, maxLen int) string {
 }
 
 // extractCommitMessage parses the LLM response to extract just the commit message
-func extractCommitMessage(response string) string {
+func extractCommitMessage(response, commentChar string) string {
+	// Drop any truncation marker the model echoed back from the prompt
+	response = truncate.Strip(response)
+
 	// Trim whitespace
 	response = strings.TrimSpace(response)
 
@@ -776,47 +830,31 @@ func extractCommitMessage(response string) string {
 
 	// Get first non-empty line
 	var firstLine string
-	for _, line := range lines {
-		if trimmedLine := strings.TrimSpace(line); trimmedLine != "" && !strings.HasPrefix(trimmedLine, "#") {
+	subjectIndex := 0
+	for i, line := range lines {
+		if trimmedLine := strings.TrimSpace(line); trimmedLine != "" && !conventional.IsCommentLine(trimmedLine, commentChar) {
 			firstLine = trimmedLine
+			subjectIndex = i
 			break
 		}
 	}
 
-	// Don't artificially truncate the first line - let git's UI handle this naturally
-	// We tell the model to aim for 50 chars in the prompt, but we won't enforce it
+	// Don't artificially truncate the first line - let git's UI handle this naturally.
+	// We tell the model to aim for 50 chars in the prompt; only a configured
+	// limit is enforced, by limitSubject
 
 	// If we have a conventional commit format, ensure it's properly formatted
-	if strings.Contains(firstLine, ":") {
-		parts := strings.SplitN(firstLine, ":", 2)
-		if len(parts) == 2 {
-			prefix := parts[0]
-			message := strings.TrimSpace(parts[1])
-
-			// Known conventional commit types
-			commitTypes := []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
-
-			// Check if prefix is a valid commit type or a type with scope
-			isValidType := false
-			for _, cType := range commitTypes {
-				if prefix == cType || strings.HasPrefix(prefix, cType+"(") && strings.HasSuffix(prefix, ")") {
-					isValidType = true
-					break
-				}
-			}
-
-			if isValidType {
-				// Proper formatting with no space before colon and one space after
-				firstLine = prefix + ": " + message
-			}
-		}
+	// with no space before the colon and one space after
+	if subject, ok := conventional.Parse(firstLine); ok && conventional.IsKnownType(subject.Type) {
+		firstLine = subject.String()
 	}
 
 	// Process body lines - preserve bullet points and maintain proper multi-line format
 	var bodyLines []string
+	var footerLines []string
 	var inBody = false
 
-	for i := 1; i < len(lines); i++ {
+	for i := subjectIndex + 1; i < len(lines); i++ {
 		trimmedLine := strings.TrimSpace(lines[i])
 
 		// Skip empty lines until we reach body content
@@ -827,8 +865,14 @@ func extractCommitMessage(response string) string {
 		// We've now reached body content
 		inBody = true
 
+		// Issue references belong in a footer paragraph of their own
+		if issueFooterRegex.MatchString(trimmedLine) {
+			footerLines = append(footerLines, trimmedLine)
+			continue
+		}
+
 		// Skip comment lines and empty lines after we've found body content
-		if trimmedLine != "" && !strings.HasPrefix(trimmedLine, "#") {
+		if trimmedLine != "" && !conventional.IsCommentLine(trimmedLine, commentChar) {
 			// Ensure bullet points have proper format
 			if strings.HasPrefix(trimmedLine, "* ") {
 				trimmedLine = "- " + trimmedLine[2:]
@@ -843,15 +887,22 @@ func extractCommitMessage(response string) string {
 	}
 
 	// For significant changes, keep full body content with all bullet points
+	message := firstLine
 	if len(bodyLines) > 0 {
 		// Ensure blank line after subject
-		return firstLine + "\n\n" + strings.Join(bodyLines, "\n")
+		message += "\n\n" + strings.Join(bodyLines, "\n")
 	}
 
-	// If no body lines, return just the first line
-	return firstLine
+	if len(footerLines) > 0 {
+		message += "\n\n" + strings.Join(footerLines, "\n")
+	}
+
+	return message
 }
 
+// issueFooterRegex matches footers that close or reference an issue, e.g. "Closes #12"
+var issueFooterRegex = regexp.MustCompile(`(?i)^(closes|fixes|resolves|refs)\s+#\d+`)
+
 // formatCommitList creates a formatted string of commit messages
 func formatCommitList(commits []string) string {
 	var result strings.Builder
@@ -863,6 +914,39 @@ func formatCommitList(commits []string) string {
 	return result.String()
 }
 
+// formatCommitListWithBodies formats commit subjects followed by their
+// truncated bodies, so the model can pick up a team's body conventions
+func formatCommitListWithBodies(commits []string, bodies []string) string {
+	// Keep bodies within a fixed budget so they never crowd out the diff
+	const maxBodyChars = 400
+	const maxTotalBodyChars = 2000
+
+	var result strings.Builder
+	used := 0
+
+	for i, commit := range commits {
+		result.WriteString(fmt.Sprintf("%d. %s\n", i+1, commit))
+
+		// Stop adding bodies once there's no room left for more than the ellipsis
+		remaining := maxTotalBodyChars - used
+		if i >= len(bodies) || bodies[i] == "" || remaining <= len("...") {
+			continue
+		}
+
+		body := TruncateWithEllipsis(bodies[i], maxBodyChars)
+		if len(body) > remaining {
+			body = TruncateWithEllipsis(body, remaining)
+		}
+		used += len(body)
+
+		for _, line := range strings.Split(body, "\n") {
+			result.WriteString("   " + line + "\n")
+		}
+	}
+
+	return result.String()
+}
+
 // getUserName attempts to get the Git user name
 func getUserName() string {
 	cmd := exec.Command("git", "config", "user.name")
@@ -1261,7 +1345,7 @@ func analyzeCodeStructure(diff string) map[string]interface{} {
 			inConstBlock = true
 			inTypeBlock = false
 			continue
-		} else if strings.HasPrefix(codeLine, ")") {
+		} else if strings.HasPrefix(codeLine, ")") || strings.HasPrefix(codeLine, "}") {
 			inConstBlock = false
 			inTypeBlock = false
 			continue
@@ -0,0 +1,242 @@
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"github.com/AccursedGalaxy/noidea/internal/truncate"
+)
+
+func TestExtractCommitMessageStripsTruncationMarker(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{
+			name:     "marker in body",
+			response: "feat(auth): add token refresh\n\n- refresh tokens before expiry\n" + truncate.Marker + "\n- retry failed requests",
+			want:     "feat(auth): add token refresh\n\n- refresh tokens before expiry\n- retry failed requests",
+		},
+		{
+			name:     "marker before subject",
+			response: truncate.Marker + "\nfix: handle empty diff",
+			want:     "fix: handle empty diff",
+		},
+		{
+			name:     "marker in code block",
+			response: "```\nchore: bump deps\n\n- " + truncate.Marker + "\n```",
+			want:     "chore: bump deps",
+		},
+		{
+			name:     "legacy marker",
+			response: "fix: guard nil config\n\n... [diff truncated]\n- add nil check",
+			want:     "fix: guard nil config\n\n- add nil check",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractCommitMessage(tt.response, "#")
+			if got != tt.want {
+				t.Errorf("extractCommitMessage() = %q, want %q", got, tt.want)
+			}
+			if truncate.Contains(got) || strings.Contains(got, "truncated") {
+				t.Errorf("extractCommitMessage() leaked a truncation marker: %q", got)
+			}
+		})
+	}
+}
+
+func TestExtractCommitMessageKeepsIssueFooter(t *testing.T) {
+	response := "fix(auth): redirect after login\n\n- keep the return URL\nCloses #42"
+	want := "fix(auth): redirect after login\n\n- keep the return URL\n\nCloses #42"
+
+	if got := extractCommitMessage(response, "#"); got != want {
+		t.Errorf("extractCommitMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractCommitMessageComments(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		commentChar string
+		want        string
+	}{
+		{
+			name:     "issue reference starts a line",
+			response: "# Please enter the commit message\nfix(api): retry timed out uploads\n\n#123 was caused by the missing retry\n- retry uploads once",
+			want:     "fix(api): retry timed out uploads\n\n#123 was caused by the missing retry\n- retry uploads once",
+		},
+		{
+			name:     "markdown header survives",
+			response: "docs: explain setup\n\n## Setup\n- add install steps\n#",
+			want:     "docs: explain setup\n\n## Setup\n- add install steps",
+		},
+		{
+			name:        "custom comment char",
+			response:    "; generated message\nfeat: add export\n\n#123 asked for CSV\n- add csv writer",
+			commentChar: ";",
+			want:        "feat: add export\n\n#123 asked for CSV\n- add csv writer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractCommitMessage(tt.response, tt.commentChar); got != tt.want {
+				t.Errorf("extractCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCommitSuggestionStream(t *testing.T) {
+	chunks := []string{"feat: add", " login\n\n", "- add form"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	config := openai.DefaultConfig("test-key")
+	config.BaseURL = server.URL
+	engine := &UnifiedFeedbackEngine{
+		client:   openai.NewClientWithConfig(config),
+		model:    "test-model",
+		provider: ProviderOpenAI,
+	}
+
+	var out strings.Builder
+	suggestion, err := engine.GenerateCommitSuggestionStream(CommitContext{Diff: "diff --git a/x b/x"}, &out)
+	if err != nil {
+		t.Fatalf("GenerateCommitSuggestionStream() error = %v", err)
+	}
+
+	if got := out.String(); got != strings.Join(chunks, "") {
+		t.Errorf("streamed %q, want the chunks in order", got)
+	}
+	if suggestion != "feat: add login\n\n- add form" {
+		t.Errorf("suggestion = %q", suggestion)
+	}
+}
+
+func TestGenerateCommitSuggestionStreamCleansFence(t *testing.T) {
+	chunks := []string{"Here's a commit message for these changes:\n\n", "```\nfeat: add", " login\n```\n"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	config := openai.DefaultConfig("test-key")
+	config.BaseURL = server.URL
+	engine := &UnifiedFeedbackEngine{
+		client:   openai.NewClientWithConfig(config),
+		model:    "test-model",
+		provider: ProviderOpenAI,
+	}
+
+	var out strings.Builder
+	suggestion, err := engine.GenerateCommitSuggestionStream(CommitContext{Diff: "diff --git a/x b/x"}, &out)
+	if err != nil {
+		t.Fatalf("GenerateCommitSuggestionStream() error = %v", err)
+	}
+	// The raw answer is streamed, but the returned message is cleaned up
+	if out.String() != strings.Join(chunks, "") {
+		t.Errorf("streamed %q, want the raw chunks", out.String())
+	}
+	if suggestion != "feat: add login" {
+		t.Errorf("suggestion = %q, want the message without the preamble and fence", suggestion)
+	}
+}
+
+func TestGenerateCommitSuggestions(t *testing.T) {
+	var requestedN int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		requestedN = request.N
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"choices":[
+			{"index":0,"message":{"role":"assistant","content":"feat: add login"}},
+			{"index":1,"message":{"role":"assistant","content":"feat: add login"}},
+			{"index":2,"message":{"role":"assistant","content":"feat(auth): add login form"}}]}`)
+	}))
+	defer server.Close()
+
+	config := openai.DefaultConfig("test-key")
+	config.BaseURL = server.URL
+	engine := &UnifiedFeedbackEngine{
+		client:   openai.NewClientWithConfig(config),
+		model:    "test-model",
+		provider: ProviderOpenAI,
+	}
+
+	suggestions, err := CommitSuggestions(engine, CommitContext{Diff: "diff --git a/x b/x"}, 3)
+	if err != nil {
+		t.Fatalf("CommitSuggestions() error = %v", err)
+	}
+	if requestedN != 3 {
+		t.Errorf("request N = %d, want 3", requestedN)
+	}
+	want := []string{"feat: add login", "feat(auth): add login form"}
+	if strings.Join(suggestions, "|") != strings.Join(want, "|") {
+		t.Errorf("suggestions = %q, want %q without duplicates", suggestions, want)
+	}
+}
+
+func TestUnifiedEngineConcurrentReuse(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"choices":[{"index":0,"message":{"role":"assistant","content":"Nice commit."}}]}`)
+	}))
+	defer server.Close()
+
+	engine := NewUnifiedFeedbackEngine("openai", "test-model", "test-key", "", filepath.Join(t.TempDir(), "missing.json"))
+	config := openai.DefaultConfig("test-key")
+	config.BaseURL = server.URL
+	config.HTTPClient = sharedHTTPClient
+	engine.client = openai.NewClientWithConfig(config)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := engine.GenerateFeedback(CommitContext{Message: "fix: typo"}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("GenerateFeedback() error = %v", err)
+	}
+	if got := requests.Load(); got != 8 {
+		t.Errorf("server saw %d requests, want 8", got)
+	}
+	if engine.selectedPersonality().Name == "" {
+		t.Error("the default personality should be used when the file is missing")
+	}
+}
@@ -0,0 +1,83 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Request types reported in Usage
+const (
+	RequestFeedback   = "feedback"
+	RequestSummary    = "summary"
+	RequestSuggestion = "suggestion"
+)
+
+// Usage describes one finished request to a provider
+type Usage struct {
+	Request          string // RequestFeedback, RequestSummary or RequestSuggestion
+	Provider         string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	Err              error
+}
+
+// OnRequest, when set, is called after every provider request with its token
+// usage or error. Callers set it to collect local telemetry.
+var OnRequest func(Usage)
+
+// report passes a finished request to OnRequest
+func (e *UnifiedFeedbackEngine) report(request string, usage openai.Usage, err error) {
+	if OnRequest == nil {
+		return
+	}
+	OnRequest(Usage{
+		Request:          request,
+		Provider:         e.provider.Name,
+		Model:            e.model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		Err:              err,
+	})
+}
+
+// ErrorKind classifies a provider error without its message, e.g. "auth",
+// "rate_limit" or "network"
+func ErrorKind(err error) string {
+	status := 0
+	var apiErr *openai.APIError
+	var requestErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.HTTPStatusCode
+	case errors.As(err, &requestErr):
+		status = requestErr.HTTPStatusCode
+	}
+
+	var netErr net.Error
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return "auth"
+	case status == http.StatusTooManyRequests:
+		return "rate_limit"
+	case status >= http.StatusInternalServerError:
+		return "server"
+	case status >= http.StatusBadRequest && strings.Contains(strings.ToLower(err.Error()), "context"):
+		return "context_length"
+	case status >= http.StatusBadRequest:
+		return "bad_request"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return "network"
+	case strings.Contains(err.Error(), "no response"):
+		return "empty_response"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,55 @@
+package git
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+)
+
+// ComputeChangeID returns a Gerrit Change-Id for a commit message. Like
+// Gerrit's commit-msg hook, it hashes a commit object built from the staged
+// tree, the parent commit, the author and committer identities and the message.
+func ComputeChangeID(message string) (string, error) {
+	tree, err := exec.Command("git", "write-tree").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to write the staged tree: %w", err)
+	}
+
+	var input strings.Builder
+	fmt.Fprintf(&input, "tree %s\n", strings.TrimSpace(string(tree)))
+
+	// The first commit of a repository has no parent
+	if parent, err := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD^0").Output(); err == nil {
+		fmt.Fprintf(&input, "parent %s\n", strings.TrimSpace(string(parent)))
+	}
+
+	for _, role := range []string{"author", "committer"} {
+		ident, err := exec.Command("git", "var", "GIT_"+strings.ToUpper(role)+"_IDENT").Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine the %s identity: %w", role, err)
+		}
+		fmt.Fprintf(&input, "%s %s\n", role, strings.TrimSpace(string(ident)))
+	}
+
+	input.WriteString("\n")
+	input.WriteString(cleanMessage(message, CommentChar()))
+
+	return changeID(input.String()), nil
+}
+
+// changeID hashes input the way 'git hash-object -t commit' does and formats
+// the result as a Change-Id
+func changeID(input string) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("commit %d\x00%s", len(input), input)))
+	return fmt.Sprintf("I%x", sum)
+}
+
+// cleanMessage drops comment lines and surrounding whitespace, as git does
+// before Gerrit hashes a message. Comments start with commentChar and are
+// recognized the same way as everywhere else noidea reads messages.
+func cleanMessage(message, commentChar string) string {
+	return strings.TrimSpace(conventional.CleanMessage(message, commentChar)) + "\n"
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+)
+
+// DiffOptions are extra git diff options that shape the diff sent to the model
+type DiffOptions struct {
+	Algorithm        string // --diff-algorithm, empty for git's default
+	FunctionContext  bool   // --function-context
+	IgnoreWhitespace bool   // --ignore-all-space
+	ContextLines     *int   // --unified, nil for git's default
+}
+
+// DiffOptionsFromConfig returns the diff options configured for suggestions
+func DiffOptionsFromConfig(cfg config.Config) DiffOptions {
+	return DiffOptions{
+		Algorithm:        cfg.Moai.DiffAlgorithm,
+		FunctionContext:  cfg.Moai.FunctionContext,
+		IgnoreWhitespace: cfg.Moai.IgnoreWhitespace,
+		ContextLines:     &cfg.Moai.ContextLines,
+	}
+}
+
+// Args returns the options as git diff arguments; none means git's defaults
+func (o DiffOptions) Args() []string {
+	var args []string
+	if o.Algorithm != "" {
+		args = append(args, "--diff-algorithm="+o.Algorithm)
+	}
+	if o.FunctionContext {
+		args = append(args, "--function-context")
+	}
+	if o.IgnoreWhitespace {
+		args = append(args, "--ignore-all-space")
+	}
+	if o.ContextLines != nil {
+		args = append(args, fmt.Sprintf("--unified=%d", *o.ContextLines))
+	}
+	return args
+}
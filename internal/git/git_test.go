@@ -195,3 +195,252 @@ func TestInstallPrepareCommitMsgHook(t *testing.T) {
 		t.Error("Prepare-commit-msg hook does not contain expected content")
 	}
 }
+
+// commitInRepo creates a commit in the repository so it has a HEAD
+func commitInRepo(t *testing.T, repoPath, message string) {
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", message)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to commit: %v\n%s", err, output)
+	}
+}
+
+// TestWorktreeHooksDir verifies hooks resolve to the shared hooks directory in a linked worktree
+func TestWorktreeHooksDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git executable not available, skipping test")
+	}
+
+	repoPath := setupTestRepo(t)
+	defer cleanupTestRepo(repoPath)
+	commitInRepo(t, repoPath, "initial")
+
+	worktreePath := filepath.Join(repoPath, "..", filepath.Base(repoPath)+"-wt")
+	cmd := exec.Command("git", "worktree", "add", "-b", "feature", worktreePath)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, output)
+	}
+	defer os.RemoveAll(worktreePath)
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(currentDir)
+
+	if err := os.Chdir(worktreePath); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	if !IsLinkedWorktree() {
+		t.Error("IsLinkedWorktree should be true inside a linked worktree")
+	}
+
+	hooksDir, err := FindHooksDir()
+	if err != nil {
+		t.Fatalf("FindHooksDir failed: %v", err)
+	}
+
+	wantDir, _ := filepath.EvalSymlinks(filepath.Join(repoPath, ".git", "hooks"))
+	gotDir, _ := filepath.EvalSymlinks(hooksDir)
+	if gotDir != wantDir {
+		t.Errorf("FindHooksDir() = %s, want %s", hooksDir, wantDir)
+	}
+
+	// A hook installed from the worktree must be visible to the main repository
+	if err := InstallPostCommitHook(hooksDir); err != nil {
+		t.Fatalf("InstallPostCommitHook failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, ".git", "hooks", "post-commit")); err != nil {
+		t.Errorf("hook not installed in the shared hooks directory: %v", err)
+	}
+
+	branch, detached, err := CurrentBranch()
+	if err != nil || detached || branch != "feature" {
+		t.Errorf("CurrentBranch() = %q, %v, %v; want feature, false, nil", branch, detached, err)
+	}
+
+	if err := os.Chdir(repoPath); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	if IsLinkedWorktree() {
+		t.Error("IsLinkedWorktree should be false in the main working tree")
+	}
+}
+
+// TestCommitSubject verifies the subject lookup behind suggest --fixup
+func TestCommitSubject(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git executable not available, skipping test")
+	}
+
+	repoPath := setupTestRepo(t)
+	defer cleanupTestRepo(repoPath)
+	commitInRepo(t, repoPath, "feat: add parser\n\n- handle quotes")
+	commitInRepo(t, repoPath, "fix: handle empty input")
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(currentDir)
+
+	if err := os.Chdir(repoPath); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	hash, subject, err := CommitSubject("HEAD~1")
+	if err != nil {
+		t.Fatalf("CommitSubject failed: %v", err)
+	}
+	if subject != "feat: add parser" {
+		t.Errorf("CommitSubject() subject = %q, want %q", subject, "feat: add parser")
+	}
+	if !IsAncestor(hash) {
+		t.Errorf("expected %s to be an ancestor of HEAD", hash)
+	}
+
+	for _, rev := range []string{"does-not-exist", "--all", "HEAD^{tree}"} {
+		if _, _, err := CommitSubject(rev); err == nil {
+			t.Errorf("CommitSubject(%q) succeeded, want an error", rev)
+		}
+	}
+}
+
+// TestCurrentBranchDetached verifies a detached HEAD falls back to the commit hash
+func TestCurrentBranchDetached(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git executable not available, skipping test")
+	}
+
+	repoPath := setupTestRepo(t)
+	defer cleanupTestRepo(repoPath)
+	commitInRepo(t, repoPath, "initial")
+
+	cmd := exec.Command("git", "checkout", "--detach")
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git checkout --detach failed: %v\n%s", err, output)
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(currentDir)
+
+	if err := os.Chdir(repoPath); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	name, detached, err := CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+	if !detached {
+		t.Error("expected a detached HEAD")
+	}
+
+	hash, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+	if name != strings.TrimSpace(string(hash)) {
+		t.Errorf("CurrentBranch() = %q, want commit hash %q", name, strings.TrimSpace(string(hash)))
+	}
+}
+
+func TestChangeIDMatchesHashObject(t *testing.T) {
+	input := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"author NoIdea Test <test@example.com> 1700000000 +0000\n" +
+		"committer NoIdea Test <test@example.com> 1700000000 +0000\n\n" +
+		cleanMessage("feat: add login\n\n# a comment\n- add form  \n\n", "#")
+
+	cmd := exec.Command("git", "hash-object", "-t", "commit", "--stdin")
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Skipf("git hash-object unavailable: %v", err)
+	}
+
+	if got, want := changeID(input), "I"+strings.TrimSpace(string(output)); got != want {
+		t.Errorf("changeID() = %s, want %s", got, want)
+	}
+}
+
+func TestDiffOptionsArgs(t *testing.T) {
+	if args := (DiffOptions{}).Args(); len(args) != 0 {
+		t.Errorf("default options should add no arguments, got %v", args)
+	}
+
+	contextLines := 0
+	got := strings.Join(DiffOptions{Algorithm: "histogram", FunctionContext: true, IgnoreWhitespace: true, ContextLines: &contextLines}.Args(), " ")
+	want := "--diff-algorithm=histogram --function-context --ignore-all-space --unified=0"
+	if got != want {
+		t.Errorf("Args() = %q, want %q", got, want)
+	}
+}
+
+func TestCleanMessageKeepsIssueReferences(t *testing.T) {
+	message := "fix: close the session leak\n\n#123 was caused by the cache\n# Please enter the commit message\n"
+	if got, want := cleanMessage(message, "#"), "fix: close the session leak\n\n#123 was caused by the cache\n"; got != want {
+		t.Errorf("cleanMessage() = %q, want %q", got, want)
+	}
+	if got, want := cleanMessage("fix: a\n; comment\n# kept\n", ";"), "fix: a\n# kept\n"; got != want {
+		t.Errorf("cleanMessage() with ';' = %q, want %q", got, want)
+	}
+}
+
+// TestPreviewMatchesHookBackup checks that the dry-run preview describes what
+// BackupHook then does to the same hooks
+func TestPreviewMatchesHookBackup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	hooksDir := t.TempDir()
+	if err := InstallPostCommitHook(hooksDir); err != nil {
+		t.Fatal(err)
+	}
+	userHook := filepath.Join(hooksDir, "prepare-commit-msg")
+	if err := os.WriteFile(userHook, []byte("#!/bin/sh\necho mine\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		hook        string
+		wantPreview string
+		wantBackup  bool
+	}{
+		{"post-commit", "Existing noidea hook would be replaced", false},
+		{"prepare-commit-msg", "Existing hook would be backed up to: " + userHook + ".bak", true},
+	}
+	for _, tt := range tests {
+		hookPath := filepath.Join(hooksDir, tt.hook)
+
+		r, w, _ := os.Pipe()
+		stdout := os.Stdout
+		os.Stdout = w
+		PreviewHookInstall(hookPath, "#!/bin/sh\n", true)
+		w.Close()
+		os.Stdout = stdout
+		preview, _ := ioutil.ReadAll(r)
+		if !strings.Contains(string(preview), tt.wantPreview) {
+			t.Errorf("%s: preview %q does not mention %q", tt.hook, preview, tt.wantPreview)
+		}
+
+		backupPath, err := BackupHook(hookPath)
+		if err != nil {
+			t.Fatalf("%s: BackupHook: %v", tt.hook, err)
+		}
+		if got := backupPath != ""; got != tt.wantBackup {
+			t.Errorf("%s: backed up to %q, want backup %t", tt.hook, backupPath, tt.wantBackup)
+		}
+	}
+
+	backup, err := os.ReadFile(userHook + ".bak")
+	if err != nil || !strings.Contains(string(backup), "echo mine") {
+		t.Errorf("the user's hook was not backed up: %q, %v", backup, err)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "post-commit.bak")); !os.IsNotExist(err) {
+		t.Error("noidea's own hook should be replaced, not backed up")
+	}
+}
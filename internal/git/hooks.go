@@ -64,10 +64,26 @@ func InstallPostCommitHook(hooksDir string) error {
 		return fmt.Errorf("failed to create hooks directory: %w", err)
 	}
 
+	hookContent, err := PostCommitHookScript()
+	if err != nil {
+		return err
+	}
+
+	// Write the hook file
+	if err := os.WriteFile(postCommitPath, []byte(hookContent), 0755); err != nil {
+		return fmt.Errorf("failed to write post-commit hook: %w", err)
+	}
+
+	fmt.Println("Installed post-commit hook at:", postCommitPath)
+	return nil
+}
+
+// PostCommitHookScript returns the contents of the post-commit hook script
+func PostCommitHookScript() (string, error) {
 	// Get the absolute path to the noidea executable
 	execPath, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("failed to get executable path: %w", err)
+		return "", fmt.Errorf("failed to get executable path: %w", err)
 	}
 
 	// Load configuration
@@ -103,13 +119,7 @@ COMMIT_MSG=$(git log -1 --pretty=%%B)
 exit 0
 `, execPath, flags)
 
-	// Write the hook file
-	if err := os.WriteFile(postCommitPath, []byte(hookContent), 0755); err != nil {
-		return fmt.Errorf("failed to write post-commit hook: %w", err)
-	}
-
-	fmt.Println("Installed post-commit hook at:", postCommitPath)
-	return nil
+	return hookContent, nil
 }
 
 // InstallPrepareCommitMsgHook installs the prepare-commit-msg hook for commit message suggestions.
@@ -123,10 +133,26 @@ func InstallPrepareCommitMsgHook(hooksDir string) error {
 		return fmt.Errorf("failed to create hooks directory: %w", err)
 	}
 
+	hookContent, err := PrepareCommitMsgHookScript()
+	if err != nil {
+		return err
+	}
+
+	// Write the hook file
+	if err := os.WriteFile(hookPath, []byte(hookContent), 0755); err != nil {
+		return fmt.Errorf("failed to write prepare-commit-msg hook: %w", err)
+	}
+
+	fmt.Println("Installed prepare-commit-msg hook at:", hookPath)
+	return nil
+}
+
+// PrepareCommitMsgHookScript returns the contents of the prepare-commit-msg hook script
+func PrepareCommitMsgHookScript() (string, error) {
 	// Get the absolute path to the noidea executable
 	execPath, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("failed to get executable path: %w", err)
+		return "", fmt.Errorf("failed to get executable path: %w", err)
 	}
 
 	// Create the hook content
@@ -195,11 +221,55 @@ echo "${CYAN}🧠 Generating commit message suggestion...${RESET}"
 exit 0
 `, execPath)
 
-	// Write the hook file
-	if err := os.WriteFile(hookPath, []byte(hookContent), 0755); err != nil {
-		return fmt.Errorf("failed to write prepare-commit-msg hook: %w", err)
+	return hookContent, nil
+}
+
+// IsNoideaHook reports whether the hook at hookPath was installed by noidea
+func IsNoideaHook(hookPath string) bool {
+	content, err := os.ReadFile(hookPath)
+	if err != nil {
+		return false
 	}
+	return strings.Contains(string(content), "# noidea -")
+}
 
-	fmt.Println("Installed prepare-commit-msg hook at:", hookPath)
-	return nil
+// BackupHook moves an existing hook that noidea didn't install aside to
+// hookPath.bak and returns the backup path. noidea's own hooks are left to be
+// replaced, so re-running init never clobbers the backup of the user's hook.
+// An empty path means nothing was moved.
+func BackupHook(hookPath string) (string, error) {
+	if _, err := os.Stat(hookPath); err != nil || IsNoideaHook(hookPath) {
+		return "", nil
+	}
+	backupPath := hookPath + ".bak"
+	if err := os.Rename(hookPath, backupPath); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", hookPath, err)
+	}
+	return backupPath, nil
+}
+
+// PreviewHookInstall prints what installing a hook would do without touching
+// the filesystem. backupExisting reports whether an existing hook would be
+// moved aside by BackupHook rather than overwritten.
+func PreviewHookInstall(hookPath, content string, backupExisting bool) {
+	fmt.Println("Would write hook:", hookPath)
+
+	if _, err := os.Stat(hookPath); err == nil {
+		switch {
+		case IsNoideaHook(hookPath):
+			fmt.Println("  Existing noidea hook would be replaced")
+		case backupExisting:
+			fmt.Println("  Existing hook would be backed up to:", hookPath+".bak")
+		default:
+			fmt.Println("  Existing hook would be overwritten")
+		}
+	} else {
+		fmt.Println("  No existing hook, a new file would be created")
+	}
+
+	fmt.Println("  Script contents:")
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		fmt.Println("  |", line)
+	}
+	fmt.Println()
 }
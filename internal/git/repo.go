@@ -0,0 +1,152 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// FindHooksDir returns the directory Git runs hooks from. In a linked worktree
+// .git is a file and the worktree's git dir has no hooks of its own, so hooks
+// live in the main repository; core.hooksPath is honored as well.
+func FindHooksDir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	hooksDir := strings.TrimSpace(string(output))
+	if hooksDir == "" {
+		return "", fmt.Errorf("unable to determine hooks directory")
+	}
+
+	// --git-path is relative to the current directory unless it is outside it
+	if !filepath.IsAbs(hooksDir) {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		hooksDir = filepath.Join(workDir, hooksDir)
+	}
+
+	return filepath.Clean(hooksDir), nil
+}
+
+// CommonDir returns the absolute path of the repository's common git
+// directory, which all linked worktrees of a repository share
+func CommonDir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	commonDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(commonDir) {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		commonDir = filepath.Join(workDir, commonDir)
+	}
+
+	return filepath.Clean(commonDir), nil
+}
+
+// IsLinkedWorktree reports whether the current directory is inside a linked
+// worktree created with 'git worktree add'
+func IsLinkedWorktree() bool {
+	output, err := exec.Command("git", "rev-parse", "--absolute-git-dir", "--git-common-dir").Output()
+	if err != nil {
+		return false
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 2 {
+		return false
+	}
+
+	commonDir := lines[1]
+	if !filepath.IsAbs(commonDir) {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return false
+		}
+		commonDir = filepath.Join(workDir, commonDir)
+	}
+
+	return filepath.Clean(lines[0]) != filepath.Clean(commonDir)
+}
+
+// RepoName returns the directory name of the current repository's top level
+func RepoName() string {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(strings.TrimSpace(string(output)))
+}
+
+// CurrentBranch returns the name of the checked-out branch. With a detached
+// HEAD there is no branch, so the short commit hash is returned instead and
+// detached is true.
+func CurrentBranch() (name string, detached bool, err error) {
+	output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err == nil {
+		return strings.TrimSpace(string(output)), false, nil
+	}
+
+	output, err = exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), true, nil
+}
+
+// CommitSubject resolves rev to a commit and returns its full hash and subject
+// line. It fails if rev doesn't name a commit.
+func CommitSubject(rev string) (hash, subject string, err error) {
+	if strings.HasPrefix(rev, "-") {
+		return "", "", fmt.Errorf("not a commit: %s", rev)
+	}
+
+	output, err := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("not a commit: %s", rev)
+	}
+	hash = strings.TrimSpace(string(output))
+
+	output, err = exec.Command("git", "log", "-1", "--format=%s", hash).Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read commit %s: %w", rev, err)
+	}
+
+	return hash, strings.TrimSpace(string(output)), nil
+}
+
+// HasCommits reports whether the current repository has a commit yet; it
+// doesn't before the first one is made
+func HasCommits() bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD").Run() == nil
+}
+
+// IsAncestor reports whether commit is reachable from HEAD
+func IsAncestor(commit string) bool {
+	return exec.Command("git", "merge-base", "--is-ancestor", commit, "HEAD").Run() == nil
+}
+
+// CommentChar returns the character git starts commit message comments
+// with: core.commentChar, or "#" when it is unset or "auto"
+func CommentChar() string {
+	output, err := exec.Command("git", "config", "--get", "core.commentChar").Output()
+	if err != nil {
+		return "#"
+	}
+	char := strings.TrimSpace(string(output))
+	if char == "" || char == "auto" {
+		return "#"
+	}
+	return char
+}
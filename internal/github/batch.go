@@ -0,0 +1,149 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/AccursedGalaxy/noidea/internal/interrupt"
+	"github.com/AccursedGalaxy/noidea/internal/releaseai"
+)
+
+// Batch outcomes for a single tag
+const (
+	BatchGenerated = "generated" // Notes were generated and published
+	BatchExisting  = "existing"  // An existing release was kept instead of regenerating
+	BatchSkipped   = "skipped"   // The tag was not processed
+	BatchFailed    = "failed"    // Generating or publishing the notes failed
+)
+
+// BatchResult records what happened to one tag in a batch run
+type BatchResult struct {
+	Tag             string
+	Status          string
+	Reason          string
+	EstimatedTokens int
+}
+
+// BatchReport summarizes a batch release-notes run
+type BatchReport struct {
+	Results []BatchResult
+	Model   string // Model the notes were generated with, for the cost estimate
+}
+
+// Count returns how many tags ended with the given status
+func (r BatchReport) Count(status string) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// EstimatedTokens returns the total estimated AI tokens used across the run
+func (r BatchReport) EstimatedTokens() int {
+	total := 0
+	for _, result := range r.Results {
+		total += result.EstimatedTokens
+	}
+	return total
+}
+
+// Format renders the report shown at the end of a batch run
+func (r BatchReport) Format() string {
+	var b strings.Builder
+
+	b.WriteString("==== Release Notes Summary ====\n")
+	b.WriteString(fmt.Sprintf("Tags processed: %d\n", len(r.Results)))
+	b.WriteString(fmt.Sprintf("Generated:      %d\n", r.Count(BatchGenerated)))
+	b.WriteString(fmt.Sprintf("Kept existing:  %d\n", r.Count(BatchExisting)))
+	b.WriteString(fmt.Sprintf("Skipped:        %d\n", r.Count(BatchSkipped)))
+	b.WriteString(fmt.Sprintf("Failed:         %d\n", r.Count(BatchFailed)))
+	b.WriteString(fmt.Sprintf("AI tokens:      ~%d (estimated)\n", r.EstimatedTokens()))
+	if cost, ok := releaseai.EstimateCost(r.Model, r.EstimatedTokens()); ok {
+		b.WriteString(fmt.Sprintf("AI cost:        ~$%.2f (estimated from %s list prices)\n", cost, r.Model))
+	} else if r.Model == "" {
+		b.WriteString("AI cost:        unavailable, no model configured\n")
+	} else {
+		b.WriteString(fmt.Sprintf("AI cost:        unavailable for model %q\n", r.Model))
+	}
+
+	var problems []string
+	for _, result := range r.Results {
+		if result.Status == BatchSkipped || result.Status == BatchFailed || result.Status == BatchExisting {
+			problems = append(problems, fmt.Sprintf("  %s %s: %s", result.Tag, result.Status, result.Reason))
+		}
+	}
+	if len(problems) > 0 {
+		b.WriteString("\nNot generated:\n")
+		b.WriteString(strings.Join(problems, "\n"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// UpdateReleaseNotesBatch generates release notes for each tag in turn and
+// reports what happened to every tag. Tags that already have release notes
+// are kept when skipExisting is set.
+func (m *ReleaseManager) UpdateReleaseNotesBatch(tags []string, skipApproval, skipExisting, waitForWorkflows bool, maxWaitSeconds int) BatchReport {
+	report := BatchReport{Model: m.config.LLM.Model}
+
+	for i, tag := range tags {
+		result := BatchResult{Tag: tag}
+
+		switch {
+		case interrupt.Interrupted():
+			result.Status = BatchSkipped
+			result.Reason = "interrupted"
+
+		case !tagExists(tag):
+			result.Status = BatchSkipped
+			result.Reason = "tag not found"
+
+		case skipExisting && m.hasReleaseNotes(tag):
+			result.Status = BatchExisting
+			result.Reason = "release already has notes"
+
+		default:
+			fmt.Printf("\n[%d/%d] %s\n", i+1, len(tags), tag)
+
+			before := m.estimatedTokens
+			err := m.UpdateReleaseNotesWithWorkflowCheck(tag, skipApproval, waitForWorkflows, maxWaitSeconds)
+			result.EstimatedTokens = m.estimatedTokens - before
+
+			switch {
+			case err == nil:
+				result.Status = BatchGenerated
+			case errors.Is(err, ErrReleaseNotesCancelled):
+				result.Status = BatchSkipped
+				result.Reason = "not approved"
+			default:
+				result.Status = BatchFailed
+				result.Reason = err.Error()
+			}
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// hasReleaseNotes reports whether a release for the tag exists with a non-empty body
+func (m *ReleaseManager) hasReleaseNotes(tag string) bool {
+	release, err := m.provider.GetRelease(tag)
+	if err != nil || release == nil {
+		return false
+	}
+
+	return strings.TrimSpace(release.Body) != ""
+}
+
+// tagExists reports whether the tag exists in the local repository
+func tagExists(tag string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+tag).Run() == nil
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+)
+
+func TestBatchReportFormat(t *testing.T) {
+	report := BatchReport{Results: []BatchResult{
+		{Tag: "v1.0.0", Status: BatchGenerated, EstimatedTokens: 1200},
+		{Tag: "v1.1.0", Status: BatchGenerated, EstimatedTokens: 800},
+		{Tag: "v1.2.0", Status: BatchExisting, Reason: "release already has notes"},
+		{Tag: "v9.9.9", Status: BatchSkipped, Reason: "tag not found"},
+	}, Model: "gpt-4o-mini"}
+
+	if got := report.Count(BatchGenerated); got != 2 {
+		t.Errorf("Count(generated) = %d, want 2", got)
+	}
+	if got := report.EstimatedTokens(); got != 2000 {
+		t.Errorf("EstimatedTokens() = %d, want 2000", got)
+	}
+
+	output := report.Format()
+	for _, want := range []string{
+		"Tags processed: 4",
+		"Generated:      2",
+		"~2000",
+		"AI cost:        ~$0.00 (estimated from gpt-4o-mini list prices)",
+		"v9.9.9 skipped: tag not found",
+		"v1.2.0 existing: release already has notes",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "v1.0.0") {
+		t.Errorf("generated tags should not be listed as not generated:\n%s", output)
+	}
+
+	report.Model = "my-local-model"
+	if output := report.Format(); !strings.Contains(output, `AI cost:        unavailable for model "my-local-model"`) {
+		t.Errorf("an unpriced model should say the cost is unavailable:\n%s", output)
+	}
+}
+
+// fakeProvider is an in-memory ReleaseProvider keyed by tag
+type fakeProvider map[string]string
+
+func (f fakeProvider) Name() string { return "fake" }
+
+func (f fakeProvider) GetRelease(tag string) (*Release, error) {
+	body, ok := f[tag]
+	if !ok {
+		return nil, nil
+	}
+	return &Release{ID: tag, Tag: tag, Body: body}, nil
+}
+
+func (f fakeProvider) CreateRelease(tag, name, body string, prerelease bool) error {
+	f[tag] = body
+	return nil
+}
+
+func (f fakeProvider) UpdateRelease(release *Release, body string) error {
+	f[release.ID] = body
+	return nil
+}
+
+func (f fakeProvider) ListCommits(fromTag, toTag string) ([]string, error) {
+	return nil, nil
+}
+
+func TestHasReleaseNotesUsesProvider(t *testing.T) {
+	provider := fakeProvider{"v1.0.0": "## Notes", "v1.1.0": "  \n"}
+	manager := NewReleaseManagerWithProvider(config.DefaultConfig(), provider)
+
+	if !manager.hasReleaseNotes("v1.0.0") {
+		t.Error("v1.0.0 has notes")
+	}
+	if manager.hasReleaseNotes("v1.1.0") {
+		t.Error("a blank body should not count as notes")
+	}
+	if manager.hasReleaseNotes("v2.0.0") {
+		t.Error("a missing release should not count as notes")
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os/exec"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/AccursedGalaxy/noidea/internal/interrupt"
 	"github.com/AccursedGalaxy/noidea/internal/secure"
 )
 
@@ -20,6 +22,22 @@ type Client struct {
 	httpClient *http.Client
 	baseURL    string
 	token      string
+	remote     string // Git remote of the repository, "" for origin
+}
+
+// Timeout is how long to wait for each API response, 0 for no limit. Callers
+// set it from Release.Timeout; slow proxies and VPNs need more than the default.
+var Timeout = 10 * time.Second
+
+// NewHTTPClient returns an HTTP client for hosting APIs that waits up to
+// Timeout and goes through the proxy in HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+func NewHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	return &http.Client{
+		Timeout:   Timeout,
+		Transport: transport,
+	}
 }
 
 // NewClient creates a new GitHub API client
@@ -30,22 +48,31 @@ func NewClient() (*Client, error) {
 	}
 
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		baseURL: secure.GitHubAPIURL,
-		token:   token,
+		httpClient: NewHTTPClient(),
+		baseURL:    secure.GitHubAPIURL,
+		token:      token,
 	}, nil
 }
 
+// NewRepoClient creates a GitHub API client for the repository of a git
+// remote ("origin" when empty), using the API of the remote's host: the
+// public API for github.com, or the GitHub Enterprise API of another host
+func NewRepoClient(remote string) (*Client, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	client.baseURL = RepoAPIURL(remote)
+	client.remote = remote
+	return client, nil
+}
+
 // NewClientWithoutAuth creates a new GitHub API client without authentication
 // for accessing public resources
 func NewClientWithoutAuth() *Client {
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		baseURL: "https://api.github.com",
+		httpClient: NewHTTPClient(),
+		baseURL:    "https://api.github.com",
 	}
 }
 
@@ -153,66 +180,132 @@ func (c *Client) IsAuthenticated() (bool, error) {
 	return err == nil, err
 }
 
-// ExtractRepoInfo extracts owner and repo name from a Git remote URL or the current repository
-func ExtractRepoInfo(remoteURL string) (string, string, error) {
-	// If no remote URL provided, try to get it from the current git repository
-	if remoteURL == "" {
-		var err error
-		remoteURL, err = getOriginRemoteURL()
-		if err != nil {
-			return "", "", err
-		}
+// ExtractRepoInfo extracts owner and repo name from the URL of a git remote
+// of the current repository ("origin" when empty), e.g. "upstream" when
+// origin is a fork
+func ExtractRepoInfo(remote string) (string, string, error) {
+	remoteURL, err := RemoteURL(remote)
+	if err != nil {
+		return "", "", err
 	}
 
-	// Handle SSH URLs (git@github.com:owner/repo.git)
-	sshPattern := regexp.MustCompile(`git@github\.com:([^/]+)/([^.]+)\.git`)
-	if matches := sshPattern.FindStringSubmatch(remoteURL); len(matches) == 3 {
-		return matches[1], matches[2], nil
+	_, owner, repo, err := ParseRepoURL(remoteURL)
+	return owner, repo, err
+}
+
+// scpPattern matches scp-style SSH remotes such as git@github.com:owner/repo.git
+var scpPattern = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// ParseRepoURL extracts the host, owner and repo name from a Git remote URL.
+// It accepts scp-style SSH remotes (git@github.mycorp.com:owner/repo.git),
+// ssh:// URLs with a port (ssh://git@host:2222/owner/repo.git) and HTTPS
+// URLs, with or without ".git" and a trailing slash.
+func ParseRepoURL(remoteURL string) (host, owner, repo string, err error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	var path string
+	if matches := scpPattern.FindStringSubmatch(remoteURL); len(matches) == 3 && !strings.Contains(remoteURL, "://") {
+		host, path = matches[1], matches[2]
+	} else if parsed, parseErr := url.Parse(remoteURL); parseErr == nil && parsed.Host != "" {
+		host, path = parsed.Hostname(), parsed.Path
 	}
 
-	// Handle HTTPS URLs (https://github.com/owner/repo.git)
-	httpsPattern := regexp.MustCompile(`https://github\.com/([^/]+)/([^./]+)(?:\.git)?`)
-	if matches := httpsPattern.FindStringSubmatch(remoteURL); len(matches) == 3 {
-		return matches[1], matches[2], nil
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(path, "/"), ".git"), "/")
+	if host == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("could not parse GitHub repository URL: %s", remoteURL)
 	}
 
-	return "", "", fmt.Errorf("could not parse GitHub repository URL: %s", remoteURL)
+	return strings.ToLower(host), parts[0], parts[1], nil
 }
 
-// getOriginRemoteURL gets the origin remote URL from the current git repository
-func getOriginRemoteURL() (string, error) {
-	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get git remote: %w", err)
+// APIURL returns the REST API base URL for a GitHub host: the public API for
+// github.com, or the /api/v3 path GitHub Enterprise Server serves it at
+func APIURL(host string) string {
+	switch strings.ToLower(host) {
+	case "", "github.com", "www.github.com", "ssh.github.com":
+		return secure.GitHubAPIURL
 	}
-	return strings.TrimSpace(string(output)), nil
+	return "https://" + host + "/api/v3"
 }
 
-// GetWorkflowRunsForRef gets the workflow runs triggered by a specific git ref (tag/branch)
-func (c *Client) GetWorkflowRunsForRef(owner, repo, ref string) ([]map[string]interface{}, error) {
-	url := fmt.Sprintf("/repos/%s/%s/actions/runs?event=push&branch=%s", owner, repo, ref)
-	response, err := c.get(url)
+// RepoAPIURL returns the API base URL for the host of a git remote ("origin"
+// when empty), or the public API when it can't be determined
+func RepoAPIURL(remote string) string {
+	remoteURL, err := RemoteURL(remote)
+	if err != nil {
+		return secure.GitHubAPIURL
+	}
+	host, _, _, err := ParseRepoURL(remoteURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get workflow runs: %w", err)
+		return secure.GitHubAPIURL
+	}
+	return APIURL(host)
+}
+
+// RemoteURL gets the URL of a remote ("origin" when empty) from the current
+// git repository. If there is no such remote, the error lists the ones there are.
+func RemoteURL(name string) (string, error) {
+	if name == "" {
+		name = "origin"
 	}
 
-	// Extract workflow runs from response
-	workflowRunsObj, ok := response["workflow_runs"]
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format: workflow_runs not found")
+	output, err := exec.Command("git", "config", "--get", "remote."+name+".url").Output()
+	if err == nil {
+		return strings.TrimSpace(string(output)), nil
 	}
 
-	workflowRuns, ok := workflowRunsObj.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format: workflow_runs is not an array")
+	remotes, listErr := exec.Command("git", "remote").Output()
+	if listErr != nil {
+		return "", fmt.Errorf("failed to get git remote: %w", err)
+	}
+	names := strings.Fields(string(remotes))
+	if len(names) == 0 {
+		return "", fmt.Errorf("remote '%s' not found: this repository has no remotes", name)
 	}
+	return "", fmt.Errorf("remote '%s' not found (available remotes: %s)", name, strings.Join(names, ", "))
+}
+
+// Workflow runs are listed 100 at a time, the most GitHub returns per page.
+// The page limit keeps a busy repository from being paged through forever.
+const (
+	workflowRunsPerPage = 100
+	maxWorkflowRunPages = 10
+)
+
+// GetWorkflowRunsForRef gets the workflow runs triggered by a specific git ref
+// (tag/branch), following every page of results so none are missed
+func (c *Client) GetWorkflowRunsForRef(owner, repo, ref string) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+	for page := 1; page <= maxWorkflowRunPages; page++ {
+		path := fmt.Sprintf("/repos/%s/%s/actions/runs?event=push&branch=%s&per_page=%d&page=%d",
+			owner, repo, url.QueryEscape(ref), workflowRunsPerPage, page)
+		response, err := c.get(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workflow runs: %w", err)
+		}
 
-	// Convert to maps
-	result := make([]map[string]interface{}, 0, len(workflowRuns))
-	for _, run := range workflowRuns {
-		if runMap, ok := run.(map[string]interface{}); ok {
-			result = append(result, runMap)
+		// Extract workflow runs from response
+		workflowRunsObj, ok := response["workflow_runs"]
+		if !ok {
+			return nil, fmt.Errorf("unexpected response format: workflow_runs not found")
+		}
+
+		workflowRuns, ok := workflowRunsObj.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected response format: workflow_runs is not an array")
+		}
+
+		// Convert to maps
+		for _, run := range workflowRuns {
+			if runMap, ok := run.(map[string]interface{}); ok {
+				result = append(result, runMap)
+			}
+		}
+
+		// A short page is the last one, as is reaching the reported total
+		total, _ := response["total_count"].(float64)
+		if len(workflowRuns) < workflowRunsPerPage || (total > 0 && len(result) >= int(total)) {
+			break
 		}
 	}
 
@@ -264,6 +357,10 @@ func (c *Client) WaitForWorkflowsToComplete(owner, repo, ref string, maxWaitSeco
 
 	for {
 		select {
+		case <-interrupt.Context().Done():
+			// Clear the spinner so the shell prompt starts on a clean line
+			fmt.Print("\r\033[K")
+			return interrupt.Context().Err()
 		case <-timeoutChan:
 			// Clear the current line before error message
 			fmt.Print("\r\033[K")
@@ -0,0 +1,137 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRemoteURL(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"remote", "add", "origin", "git@github.com:me/noidea.git"},
+		{"remote", "add", "upstream", "https://github.com/AccursedGalaxy/noidea.git"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if got, err := RemoteURL(""); err != nil || got != "git@github.com:me/noidea.git" {
+		t.Errorf(`RemoteURL("") = %q, %v; want the origin URL`, got, err)
+	}
+
+	owner, repo, err := ExtractRepoInfo("upstream")
+	if err != nil || owner != "AccursedGalaxy" || repo != "noidea" {
+		t.Errorf("ExtractRepoInfo() with the upstream remote = %q, %q, %v", owner, repo, err)
+	}
+
+	_, err = RemoteURL("fork")
+	if err == nil || !strings.Contains(err.Error(), "available remotes: origin, upstream") {
+		t.Errorf(`RemoteURL("fork") error = %v, want it to list the remotes`, err)
+	}
+}
+
+func TestParseRepoURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		host   string
+		owner  string
+		repo   string
+	}{
+		{"git@github.com:AccursedGalaxy/noidea.git", "github.com", "AccursedGalaxy", "noidea"},
+		{"https://github.com/AccursedGalaxy/noidea", "github.com", "AccursedGalaxy", "noidea"},
+		{"https://github.com/AccursedGalaxy/noidea.git/", "github.com", "AccursedGalaxy", "noidea"},
+		{"git@github.mycorp.com:team/service.api.git", "github.mycorp.com", "team", "service.api"},
+		{"ssh://git@github.mycorp.com:2222/team/service.git", "github.mycorp.com", "team", "service"},
+		{"https://user@GitHub.MyCorp.com/team/service/", "github.mycorp.com", "team", "service"},
+	}
+
+	for _, tt := range tests {
+		host, owner, repo, err := ParseRepoURL(tt.remote)
+		if err != nil {
+			t.Errorf("ParseRepoURL(%q) returned error: %v", tt.remote, err)
+			continue
+		}
+		if host != tt.host || owner != tt.owner || repo != tt.repo {
+			t.Errorf("ParseRepoURL(%q) = %q, %q, %q; want %q, %q, %q", tt.remote, host, owner, repo, tt.host, tt.owner, tt.repo)
+		}
+	}
+
+	for _, remote := range []string{"not a remote", "https://github.com/only-owner", "/local/path/repo.git"} {
+		if _, _, _, err := ParseRepoURL(remote); err == nil {
+			t.Errorf("ParseRepoURL(%q) should fail", remote)
+		}
+	}
+
+	if got := APIURL("github.com"); got != "https://api.github.com" {
+		t.Errorf(`APIURL("github.com") = %q`, got)
+	}
+	if got := APIURL("github.mycorp.com"); got != "https://github.mycorp.com/api/v3" {
+		t.Errorf(`APIURL("github.mycorp.com") = %q`, got)
+	}
+}
+
+func TestGetWorkflowRunsForRefPages(t *testing.T) {
+	const total = 130
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		pages = append(pages, query.Get("page"))
+		if query.Get("per_page") != "100" || query.Get("branch") != "v1.0.0" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+
+		page, _ := strconv.Atoi(query.Get("page"))
+		var runs []map[string]interface{}
+		for i := (page - 1) * 100; i < total && i < page*100; i++ {
+			runs = append(runs, map[string]interface{}{"id": i, "status": "queued"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total_count": total, "workflow_runs": runs})
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL}
+	runs, err := client.GetWorkflowRunsForRef("owner", "repo", "v1.0.0")
+	if err != nil {
+		t.Fatalf("GetWorkflowRunsForRef() returned error: %v", err)
+	}
+	if len(runs) != total {
+		t.Errorf("GetWorkflowRunsForRef() returned %d runs, want %d", len(runs), total)
+	}
+	if strings.Join(pages, ",") != "1,2" {
+		t.Errorf("requested pages %v, want 1 and 2", pages)
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	defer func(previous time.Duration) { Timeout = previous }(Timeout)
+	Timeout = 45 * time.Second
+
+	client := NewHTTPClient()
+	if client.Timeout != 45*time.Second {
+		t.Errorf("NewHTTPClient().Timeout = %v, want 45s", client.Timeout)
+	}
+
+	if transport, ok := client.Transport.(*http.Transport); !ok || transport.Proxy == nil {
+		t.Error("NewHTTPClient() doesn't use a proxy-aware transport")
+	}
+}
@@ -11,28 +11,48 @@ import (
 
 // InstallPostTagHook installs a Git hook that runs after tags are created
 // to create GitHub releases automatically with enhanced release notes
-func InstallPostTagHook() error {
-	// Find the git directory
-	gitDir, err := git.FindGitDir()
+//
+// With dryRun set, the target path and script are printed instead of written.
+func InstallPostTagHook(dryRun bool) error {
+	// Find the hooks directory (shared by all worktrees)
+	hooksDir, err := git.FindHooksDir()
 	if err != nil {
 		return fmt.Errorf("failed to find git directory: %w", err)
 	}
 
-	// Path to hooks directory
-	hooksDir := filepath.Join(gitDir, "hooks")
+	// Path to post-tag hook
+	hookPath := filepath.Join(hooksDir, "post-tag")
+
+	hookContent, err := PostTagHookScript()
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		git.PreviewHookInstall(hookPath, hookContent, false)
+		return nil
+	}
 
 	// Ensure the hooks directory exists
 	if err := os.MkdirAll(hooksDir, 0755); err != nil {
 		return fmt.Errorf("failed to create hooks directory: %w", err)
 	}
 
-	// Path to post-tag hook
-	hookPath := filepath.Join(hooksDir, "post-tag")
+	// Write the hook file
+	if err := os.WriteFile(hookPath, []byte(hookContent), 0755); err != nil {
+		return fmt.Errorf("failed to write post-tag hook: %w", err)
+	}
+
+	fmt.Println("Installed post-tag hook at:", hookPath)
+	return nil
+}
 
+// PostTagHookScript returns the contents of the post-tag hook script
+func PostTagHookScript() (string, error) {
 	// Get the absolute path to the noidea executable
 	execPath, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("failed to get executable path: %w", err)
+		return "", fmt.Errorf("failed to get executable path: %w", err)
 	}
 
 	// Load configuration
@@ -74,11 +94,5 @@ echo "============================================================="
 exit 0
 `, execPath, flags)
 
-	// Write the hook file
-	if err := os.WriteFile(hookPath, []byte(hookContent), 0755); err != nil {
-		return fmt.Errorf("failed to write post-tag hook: %w", err)
-	}
-
-	fmt.Println("Installed post-tag hook at:", hookPath)
-	return nil
+	return hookContent, nil
 }
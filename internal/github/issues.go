@@ -0,0 +1,119 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// issueCacheTTL is how long fetched issue titles are reused before refreshing
+const issueCacheTTL = 10 * time.Minute
+
+// IssueRef is an open issue or pull request title used as suggestion context
+type IssueRef struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	PullRequest bool   `json:"pull_request"`
+}
+
+// String formats the reference as "#12 Fix login redirect (pull request)"
+func (i IssueRef) String() string {
+	kind := "issue"
+	if i.PullRequest {
+		kind = "pull request"
+	}
+	return fmt.Sprintf("#%d %s (%s)", i.Number, i.Title, kind)
+}
+
+// issueCache is the on-disk cache of recently fetched issue titles
+type issueCache struct {
+	FetchedAt time.Time  `json:"fetched_at"`
+	Issues    []IssueRef `json:"issues"`
+}
+
+// ListOpenIssues returns the most recently updated open issues and pull requests
+func (c *Client) ListOpenIssues(owner, repo string, limit int) ([]IssueRef, error) {
+	query := url.QueryEscape(fmt.Sprintf("repo:%s/%s is:open", owner, repo))
+	response, err := c.get(fmt.Sprintf("/search/issues?q=%s&sort=updated&order=desc&per_page=%d", query, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open issues: %w", err)
+	}
+
+	items, ok := response["items"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response format: items not found")
+	}
+
+	issues := make([]IssueRef, 0, len(items))
+	for _, item := range items {
+		issue, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		number, _ := issue["number"].(float64)
+		title, _ := issue["title"].(string)
+		_, isPR := issue["pull_request"]
+		issues = append(issues, IssueRef{Number: int(number), Title: title, PullRequest: isPR})
+	}
+
+	return issues, nil
+}
+
+// RecentIssues returns open issue and pull request titles for the current
+// repository, reusing a short-lived cache so suggestions stay fast
+func RecentIssues(limit int) ([]IssueRef, error) {
+	owner, repo, err := ExtractRepoInfo("")
+	if err != nil {
+		return nil, err
+	}
+
+	cachePath := issueCachePath(owner, repo)
+	if cachePath != "" {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			var cached issueCache
+			if json.Unmarshal(data, &cached) == nil && time.Since(cached.FetchedAt) < issueCacheTTL {
+				return limitIssues(cached.Issues, limit), nil
+			}
+		}
+	}
+
+	client, err := NewRepoClient("")
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := client.ListOpenIssues(owner, repo, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if cachePath != "" {
+		if data, err := json.Marshal(issueCache{FetchedAt: time.Now(), Issues: issues}); err == nil {
+			if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+				_ = os.WriteFile(cachePath, data, 0600)
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// issueCachePath returns the cache file for a repository's issues
+func issueCachePath(owner, repo string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".noidea", "cache", fmt.Sprintf("issues_%s_%s.json", owner, repo))
+}
+
+// limitIssues returns at most limit issues
+func limitIssues(issues []IssueRef, limit int) []IssueRef {
+	if limit > 0 && len(issues) > limit {
+		return issues[:limit]
+	}
+	return issues
+}
@@ -0,0 +1,15 @@
+package github
+
+import "testing"
+
+func TestIssueRefString(t *testing.T) {
+	issue := IssueRef{Number: 12, Title: "Fix login redirect"}
+	if got := issue.String(); got != "#12 Fix login redirect (issue)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	pr := IssueRef{Number: 7, Title: "Add dark mode", PullRequest: true}
+	if got := pr.String(); got != "#7 Add dark mode (pull request)" {
+		t.Errorf("String() = %q", got)
+	}
+}
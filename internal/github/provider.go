@@ -0,0 +1,137 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Release is a published release as seen by a ReleaseProvider
+type Release struct {
+	ID   string // Provider-specific identifier used for updates
+	Tag  string
+	Body string
+}
+
+// ReleaseProvider is a hosting service release notes can be published to.
+// ReleaseManager generates the notes and leaves the API calls to a provider.
+type ReleaseProvider interface {
+	// Name is the service's display name, e.g. "GitHub"
+	Name() string
+
+	// GetRelease returns the release for a tag, or nil if there is none
+	GetRelease(tag string) (*Release, error)
+
+	// CreateRelease publishes a new release for an existing tag
+	CreateRelease(tag, name, body string, prerelease bool) error
+
+	// UpdateRelease replaces the notes of an existing release
+	UpdateRelease(release *Release, body string) error
+
+	// ListCommits returns "<short hash> <subject>" lines for the commits
+	// between two tags, newest first
+	ListCommits(fromTag, toTag string) ([]string, error)
+}
+
+// githubReleases publishes releases through the GitHub REST API for the
+// repository of the client's git remote
+type githubReleases struct {
+	client *Client
+}
+
+// NewGitHubReleaseProvider returns the GitHub release provider
+func NewGitHubReleaseProvider(client *Client) ReleaseProvider {
+	return githubReleases{client: client}
+}
+
+func (g githubReleases) Name() string {
+	return "GitHub"
+}
+
+// repoPath returns "/repos/<owner>/<repo>" for the client's repository
+func (g githubReleases) repoPath() (string, error) {
+	owner, repo, err := ExtractRepoInfo(g.client.remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine repository info: %w", err)
+	}
+	return fmt.Sprintf("/repos/%s/%s", owner, repo), nil
+}
+
+func (g githubReleases) GetRelease(tag string) (*Release, error) {
+	repoPath, err := g.repoPath()
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := g.client.get(fmt.Sprintf("%s/releases/tags/%s", repoPath, url.PathEscape(tag)))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	id, _ := release["id"].(float64)
+	body, _ := release["body"].(string)
+	return &Release{ID: fmt.Sprintf("%d", int64(id)), Tag: tag, Body: body}, nil
+}
+
+func (g githubReleases) CreateRelease(tag, name, body string, prerelease bool) error {
+	repoPath, err := g.repoPath()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"tag_name":   tag,
+		"name":       name,
+		"body":       body,
+		"draft":      false,
+		"prerelease": prerelease,
+	}
+	_, err = g.client.post(repoPath+"/releases", payload)
+	return err
+}
+
+func (g githubReleases) UpdateRelease(release *Release, body string) error {
+	repoPath, err := g.repoPath()
+	if err != nil {
+		return err
+	}
+
+	_, err = g.client.patch(fmt.Sprintf("%s/releases/%s", repoPath, release.ID), map[string]interface{}{"body": body})
+	return err
+}
+
+func (g githubReleases) ListCommits(fromTag, toTag string) ([]string, error) {
+	repoPath, err := g.repoPath()
+	if err != nil {
+		return nil, err
+	}
+
+	comparison, err := g.client.get(fmt.Sprintf("%s/compare/%s...%s", repoPath, url.PathEscape(fromTag), url.PathEscape(toTag)))
+	if err != nil {
+		return nil, err
+	}
+
+	commits, _ := comparison["commits"].([]interface{})
+	messages := make([]string, 0, len(commits))
+	// The compare API lists commits oldest first
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit, _ := commits[i].(map[string]interface{})
+		sha, _ := commit["sha"].(string)
+		details, _ := commit["commit"].(map[string]interface{})
+		message, _ := details["message"].(string)
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		subject, _, _ := strings.Cut(message, "\n")
+		messages = append(messages, sha+" "+subject)
+	}
+	return messages, nil
+}
+
+// isNotFound reports whether a client error is GitHub's 404 response
+func isNotFound(err error) bool {
+	return strings.Contains(err.Error(), "(status code: 404)")
+}
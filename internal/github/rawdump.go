@@ -0,0 +1,45 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// unsafeFileNameRegex matches characters that don't belong in a file name,
+// such as the slash in release/v1.2
+var unsafeFileNameRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// rawResponseSeparator separates several raw responses in one file
+const rawResponseSeparator = "\n\n<!-- noidea: next response -->\n\n"
+
+// debugRawPaths returns the files the raw AI response and the cleaned notes
+// for a tag are saved to with --debug-raw
+func debugRawPaths(tagName string) (rawPath, notesPath string) {
+	base := "release-notes-" + unsafeFileNameRegex.ReplaceAllString(tagName, "-")
+	return base + ".raw.md", base + ".md"
+}
+
+// saveRawResponses writes the unprocessed AI responses for a tag next to the
+// cleaned notes, so heavy cleanup or a fallback to basic notes can be traced
+// back to what the model actually returned
+func (m *ReleaseManager) saveRawResponses(tagName, releaseNotes string) {
+	rawPath, notesPath := debugRawPaths(tagName)
+
+	if len(m.rawResponses) == 0 {
+		fmt.Printf("Warning: No AI response was received for %s; %s will be empty\n", tagName, rawPath)
+	}
+
+	raw := strings.Join(m.rawResponses, rawResponseSeparator)
+	if err := os.WriteFile(rawPath, []byte(raw), 0644); err != nil {
+		fmt.Printf("Warning: Could not save raw AI response: %s\n", err)
+		return
+	}
+	if err := os.WriteFile(notesPath, []byte(releaseNotes), 0644); err != nil {
+		fmt.Printf("Warning: Could not save cleaned release notes: %s\n", err)
+		return
+	}
+
+	fmt.Printf("Saved the raw AI response to %s and the cleaned notes to %s\n", rawPath, notesPath)
+}
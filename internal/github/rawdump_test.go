@@ -0,0 +1,39 @@
+package github
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSaveRawResponses(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	m := &ReleaseManager{rawResponses: []string{"I am an AI.\n\n## Features\n- Added X", "second"}}
+	m.saveRawResponses("release/v1.2.0", "## Features\n- Added X")
+
+	rawPath, notesPath := debugRawPaths("release/v1.2.0")
+	if rawPath != "release-notes-release-v1.2.0.raw.md" || notesPath != "release-notes-release-v1.2.0.md" {
+		t.Fatalf("debugRawPaths() = %q, %q", rawPath, notesPath)
+	}
+
+	raw, err := os.ReadFile(rawPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(raw), "I am an AI.") || !strings.HasSuffix(string(raw), "second") {
+		t.Errorf("raw file = %q, want both unprocessed responses", raw)
+	}
+
+	notes, err := os.ReadFile(notesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(notes) != "## Features\n- Added X" {
+		t.Errorf("notes file = %q", notes)
+	}
+}
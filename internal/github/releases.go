@@ -1,56 +1,78 @@
 package github
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
 	"github.com/AccursedGalaxy/noidea/internal/releaseai"
+	"github.com/AccursedGalaxy/noidea/internal/truncate"
+	"github.com/AccursedGalaxy/noidea/internal/ui"
 )
 
-// ReleaseManager handles GitHub release operations
+// ReleaseManager generates release notes and publishes them through a ReleaseProvider
 type ReleaseManager struct {
-	client *Client
-	config config.Config
+	client   *Client // GitHub client for workflow checks, nil for other providers
+	provider ReleaseProvider
+	config   config.Config
+
+	// DryRun prints the final release notes instead of writing them to GitHub
+	DryRun bool
+
+	// DebugRaw saves the unprocessed AI response next to the cleaned notes
+	DebugRaw bool
+
+	// estimatedTokens accumulates the estimated AI tokens used by this manager
+	estimatedTokens int
+
+	// rawResponses holds the unprocessed AI responses for the current tag
+	rawResponses []string
 }
 
-// NewReleaseManager creates a new release manager
-func NewReleaseManager(config config.Config) (*ReleaseManager, error) {
-	client, err := NewClient()
+// ErrReleaseNotesCancelled is returned when the user declines the generated notes
+var ErrReleaseNotesCancelled = errors.New("release notes update cancelled by user")
+
+// NewReleaseManager creates a new release manager that publishes to the
+// GitHub repository of a git remote ("origin" when empty)
+func NewReleaseManager(config config.Config, remote string) (*ReleaseManager, error) {
+	client, err := NewRepoClient(remote)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ReleaseManager{
-		client: client,
-		config: config,
+		client:   client,
+		provider: NewGitHubReleaseProvider(client),
+		config:   config,
 	}, nil
 }
 
-// UpdateReleaseNotes creates or updates GitHub release notes with AI-generated content
-func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) error {
-	// Extract owner and repo from git remote
-	owner, repo, err := ExtractRepoInfo("")
-	if err != nil {
-		return fmt.Errorf("failed to determine repository info: %w", err)
+// NewReleaseManagerWithProvider creates a release manager that publishes
+// through another provider, such as GitLab
+func NewReleaseManagerWithProvider(config config.Config, provider ReleaseProvider) *ReleaseManager {
+	return &ReleaseManager{
+		provider: provider,
+		config:   config,
 	}
+}
+
+// UpdateReleaseNotes creates or updates release notes with AI-generated content
+func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) error {
+	m.rawResponses = nil
 
 	// Check if a release for this tag already exists
-	releases, err := m.client.get(fmt.Sprintf("/repos/%s/%s/releases/tags/%s", owner, repo, tagName))
+	release, err := m.provider.GetRelease(tagName)
+	if err != nil {
+		return fmt.Errorf("failed to look up %s release: %w", m.provider.Name(), err)
+	}
 
 	// Get the existing release body if available
 	var existingBody string
-	var releaseID float64 = 0
-	if err == nil {
-		if body, ok := releases["body"].(string); ok {
-			existingBody = body
-		}
-		// Store release ID for later
-		if id, ok := releases["id"].(float64); ok {
-			releaseID = id
-		}
+	if release != nil {
+		existingBody = release.Body
 	}
 
 	// Parse the existing GitHub-generated release notes
@@ -67,8 +89,12 @@ func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) e
 		prevTagName = ""
 	}
 
-	// Get commit messages between tags
+	// Get commit messages between tags, asking the provider when the local
+	// history can't answer (e.g. a shallow clone in CI)
 	commitMessages, err := getCommitMessagesBetweenTags(prevTagName, tagName)
+	if err != nil && prevTagName != "" {
+		commitMessages, err = m.provider.ListCommits(prevTagName, tagName)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get commit messages: %w", err)
 	}
@@ -85,7 +111,7 @@ func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) e
 	var releaseNotes string
 	if hasGitHubContent {
 		// Generate AI content for the overview section only
-		overviewContent, err := generateAIOverview(m.config, tagName, commitMessages)
+		overviewContent, err := m.generateAIOverview(tagName, commitMessages)
 		if err != nil {
 			fmt.Printf("Warning: Failed to generate AI overview: %s\n", err)
 			// Keep the existing overview if AI generation fails
@@ -117,6 +143,8 @@ func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) e
 				fmt.Println("Falling back to basic release notes.")
 			} else {
 				aiNotes, err := generator.GenerateReleaseNotes(tagName, commitMessages, prevTagName, diffContent)
+				m.estimatedTokens += generator.EstimatedTokens()
+				m.rawResponses = append(m.rawResponses, generator.RawResponses()...)
 				if err != nil {
 					// Fallback to basic notes if AI generation fails
 					releaseNotes = generateBasicReleaseNotes(tagName, commitMessages)
@@ -141,6 +169,23 @@ func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) e
 		}
 	}
 
+	if m.DebugRaw {
+		m.saveRawResponses(tagName, releaseNotes)
+	}
+
+	// A dry run shows exactly what would be written and stops before any write
+	if m.DryRun {
+		action := "create"
+		if release != nil {
+			action = "update"
+		}
+		fmt.Println("\n==== Release Notes for", tagName, "====")
+		fmt.Println(releaseNotes)
+		fmt.Println("============================================")
+		fmt.Printf("[dry-run] would %s release %s\n", action, tagName)
+		return nil
+	}
+
 	// Show the release notes to the user and ask for approval, unless skipped
 	var approvedNotes string
 	var approved bool
@@ -153,7 +198,7 @@ func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) e
 	} else {
 		approvedNotes, approved = showAndApproveReleaseNotes(releaseNotes, tagName)
 		if !approved {
-			return fmt.Errorf("release notes update cancelled by user")
+			return ErrReleaseNotesCancelled
 		}
 	}
 
@@ -163,16 +208,9 @@ func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) e
 	// Check for breaking changes to mark as prerelease if needed
 	isBreaking := detectBreakingChanges(commitMessages)
 
-	if releaseID > 0 {
+	if release != nil {
 		// Release exists, update it
-		// Prepare update payload
-		payload := map[string]interface{}{
-			"body": releaseNotes,
-		}
-
-		// Update the release
-		_, err = m.client.patch(fmt.Sprintf("/repos/%s/%s/releases/%d", owner, repo, int(releaseID)), payload)
-		if err != nil {
+		if err := m.provider.UpdateRelease(release, releaseNotes); err != nil {
 			return fmt.Errorf("failed to update release notes: %w", err)
 		}
 
@@ -180,17 +218,9 @@ func (m *ReleaseManager) UpdateReleaseNotes(tagName string, skipApproval bool) e
 		return nil
 	}
 
-	// Release doesn't exist, create a new one
-	payload := map[string]interface{}{
-		"tag_name":   tagName,
-		"name":       formatReleaseTitle(tagName),
-		"body":       releaseNotes,
-		"draft":      false,
-		"prerelease": isBreaking, // Mark as prerelease if it contains breaking changes
-	}
-
-	_, err = m.client.post(fmt.Sprintf("/repos/%s/%s/releases", owner, repo), payload)
-	if err != nil {
+	// Release doesn't exist, create a new one, marked as a prerelease if it
+	// contains breaking changes
+	if err := m.provider.CreateRelease(tagName, formatReleaseTitle(tagName), releaseNotes, isBreaking); err != nil {
 		return fmt.Errorf("failed to create release: %w", err)
 	}
 
@@ -253,13 +283,13 @@ func parseGitHubReleaseNotes(notes string) (overview, whatsChanged, summary stri
 }
 
 // generateAIOverview generates AI-enhanced content for the overview section only
-func generateAIOverview(cfg config.Config, tagName string, commitMessages []string) (string, error) {
-	if !cfg.LLM.Enabled {
+func (m *ReleaseManager) generateAIOverview(tagName string, commitMessages []string) (string, error) {
+	if !m.config.LLM.Enabled {
 		return "", fmt.Errorf("LLM not enabled")
 	}
 
 	// Create a new generator
-	generator, err := releaseai.NewReleaseNotesGenerator(cfg)
+	generator, err := releaseai.NewReleaseNotesGenerator(m.config)
 	if err != nil {
 		return "", err
 	}
@@ -275,6 +305,8 @@ Based on these commits:
 
 	// Generate the overview content
 	overview, err := generator.GenerateCustomContent(prompt)
+	m.estimatedTokens += generator.EstimatedTokens()
+	m.rawResponses = append(m.rawResponses, generator.RawResponses()...)
 	if err != nil {
 		return "", err
 	}
@@ -426,7 +458,7 @@ func limitDiffOutput(diff string) string {
 	// Otherwise, take the first ~150 lines with context
 	var result []string
 	result = append(result, lines[:150]...)
-	result = append(result, "... [diff truncated for brevity] ...")
+	result = append(result, truncate.Marker)
 
 	return strings.Join(result, "\n")
 }
@@ -494,85 +526,23 @@ func combineNotesWithChangelog(notes, changelog string) string {
 		return notes
 	}
 
+	// Keep only GitHub's copy of sections both sides have, like What's Changed
+	notes = dropDuplicateSections(notes, changelog)
+
 	// Ensure there's a clear separator between our notes and the GitHub changelog
 	return notes + "\n\n---\n\n" + changelog
 }
 
 // showAndApproveReleaseNotes shows the release notes to the user and asks for approval
 func showAndApproveReleaseNotes(notes, tag string) (string, bool) {
-	fmt.Println("\n==== Generated Release Notes for", tag, "====")
-	fmt.Println(notes)
-	fmt.Println("============================================")
-
-	// Ask if user wants to approve, edit, or cancel
-	fmt.Print("\nWould you like to: [a]pprove, [e]dit, or [c]ancel? ")
-	var input string
-	fmt.Scanln(&input)
-
-	input = strings.ToLower(strings.TrimSpace(input))
-
-	if input == "a" || input == "approve" {
-		return notes, true
-	} else if input == "c" || input == "cancel" {
-		return "", false
-	} else if input == "e" || input == "edit" {
-		// Create a temp file with the notes
-		tmpFile, err := os.CreateTemp("", "release-notes-*.md")
-		if err != nil {
-			fmt.Printf("Error creating temporary file: %s\n", err)
-			fmt.Print("Do you still want to approve the unedited notes? [y/n] ")
-			fmt.Scanln(&input)
-			return notes, strings.ToLower(strings.TrimSpace(input)) == "y"
-		}
-
-		// Write notes to the temp file
-		_, err = tmpFile.WriteString(notes)
-		tmpFile.Close()
-		if err != nil {
-			fmt.Printf("Error writing to temporary file: %s\n", err)
-			fmt.Print("Do you still want to approve the unedited notes? [y/n] ")
-			fmt.Scanln(&input)
-			return notes, strings.ToLower(strings.TrimSpace(input)) == "y"
-		}
-
-		// Open the editor
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "nano" // Fallback editor
-		}
-
-		cmd := exec.Command(editor, tmpFile.Name())
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-
-		err = cmd.Run()
-		if err != nil {
-			fmt.Printf("Error opening editor: %s\n", err)
-			fmt.Print("Do you still want to approve the unedited notes? [y/n] ")
-			fmt.Scanln(&input)
-			return notes, strings.ToLower(strings.TrimSpace(input)) == "y"
-		}
-
-		// Read the edited content
-		editedContent, err := os.ReadFile(tmpFile.Name())
-		if err != nil {
-			fmt.Printf("Error reading edited file: %s\n", err)
-			fmt.Print("Do you still want to approve the unedited notes? [y/n] ")
-			fmt.Scanln(&input)
-			return notes, strings.ToLower(strings.TrimSpace(input)) == "y"
-		}
-
-		// Clean up
-		os.Remove(tmpFile.Name())
-
-		fmt.Println("Release notes edited successfully.")
-		return string(editedContent), true
-	}
-
-	// Default case - ask again
-	fmt.Println("Invalid choice. Please try again.")
-	return showAndApproveReleaseNotes(notes, tag)
+	return ui.ApproveOrEditWith(notes, ui.ApproveOptions{
+		Display: func(content string) {
+			fmt.Println("\n==== Generated Release Notes for", tag, "====")
+			fmt.Println(content)
+			fmt.Println("============================================")
+		},
+		Extension: ".md",
+	})
 }
 
 // formatReleaseTitle formats a release title nicely
@@ -679,18 +649,27 @@ func cleanGeneratedOverview(text string) string {
 	return result
 }
 
-// UpdateReleaseNotesWithWorkflowCheck creates or updates GitHub release notes after checking workflow status
+// UpdateReleaseNotesWithWorkflowCheck creates or updates release notes after checking workflow status
 func (m *ReleaseManager) UpdateReleaseNotesWithWorkflowCheck(tagName string, skipApproval bool, waitForWorkflows bool, maxWaitSeconds int) error {
-	// Extract owner and repo from git remote
-	owner, repo, err := ExtractRepoInfo("")
-	if err != nil {
-		return fmt.Errorf("failed to determine repository info: %w", err)
+	// Workflow runs are a GitHub Actions concept
+	if waitForWorkflows && m.client == nil {
+		fmt.Printf("Warning: waiting for workflows is only supported on GitHub, not %s\n", m.provider.Name())
+		waitForWorkflows = false
 	}
 
 	// Wait for workflows to complete if requested
 	if waitForWorkflows {
+		// Extract owner and repo from git remote
+		owner, repo, err := ExtractRepoInfo(m.client.remote)
+		if err != nil {
+			return fmt.Errorf("failed to determine repository info: %w", err)
+		}
+
 		// Wait for GitHub workflows to finish
-		err := m.client.WaitForWorkflowsToComplete(owner, repo, tagName, maxWaitSeconds)
+		err = m.client.WaitForWorkflowsToComplete(owner, repo, tagName, maxWaitSeconds)
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
 		if err != nil {
 			fmt.Printf("Warning: %s\n", err)
 			fmt.Println("Proceeding anyway...")
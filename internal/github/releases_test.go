@@ -0,0 +1,100 @@
+package github
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+)
+
+// recordingProvider is a fakeProvider that remembers every write
+type recordingProvider struct {
+	fakeProvider
+	writes []string
+}
+
+func (r *recordingProvider) CreateRelease(tag, name, body string, prerelease bool) error {
+	r.writes = append(r.writes, "create "+tag)
+	return r.fakeProvider.CreateRelease(tag, name, body, prerelease)
+}
+
+func (r *recordingProvider) UpdateRelease(release *Release, body string) error {
+	r.writes = append(r.writes, "update "+release.Tag)
+	return r.fakeProvider.UpdateRelease(release, body)
+}
+
+// chdirTaggedRepo creates a repository with tags v1.0.0 and v1.1.0 and makes
+// it the working directory
+func chdirTaggedRepo(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=NoIdea Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=NoIdea Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	git("init", "-q")
+	for _, release := range []struct{ file, message, tag string }{
+		{"a.txt", "feat: first feature", "v1.0.0"},
+		{"b.txt", "fix: second release fix", "v1.1.0"},
+	} {
+		if err := os.WriteFile(filepath.Join(dir, release.file), []byte(release.message+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", release.file)
+		git("commit", "-q", "-m", release.message)
+		git("tag", release.tag)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestUpdateReleaseNotesDryRun(t *testing.T) {
+	chdirTaggedRepo(t)
+
+	// v1.0.0 has a release to update, v1.1.0 has none yet
+	provider := &recordingProvider{fakeProvider: fakeProvider{"v1.0.0": "Old notes"}}
+	manager := NewReleaseManagerWithProvider(config.DefaultConfig(), provider)
+	manager.DryRun = true
+
+	for _, tag := range []string{"v1.0.0", "v1.1.0"} {
+		if err := manager.UpdateReleaseNotes(tag, false); err != nil {
+			t.Fatalf("UpdateReleaseNotes(%s) error = %v", tag, err)
+		}
+	}
+	if len(provider.writes) != 0 {
+		t.Errorf("dry run wrote to the provider: %v", provider.writes)
+	}
+	if provider.fakeProvider["v1.0.0"] != "Old notes" {
+		t.Errorf("dry run changed the existing release to %q", provider.fakeProvider["v1.0.0"])
+	}
+	if _, ok := provider.fakeProvider["v1.1.0"]; ok {
+		t.Error("dry run created a release")
+	}
+
+	// Without --dry-run the same calls write both releases
+	manager.DryRun = false
+	for _, tag := range []string{"v1.0.0", "v1.1.0"} {
+		if err := manager.UpdateReleaseNotes(tag, true); err != nil {
+			t.Fatalf("UpdateReleaseNotes(%s) error = %v", tag, err)
+		}
+	}
+	if len(provider.writes) != 2 || provider.writes[0] != "update v1.0.0" || provider.writes[1] != "create v1.1.0" {
+		t.Errorf("writes = %v, want an update of v1.0.0 and a create of v1.1.0", provider.writes)
+	}
+}
@@ -0,0 +1,100 @@
+package github
+
+import (
+	"strings"
+	"unicode"
+)
+
+// changelogSectionKeys are section titles that all list the individual
+// changes, so the AI's version and GitHub's version are the same section
+var changelogSectionKeys = map[string]bool{
+	"what's changed": true,
+	"whats changed":  true,
+	"changelog":      true,
+	"full changelog": true,
+	"commits":        true,
+	"changes":        true,
+}
+
+// notesSection is a markdown section: a header line and the lines up to
+// the next header. The text before the first header has level 0.
+type notesSection struct {
+	level int
+	key   string
+	lines []string
+}
+
+// sectionKey returns the normalized title of a markdown header line, or
+// false when the line is not a header. Emoji and punctuation are ignored
+// and every changelog-like title maps to "changelog".
+func sectionKey(line string) (key string, level int, ok bool) {
+	trimmed := strings.TrimSpace(line)
+	level = len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+	if level == 0 || level > 6 || !strings.HasPrefix(trimmed[level:], " ") {
+		return "", 0, false
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(trimmed[level:]) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == ' ' {
+			b.WriteRune(r)
+		}
+	}
+	key = strings.Join(strings.Fields(b.String()), " ")
+	if key == "" {
+		return "", 0, false
+	}
+	if changelogSectionKeys[key] {
+		key = "changelog"
+	}
+	return key, level, true
+}
+
+// splitSections splits markdown notes at their header lines
+func splitSections(notes string) []notesSection {
+	sections := []notesSection{{}}
+	for _, line := range strings.Split(notes, "\n") {
+		if key, level, ok := sectionKey(line); ok {
+			sections = append(sections, notesSection{level: level, key: key})
+		}
+		current := &sections[len(sections)-1]
+		current.lines = append(current.lines, line)
+	}
+	return sections
+}
+
+// dropDuplicateSections removes the sections of notes whose title also
+// appears in changelog, together with their subsections, so combining
+// AI notes with GitHub's changelog doesn't show the same section twice.
+// GitHub's version is kept because it links every pull request.
+func dropDuplicateSections(notes, changelog string) string {
+	changelogKeys := map[string]bool{}
+	for _, section := range splitSections(changelog) {
+		if section.key != "" {
+			changelogKeys[section.key] = true
+		}
+	}
+	fullChangelogLink := strings.Contains(changelog, "**Full Changelog**")
+
+	var kept []string
+	dropLevel := 0
+	for _, section := range splitSections(notes) {
+		if dropLevel > 0 && section.level > dropLevel {
+			continue
+		}
+		dropLevel = 0
+		if changelogKeys[section.key] {
+			dropLevel = section.level
+			continue
+		}
+
+		for _, line := range section.lines {
+			if fullChangelogLink && strings.HasPrefix(strings.TrimSpace(line), "**Full Changelog**") {
+				continue
+			}
+			kept = append(kept, line)
+		}
+	}
+
+	return strings.TrimRight(strings.Join(kept, "\n"), "\n ")
+}
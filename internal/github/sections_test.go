@@ -0,0 +1,69 @@
+package github
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCombineNotesWithChangelogDropsDuplicateSections(t *testing.T) {
+	aiNotes := `# Release v1.2.0
+
+## Overview
+
+Faster startup.
+
+## ✨ What's Changed
+
+### Features
+- feat: lazy config loading
+
+## Bug Fixes
+- fix: crash on empty repo
+
+**Full Changelog**: v1.1.0...v1.2.0`
+
+	changelog := `## What's Changed
+* feat: lazy config loading by @dev in https://github.com/o/r/pull/12
+* fix: crash on empty repo by @dev in https://github.com/o/r/pull/13
+
+**Full Changelog**: https://github.com/o/r/compare/v1.1.0...v1.2.0`
+
+	combined := combineNotesWithChangelog(aiNotes, changelog)
+
+	if got := strings.Count(strings.ToLower(combined), "what's changed"); got != 1 {
+		t.Errorf("What's Changed appears %d times, want 1:\n%s", got, combined)
+	}
+	if got := strings.Count(combined, "**Full Changelog**"); got != 1 {
+		t.Errorf("Full Changelog appears %d times, want 1:\n%s", got, combined)
+	}
+	if strings.Contains(combined, "### Features") {
+		t.Errorf("subsections of the dropped section should go too:\n%s", combined)
+	}
+	for _, want := range []string{"## Overview", "Faster startup.", "## Bug Fixes", "pull/12"} {
+		if !strings.Contains(combined, want) {
+			t.Errorf("combined notes missing %q:\n%s", want, combined)
+		}
+	}
+}
+
+func TestSectionKey(t *testing.T) {
+	tests := []struct {
+		line  string
+		key   string
+		level int
+		ok    bool
+	}{
+		{"## What's Changed", "changelog", 2, true},
+		{"### 📝 Changelog", "changelog", 3, true},
+		{"## 🐛 Bug Fixes", "bug fixes", 2, true},
+		{"#123 is fixed", "", 0, false},
+		{"plain text", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		key, level, ok := sectionKey(tt.line)
+		if key != tt.key || level != tt.level || ok != tt.ok {
+			t.Errorf("sectionKey(%q) = %q, %d, %v, want %q, %d, %v", tt.line, key, level, ok, tt.key, tt.level, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package gitlab provides a GitLab API client for publishing release notes
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/AccursedGalaxy/noidea/internal/github"
+	"github.com/AccursedGalaxy/noidea/internal/secure"
+)
+
+// Client represents a GitLab API client for a single project
+type Client struct {
+	httpClient *http.Client
+	baseURL    string // API root, e.g. https://gitlab.com/api/v4
+	token      string
+	project    string // URL-encoded "group/project" path
+}
+
+// NewClient creates a GitLab API client for the project of a git remote
+// ("origin" when empty). instanceURL is the GitLab instance, e.g. https://gitlab.com.
+func NewClient(instanceURL, remote string) (*Client, error) {
+	token, err := secure.GetGitLabToken()
+	if err != nil {
+		return nil, fmt.Errorf("GitLab authentication required. Run 'noidea gitlab auth' to authenticate: %w", err)
+	}
+
+	if instanceURL == "" {
+		instanceURL = secure.GitLabURL
+	}
+
+	remoteURL, err := github.RemoteURL(remote)
+	if err != nil {
+		return nil, err
+	}
+	project, err := ExtractProjectPath(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		httpClient: github.NewHTTPClient(),
+		baseURL:    strings.TrimRight(instanceURL, "/") + "/api/v4",
+		token:      token,
+		project:    url.PathEscape(project),
+	}, nil
+}
+
+// Name returns the provider's display name
+func (c *Client) Name() string {
+	return "GitLab"
+}
+
+// GetRelease returns the release for a tag, or nil if there is none
+func (c *Client) GetRelease(tag string) (*github.Release, error) {
+	var release struct {
+		TagName     string `json:"tag_name"`
+		Description string `json:"description"`
+	}
+	err := c.do("GET", fmt.Sprintf("/projects/%s/releases/%s", c.project, url.PathEscape(tag)), nil, &release)
+	if err != nil {
+		if strings.Contains(err.Error(), "(status code: 404)") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// GitLab identifies releases by their tag
+	return &github.Release{ID: tag, Tag: tag, Body: release.Description}, nil
+}
+
+// CreateRelease publishes a new release for an existing tag. GitLab has no
+// prerelease flag, so prerelease is ignored.
+func (c *Client) CreateRelease(tag, name, body string, prerelease bool) error {
+	payload := map[string]interface{}{
+		"tag_name":    tag,
+		"name":        name,
+		"description": body,
+	}
+	return c.do("POST", fmt.Sprintf("/projects/%s/releases", c.project), payload, nil)
+}
+
+// UpdateRelease replaces the notes of an existing release
+func (c *Client) UpdateRelease(release *github.Release, body string) error {
+	payload := map[string]interface{}{
+		"description": body,
+	}
+	return c.do("PUT", fmt.Sprintf("/projects/%s/releases/%s", c.project, url.PathEscape(release.ID)), payload, nil)
+}
+
+// ListCommits returns "<short hash> <subject>" lines for the commits between
+// two tags, newest first
+func (c *Client) ListCommits(fromTag, toTag string) ([]string, error) {
+	var comparison struct {
+		Commits []struct {
+			ShortID string `json:"short_id"`
+			Title   string `json:"title"`
+		} `json:"commits"`
+	}
+	query := url.Values{"from": {fromTag}, "to": {toTag}}
+	err := c.do("GET", fmt.Sprintf("/projects/%s/repository/compare?%s", c.project, query.Encode()), nil, &comparison)
+	if err != nil {
+		return nil, err
+	}
+
+	// The compare API lists commits oldest first
+	messages := make([]string, 0, len(comparison.Commits))
+	for i := len(comparison.Commits) - 1; i >= 0; i-- {
+		commit := comparison.Commits[i]
+		messages = append(messages, commit.ShortID+" "+commit.Title)
+	}
+	return messages, nil
+}
+
+// do executes a request against the GitLab API and decodes the response into result
+func (c *Client) do(method, path string, payload interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("GitLab API error: %s (status code: %d)", string(body), resp.StatusCode)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(body, result)
+}
+
+// ExtractProjectPath extracts the "group/project" path, including any
+// subgroups, from a Git remote URL
+func ExtractProjectPath(remoteURL string) (string, error) {
+	// Handle SSH URLs (git@gitlab.com:group/project.git)
+	sshPattern := regexp.MustCompile(`^[^@/]+@[^:/]+:(.+?)(?:\.git)?/?$`)
+	if matches := sshPattern.FindStringSubmatch(remoteURL); len(matches) == 2 {
+		return matches[1], nil
+	}
+
+	// Handle HTTPS and ssh:// URLs (https://gitlab.com/group/project.git)
+	parsed, err := url.Parse(remoteURL)
+	if err == nil && parsed.Host != "" {
+		path := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+		if strings.Contains(path, "/") {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not parse GitLab project URL: %s", remoteURL)
+}
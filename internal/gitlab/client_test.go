@@ -0,0 +1,31 @@
+package gitlab
+
+import "testing"
+
+func TestExtractProjectPath(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"git@gitlab.com:group/project.git", "group/project"},
+		{"git@gitlab.example.com:group/sub/project.git", "group/sub/project"},
+		{"https://gitlab.com/group/project.git", "group/project"},
+		{"https://gitlab.com/group/sub/project", "group/sub/project"},
+		{"ssh://git@gitlab.com:2222/group/project.git", "group/project"},
+	}
+
+	for _, tt := range tests {
+		got, err := ExtractProjectPath(tt.remote)
+		if err != nil {
+			t.Errorf("ExtractProjectPath(%q) returned error: %v", tt.remote, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExtractProjectPath(%q) = %q, want %q", tt.remote, got, tt.want)
+		}
+	}
+
+	if _, err := ExtractProjectPath("not a remote"); err == nil {
+		t.Error("expected an error for an unparseable remote")
+	}
+}
@@ -0,0 +1,119 @@
+package history
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache lock timing; a lock older than lockStaleAfter was left by a process
+// that died and is taken over
+const (
+	lockTimeout    = 2 * time.Second
+	lockRetry      = 20 * time.Millisecond
+	lockStaleAfter = 10 * time.Second
+)
+
+// lockCacheFile takes an exclusive lock on a cache file so concurrent noidea
+// processes, such as a hook and a manual command, update it one at a time
+func lockCacheFile(path string) (unlock func(), err error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the lock on %s", path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// writeFileAtomic replaces path with data via a temporary file and a rename,
+// so readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// CacheFile describes one of the collector's cache files
+type CacheFile struct {
+	Name    string // What is cached, e.g. "commits"
+	Path    string
+	Exists  bool
+	Size    int64
+	Entries int  // Cached commits or results
+	Corrupt bool // The file can't be parsed and will be rebuilt
+}
+
+// CacheInfo describes the commit, summary statistics and AI insight caches
+// as they are on disk, including commits that no longer exist in the
+// repository and are dropped on the next load
+func (h *HistoryCollector) CacheInfo() []CacheFile {
+	countJSON := func(data []byte) (int, error) {
+		var entries map[string]json.RawMessage
+		err := json.Unmarshal(data, &entries)
+		return len(entries), err
+	}
+	countGob := func(data []byte) (int, error) {
+		var entries map[string]statsCacheEntry
+		err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entries)
+		return len(entries), err
+	}
+
+	caches := []struct {
+		file  CacheFile
+		count func([]byte) (int, error)
+	}{
+		{CacheFile{Name: "commits", Path: h.cacheFile}, countJSON},
+		{CacheFile{Name: "summary statistics", Path: h.statsCacheFile()}, countGob},
+		{CacheFile{Name: "AI insights", Path: h.insightCacheFile()}, countJSON},
+	}
+
+	files := make([]CacheFile, 0, len(caches))
+	for _, cache := range caches {
+		file := cache.file
+		if data, err := os.ReadFile(file.Path); err == nil {
+			file.Exists = true
+			file.Size = int64(len(data))
+			file.Entries, err = cache.count(data)
+			file.Corrupt = err != nil
+		}
+		files = append(files, file)
+	}
+
+	return files
+}
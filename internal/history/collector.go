@@ -1,6 +1,8 @@
 package history
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,6 +11,9 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/git"
+	"github.com/AccursedGalaxy/noidea/internal/truncate"
 )
 
 // CommitInfo represents metadata about a single git commit
@@ -18,6 +23,7 @@ type CommitInfo struct {
 	Email       string      `json:"email"`
 	Timestamp   time.Time   `json:"timestamp"`
 	Message     string      `json:"message"`
+	Body        string      `json:"body,omitempty"`
 	Files       []string    `json:"files"`
 	Stats       CommitStats `json:"stats"`
 	DiffSummary string      `json:"diff_summary,omitempty"`
@@ -45,6 +51,7 @@ type HistoryCollector struct {
 	cacheDir  string
 	cacheFile string
 	cached    map[string]CommitInfo
+	dropped   map[string]bool // Hashes removed here, so saving doesn't restore them
 }
 
 // NewHistoryCollector creates a new collector with optional caching
@@ -60,12 +67,11 @@ func NewHistoryCollector() (*HistoryCollector, error) {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	cacheFile := filepath.Join(cacheDir, "history_cache.json")
-
 	collector := &HistoryCollector{
 		cacheDir:  cacheDir,
-		cacheFile: cacheFile,
+		cacheFile: commitCacheFile(cacheDir),
 		cached:    make(map[string]CommitInfo),
+		dropped:   make(map[string]bool),
 	}
 
 	// Load cache if exists
@@ -74,6 +80,24 @@ func NewHistoryCollector() (*HistoryCollector, error) {
 	return collector, nil
 }
 
+// commitCacheVersion is part of the commit cache file name. Bump it whenever
+// CommitInfo gains a field that commits cached by older versions lack, so they
+// are fetched again instead of served without it. Version 2 added Body.
+const commitCacheVersion = 2
+
+// commitCacheFile returns the commit cache for the current repository. Each
+// repository gets its own file, so pruning commits that are missing here
+// never touches commits cached for other repositories.
+func commitCacheFile(cacheDir string) string {
+	commonDir, err := git.CommonDir()
+	if err != nil {
+		return filepath.Join(cacheDir, fmt.Sprintf("history_cache-v%d.json", commitCacheVersion))
+	}
+
+	sum := sha256.Sum256([]byte(commonDir))
+	return filepath.Join(cacheDir, fmt.Sprintf("history_cache-v%d-%s.json", commitCacheVersion, hex.EncodeToString(sum[:8])))
+}
+
 // loadCache attempts to load the commit cache from disk
 func (h *HistoryCollector) loadCache() {
 	data, err := os.ReadFile(h.cacheFile)
@@ -85,17 +109,100 @@ func (h *HistoryCollector) loadCache() {
 	if err := json.Unmarshal(data, &h.cached); err != nil {
 		// If cache is corrupted, start fresh
 		h.cached = make(map[string]CommitInfo)
+		return
+	}
+
+	h.dropMissing()
+}
+
+// dropMissing removes cached commits that no longer exist in the repository,
+// such as those rewritten away by a rebase or amend. It checks every hash
+// with a single 'git cat-file' call and keeps the cache outside a repository.
+func (h *HistoryCollector) dropMissing() {
+	if len(h.cached) == 0 {
+		return
+	}
+
+	var input strings.Builder
+	for hash := range h.cached {
+		input.WriteString(hash + "\n")
+	}
+
+	cmd := exec.Command("git", "cat-file", "--batch-check")
+	cmd.Stdin = strings.NewReader(input.String())
+	output, err := cmd.Output()
+	if err != nil {
+		return
+	}
+
+	// Missing objects are reported as "<hash> missing"
+	for _, line := range strings.Split(string(output), "\n") {
+		if hash, found := strings.CutSuffix(line, " missing"); found {
+			h.drop(hash)
+		}
+	}
+}
+
+// drop removes a commit from the cache
+func (h *HistoryCollector) drop(hash string) {
+	delete(h.cached, hash)
+	if h.dropped != nil {
+		h.dropped[hash] = true
+	}
+}
+
+// commitInfo returns a commit from the cache, fetching it when it isn't cached
+// or lacks a requested diff. A cached commit that can no longer be read is
+// dropped and fetched again, and one that can't be fetched is removed.
+func (h *HistoryCollector) commitInfo(hash string, includeDiff bool) (CommitInfo, error) {
+	if commit, found := h.cached[hash]; found {
+		if !includeDiff || commit.DiffSummary != "" {
+			return commit, nil
+		}
+		if diffSummary, err := h.getDiffSummary(hash); err == nil {
+			commit.DiffSummary = diffSummary
+			h.cached[hash] = commit
+			return commit, nil
+		}
+		h.drop(hash)
 	}
+
+	commit, err := h.getCommitInfo(hash, includeDiff)
+	if err != nil {
+		h.drop(hash)
+		return commit, err
+	}
+
+	h.cached[hash] = commit
+	return commit, nil
 }
 
-// saveCache persists the commit cache to disk
+// saveCache persists the commit cache to disk. Commits cached by other
+// processes since this one loaded the cache are merged in rather than lost.
 func (h *HistoryCollector) saveCache() error {
+	unlock, err := lockCacheFile(h.cacheFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if data, err := os.ReadFile(h.cacheFile); err == nil {
+		var onDisk map[string]CommitInfo
+		if json.Unmarshal(data, &onDisk) == nil {
+			for hash, commit := range onDisk {
+				if _, found := h.cached[hash]; !found && !h.dropped[hash] {
+					h.cached[hash] = commit
+				}
+			}
+		}
+	}
+
 	data, err := json.Marshal(h.cached)
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache: %w", err)
 	}
 
-	return os.WriteFile(h.cacheFile, data, 0644)
+	return writeFileAtomic(h.cacheFile, data, 0644)
 }
 
 // GetCommitHistory retrieves commit history based on the provided filter
@@ -134,7 +241,8 @@ func (h *HistoryCollector) GetCommitHistory(filter HistoryFilter) ([]CommitInfo,
 
 	// Branch filter
 	if filter.Branch != "" {
-		args = append(args, filter.Branch)
+		// "--" keeps a branch named like a file from being read as a path
+		args = append(args, filter.Branch, "--")
 	}
 
 	// Execute git command
@@ -157,30 +265,11 @@ func (h *HistoryCollector) GetCommitHistory(filter HistoryFilter) ([]CommitInfo,
 			continue
 		}
 
-		// Check cache first
-		if commit, found := h.cached[hash]; found {
-			// If we need diff but it's not in cache, we'll fetch it
-			if filter.IncludeDiff && commit.DiffSummary == "" {
-				diffSummary, err := h.getDiffSummary(hash)
-				if err == nil {
-					commit.DiffSummary = diffSummary
-					h.cached[hash] = commit // Update cache
-				}
-			}
-
-			commits = append(commits, commit)
-			continue
-		}
-
-		// Fetch commit info for uncached commits
-		commit, err := h.getCommitInfo(hash, filter.IncludeDiff)
+		commit, err := h.commitInfo(hash, filter.IncludeDiff)
 		if err != nil {
 			// Skip commits that can't be retrieved
 			continue
 		}
-
-		// Add to cache
-		h.cached[hash] = commit
 		commits = append(commits, commit)
 	}
 
@@ -190,19 +279,54 @@ func (h *HistoryCollector) GetCommitHistory(filter HistoryFilter) ([]CommitInfo,
 	return commits, nil
 }
 
+// ResolveCommit resolves a hash or other revision to a full commit hash
+func ResolveCommit(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", fmt.Errorf("no commit specified")
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("'%s' does not resolve to a commit in this repository", ref)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetCommit retrieves a single commit by hash (or any revision git understands)
+func (h *HistoryCollector) GetCommit(ref string, includeDiff bool) (CommitInfo, error) {
+	hash, err := ResolveCommit(ref)
+	if err != nil {
+		return CommitInfo{}, err
+	}
+
+	commit, err := h.commitInfo(hash, includeDiff)
+	if err != nil {
+		return commit, err
+	}
+
+	h.saveCache()
+
+	return commit, nil
+}
+
 // getCommitInfo fetches detailed info for a specific commit
 func (h *HistoryCollector) getCommitInfo(hash string, includeDiff bool) (CommitInfo, error) {
 	var commit CommitInfo
 	commit.Hash = hash
 
-	// Get commit metadata
-	cmd := exec.Command("git", "show", "--format=%an%n%ae%n%at%n%B", "--name-only", hash)
+	// Get commit metadata, with a NUL byte separating the message from the file list
+	cmd := exec.Command("git", "show", "--format=%an%n%ae%n%at%n%B%x00", "--name-only", hash)
 	output, err := cmd.Output()
 	if err != nil {
 		return commit, fmt.Errorf("failed to get commit metadata: %w", err)
 	}
 
-	lines := strings.Split(string(output), "\n")
+	header, fileList, _ := strings.Cut(string(output), "\x00")
+
+	lines := strings.SplitN(header, "\n", 4)
 	if len(lines) < 4 {
 		return commit, fmt.Errorf("invalid commit data format")
 	}
@@ -217,28 +341,16 @@ func (h *HistoryCollector) getCommitInfo(hash string, includeDiff bool) (CommitI
 	}
 	commit.Timestamp = time.Unix(timestamp, 0)
 
-	// Parse message (might span multiple lines)
-	var messageBuilder strings.Builder
-	lineIndex := 3
-	for ; lineIndex < len(lines); lineIndex++ {
-		if lines[lineIndex] == "" {
-			break
-		}
-		if messageBuilder.Len() > 0 {
-			messageBuilder.WriteString("\n")
-		}
-		messageBuilder.WriteString(lines[lineIndex])
-	}
-	commit.Message = messageBuilder.String()
-
-	// Skip any blank lines
-	for ; lineIndex < len(lines) && lines[lineIndex] == ""; lineIndex++ {
-	}
+	// The first paragraph is the message, anything after the first blank line is the body
+	fullMessage := strings.TrimSpace(lines[3])
+	message, body, _ := strings.Cut(fullMessage, "\n\n")
+	commit.Message = strings.TrimSpace(message)
+	commit.Body = strings.TrimSpace(body)
 
 	// Collect changed files
-	for ; lineIndex < len(lines); lineIndex++ {
-		if lines[lineIndex] != "" {
-			commit.Files = append(commit.Files, lines[lineIndex])
+	for _, file := range strings.Split(fileList, "\n") {
+		if file = strings.TrimSpace(file); file != "" {
+			commit.Files = append(commit.Files, file)
 		}
 	}
 
@@ -312,14 +424,14 @@ func (h *HistoryCollector) getDiffSummary(hash string) (string, error) {
 	// Truncate very large diffs to avoid token explosion when sent to LLMs
 	const maxDiffLength = 5000
 	if len(diffText) > maxDiffLength {
-		return diffText[:maxDiffLength] + "... [diff truncated]", nil
+		return diffText[:maxDiffLength] + truncate.Notice(), nil
 	}
 
 	return diffText, nil
 }
 
 // GetCommitRange retrieves commits between two dates
-func (h *HistoryCollector) GetCommitRange(startTime, endTime time.Time) ([]CommitInfo, error) {
+func (h *HistoryCollector) GetCommitRange(startTime, endTime time.Time, includeDiff bool) ([]CommitInfo, error) {
 	args := []string{
 		"log",
 		"--format=%H",
@@ -344,20 +456,10 @@ func (h *HistoryCollector) GetCommitRange(startTime, endTime time.Time) ([]Commi
 			continue
 		}
 
-		// Check cache first
-		if commit, found := h.cached[hash]; found {
-			commits = append(commits, commit)
-			continue
-		}
-
-		// Fetch commit info
-		commit, err := h.getCommitInfo(hash, false)
+		commit, err := h.commitInfo(hash, includeDiff)
 		if err != nil {
 			continue
 		}
-
-		// Add to cache
-		h.cached[hash] = commit
 		commits = append(commits, commit)
 	}
 
@@ -366,7 +468,8 @@ func (h *HistoryCollector) GetCommitRange(startTime, endTime time.Time) ([]Commi
 	return commits, nil
 }
 
-// CalculateStats generates aggregated statistics for a set of commits
+// CalculateStats generates aggregated statistics for a set of commits.
+// Bump statsCacheVersion when changing which stats it computes.
 func (h *HistoryCollector) CalculateStats(commits []CommitInfo) map[string]interface{} {
 	stats := make(map[string]interface{})
 
@@ -419,14 +522,21 @@ func (h *HistoryCollector) CalculateStats(commits []CommitInfo) map[string]inter
 	}
 	stats["commits_by_hour"] = hourOfDay
 
+	// Reverts are rework: changes that had to be undone
+	stats["revert_commits"] = len(FindReverts(commits))
+
 	return stats
 }
 
-// ClearCache removes the cache file
+// ClearCache removes the cache files
 func (h *HistoryCollector) ClearCache() error {
 	h.cached = make(map[string]CommitInfo)
-	if _, err := os.Stat(h.cacheFile); err == nil {
-		return os.Remove(h.cacheFile)
+	for _, file := range []string{h.cacheFile, h.statsCacheFile(), h.insightCacheFile()} {
+		if _, err := os.Stat(file); err == nil {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
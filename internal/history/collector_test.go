@@ -0,0 +1,424 @@
+package history
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/git"
+)
+
+// runGit runs a git command in dir and returns its trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=NoIdea Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=NoIdea Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestDropMissingAfterAmend(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "a.txt")
+	runGit(t, repo, "commit", "-q", "-m", "feat: first")
+	original := runGit(t, repo, "rev-parse", "HEAD")
+
+	// Rewrite the commit and make the old object unreachable and gone
+	runGit(t, repo, "commit", "-q", "--amend", "-m", "feat: amended")
+	amended := runGit(t, repo, "rev-parse", "HEAD")
+	runGit(t, repo, "reflog", "expire", "--expire=now", "--all")
+	runGit(t, repo, "gc", "-q", "--prune=now")
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	h := &HistoryCollector{cached: map[string]CommitInfo{
+		original: {Hash: original, Message: "feat: first"},
+		amended:  {Hash: amended, Message: "feat: amended"},
+	}}
+	h.dropMissing()
+
+	if _, found := h.cached[original]; found {
+		t.Error("rewritten commit should be dropped from the cache")
+	}
+	if _, found := h.cached[amended]; !found {
+		t.Error("existing commit should stay cached")
+	}
+
+	// Cached entries are used as they are when no diff is needed
+	h.cached[amended] = CommitInfo{Hash: amended, Message: "stale"}
+	commit, err := h.commitInfo(amended, false)
+	if err != nil || commit.Message != "stale" {
+		t.Errorf("commitInfo() without diff should use the cache, got %+v, %v", commit, err)
+	}
+	if _, err := h.commitInfo(original, false); err == nil {
+		t.Error("commitInfo() for a rewritten commit should fail")
+	}
+}
+
+func TestSaveCacheConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	cacheFile := filepath.Join(dir, "history_cache.json")
+
+	// Each collector caches its own commit; none may be lost or corrupt the file
+	const writers = 8
+	done := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			h := &HistoryCollector{
+				cacheDir:  dir,
+				cacheFile: cacheFile,
+				cached:    map[string]CommitInfo{},
+				dropped:   map[string]bool{},
+			}
+			hash := strings.Repeat(string(rune('a'+i)), 40)
+			h.cached[hash] = CommitInfo{Hash: hash}
+			done <- h.saveCache()
+		}(i)
+	}
+	for i := 0; i < writers; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("saveCache() error = %v", err)
+		}
+	}
+
+	h := &HistoryCollector{cacheDir: dir, cacheFile: cacheFile, cached: map[string]CommitInfo{}}
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &h.cached); err != nil {
+		t.Fatalf("cache is not valid JSON: %v", err)
+	}
+	if len(h.cached) != writers {
+		t.Errorf("cache has %d commits, want %d", len(h.cached), writers)
+	}
+	if _, err := os.Stat(cacheFile + ".lock"); !os.IsNotExist(err) {
+		t.Error("lock file should be removed after saving")
+	}
+}
+
+func TestInsightCache(t *testing.T) {
+	h := &HistoryCollector{cacheDir: t.TempDir()}
+	commits := []CommitInfo{{Hash: "aaa"}, {Hash: "bbb"}}
+
+	key := InsightCacheKey(commits, "snarky_reviewer", "72", "xai", "grok-2-1212")
+	if _, ok := h.GetCachedInsight(key, 0); ok {
+		t.Fatal("empty cache should miss")
+	}
+	if err := h.CacheInsight(key, "• Nice work"); err != nil {
+		t.Fatalf("CacheInsight() error = %v", err)
+	}
+	if insight, ok := h.GetCachedInsight(key, 0); !ok || insight != "• Nice work" {
+		t.Errorf("GetCachedInsight() = %q, %v", insight, ok)
+	}
+
+	// Commit order doesn't matter, but every other input does
+	reordered := []CommitInfo{{Hash: "bbb"}, {Hash: "aaa"}}
+	if InsightCacheKey(reordered, "snarky_reviewer", "72", "xai", "grok-2-1212") != key {
+		t.Error("key should not depend on commit order")
+	}
+	for _, other := range []string{
+		InsightCacheKey(commits[:1], "snarky_reviewer", "72", "xai", "grok-2-1212"),
+		InsightCacheKey(commits, "supportive_mentor", "72", "xai", "grok-2-1212"),
+		InsightCacheKey(commits, "snarky_reviewer", "72", "openai", "gpt-4o"),
+	} {
+		if _, ok := h.GetCachedInsight(other, 0); ok || other == key {
+			t.Error("different inputs should not hit the cached insight")
+		}
+	}
+
+	// An insight older than the TTL is regenerated
+	entries := h.loadInsightCache()
+	entries[key] = insightCacheEntry{Insight: "• Nice work", CachedAt: time.Now().Add(-2 * time.Hour)}
+	data, _ := json.Marshal(entries)
+	if err := os.WriteFile(h.insightCacheFile(), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := h.GetCachedInsight(key, time.Hour); ok {
+		t.Error("insight older than the TTL should miss")
+	}
+	if _, ok := h.GetCachedInsight(key, 3*time.Hour); !ok {
+		t.Error("insight within the TTL should hit")
+	}
+}
+
+func TestStatsCache(t *testing.T) {
+	h := &HistoryCollector{cacheDir: t.TempDir()}
+	commits := []CommitInfo{{Hash: "aaa"}, {Hash: "bbb"}}
+	stats := map[string]interface{}{
+		"total_commits":       2,
+		"author_distribution": map[string]int{"Ada": 2},
+	}
+
+	if _, ok := h.GetCachedStats(commits); ok {
+		t.Fatal("empty cache should miss")
+	}
+	if err := h.CacheStats(commits, stats); err != nil {
+		t.Fatalf("CacheStats() error = %v", err)
+	}
+
+	// The same commits in any order hit, and the stats survive the round trip
+	cached, ok := h.GetCachedStats([]CommitInfo{{Hash: "bbb"}, {Hash: "aaa"}})
+	if !ok || cached["total_commits"] != 2 || cached["author_distribution"].(map[string]int)["Ada"] != 2 {
+		t.Errorf("GetCachedStats() = %v, %v", cached, ok)
+	}
+	// A new commit changes the set, so the old stats aren't reused
+	if _, ok := h.GetCachedStats(append(commits, CommitInfo{Hash: "ccc"})); ok {
+		t.Error("a different commit set should miss")
+	}
+
+	// Once the cache is full, adding an entry evicts the oldest one
+	for i := 0; i < maxStatsCacheEntries; i++ {
+		set := []CommitInfo{{Hash: fmt.Sprintf("set-%d", i)}}
+		if err := h.CacheStats(set, map[string]interface{}{"total_commits": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries := h.loadStatsCache(); len(entries) != maxStatsCacheEntries {
+		t.Errorf("cache holds %d entries, want %d", len(entries), maxStatsCacheEntries)
+	}
+	if _, ok := h.GetCachedStats(commits); ok {
+		t.Error("the oldest entry should have been evicted")
+	}
+	if _, ok := h.GetCachedStats([]CommitInfo{{Hash: "set-0"}}); !ok {
+		t.Error("newer entries should stay cached")
+	}
+}
+
+func TestCacheInfoAndClear(t *testing.T) {
+	dir := t.TempDir()
+	h := &HistoryCollector{
+		cacheDir:  dir,
+		cacheFile: filepath.Join(dir, "history_cache.json"),
+		cached:    map[string]CommitInfo{"aaa": {Hash: "aaa"}, "bbb": {Hash: "bbb"}},
+	}
+	if err := h.saveCache(); err != nil {
+		t.Fatal(err)
+	}
+	commits := []CommitInfo{{Hash: "aaa"}}
+	if err := h.CacheStats(commits, map[string]interface{}{"total_commits": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(h.insightCacheFile(), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := h.CacheInfo()
+	if len(files) != 3 {
+		t.Fatalf("CacheInfo() returned %d files, want 3", len(files))
+	}
+	if commits := files[0]; !commits.Exists || commits.Entries != 2 || commits.Size == 0 || commits.Corrupt {
+		t.Errorf("commit cache = %+v, want 2 entries", commits)
+	}
+	if stats := files[1]; !stats.Exists || stats.Entries != 1 || stats.Corrupt {
+		t.Errorf("stats cache = %+v, want 1 entry", stats)
+	}
+	if insights := files[2]; !insights.Exists || !insights.Corrupt {
+		t.Errorf("insight cache = %+v, want corrupt", insights)
+	}
+
+	if err := h.ClearCache(); err != nil {
+		t.Fatalf("ClearCache() error = %v", err)
+	}
+	for _, file := range h.CacheInfo() {
+		if file.Exists {
+			t.Errorf("%s cache should be removed", file.Name)
+		}
+	}
+}
+
+func TestGetCommitByHash(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("first line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "a.txt")
+	runGit(t, repo, "commit", "-q", "-m", "feat: add a.txt")
+	first := runGit(t, repo, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(repo, "b.txt"), []byte("b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "b.txt")
+	runGit(t, repo, "commit", "-q", "-m", "feat: add b.txt")
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// An abbreviated hash loads the older commit, not the latest one
+	commit, err := GetCommitByHash(first[:7], true)
+	if err != nil {
+		t.Fatalf("GetCommitByHash() error = %v", err)
+	}
+	if commit.Hash != first || commit.Message != "feat: add a.txt" {
+		t.Errorf("commit = %s %q, want %s \"feat: add a.txt\"", commit.Hash, commit.Message, first)
+	}
+	if len(commit.Files) != 1 || commit.Files[0] != "a.txt" || !strings.Contains(commit.DiffSummary, "first line") {
+		t.Errorf("files = %v, diff = %q", commit.Files, commit.DiffSummary)
+	}
+
+	if commit, err := GetCommitByHash("HEAD~1", false); err != nil || commit.Hash != first {
+		t.Errorf("GetCommitByHash(HEAD~1) = %s, %v", commit.Hash, err)
+	}
+	if _, err := GetCommitByHash("deadbeef", false); err == nil || !strings.Contains(err.Error(), "does not resolve") {
+		t.Errorf("unknown hash error = %v", err)
+	}
+}
+
+func TestCommitCachePerRepository(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	// Cache the latest commit of a new repository and return its hash
+	cacheRepo := func() (string, *HistoryCollector) {
+		repo := t.TempDir()
+		runGit(t, repo, "init", "-q")
+		if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte(repo+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, repo, "add", "a.txt")
+		runGit(t, repo, "commit", "-q", "-m", "feat: add a.txt")
+		if err := os.Chdir(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		h, err := NewHistoryCollector()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := runGit(t, repo, "rev-parse", "HEAD")
+		if _, err := h.GetCommit(hash, false); err != nil {
+			t.Fatal(err)
+		}
+		return hash, h
+	}
+
+	hashA, a := cacheRepo()
+	repoA, _ := os.Getwd()
+	hashB, b := cacheRepo()
+	if a.cacheFile == b.cacheFile {
+		t.Fatalf("both repositories use %s", a.cacheFile)
+	}
+
+	// Loading in A must not prune B's commits, which A doesn't have
+	if err := os.Chdir(repoA); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewHistoryCollector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := reloaded.cached[hashA]; !found {
+		t.Error("repository A's commit should still be cached")
+	}
+	if err := reloaded.saveCache(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(b.cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cachedB map[string]CommitInfo
+	if err := json.Unmarshal(data, &cachedB); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := cachedB[hashB]; !found {
+		t.Error("working in repository A dropped repository B's cached commit")
+	}
+}
+
+func TestStatsCacheIgnoresOlderVersions(t *testing.T) {
+	h := &HistoryCollector{cacheDir: t.TempDir()}
+	commits := []CommitInfo{{Hash: "aaa"}, {Hash: "bbb"}}
+
+	// Entries written before keys carried a version hash only the commits
+	unversioned := sha256.Sum256([]byte("aaa\nbbb\n"))
+	entries := map[string]statsCacheEntry{
+		hex.EncodeToString(unversioned[:]): {Stats: map[string]interface{}{"total_commits": 2}, CachedAt: time.Now()},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entries); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(h.statsCacheFile(), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := h.GetCachedStats(commits); ok {
+		t.Error("stats cached by an older version should be recomputed")
+	}
+}
+
+func TestCommitCacheFetchesBodiesMissingFromOlderCaches(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "a.txt")
+	runGit(t, repo, "commit", "-q", "-m", "feat: add a.txt", "-m", "Explain why a.txt exists.")
+	hash := runGit(t, repo, "rev-parse", "HEAD")
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	// A cache written before bodies were stored, in every place it could have been
+	cacheDir := filepath.Join(home, ".noidea", "cache")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	old, _ := json.Marshal(map[string]CommitInfo{hash: {Hash: hash, Message: "feat: add a.txt"}})
+	commonDir, err := git.CommonDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(commonDir))
+	for _, name := range []string{"history_cache.json", "history_cache-" + hex.EncodeToString(sum[:8]) + ".json"} {
+		if err := os.WriteFile(filepath.Join(cacheDir, name), old, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h, err := NewHistoryCollector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := h.GetCommit(hash, false)
+	if err != nil || commit.Body != "Explain why a.txt exists." {
+		t.Errorf("GetCommit() body = %q, %v; want the body fetched from git", commit.Body, err)
+	}
+}
@@ -0,0 +1,181 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+)
+
+// FocusArea describes a part of the repository that received attention
+type FocusArea struct {
+	Name    string
+	Count   int
+	Percent float64
+}
+
+// containerDirs are generic top-level directories that say little about the
+// actual focus of the work, so we look one level deeper when we see them
+var containerDirs = map[string]bool{
+	"internal": true,
+	"pkg":      true,
+	"src":      true,
+	"lib":      true,
+	"app":      true,
+}
+
+// focusDir returns the directory that best describes where a file lives
+func focusDir(file string) string {
+	parts := strings.Split(strings.Trim(file, "/"), "/")
+	if len(parts) < 2 {
+		return "(root)"
+	}
+
+	if containerDirs[parts[0]] && len(parts) > 2 {
+		return parts[1]
+	}
+
+	return parts[0]
+}
+
+// CalculateFocusAreas derives the most-changed areas of the repository from
+// the files touched by the given commits, returning at most limit entries
+func CalculateFocusAreas(commits []CommitInfo, limit int) []FocusArea {
+	counts := make(map[string]int)
+	total := 0
+
+	for _, commit := range commits {
+		for _, file := range commit.Files {
+			if strings.TrimSpace(file) == "" {
+				continue
+			}
+			counts[focusDir(file)]++
+			total++
+		}
+	}
+
+	return rankFocusAreas(counts, total, limit)
+}
+
+// CalculateTypeDistribution counts conventional commit types across commits.
+// Commits that don't follow the convention are grouped as "other", except
+// reverts made by git revert, which count as "revert".
+func CalculateTypeDistribution(commits []CommitInfo, limit int) []FocusArea {
+	counts := make(map[string]int)
+
+	for _, commit := range commits {
+		commitType := conventional.TypeOf(commit.Message)
+		if commitType == "" && IsRevert(commit) {
+			// git revert's `Revert "..."` subjects have no type prefix
+			commitType = "revert"
+		} else if commitType == "" {
+			commitType = "other"
+		}
+		counts[commitType]++
+	}
+
+	return rankFocusAreas(counts, len(commits), limit)
+}
+
+// languageByExt maps file extensions to language names for CalculateLanguages
+var languageByExt = map[string]string{
+	".go": "Go", ".js": "JavaScript", ".jsx": "JavaScript", ".ts": "TypeScript", ".tsx": "TypeScript",
+	".py": "Python", ".rb": "Ruby", ".rs": "Rust", ".java": "Java", ".kt": "Kotlin", ".swift": "Swift",
+	".c": "C", ".h": "C", ".cpp": "C++", ".cc": "C++", ".hpp": "C++", ".cs": "C#", ".php": "PHP",
+	".sh": "Shell", ".bash": "Shell", ".md": "Markdown", ".html": "HTML", ".css": "CSS", ".scss": "CSS",
+	".json": "JSON", ".yml": "YAML", ".yaml": "YAML", ".toml": "TOML", ".sql": "SQL",
+}
+
+// CalculateLanguages counts changed files by language, judged from their
+// extensions, returning at most limit entries. Unknown extensions are skipped.
+func CalculateLanguages(commits []CommitInfo, limit int) []FocusArea {
+	counts := make(map[string]int)
+	total := 0
+
+	for _, commit := range commits {
+		for _, file := range commit.Files {
+			if language, ok := languageByExt[strings.ToLower(filepath.Ext(file))]; ok {
+				counts[language]++
+				total++
+			}
+		}
+	}
+
+	return rankFocusAreas(counts, total, limit)
+}
+
+// LongestStreak returns the most consecutive days with at least one commit
+func LongestStreak(commits []CommitInfo) int {
+	days := make(map[string]bool)
+	for _, commit := range commits {
+		days[commit.Timestamp.Local().Format("2006-01-02")] = true
+	}
+
+	longest := 0
+	for day := range days {
+		start, err := time.ParseInLocation("2006-01-02", day, time.Local)
+		if err != nil {
+			continue
+		}
+		// Only count from the first day of each run
+		if days[start.AddDate(0, 0, -1).Format("2006-01-02")] {
+			continue
+		}
+
+		length := 1
+		for days[start.AddDate(0, 0, length).Format("2006-01-02")] {
+			length++
+		}
+		if length > longest {
+			longest = length
+		}
+	}
+
+	return longest
+}
+
+// rankFocusAreas sorts counted areas by frequency and converts them to percentages
+func rankFocusAreas(counts map[string]int, total int, limit int) []FocusArea {
+	if total == 0 {
+		return nil
+	}
+
+	areas := make([]FocusArea, 0, len(counts))
+	for name, count := range counts {
+		areas = append(areas, FocusArea{
+			Name:    name,
+			Count:   count,
+			Percent: float64(count) / float64(total) * 100,
+		})
+	}
+
+	sort.Slice(areas, func(i, j int) bool {
+		if areas[i].Count == areas[j].Count {
+			return areas[i].Name < areas[j].Name
+		}
+		return areas[i].Count > areas[j].Count
+	})
+
+	if limit > 0 && len(areas) > limit {
+		areas = areas[:limit]
+	}
+
+	return areas
+}
+
+// FormatFocusAreas renders focus areas as a single line, e.g. "Focus: auth (40%), ui (25%)"
+func FormatFocusAreas(label string, areas []FocusArea) string {
+	if len(areas) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(areas))
+	for _, area := range areas {
+		parts = append(parts, fmt.Sprintf("%s (%.0f%%)", area.Name, area.Percent))
+	}
+
+	return fmt.Sprintf("%s: %s", label, strings.Join(parts, ", "))
+}
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLongestStreak(t *testing.T) {
+	day := func(d int) CommitInfo {
+		return CommitInfo{Timestamp: time.Date(2025, 3, d, 12, 0, 0, 0, time.Local)}
+	}
+
+	commits := []CommitInfo{day(1), day(2), day(2), day(3), day(7), day(8)}
+	if got := LongestStreak(commits); got != 3 {
+		t.Errorf("LongestStreak() = %d, want 3", got)
+	}
+	if got := LongestStreak(nil); got != 0 {
+		t.Errorf("LongestStreak(nil) = %d, want 0", got)
+	}
+}
+
+func TestCalculateLanguages(t *testing.T) {
+	commits := []CommitInfo{
+		{Files: []string{"cmd/root.go", "cmd/root_test.go", "README.md"}},
+		{Files: []string{"internal/x.go", "LICENSE"}},
+	}
+
+	languages := CalculateLanguages(commits, 2)
+	if len(languages) != 2 || languages[0].Name != "Go" || languages[0].Count != 3 || languages[1].Name != "Markdown" {
+		t.Errorf("CalculateLanguages() = %+v", languages)
+	}
+}
+
+func TestCalculateFocusAreas(t *testing.T) {
+	commits := []CommitInfo{
+		{Files: []string{"internal/auth/login.go", "internal/auth/token.go", "cmd/login.go"}},
+		{Files: []string{"internal/auth/session.go", "README.md", ""}},
+		{Files: []string{"internal/x.go"}},
+	}
+
+	areas := CalculateFocusAreas(commits, 3)
+	if len(areas) != 3 {
+		t.Fatalf("CalculateFocusAreas() = %+v, want 3 areas", areas)
+	}
+	// internal/ is a container, so auth is the area; files directly inside
+	// internal/ stay under internal, and top-level files count as (root)
+	if areas[0].Name != "auth" || areas[0].Count != 3 || areas[0].Percent != 50 {
+		t.Errorf("top area = %+v, want auth with 3 files (50%%)", areas[0])
+	}
+	if areas[1].Name != "(root)" || areas[2].Name != "cmd" {
+		t.Errorf("ties should sort by name, got %+v", areas[1:])
+	}
+	if got := CalculateFocusAreas(nil, 3); got != nil {
+		t.Errorf("CalculateFocusAreas(nil) = %+v, want nil", got)
+	}
+}
+
+func TestCalculateTypeDistribution(t *testing.T) {
+	commits := []CommitInfo{
+		{Message: "feat(auth): add login"},
+		{Message: "Fix!: drop the old token format\n\nBREAKING CHANGE: tokens"},
+		{Message: "feat: add logout"},
+		{Message: "update readme"},
+	}
+
+	types := CalculateTypeDistribution(commits, 0)
+	got := FormatFocusAreas("Types", types)
+	if want := "Types: feat (50%), fix (25%), other (25%)"; got != want {
+		t.Errorf("FormatFocusAreas() = %q, want %q", got, want)
+	}
+	if got := FormatFocusAreas("Types", nil); got != "" {
+		t.Errorf("FormatFocusAreas(nil) = %q, want empty", got)
+	}
+}
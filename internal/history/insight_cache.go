@@ -0,0 +1,104 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxInsightCacheEntries bounds how many AI insights are kept on disk
+const maxInsightCacheEntries = 20
+
+// insightCacheEntry holds one generated AI insight
+type insightCacheEntry struct {
+	Insight  string    `json:"insight"`
+	CachedAt time.Time `json:"cached_at"`
+}
+
+// InsightCacheKey identifies an AI insight by the set of commits it describes
+// and everything else that shapes the prompt, such as the personality and model
+func InsightCacheKey(commits []CommitInfo, params ...string) string {
+	sum := sha256.New()
+	sum.Write([]byte(statsCacheKey(commits)))
+	for _, param := range params {
+		sum.Write([]byte{0})
+		sum.Write([]byte(param))
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// insightCacheFile returns the path of the AI insight cache
+func (h *HistoryCollector) insightCacheFile() string {
+	return filepath.Join(h.cacheDir, "insight_cache.json")
+}
+
+// loadInsightCache reads all cached insights, returning an empty map on any error
+func (h *HistoryCollector) loadInsightCache() map[string]insightCacheEntry {
+	entries := make(map[string]insightCacheEntry)
+
+	data, err := os.ReadFile(h.insightCacheFile())
+	if err != nil {
+		return entries
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		// If cache is corrupted, start fresh
+		return make(map[string]insightCacheEntry)
+	}
+
+	return entries
+}
+
+// GetCachedInsight returns a previously generated insight for key, unless it
+// is older than maxAge (0 for no limit)
+func (h *HistoryCollector) GetCachedInsight(key string, maxAge time.Duration) (string, bool) {
+	entry, found := h.loadInsightCache()[key]
+	if !found || entry.Insight == "" {
+		return "", false
+	}
+	if maxAge > 0 && time.Since(entry.CachedAt) > maxAge {
+		return "", false
+	}
+	return entry.Insight, true
+}
+
+// CacheInsight stores a generated insight, keeping only the most recent entries
+func (h *HistoryCollector) CacheInsight(key, insight string) error {
+	unlock, err := lockCacheFile(h.insightCacheFile())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries := h.loadInsightCache()
+	entries[key] = insightCacheEntry{Insight: insight, CachedAt: time.Now()}
+
+	// Drop the oldest entries once we're over the limit
+	if len(entries) > maxInsightCacheEntries {
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return entries[keys[i]].CachedAt.After(entries[keys[j]].CachedAt)
+		})
+		for _, key := range keys[maxInsightCacheEntries:] {
+			delete(entries, key)
+		}
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode insight cache: %w", err)
+	}
+
+	if err := writeFileAtomic(h.insightCacheFile(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write insight cache: %w", err)
+	}
+
+	return nil
+}
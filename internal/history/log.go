@@ -0,0 +1,114 @@
+package history
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logFormat starts each commit with a record separator and ends each field
+// with a unit separator, so messages can't be mistaken for --numstat lines
+const logFormat = "%x1e%H%x1f%an%x1f%ae%x1f%at%x1f%B%x1f"
+
+// LogCommits lists the commits selected by git log arguments such as
+// "--since=7.days.ago" or "main..feature", newest first and at most limit of
+// them (0 for no limit). Everything comes from a single git log --numstat, so
+// unlike GetCommitHistory it doesn't run git show per commit. It suits
+// aggregate views such as summaries: diff summaries aren't collected and the
+// commit cache isn't used.
+func LogCommits(limit int, args ...string) ([]CommitInfo, error) {
+	logArgs := []string{"log", "--numstat", "--format=" + logFormat}
+	if limit > 0 {
+		logArgs = append(logArgs, fmt.Sprintf("-n%d", limit))
+	}
+	logArgs = append(logArgs, args...)
+
+	output, err := exec.Command("git", logArgs...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit log: %w", err)
+	}
+
+	return parseLog(string(output))
+}
+
+// LogCommitsBetween lists the commits reachable from head but not from base
+// like GetCommitsBetween, using LogCommits
+func LogCommitsBetween(base, head string, limit int) ([]CommitInfo, error) {
+	for _, ref := range []string{base, head} {
+		if _, err := ResolveCommit(ref); err != nil {
+			return nil, err
+		}
+	}
+	return LogCommits(limit, base+".."+head)
+}
+
+// parseLog reads the output of git log with logFormat and --numstat
+func parseLog(output string) ([]CommitInfo, error) {
+	var commits []CommitInfo
+	for _, record := range strings.Split(output, "\x1e") {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, "\x1f", 6)
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("invalid commit log format")
+		}
+
+		timestamp, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+		}
+
+		commit := CommitInfo{
+			Hash:      fields[0],
+			Author:    fields[1],
+			Email:     fields[2],
+			Timestamp: time.Unix(timestamp, 0),
+		}
+
+		// The first paragraph is the message, anything after the first blank line is the body
+		message, body, _ := strings.Cut(strings.TrimSpace(fields[4]), "\n\n")
+		commit.Message = strings.TrimSpace(message)
+		commit.Body = strings.TrimSpace(body)
+
+		// --numstat lines are "<added>\t<deleted>\t<path>", with "-" counts for binary files
+		for _, line := range strings.Split(fields[5], "\n") {
+			parts := strings.SplitN(line, "\t", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			added, _ := strconv.Atoi(parts[0])
+			deleted, _ := strconv.Atoi(parts[1])
+			commit.Stats.Insertions += added
+			commit.Stats.Deletions += deleted
+			commit.Stats.FilesChanged++
+			commit.Files = append(commit.Files, renamedPath(parts[2]))
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// renamedPath returns the new path of a --numstat rename such as
+// "old => new" or "dir/{old => new}/file", or path itself otherwise
+func renamedPath(path string) string {
+	if !strings.Contains(path, " => ") {
+		return path
+	}
+
+	start, end := strings.Index(path, "{"), strings.Index(path, "}")
+	if start >= 0 && end > start {
+		_, renamed, _ := strings.Cut(path[start+1:end], " => ")
+		joined := path[:start] + renamed + path[end+1:]
+		// "dir/{ => sub}/file" leaves a double slash when one side is empty
+		return strings.ReplaceAll(strings.TrimPrefix(joined, "/"), "//", "/")
+	}
+
+	_, renamed, _ := strings.Cut(path, " => ")
+	return renamed
+}
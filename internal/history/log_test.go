@@ -0,0 +1,81 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogCommits(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\nb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "a.txt")
+	runGit(t, repo, "commit", "-q", "-m", "feat: first\n\n- add a.txt")
+
+	if err := os.MkdirAll(filepath.Join(repo, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "mv", "a.txt", "docs/a.txt")
+	if err := os.WriteFile(filepath.Join(repo, "b.txt"), []byte("b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "b.txt")
+	runGit(t, repo, "commit", "-q", "-m", "chore: move a.txt")
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	commits, err := LogCommits(0)
+	if err != nil {
+		t.Fatalf("LogCommits() error = %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("LogCommits() returned %d commits, want 2", len(commits))
+	}
+
+	latest, first := commits[0], commits[1]
+	if latest.Message != "chore: move a.txt" || first.Message != "feat: first" || first.Body != "- add a.txt" {
+		t.Errorf("messages = %q, %q (body %q)", latest.Message, first.Message, first.Body)
+	}
+	if first.Author != "NoIdea Test" || first.Email != "test@example.com" || first.Timestamp.IsZero() {
+		t.Errorf("author = %q <%s> at %v", first.Author, first.Email, first.Timestamp)
+	}
+	if first.Stats != (CommitStats{FilesChanged: 1, Insertions: 2}) {
+		t.Errorf("first commit stats = %+v", first.Stats)
+	}
+	if len(latest.Files) != 2 || latest.Files[0] != "b.txt" || latest.Files[1] != "docs/a.txt" {
+		t.Errorf("latest commit files = %v, want [b.txt docs/a.txt]", latest.Files)
+	}
+
+	if limited, err := LogCommits(1); err != nil || len(limited) != 1 || limited[0].Hash != latest.Hash {
+		t.Errorf("LogCommits(1) = %d commits, %v", len(limited), err)
+	}
+	if between, err := LogCommitsBetween(first.Hash, "HEAD", 0); err != nil || len(between) != 1 {
+		t.Errorf("LogCommitsBetween() = %d commits, %v", len(between), err)
+	}
+	if _, err := LogCommitsBetween("no-such-branch", "HEAD", 0); err == nil {
+		t.Error("LogCommitsBetween() should reject an unknown base")
+	}
+}
+
+func TestRenamedPath(t *testing.T) {
+	tests := map[string]string{
+		"a.txt":                 "a.txt",
+		"a.txt => b.txt":        "b.txt",
+		"src/{old => new}/f.go": "src/new/f.go",
+		"{ => docs}/a.txt":      "docs/a.txt",
+		"docs/{sub => }/a.txt":  "docs/a.txt",
+		"{a.txt => docs/a.txt}": "docs/a.txt",
+	}
+	for path, want := range tests {
+		if got := renamedPath(path); got != want {
+			t.Errorf("renamedPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
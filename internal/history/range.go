@@ -0,0 +1,111 @@
+package history
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// RangeChanges holds everything needed to describe a span of commits as one change
+type RangeChanges struct {
+	Base    string       // Full hash the range starts after
+	Head    string       // Full hash the range ends at
+	Diff    string       // Combined diff from Base to Head
+	Commits []CommitInfo // Commits in Base..Head, newest first
+}
+
+// Subjects returns the commit subjects in the range, oldest first
+func (r RangeChanges) Subjects() []string {
+	subjects := make([]string, 0, len(r.Commits))
+	for i := len(r.Commits) - 1; i >= 0; i-- {
+		subjects = append(subjects, r.Commits[i].Message)
+	}
+	return subjects
+}
+
+// MergeBase returns the best common ancestor of two revisions
+func MergeBase(a, b string) (string, error) {
+	cmd := exec.Command("git", "merge-base", a, b)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("'%s' and '%s' have no common ancestor", a, b)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CollectRange gathers the combined diff and the commits between base and head.
+// When fromMergeBase is set the range starts at the merge base of the two, which
+// matches what `git merge --squash` would bring in from a diverged branch.
+// diffArgs are extra git diff options such as --diff-algorithm=histogram.
+func (h *HistoryCollector) CollectRange(base, head string, fromMergeBase bool, diffArgs ...string) (RangeChanges, error) {
+	var changes RangeChanges
+
+	baseHash, err := ResolveCommit(base)
+	if err != nil {
+		return changes, err
+	}
+
+	headHash, err := ResolveCommit(head)
+	if err != nil {
+		return changes, err
+	}
+
+	if fromMergeBase {
+		baseHash, err = MergeBase(baseHash, headHash)
+		if err != nil {
+			return changes, err
+		}
+	}
+
+	changes.Base = baseHash
+	changes.Head = headHash
+
+	args := append([]string{"diff"}, diffArgs...)
+	diffCmd := exec.Command("git", append(args, baseHash, headHash)...)
+	diffOutput, err := diffCmd.Output()
+	if err != nil {
+		return changes, fmt.Errorf("failed to diff range: %w", err)
+	}
+	changes.Diff = string(diffOutput)
+
+	changes.Commits, err = h.GetCommitsBetween(baseHash, headHash, false)
+	if err != nil {
+		return changes, err
+	}
+
+	return changes, nil
+}
+
+// GetCommitsBetween retrieves the commits reachable from head but not from
+// base (git log base..head), i.e. the commits unique to a branch
+func (h *HistoryCollector) GetCommitsBetween(base, head string, includeDiff bool) ([]CommitInfo, error) {
+	for _, ref := range []string{base, head} {
+		if _, err := ResolveCommit(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := exec.Command("git", "log", "--format=%H", base+".."+head)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits in range: %w", err)
+	}
+
+	var commits []CommitInfo
+	for _, hash := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if hash == "" {
+			continue
+		}
+
+		commit, err := h.commitInfo(hash, includeDiff)
+		if err != nil {
+			continue
+		}
+		commits = append(commits, commit)
+	}
+
+	h.saveCache()
+
+	return commits, nil
+}
@@ -0,0 +1,65 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// commitFile writes a file in repo and commits it with message
+func commitFile(t *testing.T, repo, name, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo, name), []byte(message+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", name)
+	runGit(t, repo, "commit", "-q", "-m", message)
+}
+
+func TestGetCommitsBetween(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+	runGit(t, repo, "checkout", "-q", "-b", "main")
+	commitFile(t, repo, "base.txt", "chore: base")
+	runGit(t, repo, "checkout", "-q", "-b", "feature")
+	commitFile(t, repo, "login.go", "feat: add login")
+	commitFile(t, repo, "logout.go", "feat: add logout")
+	// Work that lands on main after the branch point isn't part of the branch
+	runGit(t, repo, "checkout", "-q", "main")
+	commitFile(t, repo, "hotfix.txt", "fix: hotfix on main")
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	h := &HistoryCollector{cacheDir: t.TempDir(), cached: make(map[string]CommitInfo)}
+
+	subjects := func(commits []CommitInfo) string {
+		var messages []string
+		for _, commit := range commits {
+			messages = append(messages, commit.Message)
+		}
+		return strings.Join(messages, ", ")
+	}
+
+	commits, err := h.GetCommitsBetween("main", "feature", false)
+	if err != nil {
+		t.Fatalf("GetCommitsBetween() error = %v", err)
+	}
+	if got := subjects(commits); got != "feat: add logout, feat: add login" {
+		t.Errorf("main..feature = %q, want only the branch's commits, newest first", got)
+	}
+
+	// The other direction lists what main has that the branch doesn't
+	commits, err = h.GetCommitsBetween("feature", "HEAD", false)
+	if err != nil || subjects(commits) != "fix: hotfix on main" {
+		t.Errorf("feature..HEAD = %q, %v", subjects(commits), err)
+	}
+
+	if _, err := h.GetCommitsBetween("missing", "feature", false); err == nil {
+		t.Error("an unknown base branch should be an error")
+	}
+}
@@ -0,0 +1,52 @@
+package history
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+)
+
+// revertedCommitRegex matches the line git revert adds to the body
+var revertedCommitRegex = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// Revert is a commit that undoes an earlier one
+type Revert struct {
+	Hash            string // The revert itself
+	RevertedHash    string // The commit it undoes, "" if the body doesn't say
+	RevertedSubject string // Subject of the undone commit, "" if unknown
+}
+
+// IsRevert reports whether a commit undoes an earlier one: git revert's
+// `Revert "<subject>"` or a conventional "revert:" subject
+func IsRevert(commit CommitInfo) bool {
+	subject := strings.SplitN(commit.Message, "\n", 2)[0]
+	return strings.HasPrefix(subject, "Revert ") || conventional.TypeOf(subject) == "revert"
+}
+
+// FindReverts lists the reverts among commits, newest first, and what each
+// one undid as far as its message tells. git revert's "This reverts commit
+// <hash>." line names the commit; other reverts may only quote its subject.
+func FindReverts(commits []CommitInfo) []Revert {
+	var reverts []Revert
+	for _, commit := range commits {
+		if !IsRevert(commit) {
+			continue
+		}
+
+		revert := Revert{Hash: commit.Hash}
+		if match := revertedCommitRegex.FindStringSubmatch(commit.Message + "\n" + commit.Body); match != nil {
+			revert.RevertedHash = match[1]
+		}
+
+		subject := strings.SplitN(commit.Message, "\n", 2)[0]
+		if quoted, ok := strings.CutPrefix(subject, "Revert \""); ok {
+			revert.RevertedSubject = strings.TrimSuffix(quoted, "\"")
+		} else if parsed, ok := conventional.Parse(subject); ok {
+			revert.RevertedSubject = parsed.Description
+		}
+
+		reverts = append(reverts, revert)
+	}
+	return reverts
+}
@@ -0,0 +1,25 @@
+package history
+
+import "testing"
+
+func TestFindReverts(t *testing.T) {
+	commits := []CommitInfo{
+		{Hash: "c3", Message: `Revert "feat(auth): add token refresh"`, Body: "This reverts commit 0123456789abcdef0123456789abcdef01234567."},
+		{Hash: "c2", Message: "revert(ui): drop the dark theme"},
+		{Hash: "c1", Message: "fix: handle reverted payments"},
+	}
+
+	reverts := FindReverts(commits)
+	if len(reverts) != 2 {
+		t.Fatalf("FindReverts() found %d reverts, want 2: %+v", len(reverts), reverts)
+	}
+
+	want := Revert{Hash: "c3", RevertedHash: "0123456789abcdef0123456789abcdef01234567", RevertedSubject: "feat(auth): add token refresh"}
+	if reverts[0] != want {
+		t.Errorf("FindReverts()[0] = %+v, want %+v", reverts[0], want)
+	}
+	want = Revert{Hash: "c2", RevertedSubject: "drop the dark theme"}
+	if reverts[1] != want {
+		t.Errorf("FindReverts()[1] = %+v, want %+v", reverts[1], want)
+	}
+}
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxStatsCacheEntries bounds how many computed summaries are kept on disk
+const maxStatsCacheEntries = 20
+
+// statsCacheVersion is part of every stats cache key. Bump it whenever
+// CalculateStats adds, removes or changes a stat, so stats cached by an older
+// version are recomputed instead of served without the change.
+const statsCacheVersion = 1
+
+// statsCacheEntry holds computed stats for one set of commits
+type statsCacheEntry struct {
+	Stats    map[string]interface{}
+	CachedAt time.Time
+}
+
+func init() {
+	// Register the concrete types stored in stats maps so gob can round-trip them
+	gob.Register(map[string]int{})
+	gob.Register(map[int]int{})
+}
+
+// statsCacheKey derives a cache key from statsCacheVersion and the set of commit hashes
+func statsCacheKey(commits []CommitInfo) string {
+	hashes := make([]string, 0, len(commits))
+	for _, c := range commits {
+		hashes = append(hashes, c.Hash)
+	}
+	sort.Strings(hashes)
+
+	sum := sha256.New()
+	fmt.Fprintf(sum, "v%d\n", statsCacheVersion)
+	for _, hash := range hashes {
+		sum.Write([]byte(hash))
+		sum.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// statsCacheFile returns the path of the computed stats cache
+func (h *HistoryCollector) statsCacheFile() string {
+	return filepath.Join(h.cacheDir, "stats_cache.gob")
+}
+
+// loadStatsCache reads all cached stats entries, returning an empty map on any error
+func (h *HistoryCollector) loadStatsCache() map[string]statsCacheEntry {
+	entries := make(map[string]statsCacheEntry)
+
+	file, err := os.Open(h.statsCacheFile())
+	if err != nil {
+		return entries
+	}
+	defer file.Close()
+
+	if err := gob.NewDecoder(file).Decode(&entries); err != nil {
+		// If cache is corrupted, start fresh
+		return make(map[string]statsCacheEntry)
+	}
+
+	return entries
+}
+
+// GetCachedStats returns previously computed stats for exactly this set of commits.
+// Any new commit changes the key, so stale stats are never returned.
+func (h *HistoryCollector) GetCachedStats(commits []CommitInfo) (map[string]interface{}, bool) {
+	if len(commits) == 0 {
+		return nil, false
+	}
+
+	entry, found := h.loadStatsCache()[statsCacheKey(commits)]
+	if !found || entry.Stats == nil {
+		return nil, false
+	}
+
+	return entry.Stats, true
+}
+
+// CacheStats stores computed stats for a set of commits, keeping only the most recent entries
+func (h *HistoryCollector) CacheStats(commits []CommitInfo, stats map[string]interface{}) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	unlock, err := lockCacheFile(h.statsCacheFile())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries := h.loadStatsCache()
+	entries[statsCacheKey(commits)] = statsCacheEntry{
+		Stats:    stats,
+		CachedAt: time.Now(),
+	}
+
+	// Drop the oldest entries once we're over the limit
+	if len(entries) > maxStatsCacheEntries {
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return entries[keys[i]].CachedAt.After(entries[keys[j]].CachedAt)
+		})
+		for _, key := range keys[maxStatsCacheEntries:] {
+			delete(entries, key)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entries); err != nil {
+		return fmt.Errorf("failed to encode stats cache: %w", err)
+	}
+
+	if err := writeFileAtomic(h.statsCacheFile(), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write stats cache: %w", err)
+	}
+
+	return nil
+}
@@ -35,6 +35,16 @@ func GetLastNCommits(count int, includeDiff bool) ([]CommitInfo, error) {
 	return collector.GetCommitHistory(filter)
 }
 
+// GetCommitByHash retrieves a single commit, validating that the hash resolves
+func GetCommitByHash(hash string, includeDiff bool) (CommitInfo, error) {
+	collector, err := NewHistoryCollector()
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("failed to create history collector: %w", err)
+	}
+
+	return collector.GetCommit(hash, includeDiff)
+}
+
 // FormatCommitSummary creates a human-readable summary of a commit
 func FormatCommitSummary(commit CommitInfo) string {
 	timeStr := commit.Timestamp.Format("2006-01-02 15:04:05")
@@ -53,6 +63,11 @@ func FormatCommitSummary(commit CommitInfo) string {
 
 // FormatCommitList creates a concise summary list of commits
 func FormatCommitList(commits []CommitInfo) string {
+	return FormatCommitListWithDateLayout(commits, "2006-01-02")
+}
+
+// FormatCommitListWithDateLayout is FormatCommitList with a custom date layout
+func FormatCommitListWithDateLayout(commits []CommitInfo, dateLayout string) string {
 	if len(commits) == 0 {
 		return "No commits found."
 	}
@@ -64,7 +79,7 @@ func FormatCommitList(commits []CommitInfo) string {
 			shortHash = shortHash[:8]
 		}
 
-		date := commit.Timestamp.Format("2006-01-02")
+		date := commit.Timestamp.Format(dateLayout)
 		time := commit.Timestamp.Format("15:04:05")
 
 		// Truncate message if too long
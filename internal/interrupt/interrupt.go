@@ -0,0 +1,72 @@
+// Package interrupt provides a process-wide context that is cancelled when the
+// user presses Ctrl-C or the process receives SIGTERM
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// ExitCode is the conventional exit status for a process stopped by SIGINT
+const ExitCode = 130
+
+// gracePeriod is how long in-flight work gets to wind down after the first
+// signal before the process exits anyway. A second signal exits immediately.
+const gracePeriod = 2 * time.Second
+
+var (
+	ctx, cancel = context.WithCancel(context.Background())
+	once        sync.Once
+	interrupted atomic.Bool
+)
+
+// Context returns the shared context that is cancelled on interrupt
+func Context() context.Context {
+	return ctx
+}
+
+// Interrupted reports whether an interrupt signal has been received
+func Interrupted() bool {
+	return interrupted.Load()
+}
+
+// Notify installs the signal handler. It is safe to call more than once.
+func Notify() {
+	once.Do(func() {
+		signals := make(chan os.Signal, 2)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+		go func() {
+			<-signals
+			stop()
+
+			select {
+			case <-signals:
+			case <-time.After(gracePeriod):
+			}
+			os.Exit(ExitCode)
+		}()
+	})
+}
+
+// stop marks the process as interrupted and cancels the shared context
+func stop() {
+	interrupted.Store(true)
+	cancel()
+	ClearLine()
+}
+
+// ClearLine erases the current terminal line, e.g. a half-drawn spinner
+func ClearLine() {
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Print("\r\033[K")
+	}
+}
@@ -0,0 +1,28 @@
+package interrupt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopCancelsContext(t *testing.T) {
+	if Interrupted() {
+		t.Fatal("Interrupted() before any signal")
+	}
+	select {
+	case <-Context().Done():
+		t.Fatal("Context() is done before any signal")
+	default:
+	}
+
+	stop()
+
+	if !Interrupted() {
+		t.Error("Interrupted() = false after stop")
+	}
+	select {
+	case <-Context().Done():
+	case <-time.After(time.Second):
+		t.Error("Context() was not cancelled")
+	}
+}
@@ -0,0 +1,72 @@
+//go:build unix
+
+package interrupt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// helperEnv makes the test binary run TestSignalHelper as the child process
+// that receives the signals
+const helperEnv = "NOIDEA_INTERRUPT_HELPER_SIGNALS"
+
+// TestSignalHelper is not a real test: it installs the handler, interrupts
+// itself and reports what it saw, then waits to be exited by the handler
+func TestSignalHelper(t *testing.T) {
+	count, err := strconv.Atoi(os.Getenv(helperEnv))
+	if err != nil {
+		t.Skip("only runs as a child of TestSignalExit")
+	}
+
+	Notify()
+	syscall.Kill(os.Getpid(), syscall.SIGINT)
+	<-Context().Done()
+	fmt.Println("interrupted:", Interrupted())
+
+	for i := 1; i < count; i++ {
+		syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	}
+	time.Sleep(10 * gracePeriod)
+	fmt.Println("still running")
+}
+
+func TestSignalExit(t *testing.T) {
+	tests := []struct {
+		name    string
+		signals int
+		minWait time.Duration // Shortest time the child should take to exit
+		maxWait time.Duration
+	}{
+		{"one signal waits for the grace period", 1, gracePeriod, 3 * gracePeriod},
+		{"second signal exits at once", 2, 0, gracePeriod / 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestSignalHelper$")
+			cmd.Env = append(os.Environ(), helperEnv+"="+strconv.Itoa(tt.signals))
+
+			start := time.Now()
+			output, err := cmd.Output()
+			elapsed := time.Since(start)
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != ExitCode {
+				t.Fatalf("child exited with %v, want status %d\n%s", err, ExitCode, output)
+			}
+			if string(output) != "interrupted: true\n" {
+				t.Errorf("child output = %q, want %q", output, "interrupted: true\n")
+			}
+			if elapsed < tt.minWait || elapsed > tt.maxWait {
+				t.Errorf("child exited after %v, want between %v and %v", elapsed, tt.minWait, tt.maxWait)
+			}
+		})
+	}
+}
@@ -5,30 +5,29 @@ package moai
 import (
 	"math/rand"
 	"strings"
-	"time"
 
 	"github.com/fatih/color"
 )
 
+// Random picks use the math/rand top-level functions, which are seeded
+// automatically and safe for concurrent use, e.g. by noidea serve.
 var (
-	// Random number generator with time-based seed
-	rng = rand.New(rand.NewSource(time.Now().UnixNano()))
-
-	// moaiFaces is a collection of Moai ASCII faces with different expressions
-	moaiFaces = []string{
-		"🗿  (ಠ_ಠ)",         // Disapproving
-		"🗿  (¬_¬)",         // Suspicious
-		"🗿  (⊙_⊙)",         // Surprised
-		"🗿  (¯\\_(:/)_/¯)", // Confused shrug
-		"🗿  (╯°□°）╯",       // Angry table flip
-		"🗿  (◉_◉)",         // Surprised or shocked
-		"🗿  (⊙﹏⊙)",         // Worried
-		"🗿  (⚆_⚆)",         // Staring
-		"🗿  ( ͡° ͜ʖ ͡°)",   // Smug
-		"🗿  (ᵔᴥᵔ)",         // Happy
-		"🗿  (•‿•)",         // Pleased
-		"🗿  (≧◡≦)",         // Very happy
-		"🗿  (─‿‿─)",        // Satisfied
+	// moaiFaces is a collection of Moai ASCII faces with different
+	// expressions, grouped by the mood they show
+	moaiFaces = []moodFace{
+		{"🗿  (ಠ_ಠ)", MoodStern},           // Disapproving
+		{"🗿  (¬_¬)", MoodStern},           // Suspicious
+		{"🗿  (⊙_⊙)", MoodNeutral},         // Surprised
+		{"🗿  (¯\\_(:/)_/¯)", MoodNeutral}, // Confused shrug
+		{"🗿  (╯°□°）╯", MoodStern},         // Angry table flip
+		{"🗿  (◉_◉)", MoodNeutral},         // Surprised or shocked
+		{"🗿  (⊙﹏⊙)", MoodNeutral},         // Worried
+		{"🗿  (⚆_⚆)", MoodNeutral},         // Staring
+		{"🗿  ( ͡° ͜ʖ ͡°)", MoodNeutral},   // Smug
+		{"🗿  (ᵔᴥᵔ)", MoodHappy},           // Happy
+		{"🗿  (•‿•)", MoodHappy},           // Pleased
+		{"🗿  (≧◡≦)", MoodHappy},           // Very happy
+		{"🗿  (─‿‿─)", MoodHappy},          // Satisfied
 	}
 
 	// Feedback templates based on commit message patterns
@@ -85,9 +84,26 @@ var (
 	}
 )
 
+// moodFace is a face in the inventory with the mood it expresses
+type moodFace struct {
+	face string
+	mood Mood
+}
+
+// Faces returns the faces for a mood, or every face when mood is empty
+func Faces(mood Mood) []string {
+	var faces []string
+	for _, f := range moaiFaces {
+		if mood == "" || f.mood == mood {
+			faces = append(faces, f.face)
+		}
+	}
+	return faces
+}
+
 // GetRandomFace returns a random Moai face
 func GetRandomFace() string {
-	return moaiFaces[rng.Intn(len(moaiFaces))]
+	return moaiFaces[rand.Intn(len(moaiFaces))].face
 }
 
 // GetRandomFeedback generates feedback based on the commit message
@@ -97,10 +113,10 @@ func GetRandomFeedback(commitMsg string) string {
 	// Check for specific patterns in the commit message
 	for pattern, templates := range feedbackTemplates {
 		if strings.Contains(commitMsg, pattern) {
-			return color.YellowString(templates[rng.Intn(len(templates))])
+			return color.YellowString(templates[rand.Intn(len(templates))])
 		}
 	}
 
 	// If no specific pattern matched, return general feedback
-	return color.YellowString(generalFeedback[rng.Intn(len(generalFeedback))])
+	return color.YellowString(generalFeedback[rand.Intn(len(generalFeedback))])
 }
@@ -102,7 +102,7 @@ func TestMoaiFaces(t *testing.T) {
 	}
 
 	// Check that all faces are non-empty and contain the Moai emoji
-	for i, face := range moaiFaces {
+	for i, face := range Faces("") {
 		if face == "" {
 			t.Errorf("Empty face at index %d", i)
 		}
@@ -112,3 +112,48 @@ func TestMoaiFaces(t *testing.T) {
 		}
 	}
 }
+
+func TestClassifyMood(t *testing.T) {
+	tests := []struct {
+		feedback string
+		want     Mood
+	}{
+		{"Great work, this is a clean and focused change!", MoodHappy},
+		{"Another vague 'fix'? Seriously, why?", MoodStern},
+		{"I'm just a Moai standing here.", MoodNeutral},
+		{"\x1b[33mNice refactor.\x1b[0m", MoodHappy},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyMood(tt.feedback); got != tt.want {
+			t.Errorf("ClassifyMood(%q) = %s, want %s", tt.feedback, got, tt.want)
+		}
+	}
+}
+
+func TestFaceModes(t *testing.T) {
+	if got := len(Faces("")); got != len(moaiFaces) {
+		t.Errorf("Faces(\"\") returned %d faces, want all %d", got, len(moaiFaces))
+	}
+	for _, mood := range []Mood{MoodHappy, MoodNeutral, MoodStern} {
+		if len(Faces(mood)) == 0 {
+			t.Errorf("no faces for mood %s", mood)
+		}
+	}
+
+	happy := strings.Join(Faces(MoodHappy), "\n")
+	for i := 0; i < 20; i++ {
+		if face := Face("mood", "Excellent, well done!"); !strings.Contains(happy, face) {
+			t.Fatalf("mood mode picked %q for praise", face)
+		}
+	}
+
+	// Sequential mode continues where the last run stopped
+	t.Setenv("HOME", t.TempDir())
+	all := Faces("")
+	for i := 0; i < len(all)+1; i++ {
+		if got, want := Face("sequential", ""), all[i%len(all)]; got != want {
+			t.Fatalf("sequential face %d = %q, want %q", i, got, want)
+		}
+	}
+}
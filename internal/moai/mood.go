@@ -0,0 +1,114 @@
+package moai
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Mood is the tone a Moai face expresses
+type Mood string
+
+// Moods faces are grouped by
+const (
+	MoodHappy   Mood = "happy"
+	MoodNeutral Mood = "neutral"
+	MoodStern   Mood = "stern"
+)
+
+var (
+	// Words that make feedback read as praise or as criticism
+	positiveWords = map[string]bool{
+		"great": true, "good": true, "nice": true, "excellent": true, "clean": true,
+		"clear": true, "solid": true, "love": true, "awesome": true, "impressive": true,
+		"perfect": true, "neat": true, "elegant": true, "bravo": true, "well": true,
+		"thanks": true, "thank": true, "tidy": true, "brilliant": true, "congrats": true,
+		"congratulations": true, "proud": true, "progress": true, "legendary": true,
+	}
+	negativeWords = map[string]bool{
+		"bad": true, "terrible": true, "vague": true, "unclear": true, "mess": true,
+		"messy": true, "lazy": true, "sloppy": true, "broken": true, "worse": true,
+		"worst": true, "ugh": true, "seriously": true, "curse": true, "blame": true,
+		"troublemaker": true, "questionable": true, "useless": true, "again": true,
+		"another": true, "yikes": true, "oops": true, "disappointing": true, "why": true,
+	}
+
+	// ansiPattern matches terminal color codes, which local feedback carries
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+	// sequentialMu guards the position of the sequential faces mode
+	sequentialMu sync.Mutex
+)
+
+// ClassifyMood guesses the tone of feedback from the praise and criticism
+// words it contains
+func ClassifyMood(feedback string) Mood {
+	words := strings.FieldsFunc(strings.ToLower(ansiPattern.ReplaceAllString(feedback, "")), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	score := 0
+	for _, word := range words {
+		switch {
+		case positiveWords[word]:
+			score++
+		case negativeWords[word]:
+			score--
+		}
+	}
+
+	switch {
+	case score > 0:
+		return MoodHappy
+	case score < 0:
+		return MoodStern
+	default:
+		return MoodNeutral
+	}
+}
+
+// Face picks a Moai face for feedback using a faces mode from the config:
+// "mood" matches the face to the feedback's tone, "sequential" cycles through
+// every face across runs, and anything else picks at random
+func Face(mode, feedback string) string {
+	switch mode {
+	case "mood":
+		faces := Faces(ClassifyMood(feedback))
+		return faces[rand.Intn(len(faces))]
+	case "sequential":
+		return nextFace()
+	default:
+		return GetRandomFace()
+	}
+}
+
+// nextFace returns the face after the one shown last, remembering the
+// position in ~/.noidea/face_index so the cycle continues across commands
+func nextFace() string {
+	sequentialMu.Lock()
+	defer sequentialMu.Unlock()
+
+	var path string
+	index := 0
+	if home, err := os.UserHomeDir(); err == nil {
+		path = filepath.Join(home, ".noidea", "face_index")
+		if data, err := os.ReadFile(path); err == nil {
+			index, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		}
+	}
+	if index < 0 || index >= len(moaiFaces) {
+		index = 0
+	}
+
+	// Failing to save only restarts the cycle next time
+	if path != "" && os.MkdirAll(filepath.Dir(path), 0755) == nil {
+		_ = os.WriteFile(path, []byte(strconv.Itoa((index+1)%len(moaiFaces))), 0644)
+	}
+
+	return moaiFaces[index].face
+}
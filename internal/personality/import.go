@@ -0,0 +1,223 @@
+package personality
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ImportResult describes the outcome of a bulk personality import
+type ImportResult struct {
+	Imported  []string          // Personalities written to the target file
+	Conflicts []string          // Personalities skipped because the name already exists
+	Invalid   map[string]string // Personality or file name mapped to the validation error
+}
+
+// ImportPersonalities loads every .toml/.json personality file in dir, validates
+// each personality and merges them into the personality file at targetPath.
+// Existing personalities with the same name are only replaced when overwrite is set.
+func ImportPersonalities(dir, targetPath string, overwrite bool) (ImportResult, error) {
+	result := ImportResult{Invalid: make(map[string]string)}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return result, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	// Load the target file as-is (without built-in defaults) so we only write user personalities
+	target := PersonalityConfig{Personalities: make(map[string]Personality)}
+	if _, err := os.Stat(targetPath); err == nil {
+		target, err = decodePersonalityFile(targetPath)
+		if err != nil {
+			return result, err
+		}
+		if target.Personalities == nil {
+			target.Personalities = make(map[string]Personality)
+		}
+	}
+
+	builtIn := DefaultPersonalities().Personalities
+	added := make(map[string]Personality)
+	replaced := false
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".toml" && ext != ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		personalities, err := readImportFile(path)
+		if err != nil {
+			result.Invalid[entry.Name()] = err.Error()
+			continue
+		}
+
+		// Sort names so conflicts and results are reported deterministically
+		names := make([]string, 0, len(personalities))
+		for name := range personalities {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			p := personalities[name]
+			if err := ValidatePersonality(p); err != nil {
+				result.Invalid[name] = err.Error()
+				continue
+			}
+
+			_, inTarget := target.Personalities[name]
+			_, isBuiltIn := builtIn[name]
+			if (inTarget || isBuiltIn) && !overwrite {
+				result.Conflicts = append(result.Conflicts, name)
+				continue
+			}
+
+			target.Personalities[name] = p
+			added[name] = p
+			replaced = replaced || inTarget
+			result.Imported = append(result.Imported, name)
+		}
+	}
+
+	if len(result.Imported) == 0 {
+		return result, nil
+	}
+
+	if err := saveImported(targetPath, target, added, replaced); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// saveImported writes the imported personalities to targetPath. New ones are
+// added after the contents of an existing TOML file, which keeps its comments
+// and layout; replacing personalities in the file rewrites all of it.
+func saveImported(targetPath string, target PersonalityConfig, added map[string]Personality, replaced bool) error {
+	if !replaced && !strings.EqualFold(filepath.Ext(targetPath), ".json") {
+		if existing, err := os.ReadFile(targetPath); err == nil {
+			if data, ok := appendTOMLPersonalities(existing, added); ok {
+				return writeFileAtomic(targetPath, data, 0644)
+			}
+		}
+	}
+	return SavePersonalities(targetPath, target)
+}
+
+// appendTOMLPersonalities returns existing TOML with added appended as
+// [personalities.<name>] tables, or false when the result wouldn't decode to
+// every personality, e.g. because the file uses an inline personalities table
+func appendTOMLPersonalities(existing []byte, added map[string]Personality) ([]byte, bool) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"personalities": added}); err != nil {
+		return nil, false
+	}
+
+	// The file already has the parent table, and TOML can't define it twice
+	tables := strings.TrimPrefix(buf.String(), "[personalities]\n")
+
+	content := strings.TrimRight(string(existing), "\n") + "\n\n" + tables
+	var check PersonalityConfig
+	if _, err := toml.Decode(content, &check); err != nil {
+		return nil, false
+	}
+	for name := range added {
+		if _, ok := check.Personalities[name]; !ok {
+			return nil, false
+		}
+	}
+	return []byte(content), true
+}
+
+// readImportFile reads a personality file that contains either a full
+// personality config or a single personality named after the file
+func readImportFile(path string) (map[string]Personality, error) {
+	fileConfig, err := decodePersonalityFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fileConfig.Personalities) > 0 {
+		return fileConfig.Personalities, nil
+	}
+
+	// Fall back to a single top-level personality definition
+	var single Personality
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read personality file: %w", err)
+		}
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil, fmt.Errorf("failed to decode personality file: %w", err)
+		}
+	} else if _, err := toml.DecodeFile(path, &single); err != nil {
+		return nil, fmt.Errorf("failed to decode personality file: %w", err)
+	}
+
+	if single.Name == "" && single.SystemPrompt == "" {
+		return nil, fmt.Errorf("no personalities found")
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return map[string]Personality{name: single}, nil
+}
+
+// SavePersonalities writes a personality config to path, as JSON or TOML based on its extension
+func SavePersonalities(path string, config PersonalityConfig) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create personality directory: %w", err)
+	}
+
+	// Encode first, so a failure leaves the existing file untouched
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		encoded, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode personalities: %w", err)
+		}
+		data = encoded
+	} else {
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+			return fmt.Errorf("failed to encode personalities: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write personality file: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never see a partly written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
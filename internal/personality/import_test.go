@@ -0,0 +1,170 @@
+package personality
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const importedTOML = `[personalities.pirate]
+name = "Pirate"
+system_prompt = "You are a pirate."
+user_prompt_format = "Commit: {{.Message}}"
+temperature = 0.5
+`
+
+const existingTOML = `# Team personalities, reviewed by the platform team
+default = "calm"
+
+[personalities.calm]
+name = "Calm"
+system_prompt = "You are calm."
+user_prompt_format = "Commit: {{.Message}}"
+temperature = 0.3
+`
+
+// writeImportDir creates a directory with the given personality files
+func writeImportDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestImportPersonalities(t *testing.T) {
+	for _, ext := range []string{".toml", ".json"} {
+		t.Run(ext, func(t *testing.T) {
+			dir := writeImportDir(t, map[string]string{
+				"pirate.toml": importedTOML,
+				"robot.json":  `{"name": "Robot", "system_prompt": "You are a robot.", "user_prompt_format": "{{.Message}}", "temperature": 0.2}`,
+				"notes.txt":   "not a personality",
+			})
+			target := filepath.Join(t.TempDir(), "personalities"+ext)
+
+			result, err := ImportPersonalities(dir, target, false)
+			if err != nil {
+				t.Fatalf("ImportPersonalities() error = %v", err)
+			}
+			if strings.Join(result.Imported, ",") != "pirate,robot" || len(result.Conflicts) != 0 || len(result.Invalid) != 0 {
+				t.Fatalf("result = %+v", result)
+			}
+
+			saved, err := decodePersonalityFile(target)
+			if err != nil {
+				t.Fatalf("the target file doesn't decode: %v", err)
+			}
+			if saved.Personalities["pirate"].Name != "Pirate" || saved.Personalities["robot"].Name != "Robot" {
+				t.Errorf("saved personalities = %+v", saved.Personalities)
+			}
+
+			// Importing the same files again conflicts with what was saved
+			result, err = ImportPersonalities(dir, target, false)
+			if err != nil {
+				t.Fatalf("second ImportPersonalities() error = %v", err)
+			}
+			if len(result.Imported) != 0 || strings.Join(result.Conflicts, ",") != "pirate,robot" {
+				t.Errorf("second result = %+v, want two conflicts", result)
+			}
+		})
+	}
+}
+
+func TestImportPersonalitiesConflicts(t *testing.T) {
+	updated := strings.Replace(existingTOML, `name = "Calm"`, `name = "Calmer"`, 1)
+	updated = strings.Replace(updated, "default = \"calm\"\n", "", 1)
+	dir := writeImportDir(t, map[string]string{
+		"calm.toml": updated,
+		// Built-in personalities can't be replaced by accident either
+		"builtin.toml": strings.Replace(importedTOML, "pirate", "snarky_reviewer", 1),
+	})
+	target := filepath.Join(t.TempDir(), "personalities.toml")
+	if err := os.WriteFile(target, []byte(existingTOML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ImportPersonalities(dir, target, false)
+	if err != nil {
+		t.Fatalf("ImportPersonalities() error = %v", err)
+	}
+	if len(result.Imported) != 0 || strings.Join(result.Conflicts, ",") != "snarky_reviewer,calm" {
+		t.Errorf("result = %+v, want both names as conflicts", result)
+	}
+	if data, _ := os.ReadFile(target); string(data) != existingTOML {
+		t.Errorf("conflicts should leave the target file unchanged:\n%s", data)
+	}
+
+	result, err = ImportPersonalities(dir, target, true)
+	if err != nil {
+		t.Fatalf("ImportPersonalities(overwrite) error = %v", err)
+	}
+	if len(result.Imported) != 2 || len(result.Conflicts) != 0 {
+		t.Errorf("overwrite result = %+v, want both imported", result)
+	}
+	saved, err := decodePersonalityFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Personalities["calm"].Name != "Calmer" || saved.Default != "calm" {
+		t.Errorf("after --overwrite: default %q, calm = %+v", saved.Default, saved.Personalities["calm"])
+	}
+}
+
+func TestImportPersonalitiesInvalid(t *testing.T) {
+	dir := writeImportDir(t, map[string]string{
+		"broken.toml":   "[personalities.x\nname = ",
+		"noprompt.toml": strings.Replace(importedTOML, `system_prompt = "You are a pirate."`, "", 1),
+		"empty.json":    `{}`,
+	})
+	target := filepath.Join(t.TempDir(), "personalities.toml")
+
+	result, err := ImportPersonalities(dir, target, false)
+	if err != nil {
+		t.Fatalf("ImportPersonalities() error = %v", err)
+	}
+	for _, name := range []string{"broken.toml", "pirate", "empty.json"} {
+		if result.Invalid[name] == "" {
+			t.Errorf("%s should be reported as invalid, got %+v", name, result.Invalid)
+		}
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("nothing valid was imported, so no file should be written")
+	}
+}
+
+func TestImportPersonalitiesKeepsComments(t *testing.T) {
+	dir := writeImportDir(t, map[string]string{"pirate.toml": importedTOML})
+	target := filepath.Join(t.TempDir(), "personalities.toml")
+	if err := os.WriteFile(target, []byte(existingTOML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ImportPersonalities(dir, target, false); err != nil {
+		t.Fatalf("ImportPersonalities() error = %v", err)
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), existingTOML) {
+		t.Errorf("new personalities should be added after the existing file:\n%s", data)
+	}
+	saved, err := decodePersonalityFile(target)
+	if err != nil {
+		t.Fatalf("the target file doesn't decode: %v\n%s", err, data)
+	}
+	if saved.Default != "calm" || saved.Personalities["calm"].Name != "Calm" || saved.Personalities["pirate"].Name != "Pirate" {
+		t.Errorf("saved = %+v", saved)
+	}
+
+	// No temporary files are left next to the target
+	entries, _ := os.ReadDir(filepath.Dir(target))
+	if len(entries) != 1 {
+		t.Errorf("files next to the target = %d, want 1", len(entries))
+	}
+}
@@ -2,10 +2,12 @@ package personality
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
 	"time"
 
@@ -25,18 +27,18 @@ type Context struct {
 
 // Personality defines a configurable AI personality
 type Personality struct {
-	Name             string  `toml:"name"`
-	Description      string  `toml:"description"`
-	SystemPrompt     string  `toml:"system_prompt"`
-	UserPromptFormat string  `toml:"user_prompt_format"`
-	MaxTokens        int     `toml:"max_tokens"`
-	Temperature      float64 `toml:"temperature"`
+	Name             string  `toml:"name" json:"name"`
+	Description      string  `toml:"description" json:"description"`
+	SystemPrompt     string  `toml:"system_prompt" json:"system_prompt"`
+	UserPromptFormat string  `toml:"user_prompt_format" json:"user_prompt_format"`
+	MaxTokens        int     `toml:"max_tokens" json:"max_tokens"`
+	Temperature      float64 `toml:"temperature" json:"temperature"`
 }
 
 // PersonalityConfig holds multiple personality configurations
 type PersonalityConfig struct {
-	Default       string                 `toml:"default"`
-	Personalities map[string]Personality `toml:"personalities"`
+	Default       string                 `toml:"default" json:"default,omitempty"`
+	Personalities map[string]Personality `toml:"personalities" json:"personalities"`
 }
 
 // DefaultPersonalities returns the built-in personality configurations
@@ -144,11 +146,10 @@ func LoadPersonalities(path string) (PersonalityConfig, error) {
 		return config, fmt.Errorf("personality file not found: %s", path)
 	}
 
-	// Load and parse TOML file
-	var fileConfig PersonalityConfig
-	_, err := toml.DecodeFile(path, &fileConfig)
+	// Load and parse the file
+	fileConfig, err := decodePersonalityFile(path)
 	if err != nil {
-		return config, fmt.Errorf("failed to decode personality file: %w", err)
+		return config, err
 	}
 
 	// Merge with defaults - any custom personalities override defaults
@@ -167,12 +168,39 @@ func LoadPersonalities(path string) (PersonalityConfig, error) {
 	return config, nil
 }
 
-// GetPersonality returns a personality by name, falling back to default if not found
+// decodePersonalityFile parses a personality file as JSON or TOML based on its extension
+func decodePersonalityFile(path string) (PersonalityConfig, error) {
+	var fileConfig PersonalityConfig
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fileConfig, fmt.Errorf("failed to read personality file: %w", err)
+		}
+		if err := json.Unmarshal(data, &fileConfig); err == nil {
+			return fileConfig, nil
+		}
+		// Older setups stored TOML under a .json name, so fall through
+		fileConfig = PersonalityConfig{}
+	}
+
+	if _, err := toml.DecodeFile(path, &fileConfig); err != nil {
+		return fileConfig, fmt.Errorf("failed to decode personality file: %w", err)
+	}
+
+	return fileConfig, nil
+}
+
+// GetPersonality returns a personality by name, falling back to default if
+// not found. "random" picks any of the loaded personalities.
 func (pc PersonalityConfig) GetPersonality(name string) (Personality, error) {
 	// If name is empty, use default
 	if name == "" {
 		name = pc.Default
 	}
+	if IsRandom(name) {
+		name = pc.RandomName(nil)
+	}
 
 	personality, exists := pc.Personalities[name]
 	if !exists {
@@ -0,0 +1,54 @@
+package personality
+
+import (
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Random is the personality name that picks one of the loaded personalities
+// at random each run
+const Random = "random"
+
+// rng picks random personalities. NOIDEA_SEED makes the picks repeatable.
+var rng = newRand()
+
+// newRand seeds from NOIDEA_SEED when it is set, otherwise from the clock
+func newRand() *rand.Rand {
+	if seed, err := strconv.ParseInt(os.Getenv("NOIDEA_SEED"), 10, 64); err == nil {
+		return rand.New(rand.NewSource(seed))
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// Seed makes the following random picks repeatable
+func Seed(seed int64) {
+	rng = rand.New(rand.NewSource(seed))
+}
+
+// IsRandom reports whether name asks for a random personality
+func IsRandom(name string) bool {
+	return strings.EqualFold(strings.TrimSpace(name), Random)
+}
+
+// RandomName picks a random personality name from the set, considering only
+// the names allow accepts when it is not nil. It returns "" when no
+// personality qualifies.
+func (pc PersonalityConfig) RandomName(allow func(name string) bool) string {
+	var names []string
+	for name := range pc.Personalities {
+		if allow == nil || allow(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+
+	// Map order is random on its own, so sort to keep seeded picks stable
+	sort.Strings(names)
+	return names[rng.Intn(len(names))]
+}
@@ -0,0 +1,31 @@
+package personality
+
+import "testing"
+
+func TestRandomPersonality(t *testing.T) {
+	personalities := DefaultPersonalities()
+
+	Seed(42)
+	first := personalities.RandomName(nil)
+	Seed(42)
+	if again := personalities.RandomName(nil); again != first {
+		t.Errorf("seeded picks differ: %q then %q", first, again)
+	}
+	if _, ok := personalities.Personalities[first]; !ok {
+		t.Errorf("RandomName() = %q, not a loaded personality", first)
+	}
+
+	onlySnarky := func(name string) bool { return name == "snarky_reviewer" }
+	if got := personalities.RandomName(onlySnarky); got != "snarky_reviewer" {
+		t.Errorf("RandomName(filter) = %q, want snarky_reviewer", got)
+	}
+	if got := personalities.RandomName(func(string) bool { return false }); got != "" {
+		t.Errorf("RandomName() with nothing allowed = %q, want empty", got)
+	}
+
+	// "random" is never looked up as a literal name
+	p, err := personalities.GetPersonality("Random")
+	if err != nil || p.Name == "" {
+		t.Errorf("GetPersonality(random) = %+v, %v", p, err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	openai "github.com/sashabaranov/go-openai"
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/interrupt"
 )
 
 // DirectLLMClient provides direct access to LLM APIs for release notes generation
@@ -108,7 +109,7 @@ func (c *DirectLLMClient) GenerateReleaseNotes(
 	prompt string,
 	maxAttempts int,
 ) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(interrupt.Context(), 60*time.Second)
 	defer cancel()
 
 	var generationErr error
@@ -0,0 +1,38 @@
+package releaseai
+
+import "strings"
+
+// modelPrices maps model name prefixes to approximate list prices in US
+// dollars per million tokens. Only total token estimates are known, so each
+// price blends input and output at the 3:1 ratio typical of release notes.
+// The longest matching prefix wins, so "gpt-4o-mini" is not priced as "gpt-4o".
+// Providers change prices, so these are only good for rough estimates.
+var modelPrices = map[string]float64{
+	"gpt-3.5-turbo":     0.75,
+	"gpt-4":             37.5,
+	"gpt-4-turbo":       15,
+	"gpt-4o":            4.38,
+	"gpt-4o-mini":       0.26,
+	"gpt-4.1":           3.5,
+	"grok-2":            4,
+	"grok-3":            6,
+	"grok-beta":         7.5,
+	"deepseek-chat":     0.48,
+	"deepseek-reasoner": 0.96,
+}
+
+// EstimateCost returns the approximate cost in US dollars of tokens used with
+// model, and false when no price is known for the model
+func EstimateCost(model string, tokens int) (float64, bool) {
+	model = strings.ToLower(model)
+	price, matched := 0.0, ""
+	for prefix, perMillion := range modelPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(matched) {
+			price, matched = perMillion, prefix
+		}
+	}
+	if matched == "" {
+		return 0, false
+	}
+	return price * float64(tokens) / 1e6, true
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/personality"
+	"github.com/AccursedGalaxy/noidea/internal/truncate"
 )
 
 // ReleaseNotesGenerator handles creating AI-enhanced release notes
@@ -13,6 +15,29 @@ type ReleaseNotesGenerator struct {
 	// Using direct client instead of feedback engine to avoid pattern confusion
 	directClient *DirectLLMClient
 	config       config.Config
+	systemPrompt string
+
+	// estimatedTokens is a running estimate of prompt and response tokens
+	estimatedTokens int
+
+	// rawResponses keeps every model response before cleanup, for debugging
+	rawResponses []string
+}
+
+// EstimateTokens roughly estimates the number of tokens in text (~4 chars per token)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// EstimatedTokens returns the estimated tokens sent and received by this generator
+func (g *ReleaseNotesGenerator) EstimatedTokens() int {
+	return g.estimatedTokens
+}
+
+// RawResponses returns the unprocessed model responses received by this
+// generator, in order
+func (g *ReleaseNotesGenerator) RawResponses() []string {
+	return g.rawResponses
 }
 
 // NewReleaseNotesGenerator creates a new release notes generator
@@ -31,21 +56,52 @@ func NewReleaseNotesGenerator(cfg config.Config) (*ReleaseNotesGenerator, error)
 	)
 
 	// Set a custom system prompt specifically for release notes
-	directClient.SetSystemPrompt(`You are a professional software release notes writer.
-Your task is to describe changes, features, and fixes in a clear, organized manner.
-IMPORTANT RULES:
+	systemPrompt, err := releaseNotesSystemPrompt(cfg)
+	if err != nil {
+		return nil, err
+	}
+	directClient.SetSystemPrompt(systemPrompt)
+
+	return &ReleaseNotesGenerator{
+		directClient: directClient,
+		config:       cfg,
+		systemPrompt: systemPrompt,
+	}, nil
+}
+
+// releaseNotesRules keep release notes about the software whatever the
+// voice; %s is the rule for the tone
+const releaseNotesRules = `IMPORTANT RULES:
 1. NEVER analyze commit message patterns or quality
 2. Focus ONLY on actual software changes and features
 3. Begin with a clear overview of key changes
 4. Organize changes into relevant categories
-5. Keep the tone professional and factual
+5. %s
 6. Remove any sections if there are no relevant changes
-7. Do not introduce yourself or explain your role`)
+7. Do not introduce yourself or explain your role`
+
+// releaseNotesSystemPrompt returns the system prompt for release notes: a
+// professional writer, or the personality in release.personality writing in
+// its own voice under the same rules
+func releaseNotesSystemPrompt(cfg config.Config) (string, error) {
+	if cfg.Release.Personality == "" {
+		return `You are a professional software release notes writer.
+Your task is to describe changes, features, and fixes in a clear, organized manner.
+` + fmt.Sprintf(releaseNotesRules, "Keep the tone professional and factual"), nil
+	}
 
-	return &ReleaseNotesGenerator{
-		directClient: directClient,
-		config:       cfg,
-	}, nil
+	// A missing personality file still leaves the built-in personalities
+	personalities, _ := personality.LoadPersonalities(cfg.Moai.PersonalityFile)
+	voice, err := personalities.GetPersonality(cfg.Release.Personality)
+	if err != nil {
+		return "", fmt.Errorf("unknown release personality: %w", err)
+	}
+
+	return voice.SystemPrompt + `
+
+You are now writing software release notes. Describe changes, features, and fixes in a clear,
+organized manner, in your own voice. Ignore any length limit above: the notes must cover every change.
+` + fmt.Sprintf(releaseNotesRules, "Let your personality shape the wording, but keep every statement factual"), nil
 }
 
 // GenerateReleaseNotes creates AI-enhanced release notes from commit messages
@@ -65,10 +121,12 @@ func (g *ReleaseNotesGenerator) GenerateReleaseNotes(
 
 	// Use direct LLM client for generation (separate from feedback system)
 	notes, err := g.directClient.GenerateReleaseNotes(prompt, 3) // Try up to 3 times
+	g.estimatedTokens += EstimateTokens(prompt) + EstimateTokens(notes)
 	if err != nil {
 		fmt.Printf("Warning: Direct release notes generation failed: %s\n", err)
 		return generateBasicReleaseNotes(version, commitMessages), nil
 	}
+	g.rawResponses = append(g.rawResponses, notes)
 
 	// Clean up the response and check if it's usable
 	notes = cleanReleaseNotes(notes)
@@ -94,14 +152,17 @@ func (g *ReleaseNotesGenerator) GenerateCustomContent(prompt string) (string, er
 		return "", fmt.Errorf("failed to create LLM client: %w", err)
 	}
 
-	// Set maximum tokens to a reasonable value for a paragraph
+	// Write in the same voice as the notes, with a reasonable limit for a paragraph
+	client.SetSystemPrompt(g.systemPrompt)
 	client.SetMaxTokens(1000)
 
 	// Generate content
 	response, err := client.GenerateContent(prompt)
+	g.estimatedTokens += EstimateTokens(prompt) + EstimateTokens(response)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate custom content: %w", err)
 	}
+	g.rawResponses = append(g.rawResponses, response)
 
 	// Clean up the response
 	return cleanReleaseNotes(response), nil
@@ -109,7 +170,9 @@ func (g *ReleaseNotesGenerator) GenerateCustomContent(prompt string) (string, er
 
 // cleanReleaseNotes removes any self-introduction or AI mentions from notes
 func cleanReleaseNotes(notes string) string {
-	if notes == "" {
+	// Drop any truncation marker the model echoed back from the prompt
+	notes = truncate.Strip(notes)
+	if strings.TrimSpace(notes) == "" {
 		return ""
 	}
 
@@ -231,7 +294,7 @@ func buildReleaseNotesPrompt(version string, commitMessages []string, previousVe
 		// Limit diff content to avoid overwhelming the model
 		if len(diffContent) > 2000 {
 			sb.WriteString(diffContent[:2000])
-			sb.WriteString("\n... [additional changes truncated] ...\n")
+			sb.WriteString(truncate.Notice())
 		} else {
 			sb.WriteString(diffContent)
 		}
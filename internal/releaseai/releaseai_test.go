@@ -0,0 +1,94 @@
+package releaseai
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/AccursedGalaxy/noidea/internal/config"
+	"github.com/AccursedGalaxy/noidea/internal/personality"
+	"github.com/AccursedGalaxy/noidea/internal/truncate"
+)
+
+func TestCleanReleaseNotesStripsTruncationMarker(t *testing.T) {
+	notes := "# Release v1.2.0\n\n## Changes\n\n- Faster startup\n" + truncate.Marker + "\n- Fewer prompts\n"
+
+	got := cleanReleaseNotes(notes)
+	if truncate.Contains(got) {
+		t.Errorf("cleanReleaseNotes() leaked a truncation marker: %q", got)
+	}
+
+	want := "# Release v1.2.0\n\n## Changes\n\n- Faster startup\n- Fewer prompts\n"
+	if got != want {
+		t.Errorf("cleanReleaseNotes() = %q, want %q", got, want)
+	}
+
+	if got := cleanReleaseNotes(truncate.Marker); got != "" {
+		t.Errorf("cleanReleaseNotes(marker only) = %q, want empty", got)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := map[string]int{
+		"":          0,
+		"abc":       1,
+		"abcd":      1,
+		"abcdefghi": 3,
+	}
+
+	for text, want := range tests {
+		if got := EstimateTokens(text); got != want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestReleaseNotesSystemPrompt(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Moai.PersonalityFile = ""
+
+	prompt, err := releaseNotesSystemPrompt(cfg)
+	if err != nil || !strings.Contains(prompt, "Keep the tone professional and factual") {
+		t.Errorf("default prompt = %q, %v; want the professional tone", prompt, err)
+	}
+
+	cfg.Release.Personality = "snarky_reviewer"
+	prompt, err = releaseNotesSystemPrompt(cfg)
+	if err != nil {
+		t.Fatalf("releaseNotesSystemPrompt() returned error: %v", err)
+	}
+	voice := personality.DefaultPersonalities().Personalities["snarky_reviewer"]
+	if !strings.HasPrefix(prompt, voice.SystemPrompt) {
+		t.Errorf("prompt doesn't start with the personality's system prompt:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "NEVER analyze commit message patterns") {
+		t.Errorf("prompt dropped the release notes rules:\n%s", prompt)
+	}
+
+	cfg.Release.Personality = "no_such_voice"
+	if _, err := releaseNotesSystemPrompt(cfg); err == nil {
+		t.Error("releaseNotesSystemPrompt() with an unknown personality should fail")
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		model  string
+		tokens int
+		want   float64
+		known  bool
+	}{
+		{"gpt-4o", 1000000, 4.38, true},
+		{"gpt-4o-mini", 1000000, 0.26, true}, // Not priced as gpt-4o
+		{"grok-2-1212", 500000, 2, true},
+		{"DeepSeek-Chat", 2000000, 0.96, true},
+		{"llama3", 1000000, 0, false},
+		{"", 1000, 0, false},
+	}
+	for _, tt := range tests {
+		got, known := EstimateCost(tt.model, tt.tokens)
+		if known != tt.known || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateCost(%q, %d) = %v, %v; want %v, %v", tt.model, tt.tokens, got, known, tt.want, tt.known)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package secure
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Keyring is the small set of secret-store operations noidea relies on.
+// The system keyring is used by default; tests can swap in MemoryKeyring.
+type Keyring interface {
+	Set(service, user, secret string) error
+	Get(service, user string) (string, error)
+	Delete(service, user string) error
+}
+
+// itemLocator is implemented by keyrings that can say where an item is kept,
+// in terms the platform's own tools understand
+type itemLocator interface {
+	Location(service, user string) string
+}
+
+// KeyringOptions selects where the system keyring stores API keys. Fields
+// that don't apply to the current platform are ignored.
+type KeyringOptions struct {
+	// Keychain is the macOS keychain, a name such as "login" or a path to a
+	// keychain file. Empty uses the default keychain.
+	Keychain string
+
+	// Collection is the Linux Secret Service collection. Empty uses the
+	// "login" collection, or the default one when there is no login collection.
+	Collection string
+}
+
+// keyringOptionsFromEnv applies NOIDEA_KEYCHAIN and NOIDEA_KEYRING_COLLECTION
+func keyringOptionsFromEnv(opts KeyringOptions) KeyringOptions {
+	if val := os.Getenv("NOIDEA_KEYCHAIN"); val != "" {
+		opts.Keychain = val
+	}
+	if val := os.Getenv("NOIDEA_KEYRING_COLLECTION"); val != "" {
+		opts.Collection = val
+	}
+	return opts
+}
+
+// itemLabel is the human-readable name of a stored API key, as shown in
+// Keychain Access or Seahorse
+func itemLabel(user string) string {
+	return fmt.Sprintf("noidea API key (%s)", user)
+}
+
+// MemoryKeyring keeps secrets in process memory. It never touches the
+// developer's real keychain, which makes it suitable for hermetic tests.
+type MemoryKeyring struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+// Location describes the in-memory keyring
+func (m *MemoryKeyring) Location(service, user string) string {
+	return fmt.Sprintf("in-memory keyring (NOIDEA_KEYRING=memory), service %q, account %q", service, user)
+}
+
+// NewMemoryKeyring creates an empty in-memory keyring
+func NewMemoryKeyring() *MemoryKeyring {
+	return &MemoryKeyring{items: make(map[string]string)}
+}
+
+// Set stores a secret
+func (m *MemoryKeyring) Set(service, user, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[service+"\x00"+user] = secret
+	return nil
+}
+
+// Get retrieves a secret, returning ErrKeyNotFound if it isn't stored
+func (m *MemoryKeyring) Get(service, user string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	secret, ok := m.items[service+"\x00"+user]
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+	return secret, nil
+}
+
+// Delete removes a secret, returning ErrKeyNotFound if it isn't stored
+func (m *MemoryKeyring) Delete(service, user string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := service + "\x00" + user
+	if _, ok := m.items[key]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(m.items, key)
+	return nil
+}
+
+// activeKeyring is the backend used by the storage functions. Setting
+// NOIDEA_KEYRING=memory selects the in-memory keyring for the whole process.
+var activeKeyring = defaultKeyring()
+
+// defaultKeyring picks the keyring backend based on the environment
+func defaultKeyring() Keyring {
+	if os.Getenv("NOIDEA_KEYRING") == "memory" {
+		return NewMemoryKeyring()
+	}
+	return systemKeyring{opts: keyringOptionsFromEnv(KeyringOptions{})}
+}
+
+// SetKeyringOptions points the system keyring at a keychain or collection.
+// Environment variables take precedence over opts, and a memory or test
+// keyring is left in place.
+func SetKeyringOptions(opts KeyringOptions) {
+	if _, ok := activeKeyring.(systemKeyring); ok {
+		activeKeyring = systemKeyring{opts: keyringOptionsFromEnv(opts)}
+	}
+}
+
+// KeyringItemLocation describes where the API key for a provider is kept in
+// the system keyring, e.g. the keychain and account on macOS
+func KeyringItemLocation(provider string) string {
+	provider = normalizeProviderName(provider)
+	if locator, ok := activeKeyring.(itemLocator); ok {
+		return locator.Location(ServiceName, provider)
+	}
+	return fmt.Sprintf("service %q, account %q", ServiceName, provider)
+}
+
+// SetKeyring replaces the keyring backend and returns a function that
+// restores the previous one, e.g. `defer secure.SetKeyring(k)()` in tests
+func SetKeyring(k Keyring) func() {
+	previous := activeKeyring
+	activeKeyring = k
+	return func() {
+		activeKeyring = previous
+	}
+}
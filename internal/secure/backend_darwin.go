@@ -0,0 +1,127 @@
+//go:build darwin
+
+package secure
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"al.essio.dev/pkg/shellescape"
+)
+
+const (
+	// securityPath is the macOS keychain command-line tool
+	securityPath = "/usr/bin/security"
+
+	// Prefixes go-keyring adds to encoded secrets, so keys stored by earlier
+	// versions stay readable
+	hexSecretPrefix    = "go-keyring-encoded:"
+	base64SecretPrefix = "go-keyring-base64:"
+)
+
+// systemKeyring stores secrets as generic passwords in a macOS keychain
+type systemKeyring struct {
+	opts KeyringOptions
+}
+
+// keychain resolves the configured keychain to the argument `security`
+// expects, or "" for the default keychain. A bare name like "login" means
+// ~/Library/Keychains/login.keychain-db.
+func (k systemKeyring) keychain() string {
+	name := k.opts.Keychain
+	if name == "" {
+		return ""
+	}
+	if strings.HasPrefix(name, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			name = filepath.Join(homeDir, name[2:])
+		}
+	}
+	if !strings.Contains(name, "/") && !strings.Contains(name, ".keychain") {
+		name += ".keychain-db"
+	}
+	return name
+}
+
+// withKeychain appends the keychain argument when one is configured
+func (k systemKeyring) withKeychain(args ...string) []string {
+	if keychain := k.keychain(); keychain != "" {
+		args = append(args, keychain)
+	}
+	return args
+}
+
+func (k systemKeyring) Set(service, user, secret string) error {
+	// Encode the secret so multi-line or non-ASCII values survive the
+	// round trip, the same way go-keyring does
+	secret = base64SecretPrefix + base64.StdEncoding.EncodeToString([]byte(secret))
+
+	// Pass the command on stdin so the secret never shows up in `ps`
+	cmd := exec.Command(securityPath, "-i")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	args := k.withKeychain("add-generic-password", "-U",
+		"-s", service, "-a", user, "-l", itemLabel(user), "-w", secret)
+	for i, arg := range args {
+		args[i] = shellescape.Quote(arg)
+	}
+	if _, err := io.WriteString(stdin, strings.Join(args, " ")+"\n"); err != nil {
+		return err
+	}
+	if err := stdin.Close(); err != nil {
+		return err
+	}
+	return cmd.Wait()
+}
+
+func (k systemKeyring) Get(service, user string) (string, error) {
+	out, err := exec.Command(securityPath,
+		k.withKeychain("find-generic-password", "-s", service, "-a", user, "-w")...).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(out), "could not be found") {
+			return "", ErrKeyNotFound
+		}
+		return "", fmt.Errorf("keychain lookup failed: %s", strings.TrimSpace(string(out)))
+	}
+
+	secret := strings.TrimSpace(string(out))
+	switch {
+	case strings.HasPrefix(secret, hexSecretPrefix):
+		decoded, err := hex.DecodeString(strings.TrimPrefix(secret, hexSecretPrefix))
+		return string(decoded), err
+	case strings.HasPrefix(secret, base64SecretPrefix):
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, base64SecretPrefix))
+		return string(decoded), err
+	}
+	return secret, nil
+}
+
+func (k systemKeyring) Delete(service, user string) error {
+	out, err := exec.Command(securityPath,
+		k.withKeychain("delete-generic-password", "-s", service, "-a", user)...).CombinedOutput()
+	if strings.Contains(string(out), "could not be found") {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+// Location names the item as Keychain Access and `security` show it
+func (k systemKeyring) Location(service, user string) string {
+	keychain := k.keychain()
+	if keychain == "" {
+		keychain = "default keychain"
+	}
+	return fmt.Sprintf("%s, item %q (service %q, account %q)", keychain, itemLabel(user), service, user)
+}
@@ -0,0 +1,150 @@
+//go:build linux
+
+package secure
+
+import (
+	"fmt"
+
+	dbus "github.com/godbus/dbus/v5"
+	ss "github.com/zalando/go-keyring/secret_service"
+)
+
+const (
+	// collectionPath is where the Secret Service exposes named collections
+	collectionPath = "/org/freedesktop/secrets/collection/"
+
+	// defaultCollectionAlias is the user's default collection
+	defaultCollectionAlias = "/org/freedesktop/secrets/aliases/default"
+
+	// secretServiceName is the D-Bus name of the Secret Service
+	secretServiceName = "org.freedesktop.secrets"
+)
+
+// systemKeyring stores secrets in a Secret Service collection (GNOME
+// Keyring, KWallet, KeePassXC). Items use the same attributes as go-keyring,
+// so keys stored by earlier versions are still found.
+type systemKeyring struct {
+	opts KeyringOptions
+}
+
+// collectionName is the configured collection, "login" by default
+func (k systemKeyring) collectionName() string {
+	if k.opts.Collection == "" {
+		return "login"
+	}
+	return k.opts.Collection
+}
+
+// collection returns the configured collection. With create, a missing
+// named collection is created, which may prompt for a password.
+func (k systemKeyring) collection(svc *ss.SecretService, create bool) (dbus.BusObject, error) {
+	if k.opts.Collection == "" {
+		return svc.GetLoginCollection(), nil
+	}
+	if k.opts.Collection == "default" {
+		return svc.Object(secretServiceName, dbus.ObjectPath(defaultCollectionAlias)), nil
+	}
+
+	path := dbus.ObjectPath(collectionPath + k.opts.Collection)
+	if err := svc.CheckCollectionPath(path); err == nil {
+		return svc.Object(secretServiceName, path), nil
+	}
+	if !create {
+		return nil, ErrKeyNotFound
+	}
+	collection, err := svc.CreateCollection(k.opts.Collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keyring collection %q: %w", k.opts.Collection, err)
+	}
+	return collection, nil
+}
+
+// findItem looks up the item for service and user in the collection
+func (k systemKeyring) findItem(svc *ss.SecretService, service, user string) (dbus.ObjectPath, error) {
+	collection, err := k.collection(svc, false)
+	if err != nil {
+		return "", err
+	}
+	if err := svc.Unlock(collection.Path()); err != nil {
+		return "", err
+	}
+
+	results, err := svc.SearchItems(collection, map[string]string{"service": service, "username": user})
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "", ErrKeyNotFound
+	}
+	return results[0], nil
+}
+
+func (k systemKeyring) Set(service, user, secret string) error {
+	svc, err := ss.NewSecretService()
+	if err != nil {
+		return err
+	}
+
+	session, err := svc.OpenSession()
+	if err != nil {
+		return err
+	}
+	defer svc.Close(session)
+
+	collection, err := k.collection(svc, true)
+	if err != nil {
+		return err
+	}
+	if err := svc.Unlock(collection.Path()); err != nil {
+		return err
+	}
+
+	attributes := map[string]string{"service": service, "username": user}
+	return svc.CreateItem(collection, itemLabel(user), attributes, ss.NewSecret(session.Path(), secret))
+}
+
+func (k systemKeyring) Get(service, user string) (string, error) {
+	svc, err := ss.NewSecretService()
+	if err != nil {
+		return "", err
+	}
+
+	item, err := k.findItem(svc, service, user)
+	if err != nil {
+		return "", err
+	}
+
+	session, err := svc.OpenSession()
+	if err != nil {
+		return "", err
+	}
+	defer svc.Close(session)
+
+	if err := svc.Unlock(item); err != nil {
+		return "", err
+	}
+	secret, err := svc.GetSecret(item, session.Path())
+	if err != nil {
+		return "", err
+	}
+	return string(secret.Value), nil
+}
+
+func (k systemKeyring) Delete(service, user string) error {
+	svc, err := ss.NewSecretService()
+	if err != nil {
+		return err
+	}
+
+	item, err := k.findItem(svc, service, user)
+	if err != nil {
+		return err
+	}
+	return svc.Delete(item)
+}
+
+// Location names the item as Seahorse and `secret-tool` show it
+func (k systemKeyring) Location(service, user string) string {
+	return fmt.Sprintf("Secret Service collection %q, item %q (service=%s username=%s)",
+		k.collectionName(), itemLabel(user), service, user)
+}
@@ -0,0 +1,36 @@
+//go:build !darwin && !linux
+
+package secure
+
+import (
+	"fmt"
+	"runtime"
+
+	keyring "github.com/zalando/go-keyring"
+)
+
+// systemKeyring stores secrets in the OS keyring via go-keyring. This
+// platform has no keychain or collection to choose, so opts is unused.
+type systemKeyring struct {
+	opts KeyringOptions
+}
+
+func (systemKeyring) Set(service, user, secret string) error {
+	return keyring.Set(service, user, secret)
+}
+
+func (systemKeyring) Get(service, user string) (string, error) {
+	return keyring.Get(service, user)
+}
+
+func (systemKeyring) Delete(service, user string) error {
+	return keyring.Delete(service, user)
+}
+
+// Location names the credential as the platform's tools show it
+func (systemKeyring) Location(service, user string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("Windows Credential Manager, target %q", service+":"+user)
+	}
+	return fmt.Sprintf("system keyring, service %q, account %q", service, user)
+}
@@ -31,13 +31,15 @@ func DeleteGitHubToken() error {
 	return DeleteAPIKey(GitHubTokenKey)
 }
 
-// ValidateGitHubToken checks if the GitHub token is valid by making a request to the GitHub API
-func ValidateGitHubToken(token string) (bool, map[string]interface{}, error) {
+// ValidateGitHubToken checks if the GitHub token is valid by making a request
+// to the GitHub API at apiURL, such as GitHubAPIURL or a GitHub Enterprise
+// server's https://<host>/api/v3
+func ValidateGitHubToken(apiURL, token string) (bool, map[string]interface{}, error) {
 	client := &http.Client{
 		Timeout: 5 * time.Second,
 	}
 
-	req, err := http.NewRequest("GET", GitHubAPIURL+"/user", nil)
+	req, err := http.NewRequest("GET", apiURL+"/user", nil)
 	if err != nil {
 		return false, nil, fmt.Errorf("failed to create request: %w", err)
 	}
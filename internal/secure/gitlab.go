@@ -0,0 +1,63 @@
+package secure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// GitLabTokenKey is the key used to store the GitLab token in the secure storage
+	GitLabTokenKey = "gitlab-token"
+
+	// GitLabURL is the default GitLab instance
+	GitLabURL = "https://gitlab.com"
+)
+
+// StoreGitLabToken securely stores a GitLab Personal Access Token
+func StoreGitLabToken(token string) error {
+	return StoreAPIKey(GitLabTokenKey, token)
+}
+
+// GetGitLabToken retrieves the GitLab Personal Access Token from secure storage
+func GetGitLabToken() (string, error) {
+	return GetAPIKey(GitLabTokenKey)
+}
+
+// DeleteGitLabToken removes the GitLab Personal Access Token from secure storage
+func DeleteGitLabToken() error {
+	return DeleteAPIKey(GitLabTokenKey)
+}
+
+// ValidateGitLabToken checks if the GitLab token is valid by requesting the
+// current user from the instance at baseURL
+func ValidateGitLabToken(baseURL, token string) (bool, map[string]interface{}, error) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimRight(baseURL, "/")+"/api/v4/user", nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Add("PRIVATE-TOKEN", token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, nil, fmt.Errorf("connection error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, nil, fmt.Errorf("invalid token or API error, status code: %d", resp.StatusCode)
+	}
+
+	var userData map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&userData); err != nil {
+		return true, nil, fmt.Errorf("failed to parse user data: %w", err)
+	}
+
+	return true, userData, nil
+}
@@ -10,8 +10,6 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
-
-	keyring "github.com/zalando/go-keyring"
 )
 
 const (
@@ -158,7 +156,7 @@ func StoreAPIKey(provider, apiKey string) error {
 	// Standardize the provider name for consistency
 	provider = normalizeProviderName(provider)
 
-	err := keyring.Set(ServiceName, provider, apiKey)
+	err := activeKeyring.Set(ServiceName, provider, apiKey)
 	if err != nil {
 		// If keyring failed, try to use fallback storage
 		return storeInFallbackStorage(provider, apiKey)
@@ -173,7 +171,7 @@ func GetAPIKey(provider string) (string, error) {
 	provider = normalizeProviderName(provider)
 
 	// Try to get from keyring first
-	apiKey, err := keyring.Get(ServiceName, provider)
+	apiKey, err := activeKeyring.Get(ServiceName, provider)
 	if err == nil && apiKey != "" {
 		return apiKey, nil
 	}
@@ -188,7 +186,7 @@ func DeleteAPIKey(provider string) error {
 	provider = normalizeProviderName(provider)
 
 	// Try to delete from keyring
-	err := keyring.Delete(ServiceName, provider)
+	err := activeKeyring.Delete(ServiceName, provider)
 
 	// Also delete from fallback if it exists (regardless of keyring result)
 	fallbackErr := deleteFromFallbackStorage(provider)
@@ -374,14 +372,14 @@ func GetSecureStorageStatus() map[string]string {
 	testKey := "noidea-test-key"
 	testValue := "noidea-test-value"
 
-	err := keyring.Set(ServiceName, testKey, testValue)
+	err := activeKeyring.Set(ServiceName, testKey, testValue)
 	if err == nil {
 		// Successfully stored, now try to retrieve
-		value, err := keyring.Get(ServiceName, testKey)
+		value, err := activeKeyring.Get(ServiceName, testKey)
 		if err == nil && value == testValue {
 			status["keyring"] = "available"
 			// Clean up test key
-			keyring.Delete(ServiceName, testKey)
+			activeKeyring.Delete(ServiceName, testKey)
 		} else {
 			status["keyring"] = "retrieval-failed"
 		}
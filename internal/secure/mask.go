@@ -0,0 +1,14 @@
+package secure
+
+// Mask hides a secret for display, keeping only enough of it to tell keys
+// apart. Short keys are fully masked so no meaningful portion is ever printed.
+// Every key, token or password that is shown to the user goes through Mask.
+func Mask(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) <= 12 {
+		return "***"
+	}
+	return key[:4] + "..." + key[len(key)-4:]
+}
@@ -0,0 +1,137 @@
+package secure
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", ""},
+		{"short", "***"},
+		{"exactly12chr", "***"},
+		{"xai-abcdefghijklmnop1234", "xai-...1234"},
+	}
+
+	for _, tt := range tests {
+		if got := Mask(tt.key); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+// secretNamePattern matches variable and field names that hold a key
+var secretNamePattern = regexp.MustCompile(`^(?i:api_?key|\w*apikey|token|secret|password)$`)
+
+// printFuncs are the functions whose arguments end up in output or logs
+var printFuncs = map[string]bool{
+	"fmt.Print": true, "fmt.Printf": true, "fmt.Println": true,
+	"fmt.Fprint": true, "fmt.Fprintf": true, "fmt.Fprintln": true,
+	"fmt.Sprint": true, "fmt.Sprintf": true, "fmt.Sprintln": true, "fmt.Errorf": true,
+	"log.Print": true, "log.Printf": true, "log.Println": true,
+	"log.Fatal": true, "log.Fatalf": true, "log.Fatalln": true,
+}
+
+// allowedSecretPrints lists the file and variable of secrets that are shown
+// in full on purpose, with the reason
+var allowedSecretPrints = map[string]string{
+	"cmd/serve.go:token":   "a generated token is shown once so editors can use it",
+	"cmd/config.go:apiKey": "config show prints the key in full only with --reveal",
+}
+
+// TestNoUnmaskedKeysPrinted fails when a key variable is passed straight to a
+// print or log function anywhere in the module. Wrap it in Mask, or print
+// len(key) instead.
+func TestNoUnmaskedKeysPrinted(t *testing.T) {
+	root := filepath.Join("..", "..")
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != root && (strings.HasPrefix(name, ".") || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !printFuncs[callName(call)] {
+				return true
+			}
+			for _, arg := range call.Args {
+				rel, _ := filepath.Rel(root, path)
+				if name := unmaskedSecret(arg); name != "" && allowedSecretPrints[filepath.ToSlash(rel)+":"+name] == "" {
+					t.Errorf("%s: %s is printed without secure.Mask", fset.Position(arg.Pos()), name)
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// callName returns "pkg.Func" for a call of a package-level function
+func callName(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return pkg.Name + "." + sel.Sel.Name
+}
+
+// unmaskedSecret returns the name of a key variable used in expr outside a
+// call to Mask or len, or "" if there is none
+func unmaskedSecret(expr ast.Expr) string {
+	var found string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if found != "" {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			switch fun := node.Fun.(type) {
+			case *ast.Ident:
+				return fun.Name != "Mask" && fun.Name != "len"
+			case *ast.SelectorExpr:
+				return fun.Sel.Name != "Mask"
+			}
+		case *ast.SelectorExpr:
+			if secretNamePattern.MatchString(node.Sel.Name) {
+				found = node.Sel.Name
+			}
+		case *ast.Ident:
+			if secretNamePattern.MatchString(node.Name) {
+				found = node.Name
+			}
+		}
+		return true
+	})
+	return found
+}
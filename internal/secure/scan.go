@@ -0,0 +1,118 @@
+package secure
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SecretFinding describes a likely secret found in a diff
+type SecretFinding struct {
+	File    string // File the secret was added to
+	Line    int    // Line number in the new version of the file
+	Kind    string // Kind of secret, e.g. "AWS access key"
+	Excerpt string // Redacted excerpt of the match
+}
+
+// secretPattern is a high-confidence pattern for a known kind of secret
+type secretPattern struct {
+	kind  string
+	regex *regexp.Regexp
+}
+
+var secretPatterns = []secretPattern{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ((RSA|EC|DSA|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+	{"OpenAI API key", regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{32,}\b`)},
+	{"xAI API key", regexp.MustCompile(`\bxai-[A-Za-z0-9]{32,}\b`)},
+}
+
+// assignmentRegex finds values assigned to secret-sounding names, which are
+// then checked for entropy to avoid flagging placeholders
+var assignmentRegex = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|api[_-]?key|access[_-]?key|auth)[A-Za-z0-9_]*["']?\s*[:=]\s*["']?([A-Za-z0-9/+_=.-]{20,})`)
+
+// hunkHeaderRegex extracts the starting line of the new file from a hunk header
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)`)
+
+// minSecretEntropy is the Shannon entropy (bits per char) above which a value looks random
+const minSecretEntropy = 4.0
+
+// ScanDiffForSecrets looks for high-confidence secrets in the lines a diff adds
+func ScanDiffForSecrets(diff string) []SecretFinding {
+	var findings []SecretFinding
+	currentFile := ""
+	lineNum := 0
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			currentFile = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			continue
+		case strings.HasPrefix(line, "@@"):
+			if matches := hunkHeaderRegex.FindStringSubmatch(line); len(matches) > 1 {
+				start, _ := strconv.Atoi(matches[1])
+				lineNum = start - 1
+			}
+			continue
+		case strings.HasPrefix(line, "-"):
+			// Removed lines don't advance the new file's line count
+			continue
+		}
+
+		lineNum++
+		if !strings.HasPrefix(line, "+") {
+			continue
+		}
+		added := line[1:]
+
+		if finding, ok := matchSecret(added); ok {
+			finding.File = currentFile
+			finding.Line = lineNum
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// matchSecret checks a single added line against the known secret patterns
+func matchSecret(line string) (SecretFinding, bool) {
+	for _, pattern := range secretPatterns {
+		if match := pattern.regex.FindString(line); match != "" {
+			return SecretFinding{Kind: pattern.kind, Excerpt: Mask(match)}, true
+		}
+	}
+
+	if matches := assignmentRegex.FindStringSubmatch(line); len(matches) > 3 {
+		value := matches[3]
+		if shannonEntropy(value) >= minSecretEntropy {
+			return SecretFinding{Kind: "high-entropy token", Excerpt: Mask(value)}, true
+		}
+	}
+
+	return SecretFinding{}, false
+}
+
+// shannonEntropy computes the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+
+	entropy := 0.0
+	length := float64(len([]rune(s)))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
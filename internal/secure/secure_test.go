@@ -2,9 +2,19 @@ package secure
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
+// TestMain keeps the package tests away from the developer's real keychain
+func TestMain(m *testing.M) {
+	restore := SetKeyring(NewMemoryKeyring())
+	code := m.Run()
+	restore()
+	os.Exit(code)
+}
+
 // TestObfuscateDeobfuscate tests the obfuscation and deobfuscation functions
 func TestObfuscateDeobfuscate(t *testing.T) {
 	testCases := []string{
@@ -150,3 +160,58 @@ func TestGetSecureStorageStatus(t *testing.T) {
 		t.Error("Platform value is empty")
 	}
 }
+
+// TestAPIKeyRoundTrip stores, reads and deletes a key through the in-memory keyring
+func TestAPIKeyRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", origHome)
+	os.Setenv("HOME", tempDir)
+
+	if err := StoreAPIKey("gpt", "sk-roundtrip"); err != nil {
+		t.Fatalf("StoreAPIKey failed: %v", err)
+	}
+
+	// Aliases resolve to the same stored key
+	key, err := GetAPIKey("openai")
+	if err != nil {
+		t.Fatalf("GetAPIKey failed: %v", err)
+	}
+	if key != "sk-roundtrip" {
+		t.Errorf("GetAPIKey = %q, want %q", key, "sk-roundtrip")
+	}
+
+	// Nothing should have been written to the fallback file
+	if _, err := os.Stat(filepath.Join(tempDir, FallbackDir, FallbackFile)); !os.IsNotExist(err) {
+		t.Errorf("expected no fallback file, stat returned: %v", err)
+	}
+
+	if err := DeleteAPIKey("openai"); err != nil {
+		t.Fatalf("DeleteAPIKey failed: %v", err)
+	}
+	if _, err := GetAPIKey("openai"); err != ErrKeyNotFound {
+		t.Errorf("expected ErrKeyNotFound after delete, got %v", err)
+	}
+}
+
+// TestKeyringOptions checks that options reach the system keyring, with the
+// environment taking precedence, and leave a test keyring alone
+func TestKeyringOptions(t *testing.T) {
+	memory := activeKeyring
+	SetKeyringOptions(KeyringOptions{Keychain: "work"})
+	if activeKeyring != memory {
+		t.Fatal("SetKeyringOptions replaced the memory keyring")
+	}
+
+	defer SetKeyring(systemKeyring{})()
+	t.Setenv("NOIDEA_KEYRING_COLLECTION", "noidea")
+	SetKeyringOptions(KeyringOptions{Keychain: "work", Collection: "login"})
+
+	want := KeyringOptions{Keychain: "work", Collection: "noidea"}
+	if got := activeKeyring.(systemKeyring).opts; got != want {
+		t.Errorf("keyring options = %+v, want %+v", got, want)
+	}
+	if location := KeyringItemLocation("grok"); !strings.Contains(location, "xai") || strings.Contains(location, "grok") {
+		t.Errorf("KeyringItemLocation() = %q, want the normalized provider", location)
+	}
+}
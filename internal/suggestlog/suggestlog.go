@@ -0,0 +1,311 @@
+// Package suggestlog keeps an opt-in record of commit message suggestions and
+// what became of them, so users can see how useful the suggestions are
+package suggestlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+	"github.com/AccursedGalaxy/noidea/internal/git"
+)
+
+// Suggestion outcomes
+const (
+	Accepted = "accepted" // Used as suggested
+	Edited   = "edited"   // Used after the user changed it
+	Declined = "declined" // Rejected in interactive mode
+	Proposed = "proposed" // Written or printed without a prompt; resolved when the commit lands
+)
+
+// MaxLogSize is the size at which the log is pruned to its newest entries
+const MaxLogSize = 1 << 20
+
+// pendingWindow is how long a proposed suggestion can wait for its commit
+const pendingWindow = time.Hour
+
+// Entry is a single logged suggestion
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Repo       string    `json:"repo,omitempty"`
+	Suggestion string    `json:"suggestion"`
+	Outcome    string    `json:"outcome"`
+	Final      string    `json:"final,omitempty"`
+}
+
+// LogPath returns the location of the suggestions log
+func LogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".noidea", "suggestions.jsonl"), nil
+}
+
+// Record appends a suggestion and its outcome to the log
+func Record(suggestion, outcome, final string) error {
+	path, err := LogPath()
+	if err != nil {
+		return err
+	}
+
+	entry := Entry{
+		Time:       time.Now(),
+		Repo:       repoRoot(),
+		Suggestion: suggestion,
+		Outcome:    outcome,
+		Final:      final,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode suggestion: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open suggestions log: %w", err)
+	}
+	_, err = file.Write(append(data, '\n'))
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write suggestions log: %w", err)
+	}
+
+	return prune(path, MaxLogSize)
+}
+
+// RecordCommit resolves the most recent proposed suggestion in this repository
+// with the message that was actually committed
+func RecordCommit(message string) error {
+	path, err := LogPath()
+	if err != nil {
+		return err
+	}
+
+	entries, err := Load()
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	repo := repoRoot()
+	commentChar := git.CommentChar()
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := &entries[i]
+		if entry.Repo != repo || time.Since(entry.Time) > pendingWindow {
+			continue
+		}
+		if entry.Outcome != Proposed {
+			// The latest suggestion here was already resolved
+			return nil
+		}
+
+		entry.Final = message
+		entry.Outcome = Accepted
+		if normalize(message, commentChar) != normalize(entry.Suggestion, commentChar) {
+			entry.Outcome = Edited
+		}
+		return write(path, entries)
+	}
+
+	return nil
+}
+
+// Load reads every entry in the log, skipping lines that can't be parsed
+func Load() ([]Entry, error) {
+	path, err := LogPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suggestions log: %w", err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLogSize)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// write replaces the log with the given entries
+func write(path string, entries []Entry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode suggestion: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write suggestions log: %w", err)
+	}
+	return nil
+}
+
+// prune keeps the newest entries within half of maxSize once the log grows past it
+func prune(path string, maxSize int64) error {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() <= maxSize {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read suggestions log: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	size := int64(0)
+	start := len(lines)
+	for start > 0 && size+int64(len(lines[start-1])+1) <= maxSize/2 {
+		start--
+		size += int64(len(lines[start]) + 1)
+	}
+
+	kept := strings.Join(lines[start:], "\n")
+	if kept != "" {
+		kept += "\n"
+	}
+	if err := os.WriteFile(path, []byte(kept), 0600); err != nil {
+		return fmt.Errorf("failed to prune suggestions log: %w", err)
+	}
+	return nil
+}
+
+// normalize strips what git itself would drop from a commit message, with
+// comment lines starting with commentChar
+func normalize(message, commentChar string) string {
+	return strings.TrimSpace(conventional.CleanMessage(message, commentChar))
+}
+
+// repoRoot returns the top-level directory of the current repository, if any
+func repoRoot() string {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Stats summarizes how suggestions were received
+type Stats struct {
+	Total    int
+	Outcomes map[string]int
+	Edits    map[string]int
+}
+
+// AcceptanceRate is the share of resolved suggestions that were used, with or
+// without edits, as a percentage
+func (s Stats) AcceptanceRate() float64 {
+	used := s.Outcomes[Accepted] + s.Outcomes[Edited]
+	resolved := used + s.Outcomes[Declined]
+	if resolved == 0 {
+		return 0
+	}
+	return float64(used) / float64(resolved) * 100
+}
+
+// CommonEdits returns the kinds of edits ordered from most to least frequent
+func (s Stats) CommonEdits() []string {
+	kinds := make([]string, 0, len(s.Edits))
+	for kind := range s.Edits {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if s.Edits[kinds[i]] == s.Edits[kinds[j]] {
+			return kinds[i] < kinds[j]
+		}
+		return s.Edits[kinds[i]] > s.Edits[kinds[j]]
+	})
+	return kinds
+}
+
+// Summarize computes statistics over logged entries
+func Summarize(entries []Entry) Stats {
+	stats := Stats{
+		Outcomes: make(map[string]int),
+		Edits:    make(map[string]int),
+	}
+
+	commentChar := git.CommentChar()
+	for _, entry := range entries {
+		stats.Total++
+		stats.Outcomes[entry.Outcome]++
+		if entry.Outcome == Edited {
+			for _, kind := range classifyEdit(entry.Suggestion, entry.Final, commentChar) {
+				stats.Edits[kind]++
+			}
+		}
+	}
+
+	return stats
+}
+
+// classifyEdit describes how a final message differs from the suggestion
+func classifyEdit(suggestion, final, commentChar string) []string {
+	suggestedSubject, suggestedBody := splitMessage(normalize(suggestion, commentChar))
+	finalSubject, finalBody := splitMessage(normalize(final, commentChar))
+
+	var kinds []string
+	if conventional.TypeOf(suggestedSubject) != conventional.TypeOf(finalSubject) {
+		kinds = append(kinds, "changed type")
+	}
+	if stripType(suggestedSubject) != stripType(finalSubject) {
+		kinds = append(kinds, "reworded subject")
+	}
+
+	switch {
+	case suggestedBody == "" && finalBody != "":
+		kinds = append(kinds, "added body")
+	case suggestedBody != "" && finalBody == "":
+		kinds = append(kinds, "removed body")
+	case suggestedBody != finalBody:
+		kinds = append(kinds, "changed body")
+	}
+
+	return kinds
+}
+
+// splitMessage separates a commit message into its subject and body
+func splitMessage(message string) (string, string) {
+	parts := strings.SplitN(message, "\n", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], strings.TrimSpace(parts[1])
+}
+
+// stripType returns the subject without its conventional commit prefix
+func stripType(subject string) string {
+	if parsed, ok := conventional.Parse(subject); ok {
+		return parsed.Description
+	}
+	return strings.TrimSpace(subject)
+}
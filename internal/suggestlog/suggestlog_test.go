@@ -0,0 +1,75 @@
+package suggestlog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	entries := []Entry{
+		{Suggestion: "feat: add login", Outcome: Accepted, Final: "feat: add login"},
+		{Suggestion: "feat: add logout", Outcome: Edited, Final: "fix: add logout"},
+		{Suggestion: "feat: add form", Outcome: Edited, Final: "feat: add signup form\n\n- validate input"},
+		{Suggestion: "chore: tidy", Outcome: Declined},
+		{Suggestion: "docs: readme", Outcome: Proposed},
+	}
+
+	stats := Summarize(entries)
+
+	if stats.Total != 5 {
+		t.Errorf("Total = %d, want 5", stats.Total)
+	}
+	if rate := stats.AcceptanceRate(); rate != 75 {
+		t.Errorf("AcceptanceRate() = %.1f, want 75", rate)
+	}
+
+	wantEdits := map[string]int{"changed type": 1, "reworded subject": 1, "added body": 1}
+	for kind, want := range wantEdits {
+		if got := stats.Edits[kind]; got != want {
+			t.Errorf("Edits[%q] = %d, want %d", kind, got, want)
+		}
+	}
+}
+
+func TestNormalizeIgnoresComments(t *testing.T) {
+	message := "feat: add login  \n\n# Please enter the commit message\n"
+	if got := normalize(message, "#"); got != "feat: add login" {
+		t.Errorf("normalize() = %q", got)
+	}
+
+	// An issue reference in the body is content, as it is for suggest and lint
+	message = "fix: close the session leak\n\n#123 was caused by the cache\n"
+	if got := normalize(message, "#"); got != strings.TrimSpace(message) {
+		t.Errorf("normalize() = %q, want the #123 line kept", got)
+	}
+}
+
+func TestPruneKeepsNewestEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "suggestions.jsonl")
+
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, strings.Repeat("x", 99))
+	}
+	lines[99] = strings.Repeat("y", 99)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := prune(path, 2000); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > 1000 {
+		t.Errorf("pruned log is %d bytes, want at most 1000", len(data))
+	}
+	if !strings.HasSuffix(string(data), strings.Repeat("y", 99)+"\n") {
+		t.Error("prune should keep the newest entry")
+	}
+}
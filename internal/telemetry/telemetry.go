@@ -0,0 +1,322 @@
+// Package telemetry keeps opt-in, local-only metrics about AI requests and
+// suggestions: token usage, error types and how often suggestions are used.
+// Events hold no messages, diffs, keys or paths, and nothing is sent anywhere;
+// sharing them is up to the user via an explicit export.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/conventional"
+	"github.com/AccursedGalaxy/noidea/internal/git"
+)
+
+// Event kinds
+const (
+	KindRequest    = "request"    // A request to an AI provider
+	KindSuggestion = "suggestion" // A suggested commit message and its outcome
+)
+
+// Suggestion outcomes, matching the suggestions log
+const (
+	Accepted = "accepted"
+	Edited   = "edited"
+	Declined = "declined"
+	Proposed = "proposed" // Resolved by RecordCommit when the commit lands
+)
+
+// MaxLogSize is the size at which the telemetry file is pruned to its newest events
+const MaxLogSize = 1 << 20
+
+// pendingWindow is how long a proposed suggestion can wait for its commit
+const pendingWindow = time.Hour
+
+// Event is a single metric. Suggestions and repositories are only stored as
+// digests, to match a proposed suggestion with the commit that follows it.
+type Event struct {
+	Time             time.Time `json:"time"`
+	Kind             string    `json:"kind"`
+	Request          string    `json:"request,omitempty"` // "feedback", "summary" or "suggestion"
+	Provider         string    `json:"provider,omitempty"`
+	Model            string    `json:"model,omitempty"`
+	PromptTokens     int       `json:"prompt_tokens,omitempty"`
+	CompletionTokens int       `json:"completion_tokens,omitempty"`
+	Error            string    `json:"error,omitempty"` // Error type such as "rate_limit", never the message
+	Outcome          string    `json:"outcome,omitempty"`
+	Repo             string    `json:"repo,omitempty"`
+	Digest           string    `json:"digest,omitempty"`
+}
+
+// LogPath returns the location of the telemetry file
+func LogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".noidea", "telemetry.jsonl"), nil
+}
+
+// RecordRequest records a finished AI request; errorKind is empty on success
+func RecordRequest(request, provider, model string, promptTokens, completionTokens int, errorKind string) error {
+	return record(Event{
+		Time:             time.Now(),
+		Kind:             KindRequest,
+		Request:          request,
+		Provider:         provider,
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		Error:            errorKind,
+	})
+}
+
+// RecordSuggestion records what became of a suggestion. A proposed one keeps a
+// digest of the message so RecordCommit can tell whether it was edited.
+func RecordSuggestion(suggestion, outcome string) error {
+	event := Event{Time: time.Now(), Kind: KindSuggestion, Outcome: outcome}
+	if outcome == Proposed {
+		event.Repo = digest(repoRoot())
+		event.Digest = digest(normalize(suggestion))
+	}
+	return record(event)
+}
+
+// RecordCommit resolves the most recent proposed suggestion in this repository
+// as accepted or edited, depending on the message that was committed
+func RecordCommit(message string) error {
+	path, err := LogPath()
+	if err != nil {
+		return err
+	}
+
+	events, err := Load()
+	if err != nil || len(events) == 0 {
+		return err
+	}
+
+	repo := digest(repoRoot())
+	for i := len(events) - 1; i >= 0; i-- {
+		event := &events[i]
+		if event.Kind != KindSuggestion || event.Repo != repo || time.Since(event.Time) > pendingWindow {
+			continue
+		}
+		if event.Outcome != Proposed {
+			// The latest suggestion here was already resolved
+			return nil
+		}
+
+		event.Outcome = Accepted
+		if digest(normalize(message)) != event.Digest {
+			event.Outcome = Edited
+		}
+		event.Repo, event.Digest = "", ""
+		return write(path, events)
+	}
+
+	return nil
+}
+
+// Load reads every event, skipping lines that can't be parsed
+func Load() ([]Event, error) {
+	path, err := LogPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read telemetry: %w", err)
+	}
+
+	var events []Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLogSize)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err == nil {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// Clear deletes all recorded telemetry
+func Clear() error {
+	path, err := LogPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete telemetry: %w", err)
+	}
+	return nil
+}
+
+// record appends an event, pruning the file once it grows too large
+func record(event Event) error {
+	path, err := LogPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open telemetry: %w", err)
+	}
+	_, err = file.Write(append(data, '\n'))
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write telemetry: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Size() <= MaxLogSize {
+		return nil
+	}
+	events, err := Load()
+	if err != nil {
+		return err
+	}
+	return write(path, events[len(events)/2:])
+}
+
+// write replaces the telemetry file with the given events
+func write(path string, events []Event) error {
+	var buf bytes.Buffer
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode telemetry: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write telemetry: %w", err)
+	}
+	return nil
+}
+
+// normalize strips what git itself would drop from a commit message, with
+// comment lines starting with git's comment character
+func normalize(message string) string {
+	return strings.TrimSpace(conventional.CleanMessage(message, git.CommentChar()))
+}
+
+// digest returns a short, one-way fingerprint of s
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// repoRoot returns the top-level directory of the current repository, if any
+func repoRoot() string {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// ModelUsage is the request and token count for one provider and model
+type ModelUsage struct {
+	Requests         int `json:"requests"`
+	Errors           int `json:"errors"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Stats aggregates events; it is also the format of an export
+type Stats struct {
+	Since       time.Time             `json:"since"`
+	Until       time.Time             `json:"until"`
+	Requests    int                   `json:"requests"`
+	Models      map[string]ModelUsage `json:"models"`   // By "provider/model"
+	Errors      map[string]int        `json:"errors"`   // By error type
+	Outcomes    map[string]int        `json:"outcomes"` // Suggestion outcomes
+	Acceptance  float64               `json:"acceptance_rate"`
+	TotalTokens int                   `json:"total_tokens"`
+}
+
+// Summarize aggregates events into statistics
+func Summarize(events []Event) Stats {
+	stats := Stats{
+		Models:   make(map[string]ModelUsage),
+		Errors:   make(map[string]int),
+		Outcomes: make(map[string]int),
+	}
+
+	for _, event := range events {
+		if stats.Since.IsZero() || event.Time.Before(stats.Since) {
+			stats.Since = event.Time
+		}
+		if event.Time.After(stats.Until) {
+			stats.Until = event.Time
+		}
+
+		switch event.Kind {
+		case KindRequest:
+			stats.Requests++
+			key := event.Provider + "/" + event.Model
+			usage := stats.Models[key]
+			usage.Requests++
+			usage.PromptTokens += event.PromptTokens
+			usage.CompletionTokens += event.CompletionTokens
+			if event.Error != "" {
+				usage.Errors++
+				stats.Errors[event.Error]++
+			}
+			stats.Models[key] = usage
+			stats.TotalTokens += event.PromptTokens + event.CompletionTokens
+		case KindSuggestion:
+			stats.Outcomes[event.Outcome]++
+		}
+	}
+
+	used := stats.Outcomes[Accepted] + stats.Outcomes[Edited]
+	if resolved := used + stats.Outcomes[Declined]; resolved > 0 {
+		stats.Acceptance = float64(used) / float64(resolved) * 100
+	}
+
+	return stats
+}
+
+// SortedKeys returns the keys of a count map from most to least frequent
+func SortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] == counts[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return counts[keys[i]] > counts[keys[j]]
+	})
+	return keys
+}
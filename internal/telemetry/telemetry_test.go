@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	events := []Event{
+		{Kind: KindRequest, Provider: "OpenAI", Model: "gpt-4o", PromptTokens: 100, CompletionTokens: 20},
+		{Kind: KindRequest, Provider: "OpenAI", Model: "gpt-4o", Error: "rate_limit"},
+		{Kind: KindRequest, Provider: "xAI", Model: "grok-2-1212", PromptTokens: 50, CompletionTokens: 10},
+		{Kind: KindSuggestion, Outcome: Accepted},
+		{Kind: KindSuggestion, Outcome: Edited},
+		{Kind: KindSuggestion, Outcome: Declined},
+		{Kind: KindSuggestion, Outcome: Declined},
+		{Kind: KindSuggestion, Outcome: Proposed},
+	}
+
+	stats := Summarize(events)
+
+	if stats.Requests != 3 || stats.TotalTokens != 180 {
+		t.Errorf("Requests = %d, TotalTokens = %d; want 3, 180", stats.Requests, stats.TotalTokens)
+	}
+	if usage := stats.Models["OpenAI/gpt-4o"]; usage != (ModelUsage{Requests: 2, Errors: 1, PromptTokens: 100, CompletionTokens: 20}) {
+		t.Errorf("OpenAI/gpt-4o usage = %+v", usage)
+	}
+	if stats.Errors["rate_limit"] != 1 {
+		t.Errorf("Errors = %v", stats.Errors)
+	}
+	if stats.Acceptance != 50 {
+		t.Errorf("Acceptance = %.1f, want 50", stats.Acceptance)
+	}
+}
+
+func TestRecordCommitResolvesProposedSuggestion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := RecordSuggestion("feat: add login", Proposed); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordCommit("feat: add login\n# Please enter the commit message\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second commit doesn't resolve the already resolved suggestion again
+	if err := RecordSuggestion("fix: handle errors", Proposed); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordCommit("fix: handle login errors"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordCommit("chore: unrelated"); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Outcome != Accepted || events[1].Outcome != Edited {
+		t.Fatalf("events = %+v, want accepted then edited", events)
+	}
+
+	// Nothing that could identify the message or repository is kept
+	path, _ := LogPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "login") || strings.Contains(string(data), `"digest"`) {
+		t.Errorf("telemetry file leaks message details:\n%s", data)
+	}
+}
@@ -0,0 +1,68 @@
+// Package truncate provides the single marker used whenever content is cut
+// down before being sent to an LLM, and helpers to keep it out of results
+package truncate
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Marker is the one truncation marker used across noidea. It is deliberately
+// distinctive so it can be reliably stripped if a model echoes it back.
+const Marker = "[noidea: content truncated]"
+
+// legacyMarkerRegex matches the ad-hoc markers used by earlier versions, which
+// may still show up in cached prompts or be reproduced by a model
+var legacyMarkerRegex = regexp.MustCompile(`(?i)(\.\.\.\s*)?(\[(diff truncated( for brevity)?|additional changes truncated)\]|\(additional lines omitted for brevity\))(\s*\.\.\.)?|\[Note: Some context was truncated[^\]]*\]`)
+
+// Notice returns the marker on its own line, ready to append to truncated text
+func Notice() string {
+	return "\n" + Marker + "\n"
+}
+
+// Lines cuts every line of s longer than max characters down to its first
+// max characters followed by the marker, so one huge line such as minified
+// code can't take over a prompt. A max of 0 leaves s unchanged. Cutting
+// text that was already cut gives the same result.
+func Lines(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) <= max {
+			continue
+		}
+		runes := []rune(line)
+		lines[i] = string(runes[:max]) + " " + Marker
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Contains reports whether s contains the truncation marker or a legacy variant
+func Contains(s string) bool {
+	return strings.Contains(s, Marker) || legacyMarkerRegex.MatchString(s)
+}
+
+// Strip removes every truncation marker from s. Lines that only held a
+// marker are dropped entirely so no stray blank bullets are left behind.
+func Strip(s string) string {
+	if !Contains(s) {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		cleaned := strings.ReplaceAll(line, Marker, "")
+		cleaned = legacyMarkerRegex.ReplaceAllString(cleaned, "")
+		if cleaned != line && strings.Trim(cleaned, " \t-*•") == "" {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(cleaned, " \t"))
+	}
+
+	return strings.Join(kept, "\n")
+}
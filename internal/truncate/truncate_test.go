@@ -0,0 +1,52 @@
+package truncate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no marker", "feat: add login", "feat: add login"},
+		{"marker line", "feat: add login\n\n" + Marker + "\n- add form", "feat: add login\n\n- add form"},
+		{"inline marker", "feat: add login " + Marker, "feat: add login"},
+		{"bullet marker", "fix: x\n\n- " + Marker + "\n- y", "fix: x\n\n- y"},
+		{"legacy diff", "fix: x\n... [diff truncated]", "fix: x"},
+		{"legacy omitted", "fix: x\n... (additional lines omitted for brevity) ...", "fix: x"},
+		{"legacy note", "fix: x\n[Note: Some context was truncated due to size constraints]", "fix: x"},
+		{"legacy release", "## Changes\n... [additional changes truncated] ...\n- a", "## Changes\n- a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Strip(tt.input)
+			if got != tt.want {
+				t.Errorf("Strip(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if Contains(got) {
+				t.Errorf("Strip(%q) still contains a marker: %q", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	long := "+" + strings.Repeat("é", 60)
+	text := "diff --git a/app.min.js b/app.min.js\n" + long + "\n+short"
+
+	got := Lines(text, 40)
+	want := "diff --git a/app.min.js b/app.min.js\n+" + strings.Repeat("é", 39) + " " + Marker + "\n+short"
+	if got != want {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+	if again := Lines(got, 40); again != got {
+		t.Errorf("Lines() on cut text = %q, want it unchanged", again)
+	}
+	if Lines(text, 0) != text {
+		t.Error("Lines() with a max of 0 changed the text")
+	}
+}
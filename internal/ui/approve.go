@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+// AssumeYes skips all approval prompts, accepting content as-is (set by --yes)
+var AssumeYes bool
+
+// DefaultMaxRetries is how many invalid choices are tolerated before a prompt gives up
+const DefaultMaxRetries = 3
+
+// MaxRetries is how many times an invalid choice is re-prompted before the
+// content is declined; 0 declines on the first invalid choice
+var MaxRetries = DefaultMaxRetries
+
+// invalidChoices counts the invalid answers given to one prompt
+type invalidChoices struct {
+	out   io.Writer
+	count int
+}
+
+// retry reports an invalid choice to out and whether the prompt should be
+// shown again, which it isn't once more than MaxRetries have been made
+func (c *invalidChoices) retry() bool {
+	c.count++
+	if c.count > MaxRetries {
+		fmt.Fprintln(c.out, "Too many invalid choices, cancelling.")
+		return false
+	}
+	fmt.Fprintln(c.out, "Invalid choice. Please try again.")
+	return true
+}
+
+// ApproveOptions customizes the approval prompt
+type ApproveOptions struct {
+	// Display prints the content before prompting (defaults to plain output)
+	Display func(content string)
+	// Regenerate produces fresh content; the regenerate choice is hidden when nil
+	Regenerate func() (string, error)
+	// Extension is used for the temporary file opened in the editor, e.g. ".md"
+	Extension string
+
+	// Input and Output default to stdin and stdout
+	Input  io.Reader
+	Output io.Writer
+}
+
+// IsInteractive reports whether stdin is attached to a terminal
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// ApproveOrEdit shows content and lets the user accept, edit, or cancel it.
+// It returns the (possibly edited) content and whether it was accepted.
+func ApproveOrEdit(content string) (string, bool) {
+	return ApproveOrEditWith(content, ApproveOptions{})
+}
+
+// ApproveOrEditWith is ApproveOrEdit with custom display and regeneration
+func ApproveOrEditWith(content string, opts ApproveOptions) (string, bool) {
+	if AssumeYes {
+		return content, true
+	}
+
+	if opts.Input == nil {
+		// We can't prompt without a terminal, so don't hang waiting on a pipe
+		if !IsInteractive() {
+			fmt.Fprintln(os.Stderr, "Warning: not running in a terminal, skipping approval (use --yes to accept automatically)")
+			return content, false
+		}
+		opts.Input = os.Stdin
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	if opts.Display == nil {
+		opts.Display = func(c string) {
+			fmt.Fprintln(opts.Output, c)
+		}
+	}
+
+	prompt := "Accept? [Y]es, [e]dit, [n]o: "
+	if opts.Regenerate != nil {
+		prompt = "Accept? [Y]es, [e]dit, [r]egenerate, [n]o: "
+	}
+
+	reader := bufio.NewReader(opts.Input)
+	opts.Display(content)
+
+	invalid := &invalidChoices{out: opts.Output}
+
+	for {
+		fmt.Fprint(opts.Output, color.YellowString(prompt))
+		response, err := reader.ReadString('\n')
+		if err != nil && response == "" {
+			// Input closed, treat as cancel
+			fmt.Fprintln(opts.Output)
+			return content, false
+		}
+
+		switch strings.ToLower(strings.TrimSpace(response)) {
+		case "", "y", "yes", "a", "approve":
+			return content, true
+
+		case "e", "edit":
+			edited, err := EditContent(content, opts.Extension)
+			if err != nil {
+				fmt.Fprintln(opts.Output, color.RedString("Error:"), err)
+				continue
+			}
+			content = edited
+			opts.Display(content)
+
+		case "r", "regenerate":
+			if opts.Regenerate == nil {
+				if !invalid.retry() {
+					return content, false
+				}
+				continue
+			}
+			regenerated, err := opts.Regenerate()
+			if err != nil {
+				fmt.Fprintln(opts.Output, color.RedString("Error:"), "Failed to regenerate:", err)
+				continue
+			}
+			content = regenerated
+			opts.Display(content)
+
+		case "n", "no", "c", "cancel":
+			return content, false
+
+		default:
+			if !invalid.retry() {
+				return content, false
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestApproveOrEditWith(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantContent  string
+		wantAccepted bool
+	}{
+		{"Default accepts", "\n", "original", true},
+		{"Yes accepts", "y\n", "original", true},
+		{"No declines", "n\n", "original", false},
+		{"EOF cancels", "", "original", false},
+		{"Invalid then yes", "what\ny\n", "original", true},
+		{"Too many invalid choices cancel", "a1\na2\na3\na4\ny\n", "original", false},
+		{"Regenerate then yes", "r\ny\n", "regenerated", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			content, accepted := ApproveOrEditWith("original", ApproveOptions{
+				Regenerate: func() (string, error) { return "regenerated", nil },
+				Input:      strings.NewReader(tt.input),
+				Output:     &out,
+			})
+
+			if accepted != tt.wantAccepted {
+				t.Errorf("accepted = %v, want %v", accepted, tt.wantAccepted)
+			}
+			if accepted && content != tt.wantContent {
+				t.Errorf("content = %q, want %q", content, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestApproveOrEditNoRetry(t *testing.T) {
+	MaxRetries = 0
+	defer func() { MaxRetries = DefaultMaxRetries }()
+
+	var out bytes.Buffer
+	_, accepted := ApproveOrEditWith("notes", ApproveOptions{
+		Input:  strings.NewReader("what\ny\n"),
+		Output: &out,
+	})
+	if accepted {
+		t.Error("expected an invalid choice to decline when retries are disabled")
+	}
+}
+
+func TestApproveOrEditAssumeYes(t *testing.T) {
+	AssumeYes = true
+	defer func() { AssumeYes = false }()
+
+	content, accepted := ApproveOrEditWith("notes", ApproveOptions{Input: strings.NewReader("n\n")})
+	if !accepted || content != "notes" {
+		t.Errorf("expected --yes to accept content unchanged, got %q, %v", content, accepted)
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ResolveEditor determines which editor to launch, following the same
+// precedence as git: GIT_EDITOR, core.editor, VISUAL, then EDITOR
+func ResolveEditor() string {
+	if editor := os.Getenv("GIT_EDITOR"); editor != "" {
+		return editor
+	}
+
+	if output, err := exec.Command("git", "config", "--get", "core.editor").Output(); err == nil {
+		if editor := strings.TrimSpace(string(output)); editor != "" {
+			return editor
+		}
+	}
+
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+
+	// Fallback editor
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "nano"
+}
+
+// OpenEditor opens the given file in the user's editor and waits for it to close
+func OpenEditor(path string) error {
+	// Editors are often configured with arguments, e.g. "code --wait"
+	parts := strings.Fields(ResolveEditor())
+	if len(parts) == 0 {
+		return fmt.Errorf("no editor configured")
+	}
+
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s: %w", parts[0], err)
+	}
+
+	return nil
+}
+
+// EditContent opens content in the user's editor and returns the edited result
+func EditContent(content, extension string) (string, error) {
+	tmpFile, err := os.CreateTemp("", "noidea-*"+extension)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString(content)
+	tmpFile.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := OpenEditor(tmpFile.Name()); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	return strings.TrimRight(string(edited), "\n"), nil
+}
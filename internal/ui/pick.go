@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// PickOptions customizes the candidate picker
+type PickOptions struct {
+	// Display prints one numbered candidate (defaults to plain output)
+	Display func(number int, candidate string)
+	// Regenerate produces fresh candidates; the regenerate choice is hidden when nil
+	Regenerate func() ([]string, error)
+	// Extension is used for the temporary file opened in the editor, e.g. ".txt"
+	Extension string
+
+	// Input and Output default to stdin and stdout
+	Input  io.Reader
+	Output io.Writer
+}
+
+// PickCandidate shows numbered candidates and lets the user choose one,
+// edit one (e<N>), regenerate them all, or cancel. It returns the chosen,
+// possibly edited, content, the index of the candidate it came from in the
+// final list, and whether one was chosen.
+func PickCandidate(candidates []string, opts PickOptions) (string, int, bool) {
+	if len(candidates) == 0 {
+		return "", -1, false
+	}
+	if AssumeYes {
+		return candidates[0], 0, true
+	}
+
+	if opts.Input == nil {
+		// We can't prompt without a terminal, so don't hang waiting on a pipe
+		if !IsInteractive() {
+			fmt.Fprintln(os.Stderr, "Warning: not running in a terminal, skipping approval (use --yes to accept automatically)")
+			return candidates[0], 0, false
+		}
+		opts.Input = os.Stdin
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	if opts.Display == nil {
+		opts.Display = func(number int, c string) {
+			fmt.Fprintf(opts.Output, "%d) %s\n\n", number, c)
+		}
+	}
+
+	reader := bufio.NewReader(opts.Input)
+	display := func() {
+		for i, candidate := range candidates {
+			opts.Display(i+1, candidate)
+		}
+	}
+	display()
+
+	invalid := &invalidChoices{out: opts.Output}
+
+	for {
+		prompt := fmt.Sprintf("Choose [1]-%d, e<N> to edit, [n]o: ", len(candidates))
+		if opts.Regenerate != nil {
+			prompt = fmt.Sprintf("Choose [1]-%d, e<N> to edit, [r]egenerate, [n]o: ", len(candidates))
+		}
+		fmt.Fprint(opts.Output, color.YellowString(prompt))
+
+		response, err := reader.ReadString('\n')
+		if err != nil && response == "" {
+			// Input closed, treat as cancel
+			fmt.Fprintln(opts.Output)
+			return candidates[0], 0, false
+		}
+		choice := strings.ToLower(strings.TrimSpace(response))
+
+		switch {
+		case choice == "" || choice == "y" || choice == "yes":
+			return candidates[0], 0, true
+
+		case choice == "n" || choice == "no" || choice == "c" || choice == "cancel":
+			return candidates[0], 0, false
+
+		case choice == "r" || choice == "regenerate":
+			if opts.Regenerate == nil {
+				if !invalid.retry() {
+					return candidates[0], 0, false
+				}
+				continue
+			}
+			regenerated, err := opts.Regenerate()
+			if err != nil {
+				fmt.Fprintln(opts.Output, color.RedString("Error:"), "Failed to regenerate:", err)
+				continue
+			}
+			if len(regenerated) > 0 {
+				candidates = regenerated
+			}
+			display()
+
+		case strings.HasPrefix(choice, "e"):
+			// "e" alone edits the first candidate, like the default choice
+			number := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(choice, "edit"), "e"))
+			if number == "" {
+				number = "1"
+			}
+			index, ok := candidateIndex(number, len(candidates))
+			if !ok {
+				if !invalid.retry() {
+					return candidates[0], 0, false
+				}
+				continue
+			}
+			edited, err := EditContent(candidates[index], opts.Extension)
+			if err != nil {
+				fmt.Fprintln(opts.Output, color.RedString("Error:"), err)
+				continue
+			}
+			return edited, index, true
+
+		default:
+			index, ok := candidateIndex(choice, len(candidates))
+			if !ok {
+				if !invalid.retry() {
+					return candidates[0], 0, false
+				}
+				continue
+			}
+			return candidates[index], index, true
+		}
+	}
+}
+
+// candidateIndex parses a 1-based candidate number into an index
+func candidateIndex(choice string, count int) (int, bool) {
+	number, err := strconv.Atoi(choice)
+	if err != nil || number < 1 || number > count {
+		return 0, false
+	}
+	return number - 1, true
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPickCandidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantContent  string
+		wantIndex    int
+		wantAccepted bool
+	}{
+		{"Default picks the first", "\n", "one", 0, true},
+		{"Number picks that candidate", "2\n", "two", 1, true},
+		{"Out of range then valid", "4\n3\n", "three", 2, true},
+		{"No declines", "n\n", "one", 0, false},
+		{"EOF cancels", "", "one", 0, false},
+		{"Too many invalid choices cancel", "x\nx\nx\nx\n1\n", "one", 0, false},
+		{"Regenerate then pick", "r\n2\n", "fresh two", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			content, index, accepted := PickCandidate([]string{"one", "two", "three"}, PickOptions{
+				Regenerate: func() ([]string, error) { return []string{"fresh one", "fresh two"}, nil },
+				Input:      strings.NewReader(tt.input),
+				Output:     &out,
+			})
+
+			if accepted != tt.wantAccepted {
+				t.Errorf("accepted = %v, want %v", accepted, tt.wantAccepted)
+			}
+			if accepted && (content != tt.wantContent || index != tt.wantIndex) {
+				t.Errorf("picked %q (index %d), want %q (index %d)", content, index, tt.wantContent, tt.wantIndex)
+			}
+		})
+	}
+}
@@ -41,10 +41,10 @@ setup_suggest_env() {
     # Make sure LLM is enabled for testing
     export NOIDEA_LLM_ENABLED=true
     
-    # Set a mock API key if none is provided
+    # Use the offline mock provider if no API key is provided
     if [ -z "$XAI_API_KEY" ] && [ -z "$OPENAI_API_KEY" ] && [ -z "$DEEPSEEK_API_KEY" ]; then
-        echo "No API key found, using mock key for testing"
-        export XAI_API_KEY="mock-api-key-for-testing"
+        echo "No API key found, using the offline mock provider"
+        export NOIDEA_LLM_MOCK=1
     fi
     
     # Ensure Git has a user name and email set
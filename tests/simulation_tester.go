@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/AccursedGalaxy/noidea/internal/secure"
 )
 
 // TestCase defines a simulation test case
@@ -94,7 +96,7 @@ func RunTestSuite(suite TestSuite) error {
 	// Try to load .env file from project root
 	LoadEnvFile("../.env")
 
-	// Check if we have an API key, if not use a mock one
+	// Check if we have an API key, if not use the mock provider
 	apiKey := os.Getenv("XAI_API_KEY")
 	if apiKey == "" {
 		apiKey = os.Getenv("OPENAI_API_KEY")
@@ -105,14 +107,10 @@ func RunTestSuite(suite TestSuite) error {
 
 	// Log API key status (truncated for security)
 	if apiKey != "" {
-		maskedKey := "****"
-		if len(apiKey) > 8 {
-			maskedKey = apiKey[:4] + "..." + apiKey[len(apiKey)-4:]
-		}
-		logDebug("Using API key: %s", maskedKey)
+		logDebug("Using API key: %s", secure.Mask(apiKey))
 	} else {
-		logDebug("No API key found, using mock key for testing")
-		os.Setenv("XAI_API_KEY", "mock-api-key-for-testing")
+		logDebug("No API key found, using the offline mock provider")
+		os.Setenv("NOIDEA_LLM_MOCK", "1")
 	}
 
 	// Always enable LLM for testing
@@ -576,7 +574,7 @@ func RunCommitSimulation() error {
 	// Try to load .env file from project root
 	LoadEnvFile("../.env")
 
-	// Check if we have an API key, if not use a mock one
+	// Check if we have an API key, if not use the mock provider
 	apiKey := os.Getenv("XAI_API_KEY")
 	if apiKey == "" {
 		apiKey = os.Getenv("OPENAI_API_KEY")
@@ -587,14 +585,10 @@ func RunCommitSimulation() error {
 
 	// Log API key status (truncated for security)
 	if apiKey != "" {
-		maskedKey := "****"
-		if len(apiKey) > 8 {
-			maskedKey = apiKey[:4] + "..." + apiKey[len(apiKey)-4:]
-		}
-		fmt.Printf("Using API key: %s\n", maskedKey)
+		fmt.Printf("Using API key: %s\n", secure.Mask(apiKey))
 	} else {
-		fmt.Println("No API key found, using mock key for testing")
-		os.Setenv("XAI_API_KEY", "mock-api-key-for-testing")
+		fmt.Println("No API key found, using the offline mock provider")
+		os.Setenv("NOIDEA_LLM_MOCK", "1")
 	}
 
 	// Always enable LLM for testing